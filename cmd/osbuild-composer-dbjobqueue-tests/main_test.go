@@ -13,11 +13,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue/dbjobqueue"
 
+	"github.com/ondrejbudai/osbuild-composer-public/public/common/slogger"
 	"github.com/ondrejbudai/osbuild-composer-public/public/jobqueue/jobqueuetest"
 )
 
@@ -92,18 +94,57 @@ func TestJobQueueInterface(t *testing.T) {
 	})
 }
 
+func TestScheduling(t *testing.T) {
+	jobqueuetest.TestScheduling(t, func(config jobqueue.SchedulingConfig) (jobqueue.JobQueue, func(), error) {
+		err := migrate("last")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		conn, err := pgx.Connect(context.Background(), jobqueuetest.TestDbURL())
+		if err != nil {
+			return nil, nil, err
+		}
+		defer conn.Close(context.Background())
+		for _, table := range []string{"job_dependencies", "heartbeats", "jobs"} {
+			_, err = conn.Exec(context.Background(), fmt.Sprintf("DELETE FROM %s", table))
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		q, err := dbjobqueue.NewWithConfig(jobqueuetest.TestDbURL(), dbjobqueue.Config{
+			Logger:     slogger.NewLogrusLogger(logrus.StandardLogger()),
+			Scheduling: config,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		stop := func() {
+			q.Close()
+		}
+		return q, stop, nil
+	})
+}
+
 func testMigrationPath(t *testing.T, makeJobQueue func(migration string, clean bool) (jobqueue.JobQueue, func(), error)) {
 	q, stop, err := makeJobQueue("8", false)
 	defer stop()
 	require.NoError(t, err)
 
-	id, err := q.Enqueue("test", "{\"arg\": \"impormtanmt\"}", nil, "")
+	// The queue expects the latest schema when enqueueing and dequeueing
+	// (e.g. the priority column), so insert a running job by hand.
+	conn, err := pgx.Connect(context.Background(), jobqueuetest.TestDbURL())
 	require.NoError(t, err)
-	require.NotEmpty(t, id)
-	id, tok, _, _, _, err := q.Dequeue(context.Background(), uuid.Nil, []string{"test"}, []string{""})
+	defer conn.Close(context.Background())
+	id := uuid.New()
+	tok := uuid.New()
+	_, err = conn.Exec(context.Background(),
+		`INSERT INTO jobs(id, type, args, queued_at, started_at, token, channel) VALUES ($1, 'test', $2, now(), now(), $3, '')`,
+		id, "{\"arg\": \"impormtanmt\"}", tok)
+	require.NoError(t, err)
+	_, err = conn.Exec(context.Background(), `INSERT INTO heartbeats(token, id, heartbeat) VALUES ($1, $2, now())`, tok, id)
 	require.NoError(t, err)
-	require.NotEmpty(t, id)
-	require.NotEmpty(t, tok)
 
 	// make sure entering escaped nullbytes fails in 8
 	_, err = q.RequeueOrFinishJob(id, 0, &jobqueuetest.TestResult{Logs: []byte("{\"blegh\\u0000\": \"\\u0000reallyimportant stuff!\"}")})
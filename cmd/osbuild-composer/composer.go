@@ -20,13 +20,16 @@ import (
 
 	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue/dbjobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue/sqlitejobqueue"
 
 	"github.com/osbuild/image-builder/pkg/depsolvednf"
 	"github.com/osbuild/image-builder/pkg/distrofactory"
 	"github.com/osbuild/image-builder/pkg/experimentalflags"
 	"github.com/osbuild/image-builder/pkg/reporegistry"
+	"github.com/ondrejbudai/osbuild-composer-public/public/artifacts"
 	"github.com/ondrejbudai/osbuild-composer-public/public/auth"
 	"github.com/ondrejbudai/osbuild-composer-public/public/cloudapi"
+	"github.com/ondrejbudai/osbuild-composer-public/public/common/slogger"
 	v2 "github.com/ondrejbudai/osbuild-composer-public/public/cloudapi/v2"
 	"github.com/ondrejbudai/osbuild-composer-public/public/jobqueue/fsjobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/weldr"
@@ -66,9 +69,29 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 
 	var err error
 	if config.Worker.EnableArtifacts {
-		workerConfig.ArtifactsDir, err = c.ensureStateDirectory("artifacts", 0755)
-		if err != nil {
-			return nil, err
+		switch config.Worker.ArtifactsBackend {
+		case "", ArtifactsBackendLocal:
+			workerConfig.ArtifactsDir, err = c.ensureStateDirectory("artifacts", 0755)
+			if err != nil {
+				return nil, err
+			}
+		case ArtifactsBackendS3:
+			s3Config := config.Worker.ArtifactsS3
+			workerConfig.Artifacts, err = artifacts.NewS3(artifacts.S3Config{
+				Endpoint:            s3Config.Endpoint,
+				Region:              s3Config.Region,
+				Bucket:              s3Config.Bucket,
+				Prefix:              s3Config.Prefix,
+				AccessKeyID:         s3Config.AccessKeyID,
+				SecretAccessKey:     s3Config.SecretAccessKey,
+				CABundle:            s3Config.CABundle,
+				SkipSSLVerification: s3Config.SkipSSLVerification,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("cannot create S3 artifact store: %v", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown artifacts backend %q", config.Worker.ArtifactsBackend)
 		}
 	}
 
@@ -104,8 +127,38 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 	}
 	c.solver.CleanupOldCacheDirs(repoDistros)
 
+	scheduling := jobqueue.SchedulingConfig{
+		Policy:         jobqueue.SchedulingPolicy(config.Worker.JobScheduling),
+		ChannelWeights: config.Worker.JobChannelWeights,
+		Quotas: jobqueue.QuotaConfig{
+			JobTypes:              []string{worker.JobTypeOSBuild},
+			DefaultMaxRunningJobs: config.Quotas.Default.MaxRunningJobs,
+			MaxRunningJobs:        make(map[string]int),
+		},
+	}
+	for channel, quota := range config.Quotas.Channels {
+		scheduling.Quotas.MaxRunningJobs[channel] = quota.MaxRunningJobs
+	}
+	scheduling.RetryBackoff.Initial, err = time.ParseDuration(config.Worker.JobRetryBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse job retry backoff: %v", err)
+	}
+	scheduling.RetryBackoff.Max, err = time.ParseDuration(config.Worker.JobRetryBackoffMax)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse job retry backoff max: %v", err)
+	}
+
+	jobQueue := config.Worker.JobQueue
+	if jobQueue == "" {
+		jobQueue = JobQueueFS
+		if config.Worker.PGDatabase != "" {
+			jobQueue = JobQueuePostgres
+		}
+	}
+
 	var jobs jobqueue.JobQueue
-	if config.Worker.PGDatabase != "" {
+	switch jobQueue {
+	case JobQueuePostgres:
 		dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
 			config.Worker.PGUser,
 			config.Worker.PGPassword,
@@ -119,19 +172,38 @@ func NewComposer(config *ComposerConfigFile, stateDir, cacheDir string) (*Compos
 			dbURL += fmt.Sprintf("&pool_max_conns=%d", config.Worker.PGMaxConns)
 		}
 
-		jobs, err = dbjobqueue.New(dbURL)
+		jobs, err = dbjobqueue.NewWithConfig(dbURL, dbjobqueue.Config{
+			Logger:     slogger.NewLogrusLogger(logrus.StandardLogger()),
+			Scheduling: scheduling,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot create jobqueue: %v", err)
+		}
+	case JobQueueSQLite:
+		queueDir, err := c.ensureStateDirectory("jobqueue", 0700)
+		if err != nil {
+			return nil, err
+		}
+		jobs, err = sqlitejobqueue.NewWithConfig(path.Join(queueDir, "jobs.sqlite"), sqlitejobqueue.Config{
+			Logger:     slogger.NewLogrusLogger(logrus.StandardLogger()),
+			Scheduling: scheduling,
+		})
 		if err != nil {
 			return nil, fmt.Errorf("cannot create jobqueue: %v", err)
 		}
-	} else {
+	case JobQueueFS:
 		queueDir, err := c.ensureStateDirectory("jobs", 0700)
 		if err != nil {
 			return nil, err
 		}
-		jobs, err = fsjobqueue.New(queueDir)
+		jobs, err = fsjobqueue.NewWithConfig(queueDir, fsjobqueue.Config{
+			Scheduling: scheduling,
+		})
 		if err != nil {
 			return nil, fmt.Errorf("cannot create jobqueue: %v", err)
 		}
+	default:
+		return nil, fmt.Errorf("unknown job queue %q", jobQueue)
 	}
 
 	workerConfig.RequestJobTimeout, err = time.ParseDuration(config.Worker.RequestJobTimeout)
@@ -170,6 +242,22 @@ func (c *Composer) InitAPI(cert, key string, enableTLS bool, enableMTLS bool, en
 		JWTEnabled:                    c.config.Koji.EnableJWT,
 		TenantProviderFields:          c.config.Koji.JWTTenantProviderFields,
 		BootcUseRemoteContainerSource: c.config.Bootc.UseRemoteContainerSource,
+		DefaultMaxPendingComposes:     c.config.Quotas.Default.MaxPendingComposes,
+		MaxPendingComposes:            make(map[string]int),
+		ImageTypeWorkerLabels:         make(map[string][]string),
+		DefaultS3CredentialsRefs:      c.config.GenericS3.Default.CredentialsRefs,
+		S3CredentialsRefs:             make(map[string][]string),
+	}
+	for channel, quota := range c.config.Quotas.Channels {
+		config.MaxPendingComposes[channel] = quota.MaxPendingComposes
+	}
+	for channel, s3 := range c.config.GenericS3.Channels {
+		config.S3CredentialsRefs[channel] = s3.CredentialsRefs
+	}
+	for imageType, defaults := range c.config.ImageTypes {
+		if len(defaults.WorkerLabels) > 0 {
+			config.ImageTypeWorkerLabels[imageType] = defaults.WorkerLabels
+		}
 	}
 
 	// handle experimental image-builder manifest generation option using the
@@ -12,20 +12,23 @@ import (
 )
 
 type ComposerConfigFile struct {
-	Koji               KojiAPIConfig     `toml:"koji"`
-	Worker             WorkerAPIConfig   `toml:"worker"`
-	WeldrAPI           WeldrAPIConfig    `toml:"weldr_api"`
-	Bootc              BootcConfig       `toml:"bootc"`
-	DistroAliases      map[string]string `toml:"distro_aliases" env:"DISTRO_ALIASES"`
-	LogLevel           string            `toml:"log_level"`
-	LogFormat          string            `toml:"log_format"`
-	DNFJson            string            `toml:"dnf-json"`
-	IgnoreMissingRepos bool              `toml:"ignore_missing_repos"`
-	SplunkHost         string            `env:"SPLUNK_HEC_HOST"`
-	SplunkPort         string            `env:"SPLUNK_HEC_PORT"`
-	SplunkToken        string            `env:"SPLUNK_HEC_TOKEN"`
-	GlitchTipDSN       string            `env:"GLITCHTIP_DSN"`
-	DeploymentChannel  string            `env:"CHANNEL"`
+	Koji               KojiAPIConfig              `toml:"koji"`
+	Worker             WorkerAPIConfig            `toml:"worker"`
+	WeldrAPI           WeldrAPIConfig             `toml:"weldr_api"`
+	Bootc              BootcConfig                `toml:"bootc"`
+	Quotas             QuotaConfig                `toml:"quotas"`
+	ImageTypes         map[string]ImageTypeConfig `toml:"image_types"`
+	GenericS3          GenericS3Config            `toml:"generic_s3"`
+	DistroAliases      map[string]string          `toml:"distro_aliases" env:"DISTRO_ALIASES"`
+	LogLevel           string                     `toml:"log_level"`
+	LogFormat          string                     `toml:"log_format"`
+	DNFJson            string                     `toml:"dnf-json"`
+	IgnoreMissingRepos bool                       `toml:"ignore_missing_repos"`
+	SplunkHost         string                     `env:"SPLUNK_HEC_HOST"`
+	SplunkPort         string                     `env:"SPLUNK_HEC_PORT"`
+	SplunkToken        string                     `env:"SPLUNK_HEC_TOKEN"`
+	GlitchTipDSN       string                     `env:"GLITCHTIP_DSN"`
+	DeploymentChannel  string                     `env:"CHANNEL"`
 }
 
 type KojiAPIConfig struct {
@@ -41,26 +44,63 @@ type KojiAPIConfig struct {
 }
 
 type WorkerAPIConfig struct {
-	AllowedDomains          []string `toml:"allowed_domains"`
-	CA                      string   `toml:"ca"`
-	RequestJobTimeout       string   `toml:"request_job_timeout"`
-	BasePath                string   `toml:"base_path"`
-	EnableArtifacts         bool     `toml:"enable_artifacts"`
-	PGHost                  string   `toml:"pg_host" env:"PGHOST"`
-	PGPort                  string   `toml:"pg_port" env:"PGPORT"`
-	PGDatabase              string   `toml:"pg_database" env:"PGDATABASE"`
-	PGUser                  string   `toml:"pg_user" env:"PGUSER"`
-	PGPassword              string   `toml:"pg_password" env:"PGPASSWORD"`
-	PGSSLMode               string   `toml:"pg_ssl_mode" env:"PGSSLMODE"`
-	PGMaxConns              int      `toml:"pg_max_conns" env:"PGMAXCONNS"`
-	EnableTLS               bool     `toml:"enable_tls"`
-	EnableMTLS              bool     `toml:"enable_mtls"`
-	EnableJWT               bool     `toml:"enable_jwt"`
-	JWTKeysURLs             []string `toml:"jwt_keys_urls"`
-	JWTKeysCA               string   `toml:"jwt_ca_file"`
-	JWTACLFile              string   `toml:"jwt_acl_file"`
-	JWTTenantProviderFields []string `toml:"jwt_tenant_provider_fields"`
-	WorkerHeartbeatTimeout  string   `toml:"worker_heartbeat_timeout"`
+	AllowedDomains          []string           `toml:"allowed_domains"`
+	CA                      string             `toml:"ca"`
+	RequestJobTimeout       string             `toml:"request_job_timeout"`
+	BasePath                string             `toml:"base_path"`
+	EnableArtifacts         bool               `toml:"enable_artifacts"`
+	ArtifactsBackend        string             `toml:"artifacts_backend" env:"ARTIFACTS_BACKEND"`
+	ArtifactsS3             ArtifactsS3Config  `toml:"artifacts_s3"`
+	PGHost                  string             `toml:"pg_host" env:"PGHOST"`
+	PGPort                  string             `toml:"pg_port" env:"PGPORT"`
+	PGDatabase              string             `toml:"pg_database" env:"PGDATABASE"`
+	PGUser                  string             `toml:"pg_user" env:"PGUSER"`
+	PGPassword              string             `toml:"pg_password" env:"PGPASSWORD"`
+	PGSSLMode               string             `toml:"pg_ssl_mode" env:"PGSSLMODE"`
+	PGMaxConns              int                `toml:"pg_max_conns" env:"PGMAXCONNS"`
+	EnableTLS               bool               `toml:"enable_tls"`
+	EnableMTLS              bool               `toml:"enable_mtls"`
+	EnableJWT               bool               `toml:"enable_jwt"`
+	JWTKeysURLs             []string           `toml:"jwt_keys_urls"`
+	JWTKeysCA               string             `toml:"jwt_ca_file"`
+	JWTACLFile              string             `toml:"jwt_acl_file"`
+	JWTTenantProviderFields []string           `toml:"jwt_tenant_provider_fields"`
+	WorkerHeartbeatTimeout  string             `toml:"worker_heartbeat_timeout"`
+	JobScheduling           string             `toml:"job_scheduling" env:"JOB_SCHEDULING"`
+	JobChannelWeights       map[string]float64 `toml:"job_channel_weights"`
+	JobRetryBackoff         string             `toml:"job_retry_backoff"`
+	JobRetryBackoffMax      string             `toml:"job_retry_backoff_max"`
+	JobQueue                string             `toml:"job_queue" env:"JOB_QUEUE"`
+}
+
+// Job queue backends of WorkerAPIConfig.JobQueue. When it's empty, the
+// Postgres queue is used if PGDatabase is set, the filesystem queue
+// otherwise.
+const (
+	JobQueueFS       = "fs"
+	JobQueuePostgres = "postgres"
+	JobQueueSQLite   = "sqlite"
+)
+
+// Artifact storage backends of WorkerAPIConfig.ArtifactsBackend. When it's
+// empty, the artifacts are stored in composer's state directory.
+const (
+	ArtifactsBackendLocal = "local"
+	ArtifactsBackendS3    = "s3"
+)
+
+// ArtifactsS3Config configures the bucket of an S3-compatible object store
+// the artifacts are stored in, so that several composer replicas can share
+// them.
+type ArtifactsS3Config struct {
+	Endpoint            string `toml:"endpoint" env:"ARTIFACTS_S3_ENDPOINT"`
+	Region              string `toml:"region" env:"ARTIFACTS_S3_REGION"`
+	Bucket              string `toml:"bucket" env:"ARTIFACTS_S3_BUCKET"`
+	Prefix              string `toml:"prefix" env:"ARTIFACTS_S3_PREFIX"`
+	AccessKeyID         string `toml:"access_key_id" env:"ARTIFACTS_S3_ACCESS_KEY_ID"`
+	SecretAccessKey     string `toml:"secret_access_key" env:"ARTIFACTS_S3_SECRET_ACCESS_KEY"`
+	CABundle            string `toml:"ca_bundle" env:"ARTIFACTS_S3_CA_BUNDLE"`
+	SkipSSLVerification bool   `toml:"skip_ssl_verification" env:"ARTIFACTS_S3_SKIP_SSL_VERIFICATION"`
 }
 
 type WeldrAPIConfig struct {
@@ -76,6 +116,44 @@ type BootcConfig struct {
 	UseRemoteContainerSource bool `toml:"use_remote_container_source" env:"BOOTC_USE_REMOTE_CONTAINER_SOURCE"`
 }
 
+// QuotaConfig limits the composes of tenant channels. Channels which are not
+// in Channels use the Default limits. A limit of 0 means unlimited.
+type QuotaConfig struct {
+	Default  ChannelQuotaConfig            `toml:"default"`
+	Channels map[string]ChannelQuotaConfig `toml:"channels"`
+}
+
+type ChannelQuotaConfig struct {
+	// Maximum number of osbuild jobs which are running at the same time.
+	// Further jobs are held back in the queue.
+	MaxRunningJobs int `toml:"max_running_jobs"`
+	// Maximum number of unfinished composes. Further compose requests are
+	// rejected.
+	MaxPendingComposes int `toml:"max_pending_composes"`
+}
+
+// ImageTypeConfig holds defaults of the composes of an image type, keyed by
+// the distro's image type name, e.g. "ami".
+type ImageTypeConfig struct {
+	// Capability labels a worker must have to build the image type, see
+	// the labels option of osbuild-worker.
+	WorkerLabels []string `toml:"worker_labels"`
+}
+
+// GenericS3Config restricts the S3 credentials of the workers which compose
+// requests of tenant channels may upload with. Channels which are not in
+// Channels use the Default credentials.
+type GenericS3Config struct {
+	Default  ChannelGenericS3Config            `toml:"default"`
+	Channels map[string]ChannelGenericS3Config `toml:"channels"`
+}
+
+type ChannelGenericS3Config struct {
+	// Names of the credentials_refs of the workers' generic_s3 config. A
+	// compose request referring to any other credentials is rejected.
+	CredentialsRefs []string `toml:"credentials_refs"`
+}
+
 // weldrDistrosImageTypeDenyList returns a map of distro-specific Image Type
 // deny lists for Weldr API.
 func (c *ComposerConfigFile) weldrDistrosImageTypeDenyList() map[string][]string {
@@ -109,6 +187,8 @@ func GetDefaultConfig() *ComposerConfigFile {
 			EnableMTLS:             true,
 			EnableJWT:              false,
 			WorkerHeartbeatTimeout: "1h",
+			JobRetryBackoff:        "30s",
+			JobRetryBackoffMax:     "15m",
 		},
 		WeldrAPI: WeldrAPIConfig{
 			map[string]WeldrDistroConfig{
@@ -254,5 +334,6 @@ func loadConfigFromEnv(intf interface{}) error {
 func DumpConfig(c ComposerConfigFile, w io.Writer) error {
 	// sensor sensitive fields
 	c.Worker.PGPassword = ""
+	c.Worker.ArtifactsS3.SecretAccessKey = ""
 	return toml.NewEncoder(w).Encode(c)
 }
@@ -45,6 +45,8 @@ func TestDefaultConfig(t *testing.T) {
 		EnableMTLS:             true,
 		EnableJWT:              false,
 		WorkerHeartbeatTimeout: "1h",
+		JobRetryBackoff:        "30s",
+		JobRetryBackoffMax:     "15m",
 	}, defaultConfig.Worker)
 
 	expectedWeldrAPIConfig := WeldrAPIConfig{
@@ -92,6 +94,17 @@ func TestConfig(t *testing.T) {
 	require.Equal(t, []string{"qcow2"}, config.WeldrAPI.DistroConfigs["rhel-84"].ImageTypeDenyList)
 
 	require.Equal(t, "overwrite-me-db", config.Worker.PGDatabase)
+	require.Equal(t, "fair-share", config.Worker.JobScheduling)
+	require.Equal(t, map[string]float64{"org-1": 2.0}, config.Worker.JobChannelWeights)
+	require.Equal(t, "1m", config.Worker.JobRetryBackoff)
+	require.Equal(t, "15m", config.Worker.JobRetryBackoffMax)
+	require.Equal(t, JobQueueSQLite, config.Worker.JobQueue)
+	require.Equal(t, ArtifactsBackendS3, config.Worker.ArtifactsBackend)
+	require.Equal(t, ArtifactsS3Config{
+		Endpoint: "https://minio.example.org:9000",
+		Region:   "us-east-1",
+		Bucket:   "composer-artifacts",
+	}, config.Worker.ArtifactsS3)
 
 	require.False(t, config.Koji.EnableJWT)
 	require.Equal(t, []string{"https://sso.redhat.com/auth/realms/redhat-external/protocol/openid-connect/certs"}, config.Koji.JWTKeysURLs)
@@ -107,9 +120,24 @@ func TestConfig(t *testing.T) {
 	}
 	require.Equal(t, expectedDistroAliases, config.DistroAliases)
 	require.True(t, config.Bootc.UseRemoteContainerSource)
+	require.Equal(t, QuotaConfig{
+		Default: ChannelQuotaConfig{MaxRunningJobs: 2, MaxPendingComposes: 10},
+		Channels: map[string]ChannelQuotaConfig{
+			"org-1": {MaxRunningJobs: 5},
+		},
+	}, config.Quotas)
+	require.Equal(t, map[string]ImageTypeConfig{
+		"ami": {WorkerLabels: []string{"nested-virt"}},
+	}, config.ImageTypes)
+	require.Equal(t, GenericS3Config{
+		Channels: map[string]ChannelGenericS3Config{
+			"org-1": {CredentialsRefs: []string{"ceph"}},
+		},
+	}, config.GenericS3)
 
 	// Test overriding the config file with environment variables
 	require.NoError(t, os.Setenv("PGDATABASE", "composer-db"))
+	require.NoError(t, os.Setenv("ARTIFACTS_S3_SECRET_ACCESS_KEY", "secret-key"))
 	// NOTE: use negated config value to ensure that the env variable overrides the config file value
 	require.NoError(t, os.Setenv("BOOTC_USE_REMOTE_CONTAINER_SOURCE", strconv.FormatBool(!config.Bootc.UseRemoteContainerSource)))
 
@@ -119,6 +147,7 @@ func TestConfig(t *testing.T) {
 	require.NotNil(t, config)
 
 	require.Equal(t, "composer-db", config.Worker.PGDatabase)
+	require.Equal(t, "secret-key", config.Worker.ArtifactsS3.SecretAccessKey)
 	require.False(t, config.Bootc.UseRemoteContainerSource)
 }
 
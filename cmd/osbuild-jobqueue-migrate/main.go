@@ -0,0 +1,113 @@
+// osbuild-jobqueue-migrate copies all jobs from one job queue to another,
+// e.g. from the filesystem queue of a single host installation to Postgres.
+// Job ids are kept, so artifacts in the state directory stay valid.
+//
+// Composer must not be running while jobs are migrated. Jobs which are
+// running during the migration are requeued by the new queue once their
+// heartbeat times out.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue/dbjobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue/sqlitejobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/public/jobqueue/fsjobqueue"
+)
+
+const usage = `Usage: %s -from QUEUE -to QUEUE
+
+Copies all jobs from one job queue to another and verifies the copy. The
+target queue must be empty. QUEUE is one of:
+
+  fs:DIRECTORY       filesystem queue, e.g. fs:/var/lib/osbuild-composer/jobs
+  sqlite:PATH        SQLite queue, e.g. sqlite:/var/lib/osbuild-composer/jobqueue/jobs.sqlite
+  postgres://...     Postgres queue, the database schema must be up to date
+
+`
+
+// openQueue opens the queue described by `spec`, see usage. It creates
+// the directory of a filesystem queue if `create` is set.
+func openQueue(spec string, create bool) (queue, func(), error) {
+	if strings.HasPrefix(spec, "postgres://") || strings.HasPrefix(spec, "postgresql://") {
+		q, err := dbjobqueue.New(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		return q, q.Close, nil
+	}
+
+	kind, location, ok := strings.Cut(spec, ":")
+	if !ok || location == "" {
+		return nil, nil, fmt.Errorf("invalid queue %q", spec)
+	}
+
+	switch kind {
+	case "fs":
+		if create {
+			err := os.MkdirAll(location, 0700)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		q, err := fsjobqueue.New(location)
+		if err != nil {
+			return nil, nil, err
+		}
+		return q, func() {}, nil
+	case "sqlite":
+		q, err := sqlitejobqueue.New(location)
+		if err != nil {
+			return nil, nil, err
+		}
+		return q, q.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown queue type %q", kind)
+	}
+}
+
+func main() {
+	var from, to string
+	flag.StringVar(&from, "from", "", "queue to read the jobs from")
+	flag.StringVar(&to, "to", "", "queue to write the jobs to")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), usage, os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if from == "" || to == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	source, closeSource, err := openQueue(from, false)
+	if err != nil {
+		log.Fatalf("Cannot open %s: %v", from, err)
+	}
+	defer closeSource()
+
+	target, closeTarget, err := openQueue(to, true)
+	if err != nil {
+		log.Fatalf("Cannot open %s: %v", to, err)
+	}
+	defer closeTarget()
+
+	ctx := context.Background()
+	copied, err := migrateJobs(ctx, source, target)
+	if err != nil {
+		log.Fatalf("Migration failed after copying %d jobs: %v", copied, err)
+	}
+	log.Printf("Copied %d jobs", copied)
+
+	jobs, edges, err := verifyJobs(ctx, source, target)
+	if err != nil {
+		log.Fatalf("Verification failed: %v", err)
+	}
+	log.Printf("Verified %d jobs and %d dependencies", jobs, edges)
+}
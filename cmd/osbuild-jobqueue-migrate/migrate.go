@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
+)
+
+// queue is a job queue jobs can be migrated from and to.
+type queue interface {
+	jobqueue.JobExporter
+	jobqueue.JobImporter
+}
+
+// exportJobs returns all jobs of `q` by their id.
+func exportJobs(ctx context.Context, q jobqueue.JobExporter) (map[uuid.UUID]jobqueue.JobRecord, error) {
+	jobs := make(map[uuid.UUID]jobqueue.JobRecord)
+	err := q.ExportJobs(ctx, func(r jobqueue.JobRecord) error {
+		jobs[r.ID] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// sortByDependencies orders `jobs` so that every job comes after all of its
+// dependencies. Independent jobs are ordered by the time they were queued.
+func sortByDependencies(jobs map[uuid.UUID]jobqueue.JobRecord) ([]jobqueue.JobRecord, error) {
+	ids := make([]uuid.UUID, 0, len(jobs))
+	for id := range jobs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := jobs[ids[i]], jobs[ids[j]]
+		if !a.QueuedAt.Equal(b.QueuedAt) {
+			return a.QueuedAt.Before(b.QueuedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[uuid.UUID]int)
+	sorted := make([]jobqueue.JobRecord, 0, len(jobs))
+
+	var visit func(id uuid.UUID) error
+	visit = func(id uuid.UUID) error {
+		switch state[id] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("job %s depends on itself", id)
+		}
+		state[id] = visiting
+		for _, d := range jobs[id].Dependencies {
+			if _, ok := jobs[d]; !ok {
+				return fmt.Errorf("dependency %s of job %s does not exist", d, id)
+			}
+			err := visit(d)
+			if err != nil {
+				return err
+			}
+		}
+		state[id] = done
+		sorted = append(sorted, jobs[id])
+		return nil
+	}
+
+	for _, id := range ids {
+		err := visit(id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// migrateJobs copies all jobs of `from` into `to`, which must be empty, and
+// returns the number of copied jobs.
+func migrateJobs(ctx context.Context, from, to queue) (int, error) {
+	existing, err := exportJobs(ctx, to)
+	if err != nil {
+		return 0, fmt.Errorf("error reading the target queue: %v", err)
+	}
+	if len(existing) > 0 {
+		return 0, fmt.Errorf("the target queue is not empty, it contains %d jobs", len(existing))
+	}
+
+	jobs, err := exportJobs(ctx, from)
+	if err != nil {
+		return 0, fmt.Errorf("error reading the source queue: %v", err)
+	}
+	sorted, err := sortByDependencies(jobs)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, r := range sorted {
+		err = to.ImportJob(ctx, r)
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(sorted), nil
+}
+
+// verifyJobs checks that `to` contains the same jobs as `from`, with the
+// same dependency edges, types, channels, states, arguments, results,
+// priorities, labels, and timestamps. It returns the number of verified jobs
+// and dependency edges.
+func verifyJobs(ctx context.Context, from, to jobqueue.JobExporter) (int, int, error) {
+	source, err := exportJobs(ctx, from)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error reading the source queue: %v", err)
+	}
+	target, err := exportJobs(ctx, to)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error reading the target queue: %v", err)
+	}
+
+	if len(source) != len(target) {
+		return 0, 0, fmt.Errorf("the source queue contains %d jobs, but the target queue %d", len(source), len(target))
+	}
+
+	var edges int
+	for id, s := range source {
+		t, ok := target[id]
+		if !ok {
+			return 0, 0, fmt.Errorf("job %s is missing in the target queue", id)
+		}
+
+		if s.Type != t.Type || s.Channel != t.Channel {
+			return 0, 0, fmt.Errorf("job %s has type %q and channel %q in the source queue, but type %q and channel %q in the target queue", id, s.Type, s.Channel, t.Type, t.Channel)
+		}
+		if s.State() != t.State() {
+			return 0, 0, fmt.Errorf("job %s is %s in the source queue, but %s in the target queue", id, s.State(), t.State())
+		}
+		if err := compareJobData(s, t); err != nil {
+			return 0, 0, fmt.Errorf("job %s differs in the target queue: %v", id, err)
+		}
+
+		sourceDeps := slices.Clone(s.Dependencies)
+		targetDeps := slices.Clone(t.Dependencies)
+		sortUUIDs(sourceDeps)
+		sortUUIDs(targetDeps)
+		if !slices.Equal(sourceDeps, targetDeps) {
+			return 0, 0, fmt.Errorf("job %s depends on %v in the source queue, but on %v in the target queue", id, sourceDeps, targetDeps)
+		}
+		edges += len(sourceDeps)
+	}
+
+	return len(source), edges, nil
+}
+
+// compareJobData compares what a job carries besides its type, channel,
+// state and dependencies. Queues store JSON and timestamps differently, e.g.
+// Postgres normalizes JSON and keeps only microseconds.
+func compareJobData(s, t jobqueue.JobRecord) error {
+	if !jsonEqual(s.Args, t.Args) {
+		return fmt.Errorf("arguments %s instead of %s", t.Args, s.Args)
+	}
+	if !jsonEqual(s.Result, t.Result) {
+		return fmt.Errorf("result %s instead of %s", t.Result, s.Result)
+	}
+	if s.Priority != t.Priority {
+		return fmt.Errorf("priority %d instead of %d", t.Priority, s.Priority)
+	}
+	sourceLabels := slices.Clone(s.RequiredLabels)
+	targetLabels := slices.Clone(t.RequiredLabels)
+	slices.Sort(sourceLabels)
+	slices.Sort(targetLabels)
+	if !slices.Equal(sourceLabels, targetLabels) {
+		return fmt.Errorf("required labels %v instead of %v", targetLabels, sourceLabels)
+	}
+
+	timestamps := []struct {
+		name   string
+		source time.Time
+		target time.Time
+	}{
+		{"queued", s.QueuedAt, t.QueuedAt},
+		{"started", s.StartedAt, t.StartedAt},
+		{"finished", s.FinishedAt, t.FinishedAt},
+	}
+	for _, ts := range timestamps {
+		if !ts.source.Truncate(time.Microsecond).Equal(ts.target.Truncate(time.Microsecond)) {
+			return fmt.Errorf("%s at %v instead of %v", ts.name, ts.target, ts.source)
+		}
+	}
+	return nil
+}
+
+// jsonEqual compares two JSON documents by their content, a missing
+// document equals null.
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+func sortUUIDs(ids []uuid.UUID) {
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+}
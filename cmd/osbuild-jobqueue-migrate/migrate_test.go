@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue/sqlitejobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/public/jobqueue/fsjobqueue"
+)
+
+type testArgs struct {
+	Name string `json:"name"`
+}
+
+type testResult struct {
+	Success bool `json:"success"`
+}
+
+// fillQueue enqueues a small job graph in every state into `q` and returns
+// the ids of the jobs by name.
+func fillQueue(t *testing.T, q jobqueue.JobQueue) map[string]uuid.UUID {
+	ctx := context.Background()
+	ids := make(map[string]uuid.UUID)
+	var err error
+
+	ids["finished"], err = q.Enqueue("depsolve", testArgs{"finished"}, nil, "")
+	require.NoError(t, err)
+	ids["running"], err = q.Enqueue("manifest", testArgs{"running"}, nil, "tenant")
+	require.NoError(t, err)
+
+	id, _, _, _, _, err := q.Dequeue(ctx, uuid.Nil, []string{"depsolve"}, []string{""})
+	require.NoError(t, err)
+	require.Equal(t, ids["finished"], id)
+	_, err = q.RequeueOrFinishJob(id, 0, testResult{true})
+	require.NoError(t, err)
+
+	id, _, _, _, _, err = q.Dequeue(ctx, uuid.Nil, []string{"manifest"}, []string{"tenant"})
+	require.NoError(t, err)
+	require.Equal(t, ids["running"], id)
+
+	ids["pending"], err = q.Enqueue("osbuild", testArgs{"pending"}, []uuid.UUID{ids["finished"]}, "")
+	require.NoError(t, err)
+	ids["blocked"], err = q.Enqueue("osbuild", testArgs{"blocked"}, []uuid.UUID{ids["finished"], ids["running"]}, "tenant")
+	require.NoError(t, err)
+	ids["canceled"], err = q.Enqueue("osbuild", testArgs{"canceled"}, nil, "")
+	require.NoError(t, err)
+	require.NoError(t, q.CancelJob(ids["canceled"]))
+
+	return ids
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	fsDir := filepath.Join(dir, "fs")
+	require.NoError(t, os.Mkdir(fsDir, 0700))
+	source, err := fsjobqueue.New(fsDir)
+	require.NoError(t, err)
+	ids := fillQueue(t, source)
+
+	target, err := sqlitejobqueue.New(filepath.Join(dir, "jobs.sqlite"))
+	require.NoError(t, err)
+	defer target.Close()
+
+	n, err := migrateJobs(ctx, source, target)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+
+	jobs, edges, err := verifyJobs(ctx, source, target)
+	require.NoError(t, err)
+	require.Equal(t, 5, jobs)
+	require.Equal(t, 3, edges)
+
+	// results and arguments are kept
+	_, _, result, _, _, finished, _, _, _, err := target.JobStatus(ids["finished"])
+	require.NoError(t, err)
+	require.False(t, finished.IsZero())
+	require.JSONEq(t, `{"success":true}`, string(result))
+
+	_, args, deps, channel, err := target.Job(ids["blocked"])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"blocked"}`, string(args))
+	require.ElementsMatch(t, []uuid.UUID{ids["finished"], ids["running"]}, deps)
+	require.Equal(t, "tenant", channel)
+
+	// the pending job can be dequeued from the target, the blocked one
+	// only once the running job finished
+	id, _, _, _, _, err := target.Dequeue(ctx, uuid.Nil, []string{"osbuild"}, []string{"", "tenant"})
+	require.NoError(t, err)
+	require.Equal(t, ids["pending"], id)
+
+	_, err = target.RequeueOrFinishJob(ids["running"], 0, testResult{true})
+	require.NoError(t, err)
+	id, _, _, _, _, err = target.Dequeue(ctx, uuid.Nil, []string{"osbuild"}, []string{"", "tenant"})
+	require.NoError(t, err)
+	require.Equal(t, ids["blocked"], id)
+
+	// migrating back into a fresh filesystem queue yields the same jobs
+	backDir := filepath.Join(dir, "back")
+	require.NoError(t, os.Mkdir(backDir, 0700))
+	back, err := fsjobqueue.New(backDir)
+	require.NoError(t, err)
+
+	n, err = migrateJobs(ctx, target, back)
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	_, _, err = verifyJobs(ctx, target, back)
+	require.NoError(t, err)
+}
+
+// A job keeps its result and timestamps when it's migrated to a database and
+// back to the filesystem.
+func TestMigrateRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	fsDir := filepath.Join(dir, "fs")
+	require.NoError(t, os.Mkdir(fsDir, 0700))
+	source, err := fsjobqueue.New(fsDir)
+	require.NoError(t, err)
+	ids := fillQueue(t, source)
+
+	db, err := sqlitejobqueue.New(filepath.Join(dir, "jobs.sqlite"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = migrateJobs(ctx, source, db)
+	require.NoError(t, err)
+
+	backDir := filepath.Join(dir, "back")
+	require.NoError(t, os.Mkdir(backDir, 0700))
+	back, err := fsjobqueue.New(backDir)
+	require.NoError(t, err)
+	_, err = migrateJobs(ctx, db, back)
+	require.NoError(t, err)
+
+	_, _, err = verifyJobs(ctx, source, back)
+	require.NoError(t, err)
+
+	jobs, err := exportJobs(ctx, back)
+	require.NoError(t, err)
+	finished := jobs[ids["finished"]]
+	require.JSONEq(t, `{"success":true}`, string(finished.Result))
+	require.JSONEq(t, `{"name":"finished"}`, string(finished.Args))
+	require.False(t, finished.StartedAt.IsZero())
+	require.False(t, finished.FinishedAt.IsZero())
+}
+
+// records is a queue exporting a fixed set of jobs.
+type records []jobqueue.JobRecord
+
+func (r records) ExportJobs(ctx context.Context, fn func(jobqueue.JobRecord) error) error {
+	for _, record := range r {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestVerifyJobsData(t *testing.T) {
+	ctx := context.Background()
+	queued := time.Date(2024, 1, 1, 12, 0, 0, 123456789, time.UTC)
+	source := jobqueue.JobRecord{
+		ID:             uuid.New(),
+		Type:           "osbuild",
+		Args:           json.RawMessage(`{"name": "job", "arch": "x86_64"}`),
+		Result:         json.RawMessage(`{"success": true}`),
+		Priority:       5,
+		RequiredLabels: []string{"nested-virt", "large-disk"},
+		QueuedAt:       queued,
+		StartedAt:      queued.Add(time.Minute),
+		FinishedAt:     queued.Add(time.Hour),
+	}
+
+	// JSON is compared by its content and timestamps by microseconds
+	same := source
+	same.Args = json.RawMessage(`{"arch":"x86_64","name":"job"}`)
+	same.RequiredLabels = []string{"large-disk", "nested-virt"}
+	same.QueuedAt = queued.Truncate(time.Microsecond).In(time.Local)
+	_, _, err := verifyJobs(ctx, records{source}, records{same})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(r *jobqueue.JobRecord)
+	}{
+		{"args", func(r *jobqueue.JobRecord) { r.Args = json.RawMessage(`{"name": "other"}`) }},
+		{"result", func(r *jobqueue.JobRecord) { r.Result = nil }},
+		{"priority", func(r *jobqueue.JobRecord) { r.Priority = 0 }},
+		{"labels", func(r *jobqueue.JobRecord) { r.RequiredLabels = nil }},
+		{"queued", func(r *jobqueue.JobRecord) { r.QueuedAt = queued.Add(time.Second) }},
+		{"started", func(r *jobqueue.JobRecord) { r.StartedAt = queued }},
+		{"finished", func(r *jobqueue.JobRecord) { r.FinishedAt = queued.Add(2 * time.Hour) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := source
+			tt.modify(&target)
+			_, _, err := verifyJobs(ctx, records{source}, records{target})
+			require.ErrorContains(t, err, "differs in the target queue")
+		})
+	}
+}
+
+func TestMigrateNonEmptyTarget(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	source, err := sqlitejobqueue.New(filepath.Join(dir, "source.sqlite"))
+	require.NoError(t, err)
+	defer source.Close()
+	fillQueue(t, source)
+
+	target, err := sqlitejobqueue.New(filepath.Join(dir, "target.sqlite"))
+	require.NoError(t, err)
+	defer target.Close()
+	_, err = target.Enqueue("osbuild", testArgs{"existing"}, nil, "")
+	require.NoError(t, err)
+
+	_, err = migrateJobs(ctx, source, target)
+	require.ErrorContains(t, err, "not empty")
+
+	_, _, err = verifyJobs(ctx, source, target)
+	require.Error(t, err)
+}
+
+func TestSortByDependencies(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	sorted, err := sortByDependencies(map[uuid.UUID]jobqueue.JobRecord{
+		a: {ID: a, Dependencies: []uuid.UUID{b, c}},
+		b: {ID: b, Dependencies: []uuid.UUID{c}},
+		c: {ID: c},
+	})
+	require.NoError(t, err)
+	require.Len(t, sorted, 3)
+	require.Equal(t, []uuid.UUID{c, b, a}, []uuid.UUID{sorted[0].ID, sorted[1].ID, sorted[2].ID})
+
+	_, err = sortByDependencies(map[uuid.UUID]jobqueue.JobRecord{
+		a: {ID: a, Dependencies: []uuid.UUID{b}},
+	})
+	require.ErrorContains(t, err, "does not exist")
+
+	_, err = sortByDependencies(map[uuid.UUID]jobqueue.JobRecord{
+		a: {ID: a, Dependencies: []uuid.UUID{b}},
+		b: {ID: b, Dependencies: []uuid.UUID{a}},
+	})
+	require.ErrorContains(t, err, "depends on itself")
+}
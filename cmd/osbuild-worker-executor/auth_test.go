@@ -0,0 +1,160 @@
+package main_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	main "github.com/ondrejbudai/osbuild-composer-public/cmd/osbuild-worker-executor"
+)
+
+type tokenTransport struct {
+	token string
+}
+
+func (tt *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+tt.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTokenRequired(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s3cret\n"), 0600))
+
+	client := &http.Client{Transport: &tokenTransport{"s3cret"}}
+	baseURL, _, _ := runTestServerWithArgs(t, "http", client, "-token-file", tokenFile)
+
+	rsp, err := client.Get(baseURL + "api/v1/log")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusTooEarly, rsp.StatusCode)
+
+	rsp, err = http.Get(baseURL + "api/v1/log")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, rsp.StatusCode)
+
+	wrongClient := &http.Client{Transport: &tokenTransport{"guess"}}
+	rsp, err = wrongClient.Post(baseURL+"api/v1/build", "application/x-tar", nil)
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, rsp.StatusCode)
+}
+
+type testCert struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certPath string
+	keyPath  string
+}
+
+// makeTestCert creates a certificate signed by the parent, or a self signed
+// CA if the parent is nil.
+func makeTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	tc := &testCert{
+		cert:     cert,
+		key:      key,
+		certPath: filepath.Join(dir, name+".pem"),
+		keyPath:  filepath.Join(dir, name+"-key.pem"),
+	}
+	require.NoError(t, os.WriteFile(tc.certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(tc.keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return tc
+}
+
+func TestClientCertificateRequired(t *testing.T) {
+	ca := makeTestCert(t, "ca", nil)
+	server := makeTestCert(t, "server", ca)
+	clientCert := makeTestCert(t, "client", ca)
+	otherCA := makeTestCert(t, "other-ca", nil)
+	otherClientCert := makeTestCert(t, "other-client", otherCA)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	newClient := func(c *testCert) *http.Client {
+		tlsConfig := &tls.Config{
+			RootCAs:    roots,
+			MinVersion: tls.VersionTLS12,
+		}
+		if c != nil {
+			pair, err := tls.LoadX509KeyPair(c.certPath, c.keyPath)
+			require.NoError(t, err)
+			tlsConfig.Certificates = []tls.Certificate{pair}
+		}
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	}
+
+	baseURL, _, _ := runTestServerWithArgs(t, "https", newClient(clientCert),
+		"-tls-cert", server.certPath,
+		"-tls-key", server.keyPath,
+		"-client-ca", ca.certPath,
+	)
+
+	rsp, err := newClient(clientCert).Get(baseURL + "api/v1/log")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusTooEarly, rsp.StatusCode)
+
+	_, err = newClient(nil).Get(baseURL + "api/v1/log")
+	assert.Error(t, err)
+
+	_, err = newClient(otherClientCert).Get(baseURL + "api/v1/log")
+	assert.Error(t, err)
+}
+
+func TestAuthConfigInvalid(t *testing.T) {
+	for _, args := range [][]string{
+		{"-tls-cert", "/etc/cert.pem"},
+		{"-tls-key", "/etc/key.pem"},
+		{"-client-ca", "/etc/ca.pem"},
+		{"-token-file", "/non-existing/token"},
+	} {
+		err := main.Run(context.Background(), args, os.Getenv, logrus.New())
+		assert.Error(t, err, args)
+	}
+}
@@ -10,10 +10,11 @@ type buildResult struct {
 	resultBad  string
 }
 
-func newBuildResult(config *Config) *buildResult {
+// newBuildResult returns the result of the build living in buildBase.
+func newBuildResult(buildBase string) *buildResult {
 	return &buildResult{
-		resultGood: filepath.Join(config.BuildDirBase, "result.good"),
-		resultBad:  filepath.Join(config.BuildDirBase, "result.bad"),
+		resultGood: filepath.Join(buildBase, "result.good"),
+		resultBad:  filepath.Join(buildBase, "result.bad"),
 	}
 }
 
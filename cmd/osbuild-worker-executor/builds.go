@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTooManyBuilds = errors.New("too many builds running")
+	ErrBuildNotFound = errors.New("build not found")
+)
+
+type runningBuild struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// builds keeps track of the builds of the executor. Every build lives in its
+// own directory under BuildDirBase, named after the build's ID. Finished
+// builds which are never deleted are removed once they are older than ttl.
+type builds struct {
+	baseDir string
+	max     int
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	running map[string]*runningBuild
+	// served by the routes which don't take a build ID
+	latest string
+}
+
+func newBuilds(config *Config) *builds {
+	return &builds{
+		baseDir: config.BuildDirBase,
+		max:     config.MaxBuilds,
+		ttl:     config.BuildTTL,
+		running: make(map[string]*runningBuild),
+	}
+}
+
+// start creates the directory of a new build and registers it as running
+// until finish is called. Canceling the parent or deleting the build cancels
+// the returned context.
+func (b *builds) start(parent context.Context) (id, dir string, ctx context.Context, finish func(), err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.running) >= b.max {
+		return "", "", nil, nil, ErrTooManyBuilds
+	}
+
+	if err := os.MkdirAll(b.baseDir, 0700); err != nil {
+		return "", "", nil, nil, fmt.Errorf("cannot create build base dir: %v", err)
+	}
+	if err := b.removeExpiredLocked(time.Now()); err != nil {
+		return "", "", nil, nil, fmt.Errorf("cannot remove expired builds: %v", err)
+	}
+	id = uuid.NewString()
+	dir = filepath.Join(b.baseDir, id)
+	if err := os.Mkdir(dir, 0700); err != nil {
+		return "", "", nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	rb := &runningBuild{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	b.running[id] = rb
+	b.latest = id
+
+	finish = func() {
+		cancel()
+		// the TTL counts from the end of the build
+		now := time.Now()
+		_ = os.Chtimes(dir, now, now)
+		b.mu.Lock()
+		delete(b.running, id)
+		b.mu.Unlock()
+		close(rb.done)
+	}
+	return id, dir, ctx, finish, nil
+}
+
+// dir returns the directory of the build, an empty ID means the build which
+// was started last.
+func (b *builds) dir(id string) (string, error) {
+	if id == "" {
+		b.mu.Lock()
+		id = b.latest
+		b.mu.Unlock()
+		if id == "" {
+			return "", ErrBuildNotFound
+		}
+	}
+
+	// the ID ends up in a path, only accept what start() generates
+	if _, err := uuid.Parse(id); err != nil {
+		return "", ErrBuildNotFound
+	}
+	dir := filepath.Join(b.baseDir, id)
+	if _, err := os.Stat(dir); err != nil {
+		return "", ErrBuildNotFound
+	}
+	return dir, nil
+}
+
+// fromRequest returns the directory of the build the request refers to.
+func (b *builds) fromRequest(r *http.Request) (string, error) {
+	return b.dir(r.PathValue("id"))
+}
+
+// remove stops the build if it's still running and removes its directory.
+func (b *builds) remove(id string) error {
+	dir, err := b.dir(id)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	rb := b.running[id]
+	b.mu.Unlock()
+	if rb != nil {
+		rb.cancel()
+		<-rb.done
+	}
+
+	b.mu.Lock()
+	if b.latest == id {
+		b.latest = ""
+	}
+	b.mu.Unlock()
+	return os.RemoveAll(dir)
+}
+
+// removeExpiredLocked removes the directories of the builds which aren't
+// running and weren't modified within the TTL, including the ones left behind
+// by a previous run of the executor. Needs b.mu to be held.
+func (b *builds) removeExpiredLocked(now time.Time) error {
+	if b.ttl == 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(b.baseDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		id := entry.Name()
+		if _, err := uuid.Parse(id); err != nil || !entry.IsDir() {
+			continue
+		}
+		if _, ok := b.running[id]; ok {
+			continue
+		}
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			// deleted in the meantime
+			continue
+		} else if err != nil {
+			return err
+		}
+		if now.Sub(info.ModTime()) < b.ttl {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(b.baseDir, id)); err != nil {
+			return err
+		}
+		if b.latest == id {
+			b.latest = ""
+		}
+	}
+	return nil
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -9,6 +14,20 @@ type Config struct {
 	Port string
 
 	BuildDirBase string
+	// number of builds which can run at the same time
+	MaxBuilds int
+	// finished builds which weren't deleted are removed after this long,
+	// zero keeps them until shutdown
+	BuildTTL time.Duration
+
+	// serve https instead of http if set
+	TLSCertFile string
+	TLSKeyFile  string
+	// require client certificates signed by this CA, needs https
+	ClientCAFile string
+	// require the token from this file as a bearer token in every request
+	TokenFile string
+	Token     string
 }
 
 func newConfigFromCmdline(args []string) (*Config, error) {
@@ -18,8 +37,37 @@ func newConfigFromCmdline(args []string) (*Config, error) {
 	fs.StringVar(&config.Host, "host", "localhost", "host to listen on")
 	fs.StringVar(&config.Port, "port", "8001", "port to listen on")
 	fs.StringVar(&config.BuildDirBase, "build-path", "/var/tmp/worker-executor", "base dir to run the builds in")
+	fs.IntVar(&config.MaxBuilds, "max-builds", 1, "number of builds which can run at the same time")
+	fs.DurationVar(&config.BuildTTL, "build-ttl", 24*time.Hour, "how long finished builds are kept if they aren't deleted, 0 keeps them")
+	fs.StringVar(&config.TLSCertFile, "tls-cert", "", "certificate to serve https with")
+	fs.StringVar(&config.TLSKeyFile, "tls-key", "", "key of the certificate to serve https with")
+	fs.StringVar(&config.ClientCAFile, "client-ca", "", "CA the clients' certificates need to be signed by")
+	fs.StringVar(&config.TokenFile, "token-file", "", "file with the bearer token the clients need to send")
 	if err := fs.Parse(args); err != nil {
 		return nil, err
 	}
+
+	if config.MaxBuilds < 1 {
+		return nil, fmt.Errorf("-max-builds needs to be at least 1, got %d", config.MaxBuilds)
+	}
+	if config.BuildTTL < 0 {
+		return nil, fmt.Errorf("-build-ttl cannot be negative, got %v", config.BuildTTL)
+	}
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		return nil, errors.New("-tls-cert and -tls-key need to be used together")
+	}
+	if config.ClientCAFile != "" && config.TLSCertFile == "" {
+		return nil, errors.New("-client-ca needs -tls-cert and -tls-key")
+	}
+	if config.TokenFile != "" {
+		token, err := os.ReadFile(config.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read token file: %w", err)
+		}
+		config.Token = strings.TrimSpace(string(token))
+		if config.Token == "" {
+			return nil, fmt.Errorf("token file %s is empty", config.TokenFile)
+		}
+	}
 	return &config, nil
 }
@@ -2,8 +2,8 @@ package main
 
 import (
 	"archive/tar"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"syscall"
 
 	"golang.org/x/exp/slices"
 
@@ -24,10 +25,6 @@ var (
 	supportedBuildContentTypes = []string{"application/x-tar"}
 )
 
-var (
-	ErrAlreadyBuilding = errors.New("build already starte")
-)
-
 type writeFlusher struct {
 	w       io.Writer
 	flusher http.Flusher
@@ -41,7 +38,7 @@ func (wf *writeFlusher) Write(p []byte) (n int, err error) {
 	return n, err
 }
 
-func runOSBuild(logger *logrus.Logger, buildDir string, control *controlJSON, output io.Writer) (string, error) {
+func runOSBuild(ctx context.Context, logger *logrus.Logger, buildDir string, control *controlJSON, output io.Writer) (string, error) {
 	manifest, err := os.ReadFile(filepath.Join(buildDir, "manifest.json"))
 	if err != nil {
 		return "", fmt.Errorf("cannot read manifest file: %w", err)
@@ -77,21 +74,36 @@ func runOSBuild(logger *logrus.Logger, buildDir string, control *controlJSON, ou
 		Monitor:     osbuild.MonitorJSONSeq,
 		MonitorFile: wPipe,
 	})
+	// run osbuild in its own process group so that stopping the build
+	// also stops all the stages it spawned
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Dir = buildDir
 	if err := cmd.Start(); err != nil {
 		return "", err
 	}
 	wPipe.Close()
 
+	done := make(chan struct{})
+	defer close(done)
+	go osbuildexecutor.StopOnCancel(ctx, done, cmd.Process.Pid, logger)
+
 	flusher, ok := output.(http.Flusher)
 	if !ok {
 		return "", fmt.Errorf("cannot stream the output, output needs to be http.Flusher")
 	}
 	wf := writeFlusher{w: output, flusher: flusher}
 	if _, err := io.Copy(&wf, rPipe); err != nil && err != io.EOF {
+		// nobody is going to read the output, don't leave osbuild
+		// running
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		_ = cmd.Wait()
 		return "", err
 	}
 
 	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("osbuild was stopped: %w", ctx.Err())
+		}
 		// we cannot use "http.Error()" here because the http
 		// header was already set to "201" when we started streaming
 		_, _ = wf.Write([]byte(fmt.Sprintf("cannot run osbuild: %v", err)))
@@ -166,27 +178,6 @@ func handleControlJSON(atar *tar.Reader) (*controlJSON, error) {
 	return &control, nil
 }
 
-func createBuildDir(config *Config) (string, error) {
-	buildDirBase := config.BuildDirBase
-
-	// we could create a per-build dir here but the goal is to
-	// only have a single build only so we don't bother
-	if err := os.MkdirAll(buildDirBase, 0700); err != nil {
-		return "", fmt.Errorf("cannot create build base dir: %v", err)
-	}
-
-	// ensure there is only a single build
-	buildDir := filepath.Join(buildDirBase, "build")
-	if err := os.Mkdir(buildDir, 0700); err != nil {
-		if os.IsExist(err) {
-			return "", ErrAlreadyBuilding
-		}
-		return "", err
-	}
-
-	return buildDir, nil
-}
-
 func handleManifestJSON(atar *tar.Reader, buildDir string) error {
 	if err := mustRead(atar, "manifest.json"); err != nil {
 		return err
@@ -270,7 +261,7 @@ func handleIncludedSources(atar *tar.Reader, buildDir string) error {
 
 // test for real via:
 // curl -o - --data-binary "@./test.tar" -H "Content-Type: application/x-tar"  -X POST http://localhost:8001/api/v1/build
-func handleBuild(logger *logrus.Logger, config *Config) http.Handler {
+func handleBuild(logger *logrus.Logger, builds *builds) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			logger.Debugf("handlerBuild called on %s", r.URL.Path)
@@ -296,35 +287,58 @@ func handleBuild(logger *logrus.Logger, config *Config) http.Handler {
 				return
 			}
 
-			buildDir, err := createBuildDir(config)
+			// the build is stopped when the client goes away, nobody
+			// would know about its result
+			id, buildBase, ctx, finish, err := builds.start(r.Context())
 			if err != nil {
 				logger.Error(err)
-				if err == ErrAlreadyBuilding {
-					http.Error(w, "build already started", http.StatusConflict)
+				if err == ErrTooManyBuilds {
+					http.Error(w, err.Error(), http.StatusServiceUnavailable)
 				} else {
 					http.Error(w, "create build dir", http.StatusBadRequest)
 				}
 				return
 			}
+			defer finish()
+
+			buildDir := filepath.Join(buildBase, "build")
+			if err := os.Mkdir(buildDir, 0700); err != nil {
+				logger.Error(err)
+				http.Error(w, "create build dir", http.StatusBadRequest)
+				return
+			}
+			// don't keep builds which never started around
+			abort := func(msg string) {
+				http.Error(w, msg, http.StatusBadRequest)
+				if err := os.RemoveAll(buildBase); err != nil {
+					logger.Errorf("cannot remove build dir: %v", err)
+				}
+			}
 
 			// manifest.json is the osbuild input
 			if err := handleManifestJSON(atar, buildDir); err != nil {
 				logger.Error(err)
-				http.Error(w, "manifest.json", http.StatusBadRequest)
+				abort("manifest.json")
 				return
 			}
 			// extract ".osbuild/sources" here too from the tar
 			if err := handleIncludedSources(atar, buildDir); err != nil {
 				logger.Error(err)
-				http.Error(w, "included sources/", http.StatusBadRequest)
+				abort("included sources/")
 				return
 			}
 
+			logger.Infof("build %s started", id)
+			w.Header().Set("Location", "/api/v1/build/"+id)
 			w.WriteHeader(http.StatusCreated)
+			// let the client know about the build right away
+			if flusher, ok := w.(http.Flusher); ok {
+				flusher.Flush()
+			}
 
 			// run osbuild and stream the output to the client
-			buildResult := newBuildResult(config)
-			_, err = runOSBuild(logger, buildDir, control, w)
+			buildResult := newBuildResult(buildBase)
+			_, err = runOSBuild(ctx, logger, buildDir, control, w)
 			if werr := buildResult.Mark(err); werr != nil {
 				logger.Errorf("cannot write result file %v", werr)
 			}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	main "github.com/ondrejbudai/osbuild-composer-public/cmd/osbuild-worker-executor"
 )
@@ -82,10 +83,10 @@ func makeTestPost(t *testing.T, controlJSON, manifestJSON string) *bytes.Buffer
 }
 
 func TestBuildIntegration(t *testing.T) {
-	baseURL, baseBuildDir, loggerHook := runTestServer(t)
+	baseURL, buildDirBase, loggerHook := runTestServer(t)
 	endpoint := baseURL + "api/v1/build"
 
-	restore := main.MockOsbuildBinary(t, fmt.Sprintf(`#!/bin/sh -e
+	restore := main.MockOsbuildBinary(t, `#!/bin/sh -e
 # make sure the monitor is setup correctly
 >&3 echo '^^{"message": "osbuild-stage-message 1"}'
 >&3 echo '^^{"message": "osbuild-stage-message 2"}'
@@ -101,9 +102,9 @@ echo
 test "$MY" = "env"
 
 # simulate output
-mkdir -p %[1]s/build/output/image
-echo "fake-build-result" > %[1]s/build/output/image/disk.img
-`, baseBuildDir))
+mkdir -p output/image
+echo "fake-build-result" > output/image/disk.img
+`)
 	defer restore()
 
 	buf := makeTestPost(t, `{"exports": ["tree"], "environments": ["MY=env"]}`, `{"fake": "manifest"}`)
@@ -112,6 +113,9 @@ echo "fake-build-result" > %[1]s/build/output/image/disk.img
 	defer func() { _, _ = io.ReadAll(rsp.Body) }()
 	defer rsp.Body.Close()
 	assert.Equal(t, http.StatusCreated, rsp.StatusCode)
+	buildPath := rsp.Header.Get("Location")
+	require.True(t, strings.HasPrefix(buildPath, "/api/v1/build/"), buildPath)
+	baseBuildDir := filepath.Join(buildDirBase, strings.TrimPrefix(buildPath, "/api/v1/build/"))
 
 	// check that we get the monitor output of osbuild streamed to us
 	expectedMonitorContent := `^^{"message": "osbuild-stage-message 1"}
@@ -141,23 +145,26 @@ echo "fake-build-result" > %[1]s/build/output/image/disk.img
 		assert.NotContains(t, entry.Message, "unexpected tar output")
 	}
 
-	// now get the result
-	endpoint = baseURL + "api/v1/result/image/disk.img"
-	rsp, err = http.Get(endpoint)
-	assert.NoError(t, err)
-	defer rsp.Body.Close()
-	assert.Equal(t, http.StatusOK, rsp.StatusCode)
-	body, err := io.ReadAll(rsp.Body)
-	assert.NoError(t, err)
-	assert.Equal(t, "fake-build-result\n", string(body))
+	// now get the result, the routes without a build ID serve the last
+	// build
+	for _, path := range []string{buildPath + "/result/", "/api/v1/result/"} {
+		endpoint = strings.TrimSuffix(baseURL, "/") + path + "image/disk.img"
+		rsp, err = http.Get(endpoint)
+		assert.NoError(t, err)
+		defer rsp.Body.Close()
+		assert.Equal(t, http.StatusOK, rsp.StatusCode)
+		body, err := io.ReadAll(rsp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "fake-build-result\n", string(body))
+	}
 
 	// check that the output tarball has the disk in it
-	endpoint = baseURL + "api/v1/result/output.tar"
+	endpoint = strings.TrimSuffix(baseURL, "/") + buildPath + "/result/output.tar"
 	rsp, err = http.Get(endpoint)
 	assert.NoError(t, err)
 	defer rsp.Body.Close()
 	assert.Equal(t, http.StatusOK, rsp.StatusCode)
-	body, err = io.ReadAll(rsp.Body)
+	body, err := io.ReadAll(rsp.Body)
 	assert.NoError(t, err)
 	tarPath := filepath.Join(baseBuildDir, "output.tar")
 	assert.NoError(t, os.WriteFile(tarPath, body, 0644))
@@ -173,30 +180,130 @@ echo "fake-build-result" > %[1]s/build/output/image/disk.img
 	assert.ElementsMatch(t, expected, actual)
 }
 
-func TestBuildErrorsForMultipleBuilds(t *testing.T) {
-	baseURL, buildDir, loggerHook := runTestServer(t)
-	endpoint := baseURL + "api/v1/build"
+// startSleepingBuild starts a build which only finishes when it's stopped
+// and returns its path on the server.
+func startSleepingBuild(t *testing.T, baseURL string) (string, *http.Response) {
+	buf := makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+	rsp, err := http.Post(baseURL+"api/v1/build", "application/x-tar", buf)
+	require.NoError(t, err)
+	t.Cleanup(func() { rsp.Body.Close() })
+	require.Equal(t, http.StatusCreated, rsp.StatusCode)
+	return rsp.Header.Get("Location"), rsp
+}
+
+func TestBuildErrorsForTooManyBuilds(t *testing.T) {
+	for _, maxBuilds := range []int{1, 2} {
+		t.Run(fmt.Sprintf("max-%d", maxBuilds), func(t *testing.T) {
+			baseURL, _, loggerHook := runTestServerWithArgs(t, "http", &http.Client{}, "-max-builds", fmt.Sprintf("%d", maxBuilds))
+
+			restore := main.MockOsbuildBinary(t, `#!/bin/sh
+sleep 60
+`)
+			defer restore()
+
+			builds := map[string]bool{}
+			for i := 0; i < maxBuilds; i++ {
+				path, _ := startSleepingBuild(t, baseURL)
+				builds[path] = true
+			}
+			assert.Len(t, builds, maxBuilds)
+
+			buf := makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
+			rsp, err := http.Post(baseURL+"api/v1/build", "application/x-tar", buf)
+			require.NoError(t, err)
+			defer rsp.Body.Close()
+			assert.Equal(t, http.StatusServiceUnavailable, rsp.StatusCode)
+			assert.Equal(t, main.ErrTooManyBuilds.Error(), loggerHook.LastEntry().Message)
+
+			for path := range builds {
+				req, err := http.NewRequest(http.MethodDelete, strings.TrimSuffix(baseURL, "/")+path, nil)
+				require.NoError(t, err)
+				rsp, err := http.DefaultClient.Do(req)
+				require.NoError(t, err)
+				rsp.Body.Close()
+				assert.Equal(t, http.StatusOK, rsp.StatusCode)
+			}
+		})
+	}
+}
 
-	restore := main.MockOsbuildBinary(t, fmt.Sprintf(`#!/bin/sh
+func TestBuildDelete(t *testing.T) {
+	baseURL, buildDirBase, _ := runTestServer(t)
 
-mkdir -p %[1]s/build/output/image
-echo "fake-build-result" > %[1]s/build/output/image/disk.img
-`, buildDir))
+	restore := main.MockOsbuildBinary(t, `#!/bin/sh
+>&3 echo started
+sleep 60
+`)
 	defer restore()
 
-	buf := makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
-	rsp, err := http.Post(endpoint, "application/x-tar", buf)
-	assert.NoError(t, err)
-	assert.Equal(t, http.StatusCreated, rsp.StatusCode)
-	defer func() { _, _ = io.ReadAll(rsp.Body) }()
+	path, buildRsp := startSleepingBuild(t, baseURL)
+	line, err := bufio.NewReader(buildRsp.Body).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "started\n", line)
+	buildDir := filepath.Join(buildDirBase, strings.TrimPrefix(path, "/api/v1/build/"))
+	assert.DirExists(t, buildDir)
+
+	start := time.Now()
+	req, err := http.NewRequest(http.MethodDelete, strings.TrimSuffix(baseURL, "/")+path, nil)
+	require.NoError(t, err)
+	rsp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
 	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+	// osbuild was killed, not waited for
+	assert.Less(t, time.Since(start), 30*time.Second)
+	assert.NoDirExists(t, buildDir)
 
-	buf = makeTestPost(t, `{"exports": ["tree"]}`, `{"fake": "manifest"}`)
-	rsp, err = http.Post(endpoint, "application/x-tar", buf)
-	assert.NoError(t, err)
+	// the build is gone
+	rsp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
 	defer rsp.Body.Close()
-	assert.Equal(t, http.StatusConflict, rsp.StatusCode)
-	assert.Equal(t, main.ErrAlreadyBuilding.Error(), loggerHook.LastEntry().Message)
+	assert.Equal(t, http.StatusNotFound, rsp.StatusCode)
+	rsp, err = http.Get(strings.TrimSuffix(baseURL, "/") + path + "/log")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, rsp.StatusCode)
+}
+
+func TestBuildDeleteUnknown(t *testing.T) {
+	baseURL, _, _ := runTestServer(t)
+
+	for _, id := range []string{"6a1a4e3a-1f4c-4bd3-b1f4-a0c2b1b2d5e2", "not-a-uuid"} {
+		req, err := http.NewRequest(http.MethodDelete, baseURL+"api/v1/build/"+id, nil)
+		require.NoError(t, err)
+		rsp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer rsp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, rsp.StatusCode, id)
+	}
+}
+
+func TestBuildRemovesExpiredBuilds(t *testing.T) {
+	baseURL, buildDirBase, _ := runTestServerWithArgs(t, "http", &http.Client{}, "-build-ttl", "1h")
+
+	restore := main.MockOsbuildBinary(t, `#!/bin/sh
+>&3 echo started
+`)
+	defer restore()
+
+	// left behind by a build which was never deleted
+	expired := filepath.Join(buildDirBase, "0f0b6c0e-3f7e-4a4f-9a33-4a2bb7d0a6c1")
+	require.NoError(t, os.MkdirAll(filepath.Join(expired, "build"), 0700))
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(expired, old, old))
+	recent := filepath.Join(buildDirBase, "6a1a4e3a-1f4c-4bd3-b1f4-a0c2b1b2d5e2")
+	require.NoError(t, os.Mkdir(recent, 0700))
+	notBuild := filepath.Join(buildDirBase, "not-a-build")
+	require.NoError(t, os.Mkdir(notBuild, 0700))
+	require.NoError(t, os.Chtimes(notBuild, old, old))
+
+	_, rsp := startSleepingBuild(t, baseURL)
+	_, err := io.ReadAll(rsp.Body)
+	require.NoError(t, err)
+
+	assert.NoDirExists(t, expired)
+	assert.DirExists(t, recent)
+	assert.DirExists(t, notBuild)
 }
 
 func TestHandleIncludedSourcesUnclean(t *testing.T) {
@@ -280,7 +387,7 @@ err on stdout
 }
 
 func TestBuildStreamsOutput(t *testing.T) {
-	baseURL, baseBuildDir, _ := runTestServer(t)
+	baseURL, _, _ := runTestServer(t)
 	endpoint := baseURL + "api/v1/build"
 
 	restore := main.MockOsbuildBinary(t, fmt.Sprintf(`#!/bin/sh -e
@@ -291,9 +398,9 @@ for i in $(seq 3); do
 done
 
 # simulate output
-mkdir -p %[1]s/build/output/image
-echo "fake-build-result" > %[1]s/build/output/image/disk.img
-`, baseBuildDir))
+mkdir -p output/image
+echo "fake-build-result" > output/image/disk.img
+`))
 	defer restore()
 
 	buf := makeTestPost(t, `{"exports": ["tree"], "environments": ["MY=env"]}`, `{"fake": "manifest"}`)
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+func handleDelete(logger *logrus.Logger, builds *builds) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			logger.Debugf("handlerDelete called on %s", r.URL.Path)
+
+			id := r.PathValue("id")
+			err := builds.remove(id)
+			if err == ErrBuildNotFound {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				logger.Errorf("cannot remove build %s: %v", id, err)
+				http.Error(w, "cannot remove build", http.StatusInternalServerError)
+				return
+			}
+			logger.Infof("build %s removed", id)
+			w.WriteHeader(http.StatusOK)
+		},
+	)
+}
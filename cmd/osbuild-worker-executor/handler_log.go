@@ -10,7 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func handleLog(logger *logrus.Logger, config *Config) http.Handler {
+func handleLog(logger *logrus.Logger, builds *builds) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			logger.Debugf("handlerLog called on %s", r.URL.Path)
@@ -19,13 +19,18 @@ func handleLog(logger *logrus.Logger, config *Config) http.Handler {
 				return
 			}
 
+			buildBase, ok := buildFromRequest(w, r, builds)
+			if !ok {
+				return
+			}
+
 			var f *os.File
 			var err error
-			buildResult := newBuildResult(config)
+			buildResult := newBuildResult(buildBase)
 			switch {
 			case buildResult.Bad():
 				// result will not have been moved to output directory
-				f, err = os.Open(filepath.Join(config.BuildDirBase, "build/osbuild-result.json"))
+				f, err = os.Open(filepath.Join(buildBase, "build/osbuild-result.json"))
 				if err != nil {
 					logger.Errorf("cannot open log: %v", err)
 					http.Error(w, fmt.Sprintf("unable to read log: %v", err), http.StatusInternalServerError)
@@ -33,7 +38,7 @@ func handleLog(logger *logrus.Logger, config *Config) http.Handler {
 				}
 				defer f.Close()
 			case buildResult.Good():
-				f, err = os.Open(filepath.Join(config.BuildDirBase, "build/output/osbuild-result.json"))
+				f, err = os.Open(filepath.Join(buildBase, "build/output/osbuild-result.json"))
 				if err != nil {
 					logger.Errorf("cannot open log: %v", err)
 					http.Error(w, fmt.Sprintf("unable to read log: %v", err), http.StatusInternalServerError)
@@ -50,3 +55,20 @@ func handleLog(logger *logrus.Logger, config *Config) http.Handler {
 		},
 	)
 }
+
+// buildFromRequest returns the directory of the build the request refers to
+// and writes the error response if there is no such build. The routes
+// without a build ID report the last build, before any build was started
+// they behave as if it was still running.
+func buildFromRequest(w http.ResponseWriter, r *http.Request, builds *builds) (string, bool) {
+	buildBase, err := builds.fromRequest(r)
+	switch {
+	case err == nil:
+		return buildBase, true
+	case r.PathValue("id") == "":
+		http.Error(w, "build still running", http.StatusTooEarly)
+	default:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	}
+	return "", false
+}
@@ -7,10 +7,15 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
-func simulateBuildResult(t *testing.T, buildBaseDir, result, log string) {
+// simulateBuildResult creates a finished build in buildDirBase and returns
+// its ID and directory.
+func simulateBuildResult(t *testing.T, buildDirBase, result, log string) (id, buildBaseDir string) {
+	id = uuid.NewString()
+	buildBaseDir = filepath.Join(buildDirBase, id)
 	err := os.MkdirAll(filepath.Join(buildBaseDir, "build"), 0755)
 	assert.NoError(t, err)
 	err = os.WriteFile(filepath.Join(buildBaseDir, result), nil, 0644)
@@ -24,6 +29,7 @@ func simulateBuildResult(t *testing.T, buildBaseDir, result, log string) {
 	}
 	err = os.WriteFile(path, []byte(log), 0644)
 	assert.NoError(t, err)
+	return id, buildBaseDir
 }
 
 func TestLogTooEarly(t *testing.T) {
@@ -36,10 +42,10 @@ func TestLogTooEarly(t *testing.T) {
 }
 
 func TestLogBad(t *testing.T) {
-	baseURL, buildBaseDir, _ := runTestServer(t)
-	endpoint := baseURL + "api/v1/log"
+	baseURL, buildDirBase, _ := runTestServer(t)
 
-	simulateBuildResult(t, buildBaseDir, "result.bad", "failure log")
+	id, _ := simulateBuildResult(t, buildDirBase, "result.bad", "failure log")
+	endpoint := baseURL + "api/v1/build/" + id + "/log"
 	rsp, err := http.Get(endpoint)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rsp.StatusCode)
@@ -49,10 +55,10 @@ func TestLogBad(t *testing.T) {
 }
 
 func TestLogGood(t *testing.T) {
-	baseURL, buildBaseDir, _ := runTestServer(t)
-	endpoint := baseURL + "api/v1/log"
+	baseURL, buildDirBase, _ := runTestServer(t)
 
-	simulateBuildResult(t, buildBaseDir, "result.good", "fake-build-result")
+	id, _ := simulateBuildResult(t, buildDirBase, "result.good", "fake-build-result")
+	endpoint := baseURL + "api/v1/build/" + id + "/log"
 	rsp, err := http.Get(endpoint)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, rsp.StatusCode)
@@ -60,3 +66,12 @@ func TestLogGood(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "fake-build-result", string(body))
 }
+
+func TestLogUnknownBuild(t *testing.T) {
+	baseURL, _, _ := runTestServer(t)
+	endpoint := baseURL + "api/v1/build/" + uuid.NewString() + "/log"
+
+	rsp, err := http.Get(endpoint)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rsp.StatusCode)
+}
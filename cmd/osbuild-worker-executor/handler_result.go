@@ -9,7 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func handleResult(logger *logrus.Logger, config *Config) http.Handler {
+func handleResult(logger *logrus.Logger, builds *builds) http.Handler {
 	return http.HandlerFunc(
 		func(w http.ResponseWriter, r *http.Request) {
 			logger.Debugf("handlerResult called on %s", r.URL.Path)
@@ -17,11 +17,15 @@ func handleResult(logger *logrus.Logger, config *Config) http.Handler {
 				http.Error(w, "result endpoint only supports Get", http.StatusMethodNotAllowed)
 				return
 			}
-			buildResult := newBuildResult(config)
+			buildBase, ok := buildFromRequest(w, r, builds)
+			if !ok {
+				return
+			}
+			buildResult := newBuildResult(buildBase)
 			switch {
 			case buildResult.Bad():
 				http.Error(w, "build failed", http.StatusBadRequest)
-				f, err := os.Open(filepath.Join(config.BuildDirBase, "build/osbuild-result.json"))
+				f, err := os.Open(filepath.Join(buildBase, "build/osbuild-result.json"))
 				if err != nil {
 					logger.Errorf("cannot open log: %v", err)
 					return
@@ -38,7 +42,10 @@ func handleResult(logger *logrus.Logger, config *Config) http.Handler {
 				return
 			}
 
-			fss := http.FileServer(http.Dir(filepath.Join(config.BuildDirBase, "build/output")))
+			var fss http.Handler = http.FileServer(http.Dir(filepath.Join(buildBase, "build/output")))
+			if id := r.PathValue("id"); id != "" {
+				fss = http.StripPrefix("/api/v1/build/"+id+"/result/", fss)
+			}
 			fss.ServeHTTP(w, r)
 		},
 	)
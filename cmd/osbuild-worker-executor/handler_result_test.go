@@ -20,10 +20,10 @@ func TestResultTooEarly(t *testing.T) {
 }
 
 func TestResultBad(t *testing.T) {
-	baseURL, buildBaseDir, _ := runTestServer(t)
-	endpoint := baseURL + "api/v1/result/disk.img"
+	baseURL, buildDirBase, _ := runTestServer(t)
 
-	simulateBuildResult(t, buildBaseDir, "result.bad", "failure log")
+	id, _ := simulateBuildResult(t, buildDirBase, "result.bad", "failure log")
+	endpoint := baseURL + "api/v1/build/" + id + "/result/disk.img"
 	rsp, err := http.Get(endpoint)
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusBadRequest, rsp.StatusCode)
@@ -33,10 +33,10 @@ func TestResultBad(t *testing.T) {
 }
 
 func TestResultGood(t *testing.T) {
-	baseURL, buildBaseDir, _ := runTestServer(t)
-	endpoint := baseURL + "api/v1/result/disk.img"
+	baseURL, buildDirBase, _ := runTestServer(t)
 
-	simulateBuildResult(t, buildBaseDir, "result.good", "fake-build-log")
+	id, buildBaseDir := simulateBuildResult(t, buildDirBase, "result.good", "fake-build-log")
+	endpoint := baseURL + "api/v1/build/" + id + "/result/disk.img"
 	err := os.WriteFile(filepath.Join(buildBaseDir, "build/output/disk.img"), []byte("fake-build-result"), 0644)
 	assert.NoError(t, err)
 
@@ -22,6 +22,7 @@ func newServer(logger *logrus.Logger, config *Config) http.Handler {
 	var handler http.Handler = mux
 	// todo: consider centralize logginer here?
 	//handler = loggingMiddleware(handler)
+	handler = requireToken(logger, config.Token, handler)
 	return handler
 }
 
@@ -40,9 +41,21 @@ func run(ctx context.Context, args []string, getenv func(string) string, logger
 		Handler:           srv,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
+	if config.TLSCertFile != "" {
+		httpServer.TLSConfig, err = newTLSConfig(config)
+		if err != nil {
+			return err
+		}
+	}
 	go func() {
 		logger.Printf("listening on %s\n", httpServer.Addr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if config.TLSCertFile != "" {
+			err = httpServer.ListenAndServeTLS(config.TLSCertFile, config.TLSKeyFile)
+		} else {
+			err = httpServer.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			fmt.Fprintf(os.Stderr, "error listening and serving: %s\n", err)
 		}
 	}()
@@ -19,8 +19,7 @@ import (
 
 const defaultTimeout = 5 * time.Second
 
-func waitReady(ctx context.Context, timeout time.Duration, endpoint string) error {
-	client := http.Client{}
+func waitReady(ctx context.Context, client *http.Client, timeout time.Duration, endpoint string) error {
 	startTime := time.Now()
 	for {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
@@ -65,12 +64,18 @@ func getFreePort() (int, error) {
 }
 
 func runTestServer(t *testing.T) (baseURL, buildBaseDir string, loggerHook *logrusTest.Hook) {
+	return runTestServerWithArgs(t, "http", &http.Client{})
+}
+
+// runTestServerWithArgs starts the server with additional command line
+// arguments, the client is used to wait until it's ready.
+func runTestServerWithArgs(t *testing.T, scheme string, client *http.Client, extraArgs ...string) (baseURL, buildBaseDir string, loggerHook *logrusTest.Hook) {
 	host := "localhost"
 	port, err := getFreePort()
 	assert.NoError(t, err, "failed to find a free port on localhost")
 
 	buildBaseDir = t.TempDir()
-	baseURL = fmt.Sprintf("http://%s:%d/", host, port)
+	baseURL = fmt.Sprintf("%s://%s:%d/", scheme, host, port)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	t.Cleanup(cancel)
@@ -83,11 +88,12 @@ func runTestServer(t *testing.T) (baseURL, buildBaseDir string, loggerHook *logr
 		"-port", strconv.Itoa(port),
 		"-build-path", buildBaseDir,
 	}
+	args = append(args, extraArgs...)
 	go func() {
 		_ = main.Run(ctx, args, os.Getenv, logger)
 	}()
 
-	err = waitReady(ctx, defaultTimeout, baseURL)
+	err = waitReady(ctx, client, defaultTimeout, baseURL)
 	assert.NoError(t, err)
 
 	return baseURL, buildBaseDir, loggerHook
@@ -7,8 +7,13 @@ import (
 )
 
 func addRoutes(mux *http.ServeMux, logger *logrus.Logger, config *Config) {
-	mux.Handle("/api/v1/build", handleBuild(logger, config))
-	mux.Handle("/api/v1/result/", http.StripPrefix("/api/v1/result/", handleResult(logger, config)))
-	mux.Handle("/api/v1/log", http.StripPrefix("/api/v1/log", handleLog(logger, config)))
+	builds := newBuilds(config)
+	mux.Handle("/api/v1/build", handleBuild(logger, builds))
+	mux.Handle("DELETE /api/v1/build/{id}", handleDelete(logger, builds))
+	mux.Handle("GET /api/v1/build/{id}/log", handleLog(logger, builds))
+	mux.Handle("GET /api/v1/build/{id}/result/", handleResult(logger, builds))
+	// the routes without a build ID refer to the last build
+	mux.Handle("/api/v1/result/", http.StripPrefix("/api/v1/result/", handleResult(logger, builds)))
+	mux.Handle("/api/v1/log", http.StripPrefix("/api/v1/log", handleLog(logger, builds)))
 	mux.Handle("/", handleRoot(logger, config))
 }
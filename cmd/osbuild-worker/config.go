@@ -7,6 +7,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/osbuild/image-builder/pkg/cloud/azure"
+	"github.com/osbuild/image-builder/pkg/datasizes"
 	"github.com/sirupsen/logrus"
 )
 
@@ -44,6 +45,33 @@ type ociConfig struct {
 	Credentials string `toml:"credentials"`
 }
 
+type vmwareConfig struct {
+	// TOML file with the host, username and password of the vCenter
+	Credentials string `toml:"credentials"`
+}
+
+type sftpConfig struct {
+	// default private key and user name of the targets which don't refer
+	// to a key
+	PrivateKey string `toml:"private_key"`
+	Username   string `toml:"username"`
+	// known_hosts file with the host keys of the file servers
+	KnownHosts string `toml:"known_hosts"`
+	// keys the targets can refer to by name
+	Keys map[string]sftpKeyConfig `toml:"keys"`
+}
+
+type sftpKeyConfig struct {
+	PrivateKey string `toml:"private_key"`
+	Username   string `toml:"username"`
+}
+
+type pulpConfig struct {
+	// JSON file with the username and password
+	Credentials   string `toml:"credentials"`
+	ServerAddress string `toml:"server_address"`
+}
+
 type genericS3Config struct {
 	Credentials         string `toml:"credentials"`
 	Endpoint            string `toml:"endpoint"`
@@ -51,6 +79,18 @@ type genericS3Config struct {
 	Bucket              string `toml:"bucket"`
 	CABundle            string `toml:"ca_bundle"`
 	SkipSSLVerification bool   `toml:"skip_ssl_verification"`
+	// credentials the compose requests of the cloud API can refer to by
+	// name for uploading to a bucket of their choice
+	CredentialsRefs map[string]genericS3CredentialsRef `toml:"credentials_refs"`
+}
+
+type genericS3CredentialsRef struct {
+	// AWS credentials file
+	Credentials string `toml:"credentials"`
+	// the only endpoint the credentials are sent to
+	Endpoint            string `toml:"endpoint"`
+	CABundle            string `toml:"ca_bundle"`
+	SkipSSLVerification bool   `toml:"skip_ssl_verification"`
 }
 
 type authenticationConfig struct {
@@ -72,6 +112,25 @@ type executorConfig struct {
 	Type       string `toml:"type"`
 	IAMProfile string `toml:"iam_profile"`
 	KeyName    string `toml:"key_name"`
+	// remote: URLs of pre-provisioned osbuild-worker-executor hosts, each
+	// entry runs one build at a time. List a host several times to run
+	// several builds on it at once.
+	Hosts []string `toml:"hosts"`
+	// remote and qemu.kvm: CA certificate and optional client certificate
+	// for executors serving https
+	CACertFile     string `toml:"ca_cert"`
+	ClientCertFile string `toml:"client_cert"`
+	ClientKeyFile  string `toml:"client_key"`
+	// remote and qemu.kvm: file with a bearer token sent to the executors
+	TokenFile string `toml:"token_file"`
+	// qemu.kvm: qcow2 image of the guest running osbuild-worker-executor,
+	// its memory in MiB, number of CPUs, an optional qemu binary and the
+	// UEFI firmware of aarch64, arm and riscv64 guests if it isn't edk2's
+	Image      string `toml:"image"`
+	Memory     int    `toml:"memory"`
+	CPUs       int    `toml:"cpus"`
+	QEMUBinary string `toml:"qemu_binary"`
+	Firmware   string `toml:"firmware"`
 }
 
 type repositoryMTLSConfig struct {
@@ -90,6 +149,23 @@ type bootcInfoResolveConfig struct {
 	CleanupImages bool `toml:"cleanup_images"`
 }
 
+// Number of jobs of each class the worker runs at the same time. Every slot
+// requests and runs one job at a time.
+type slotsConfig struct {
+	// osbuild, koji-init, koji-finalize, aws-ec2-copy, aws-ec2-share and
+	// bootc-info-resolve jobs
+	Build int `toml:"build"`
+	// depsolve, search-packages, image-builder-manifest, container-resolve,
+	// ostree-resolve and file-resolve jobs
+	Light int `toml:"light"`
+}
+
+type storeCacheConfig struct {
+	// upper bound of the store, e.g. "50 GiB"; osbuild's own cache of
+	// pipeline objects is bounded by it too
+	MaxSize datasizes.Size `toml:"max_size"`
+}
+
 type workerConfig struct {
 	Composer       *composerConfig             `toml:"composer"`
 	Koji           map[string]kojiServerConfig `toml:"koji"`
@@ -100,6 +176,9 @@ type workerConfig struct {
 	Authentication *authenticationConfig       `toml:"authentication"`
 	Containers     *containersConfig           `toml:"containers"`
 	OCI            *ociConfig                  `toml:"oci"`
+	Pulp           *pulpConfig                 `toml:"pulp"`
+	VMWare         *vmwareConfig               `toml:"vmware"`
+	SFTP           *sftpConfig                 `toml:"sftp"`
 	// default value: /api/worker/v1
 	BasePath string `toml:"base_path"`
 	DNFJson  string `toml:"dnf-json"`
@@ -111,6 +190,17 @@ type workerConfig struct {
 	DeploymentChannel string `toml:"deployment_channel"`
 	// clean store between runs, this should only be used with workers running on AWS within an ASG
 	CleanStore bool `toml:"clean_store"`
+	// share one size-bounded store between all build slots and keep it
+	// between jobs, can't be used together with clean_store
+	StoreCache *storeCacheConfig `toml:"store_cache"`
+	// capability labels the worker registers with, e.g. "nested-virt",
+	// only jobs which require a subset of them are run by the worker
+	Labels []string `toml:"labels"`
+	// default value: &{ Build: 1, Light: 1 }
+	Slots *slotsConfig `toml:"slots"`
+	// how long a draining worker lets running jobs finish before handing
+	// them back to osbuild-composer, e.g. "30m"; 0 hands them back right away
+	DrainTimeout time.Duration `toml:"drain_timeout"`
 }
 
 func parseConfig(file string) (*workerConfig, error) {
@@ -121,6 +211,10 @@ func parseConfig(file string) (*workerConfig, error) {
 			Type: "host",
 		},
 		DeploymentChannel: "local",
+		Slots: &slotsConfig{
+			Build: 1,
+			Light: 1,
+		},
 	}
 
 	_, err := toml.DecodeFile(file, &config)
@@ -143,11 +237,58 @@ func parseConfig(file string) (*workerConfig, error) {
 		}
 	}
 
+	if config.DrainTimeout < 0 {
+		return nil, fmt.Errorf("invalid drain timeout: %v", config.DrainTimeout)
+	}
+
+	if config.StoreCache != nil {
+		if config.CleanStore {
+			return nil, fmt.Errorf("clean_store and store_cache can't be used together")
+		}
+		if config.StoreCache.MaxSize.Uint64() == 0 {
+			return nil, fmt.Errorf("the store cache needs a max_size")
+		}
+		// other executors would have to ship the whole store shared by
+		// all build slots with every build
+		if config.OSBuildExecutor.Type != "host" {
+			return nil, fmt.Errorf("the store cache only works with the host OSBuildExecutor, got %s", config.OSBuildExecutor.Type)
+		}
+	}
+
+	if config.GenericS3 != nil {
+		for name, ref := range config.GenericS3.CredentialsRefs {
+			if ref.Endpoint == "" {
+				return nil, fmt.Errorf("the generic S3 credentials %q need an endpoint", name)
+			}
+		}
+	}
+
+	if config.Slots.Build < 1 || config.Slots.Light < 1 {
+		return nil, fmt.Errorf("the worker needs at least one build and one light job slot, got %d and %d", config.Slots.Build, config.Slots.Light)
+	}
+
 	switch config.OSBuildExecutor.Type {
-	case "host", "aws.ec2", "qemu.kvm":
+	case "host", "aws.ec2":
 		// good and supported
+	case "qemu.kvm":
+		if (config.OSBuildExecutor.ClientCertFile != "" || config.OSBuildExecutor.ClientKeyFile != "") && config.OSBuildExecutor.CACertFile == "" {
+			return nil, fmt.Errorf("the qemu.kvm OSBuildExecutor needs a CA certificate when using a client certificate")
+		}
+		if config.OSBuildExecutor.Image == "" {
+			return nil, fmt.Errorf("the qemu.kvm OSBuildExecutor needs a guest image")
+		}
+		if config.OSBuildExecutor.Memory < 0 || config.OSBuildExecutor.CPUs < 0 {
+			return nil, fmt.Errorf("invalid qemu.kvm OSBuildExecutor guest size: %d MiB, %d CPUs", config.OSBuildExecutor.Memory, config.OSBuildExecutor.CPUs)
+		}
+	case "remote":
+		if len(config.OSBuildExecutor.Hosts) == 0 {
+			return nil, fmt.Errorf("the remote OSBuildExecutor needs at least one host")
+		}
+		if (config.OSBuildExecutor.ClientCertFile != "" || config.OSBuildExecutor.ClientKeyFile != "") && config.OSBuildExecutor.CACertFile == "" {
+			return nil, fmt.Errorf("the remote OSBuildExecutor needs a CA certificate when using a client certificate")
+		}
 	default:
-		return nil, fmt.Errorf("OSBuildExecutor needs to be host, aws.ec2, or qemu.kvm. Got: %s.", config.OSBuildExecutor)
+		return nil, fmt.Errorf("OSBuildExecutor needs to be host, aws.ec2, qemu.kvm, or remote. Got: %s.", config.OSBuildExecutor.Type)
 	}
 
 	return &config, nil
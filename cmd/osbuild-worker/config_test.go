@@ -4,7 +4,9 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
+	"github.com/osbuild/image-builder/pkg/datasizes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,6 +23,8 @@ func Test_parseConfig(t *testing.T) {
 base_path = "/api/image-builder-worker/v1"
 dnf-json = "/usr/libexec/osbuild-depsolve-dnf"
 clean_store = true
+labels = [ "nested-virt", "large-disk" ]
+drain_timeout = "30m"
 
 [composer]
 proxy = "http://proxy.example.com"
@@ -54,6 +58,22 @@ bucket = "buckethead"
 [oci]
 credentials = "/etc/osbuild-worker/oci-creds"
 
+[pulp]
+credentials = "/etc/osbuild-worker/pulp-creds"
+server_address = "https://pulp.example.com"
+
+[vmware]
+credentials = "/etc/osbuild-worker/vmware-creds"
+
+[sftp]
+private_key = "/etc/osbuild-worker/sftp-key"
+username = "osbuild"
+known_hosts = "/etc/osbuild-worker/sftp-known-hosts"
+
+[sftp.keys.fileserver]
+private_key = "/etc/osbuild-worker/fileserver-key"
+username = "images"
+
 [generic_s3]
 credentials = "/etc/osbuild-worker/s3-creds"
 endpoint = "http://s3.example.com"
@@ -62,6 +82,11 @@ bucket = "slash"
 ca_bundle = "/etc/osbuild-worker/s3-ca-bundle"
 skip_ssl_verification = true
 
+[generic_s3.credentials_refs.ceph]
+credentials = "/etc/osbuild-worker/ceph-creds"
+endpoint = "https://ceph.example.com"
+ca_bundle = "/etc/osbuild-worker/ceph-ca-bundle"
+
 [authentication]
 oauth_url = "https://example.com/token"
 client_id = "toucan"
@@ -72,10 +97,15 @@ offline_token = "/etc/osbuild-worker/offline_token"
 type = "aws.ec2"
 iam_profile = "osbuild-worker"
 key_name = "osbuild-worker"
+
+[slots]
+build = 1
+light = 4
 `,
 			want: &workerConfig{
 				BasePath: "/api/image-builder-worker/v1",
 				DNFJson:  "/usr/libexec/osbuild-depsolve-dnf",
+				Labels:   []string{"nested-virt", "large-disk"},
 				OSBuildExecutor: &executorConfig{
 					Type:       "aws.ec2",
 					IAMProfile: "osbuild-worker",
@@ -115,6 +145,24 @@ key_name = "osbuild-worker"
 				OCI: &ociConfig{
 					Credentials: "/etc/osbuild-worker/oci-creds",
 				},
+				Pulp: &pulpConfig{
+					Credentials:   "/etc/osbuild-worker/pulp-creds",
+					ServerAddress: "https://pulp.example.com",
+				},
+				VMWare: &vmwareConfig{
+					Credentials: "/etc/osbuild-worker/vmware-creds",
+				},
+				SFTP: &sftpConfig{
+					PrivateKey: "/etc/osbuild-worker/sftp-key",
+					Username:   "osbuild",
+					KnownHosts: "/etc/osbuild-worker/sftp-known-hosts",
+					Keys: map[string]sftpKeyConfig{
+						"fileserver": {
+							PrivateKey: "/etc/osbuild-worker/fileserver-key",
+							Username:   "images",
+						},
+					},
+				},
 				GenericS3: &genericS3Config{
 					Credentials:         "/etc/osbuild-worker/s3-creds",
 					Endpoint:            "http://s3.example.com",
@@ -122,6 +170,13 @@ key_name = "osbuild-worker"
 					Bucket:              "slash",
 					CABundle:            "/etc/osbuild-worker/s3-ca-bundle",
 					SkipSSLVerification: true,
+					CredentialsRefs: map[string]genericS3CredentialsRef{
+						"ceph": {
+							Credentials: "/etc/osbuild-worker/ceph-creds",
+							Endpoint:    "https://ceph.example.com",
+							CABundle:    "/etc/osbuild-worker/ceph-ca-bundle",
+						},
+					},
 				},
 				Authentication: &authenticationConfig{
 					OAuthURL:         "https://example.com/token",
@@ -131,6 +186,11 @@ key_name = "osbuild-worker"
 				},
 				DeploymentChannel: "local",
 				CleanStore:        true,
+				Slots: &slotsConfig{
+					Build: 1,
+					Light: 4,
+				},
+				DrainTimeout: 30 * time.Minute,
 			},
 		},
 		{
@@ -143,6 +203,10 @@ key_name = "osbuild-worker"
 				},
 				DeploymentChannel: "local",
 				CleanStore:        false,
+				Slots: &slotsConfig{
+					Build: 1,
+					Light: 1,
+				},
 			},
 		},
 		{
@@ -155,6 +219,88 @@ key_name = "osbuild-worker"
 				},
 				DeploymentChannel: "staging",
 				CleanStore:        false,
+				Slots: &slotsConfig{
+					Build: 1,
+					Light: 1,
+				},
+			},
+		},
+		{
+			name: "qemu_kvm_executor",
+			config: `
+[osbuild_executor]
+type = "qemu.kvm"
+image = "/var/lib/osbuild-worker/executor.qcow2"
+memory = 8192
+cpus = 4
+firmware = "/usr/share/AAVMF/AAVMF_CODE.fd"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type:     "qemu.kvm",
+					Image:    "/var/lib/osbuild-worker/executor.qcow2",
+					Memory:   8192,
+					CPUs:     4,
+					Firmware: "/usr/share/AAVMF/AAVMF_CODE.fd",
+				},
+				DeploymentChannel: "local",
+				Slots: &slotsConfig{
+					Build: 1,
+					Light: 1,
+				},
+			},
+		},
+		{
+			name: "remote_executor",
+			config: `
+[osbuild_executor]
+type = "remote"
+hosts = [ "https://builder1.example.com:8001", "https://builder2.example.com:8001" ]
+ca_cert = "/etc/osbuild-worker/executor-ca.pem"
+client_cert = "/etc/osbuild-worker/executor-client.pem"
+client_key = "/etc/osbuild-worker/executor-client-key.pem"
+token_file = "/etc/osbuild-worker/executor-token"
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type:           "remote",
+					Hosts:          []string{"https://builder1.example.com:8001", "https://builder2.example.com:8001"},
+					CACertFile:     "/etc/osbuild-worker/executor-ca.pem",
+					ClientCertFile: "/etc/osbuild-worker/executor-client.pem",
+					ClientKeyFile:  "/etc/osbuild-worker/executor-client-key.pem",
+					TokenFile:      "/etc/osbuild-worker/executor-token",
+				},
+				DeploymentChannel: "local",
+				Slots: &slotsConfig{
+					Build: 1,
+					Light: 1,
+				},
+			},
+		},
+		{
+			name: "store_cache",
+			config: `
+[store_cache]
+max_size = "20 GiB"
+
+[slots]
+build = 4
+`,
+			want: &workerConfig{
+				BasePath: "/api/worker/v1",
+				OSBuildExecutor: &executorConfig{
+					Type: "host",
+				},
+				DeploymentChannel: "local",
+				StoreCache: &storeCacheConfig{
+					MaxSize: 20 * datasizes.GiB,
+				},
+				Slots: &slotsConfig{
+					Build: 4,
+					Light: 1,
+				},
 			},
 		},
 	}
@@ -187,6 +333,80 @@ key_name = "osbuild-worker"
 [azure]
 credentials = "/etc/osbuild-worker/azure-creds"
 upload_threads = -5
+`)
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong drain timeout", func(t *testing.T) {
+		configFile := prepareConfig(t, `drain_timeout = "-5m"`)
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("qemu.kvm executor without image", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[osbuild_executor]
+type = "qemu.kvm"
+memory = 8192
+`)
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("remote executor without hosts", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[osbuild_executor]
+type = "remote"
+`)
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("store cache without max size", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[store_cache]
+`)
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("store cache with clean store", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+clean_store = true
+
+[store_cache]
+max_size = "20 GiB"
+`)
+		_, err := parseConfig(configFile)
+		require.Error(t, err)
+	})
+
+	t.Run("store cache with a remote executor", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[store_cache]
+max_size = "20 GiB"
+
+[osbuild_executor]
+type = "aws.ec2"
+`)
+		_, err := parseConfig(configFile)
+		require.ErrorContains(t, err, "the store cache only works with the host OSBuildExecutor")
+	})
+
+	t.Run("generic S3 credentials without endpoint", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[generic_s3.credentials_refs.ceph]
+credentials = "/etc/osbuild-worker/ceph-creds"
+`)
+		_, err := parseConfig(configFile)
+		require.ErrorContains(t, err, `the generic S3 credentials "ceph" need an endpoint`)
+	})
+
+	t.Run("wrong slots config", func(t *testing.T) {
+		configFile := prepareConfig(t, `
+[slots]
+build = 0
 `)
 		_, err := parseConfig(configFile)
 		require.Error(t, err)
@@ -5,6 +5,11 @@ var (
 	MakeJobErrorFromOsbuildOutput = makeJobErrorFromOsbuildOutput
 	Main                          = main
 	ParseManifestPipelines        = parseManifestPipelines
+	GetVMWareCredentials          = (*OSBuildJobImpl).getVMWareCredentials
+	GetPulpCredentials            = (*OSBuildJobImpl).getPulpCredentials
+	GetAWSForGenericS3Target      = (*OSBuildJobImpl).getAWSForGenericS3Target
+	UploadToS3                    = uploadToS3
+	GetSFTPUploader               = (*OSBuildJobImpl).getSFTPUploader
 )
 
 func MockRun(new func()) (restore func()) {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -23,7 +24,7 @@ type AWSEC2CopyJobImpl struct {
 	AWSCreds string
 }
 
-func (impl *AWSEC2CopyJobImpl) Run(job worker.Job) error {
+func (impl *AWSEC2CopyJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 	result := worker.AWSEC2CopyJobResult{}
 
@@ -81,7 +82,7 @@ type AWSEC2ShareJobImpl struct {
 	AWSCreds string
 }
 
-func (impl *AWSEC2ShareJobImpl) Run(job worker.Job) error {
+func (impl *AWSEC2ShareJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 	result := worker.AWSEC2ShareJobResult{}
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 
@@ -32,7 +33,7 @@ type BootcInfoResolveJobImpl struct {
 	CleanupImages bool
 }
 
-func (impl *BootcInfoResolveJobImpl) Run(job worker.Job) error {
+func (impl *BootcInfoResolveJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 
 	result := worker.BootcInfoResolveJobResult{}
@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -303,7 +304,7 @@ func TestBootcInfoResolveJobRun(t *testing.T) {
 			impl := &main.BootcInfoResolveJobImpl{
 				CleanupImages: tt.cleanupImages,
 			}
-			runErr := impl.Run(jobMock)
+			runErr := impl.Run(context.Background(), jobMock)
 
 			if tt.wantRunErrSubstr != "" {
 				require.Error(t, runErr)
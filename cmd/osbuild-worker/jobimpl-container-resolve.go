@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/sirupsen/logrus"
@@ -14,7 +15,7 @@ type ContainerResolveJobImpl struct {
 	AuthFilePath string
 }
 
-func (impl *ContainerResolveJobImpl) Run(job worker.Job) error {
+func (impl *ContainerResolveJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 
 	result := worker.ContainerResolveJobResult{}
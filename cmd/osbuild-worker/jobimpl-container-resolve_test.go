@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -265,7 +266,7 @@ func TestContainerResolveJobRun(t *testing.T) {
 			jobMock := tt.mockMockJobFunc(t, worker.JobTypeContainerResolve, rawArgs, tt.dynArgs...)
 
 			impl := &main.ContainerResolveJobImpl{AuthFilePath: ""}
-			runErr := impl.Run(jobMock)
+			runErr := impl.Run(context.Background(), jobMock)
 
 			if tt.wantRunErrSubstr != "" {
 				require.Error(t, runErr)
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -152,7 +153,7 @@ func workerClientErrorFrom(err error, logWithId *logrus.Entry) *clienterrors.Err
 	}
 }
 
-func (impl *DepsolveJobImpl) Run(job worker.Job) error {
+func (impl *DepsolveJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 
 	var result worker.DepsolveJobResult
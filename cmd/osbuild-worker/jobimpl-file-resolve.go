@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/ondrejbudai/osbuild-composer-public/public/remotefile"
@@ -10,7 +12,7 @@ import (
 
 type FileResolveJobImpl struct{}
 
-func (impl *FileResolveJobImpl) Run(job worker.Job) error {
+func (impl *FileResolveJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 
 	var err error
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -16,7 +17,7 @@ type ImageBuilderManifestJobImpl struct {
 	RepositoryMTLSConfig *RepositoryMTLSConfig
 }
 
-func (impl *ImageBuilderManifestJobImpl) Run(job worker.Job) error {
+func (impl *ImageBuilderManifestJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id().String())
 
 	result := &worker.ImageBuilderManifestJobResult{
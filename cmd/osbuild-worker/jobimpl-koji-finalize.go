@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net/url"
@@ -82,7 +83,7 @@ func (impl *KojiFinalizeJobImpl) kojiFail(server string, buildID int, token stri
 	return k.CGFailBuild(buildID, token)
 }
 
-func (impl *KojiFinalizeJobImpl) Run(job worker.Job) error {
+func (impl *KojiFinalizeJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id().String())
 
 	// initialize the result variable to be used to report status back to composer
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
@@ -50,7 +51,7 @@ func (impl *KojiInitJobImpl) kojiInit(server, name, version, release string) (st
 	return buildInfo.Token, uint64(buildInfo.BuildID), nil // nolint: gosec
 }
 
-func (impl *KojiInitJobImpl) Run(job worker.Job) error {
+func (impl *KojiInitJobImpl) Run(ctx context.Context, job worker.Job) error {
 	var args worker.KojiInitJob
 	err := job.Args(&args)
 	if err != nil {
@@ -8,17 +8,21 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"net"
 	"net/url"
 	"os"
 	"os/exec"
 	"path"
 	"runtime/debug"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/osbuild/image-builder/pkg/arch"
 	"github.com/osbuild/image-builder/pkg/cloud/azure"
@@ -35,6 +39,8 @@ import (
 	"github.com/ondrejbudai/osbuild-composer-public/public/common"
 	"github.com/ondrejbudai/osbuild-composer-public/public/osbuildexecutor"
 	"github.com/ondrejbudai/osbuild-composer-public/public/target"
+	"github.com/ondrejbudai/osbuild-composer-public/public/upload/pulp"
+	"github.com/ondrejbudai/osbuild-composer-public/public/upload/sftp"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker/clienterrors"
 )
@@ -51,6 +57,16 @@ type S3Configuration struct {
 	Bucket              string
 	CABundle            string
 	SkipSSLVerification bool
+	CredentialsRefs     map[string]S3CredentialsRef
+}
+
+// S3CredentialsRef are credentials which targets refer to by name
+type S3CredentialsRef struct {
+	Creds string
+	// the only endpoint the credentials are sent to
+	Endpoint            string
+	CABundle            string
+	SkipSSLVerification bool
 }
 
 type ContainersConfiguration struct {
@@ -73,6 +89,24 @@ type OCIConfiguration struct {
 	Namespace    string
 }
 
+type VMWareConfiguration struct {
+	Host     string
+	Username string
+	Password string
+}
+
+type SFTPKey struct {
+	PrivateKey string
+	Username   string
+}
+
+type SFTPConfiguration struct {
+	// used by the targets which don't refer to a key
+	Key        SFTPKey
+	KnownHosts string
+	Keys       map[string]SFTPKey
+}
+
 type PulpConfiguration struct {
 	CredsFilePath string
 	ServerAddress string
@@ -82,10 +116,15 @@ type ExecutorConfiguration struct {
 	Type       string
 	IAMProfile string
 	KeyName    string
+	// only used by the remote executor
+	RemoteHosts *osbuildexecutor.RemoteHostPool
+	// only used by the qemu.kvm executor
+	QEMU osbuildexecutor.QEMUOptions
 }
 
 type OSBuildJobImpl struct {
 	Store                string
+	StoreCache           *storeCache // shared by all build slots if set
 	Output               string
 	OSBuildExecutor      ExecutorConfiguration
 	KojiServers          map[string]kojiServer
@@ -98,6 +137,8 @@ type OSBuildJobImpl struct {
 	S3Config             S3Configuration
 	ContainersConfig     ContainersConfiguration
 	PulpConfig           PulpConfiguration
+	VMWareConfig         VMWareConfiguration
+	SFTPConfig           SFTPConfiguration
 	RepositoryMTLSConfig *RepositoryMTLSConfig
 }
 
@@ -183,6 +224,30 @@ func (impl *OSBuildJobImpl) getAWSForS3Target(options *target.AWSS3TargetOptions
 	return aws, bucket, err
 }
 
+// getAWSForGenericS3Target returns an *awscloud.AWS object for the endpoint of
+// the target with the credentials the target refers to.
+func (impl *OSBuildJobImpl) getAWSForGenericS3Target(options *target.GenericS3TargetOptions) (*awscloud.AWS, error) {
+	if options.Endpoint == "" {
+		return nil, fmt.Errorf("no S3 endpoint provided")
+	}
+
+	ref, ok := impl.S3Config.CredentialsRefs[options.CredentialsRef]
+	if !ok || ref.Creds == "" {
+		return nil, fmt.Errorf("unknown S3 credentials %q", options.CredentialsRef)
+	}
+	if ref.Endpoint != options.Endpoint {
+		return nil, fmt.Errorf("S3 credentials %q cannot be used for %s", options.CredentialsRef, options.Endpoint)
+	}
+
+	// S3-compatible object stores usually ignore the region, but the
+	// requests have to be signed for one
+	region := options.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return awscloud.NewForEndpointFromFile(ref.Creds, options.Endpoint, region, ref.CABundle, ref.SkipSSLVerification)
+}
+
 // getGCP returns an *gcp.GCP object using credentials based on the following
 // predefined preference:
 //
@@ -322,6 +387,120 @@ func (impl *OSBuildJobImpl) getContainerClient(destination string, targetOptions
 	return client, nil
 }
 
+// getVMWareCredentials returns the credentials of the target. The cloud API
+// doesn't pass the vCenter nor any credentials, the ones of the worker
+// configuration are used then. They are never sent to any other vCenter.
+func (impl *OSBuildJobImpl) getVMWareCredentials(targetOptions *target.VMWareTargetOptions) vmware.Credentials {
+	credentials := vmware.Credentials{
+		Username:   targetOptions.Username,
+		Password:   targetOptions.Password,
+		Host:       targetOptions.Host,
+		Cluster:    targetOptions.Cluster,
+		Datacenter: targetOptions.Datacenter,
+		Datastore:  targetOptions.Datastore,
+		Folder:     targetOptions.Folder,
+	}
+	if credentials.Host == "" {
+		credentials.Host = impl.VMWareConfig.Host
+		if credentials.Username == "" && credentials.Password == "" {
+			credentials.Username = impl.VMWareConfig.Username
+			credentials.Password = impl.VMWareConfig.Password
+		}
+	}
+	return credentials
+}
+
+// getPulpCredentials returns the credentials of the target, falling back to
+// the ones from the worker configuration. Those are only sent to the Pulp
+// server of the worker configuration, never to a server of the target.
+func (impl *OSBuildJobImpl) getPulpCredentials(targetOptions *target.PulpOSTreeTargetOptions) (*pulp.Credentials, error) {
+	if targetOptions.Username != "" && targetOptions.Password != "" {
+		return &pulp.Credentials{
+			Username: targetOptions.Username,
+			Password: targetOptions.Password,
+		}, nil
+	}
+	if targetOptions.ServerAddress != "" && targetOptions.ServerAddress != impl.PulpConfig.ServerAddress {
+		return nil, nil
+	}
+	if impl.PulpConfig.CredsFilePath == "" {
+		return nil, nil
+	}
+	return pulp.ReadCredentials(impl.PulpConfig.CredsFilePath)
+}
+
+// getPulpClient returns a client for the Pulp server of the target, falling
+// back to the server from the worker configuration.
+func (impl *OSBuildJobImpl) getPulpClient(targetOptions *target.PulpOSTreeTargetOptions) (*pulp.Client, error) {
+	creds, err := impl.getPulpCredentials(targetOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	address := impl.PulpConfig.ServerAddress
+	if targetOptions.ServerAddress != "" {
+		address = targetOptions.ServerAddress
+	}
+	return pulp.NewClient(address, creds)
+}
+
+// getSFTPUploader returns an uploader for the server of the target which
+// authenticates with the key the target refers to. The host keys of the
+// server have to be pinned by the target or be in the worker's known hosts.
+func (impl *OSBuildJobImpl) getSFTPUploader(targetOptions *target.SFTPTargetOptions) (*sftp.Uploader, error) {
+	if targetOptions.Host == "" {
+		return nil, fmt.Errorf("no SFTP host provided")
+	}
+
+	key := impl.SFTPConfig.Key
+	if targetOptions.KeyRef != "" {
+		var ok bool
+		key, ok = impl.SFTPConfig.Keys[targetOptions.KeyRef]
+		if !ok {
+			return nil, fmt.Errorf("unknown SFTP key %q", targetOptions.KeyRef)
+		}
+	}
+	if key.PrivateKey == "" {
+		return nil, fmt.Errorf("no SFTP private key configured")
+	}
+	signer, err := sftp.ReadPrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	username := targetOptions.Username
+	if username == "" {
+		username = key.Username
+	}
+	if username == "" {
+		return nil, fmt.Errorf("no SFTP user name provided")
+	}
+
+	var knownHostsFiles []string
+	if impl.SFTPConfig.KnownHosts != "" {
+		knownHostsFiles = append(knownHostsFiles, impl.SFTPConfig.KnownHosts)
+	}
+	hostKeyCallback, err := sftp.HostKeyCallback(targetOptions.KnownHosts, knownHostsFiles...)
+	if err != nil {
+		return nil, err
+	}
+
+	port := targetOptions.Port
+	if port == 0 {
+		port = 22
+	}
+	return &sftp.Uploader{
+		Address: net.JoinHostPort(targetOptions.Host, strconv.Itoa(port)),
+		Config: &ssh.ClientConfig{
+			User:            username,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         time.Minute,
+		},
+		Retries: 3,
+	}, nil
+}
+
 func makeJobErrorFromOsbuildOutput(result *osbuild.Result) *clienterrors.Error {
 	var errors []string
 	// validation errors
@@ -345,7 +524,7 @@ func makeJobErrorFromOsbuildOutput(result *osbuild.Result) *clienterrors.Error {
 	return clienterrors.New(clienterrors.ErrorBuildJob, "build failure", errors)
 }
 
-func (impl *OSBuildJobImpl) Run(job worker.Job) error {
+func (impl *OSBuildJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id().String())
 	// Initialize variable needed for reporting back to osbuild-composer.
 	var osbuildJobResult *worker.OSBuildJobResult = &worker.OSBuildJobResult{
@@ -549,19 +728,27 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 	switch impl.OSBuildExecutor.Type {
 	case "host":
 		executor = osbuildexecutor.NewHostExecutor()
-	case "aws.ec2":
+	case "aws.ec2", "remote", "qemu.kvm":
+		errMsg := fmt.Sprintf("Unable to create /var/tmp/osbuild-composer needed to %s executor", impl.OSBuildExecutor.Type)
 		err = os.MkdirAll("/var/tmp/osbuild-composer", 0755)
 		if err != nil {
-			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "Unable to create /var/tmp/osbuild-composer needed to aws.ec2 executor", nil)
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, errMsg, nil)
 			return err
 		}
 		tmpDir, err := os.MkdirTemp("/var/tmp/osbuild-composer", "")
 		if err != nil {
-			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "Unable to create /var/tmp/osbuild-composer needed to aws.ec2 executor", nil)
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, errMsg, nil)
 			return err
 		}
 		defer os.RemoveAll(tmpDir)
-		executor = osbuildexecutor.NewAWSEC2Executor(impl.OSBuildExecutor.IAMProfile, impl.OSBuildExecutor.KeyName, job.Id().String(), tmpDir)
+		switch impl.OSBuildExecutor.Type {
+		case "remote":
+			executor = osbuildexecutor.NewRemoteExecutor(impl.OSBuildExecutor.RemoteHosts, tmpDir)
+		case "qemu.kvm":
+			executor = osbuildexecutor.NewQEMUKVMExecutor(impl.OSBuildExecutor.QEMU, tmpDir)
+		default:
+			executor = osbuildexecutor.NewAWSEC2Executor(impl.OSBuildExecutor.IAMProfile, impl.OSBuildExecutor.KeyName, job.Id().String(), tmpDir)
+		}
 	default:
 		osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorInvalidConfig, "No osbuild executor defined", nil)
 		return err
@@ -577,7 +764,25 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 		JSONOutput: true,
 	}
 
-	osbuildJobResult.OSBuildOutput, err = executor.RunOSBuild(jobArgs.Manifest, logWithId, job, opts)
+	// the report is kept even if the build fails, it shows how far it got
+	osbuildJobResult.OSBuildReport = &worker.OSBuildReport{}
+
+	if impl.StoreCache != nil {
+		release, stats, err := impl.StoreCache.acquire(jobArgs.Manifest)
+		if err != nil {
+			osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "unable to use the store cache", err.Error())
+			return err
+		}
+		defer release()
+		osbuildJobResult.OSBuildReport.StoreCache = &stats
+		logWithId.Infof("Store cache: %d of %d sources already cached", stats.Hits, stats.Hits+stats.Misses)
+
+		opts.CacheMaxSize = impl.StoreCache.maxSize
+		// Keep the build roots in the store, the next compose for the same
+		// distribution gets them for free.
+		opts.Checkpoints = osbuildJobResult.PipelineNames.Build
+	}
+	osbuildJobResult.OSBuildOutput, err = executor.RunOSBuild(ctx, jobArgs.Manifest, logWithId, job, osbuildJobResult.OSBuildReport, opts)
 	// handle the case where something around running osbuild failed (starting, IO errors, etc.)
 	if err != nil {
 		osbuildJobResult.JobError = clienterrors.New(clienterrors.ErrorBuildJob, "osbuild failed", err.Error())
@@ -606,6 +811,12 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 	}
 
 	for _, jobTarget := range jobArgs.Targets {
+		// Not all uploaders can be interrupted, so at least don't start
+		// a new upload once the job was canceled.
+		if ctx.Err() != nil {
+			return fmt.Errorf("job canceled before uploading to %s: %w", jobTarget.Name, context.Cause(ctx))
+		}
+
 		var targetResult *target.TargetResult
 		artifact := jobTarget.OsbuildArtifact
 		switch targetOptions := jobTarget.Options.(type) {
@@ -628,16 +839,8 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			}
 
 		case *target.VMWareTargetOptions:
-			targetResult = target.NewVMWareTargetResult(&artifact)
-			credentials := vmware.Credentials{
-				Username:   targetOptions.Username,
-				Password:   targetOptions.Password,
-				Host:       targetOptions.Host,
-				Cluster:    targetOptions.Cluster,
-				Datacenter: targetOptions.Datacenter,
-				Datastore:  targetOptions.Datastore,
-				Folder:     targetOptions.Folder,
-			}
+			targetResult = target.NewVMWareTargetResult(nil, &artifact)
+			credentials := impl.getVMWareCredentials(targetOptions)
 
 			tempDirectory, err := os.MkdirTemp(impl.Output, job.Id().String()+"-vmware-*")
 			if err != nil {
@@ -680,6 +883,13 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 				targetResult.TargetError = clienterrors.New(clienterrors.ErrorUploadingImage, "No vmdk or ova provided", nil)
 				break
 			}
+			targetResult.Options = &target.VMWareTargetResultOptions{
+				ImageName:  jobTarget.ImageName,
+				Host:       credentials.Host,
+				Datacenter: credentials.Datacenter,
+				Datastore:  credentials.Datastore,
+				Folder:     credentials.Folder,
+			}
 
 		case *target.AWSTargetOptions:
 			targetResult = target.NewAWSTargetResult(nil, &artifact)
@@ -770,6 +980,30 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			}
 			targetResult.Options = &target.AWSS3TargetResultOptions{URL: url}
 
+		case *target.GenericS3TargetOptions:
+			targetResult = target.NewGenericS3TargetResult(nil, &artifact)
+			a, err := impl.getAWSForGenericS3Target(targetOptions)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+				break
+			}
+
+			if targetOptions.Bucket == "" || targetOptions.Key == "" {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidTargetConfig, "No S3 bucket or object key provided", nil)
+				break
+			}
+
+			url, targetError := uploadToS3(a, outputDirectory, jobTarget.OsbuildArtifact.ExportName, targetOptions.Bucket, targetOptions.Key, jobTarget.OsbuildArtifact.ExportFilename, false)
+			if targetError != nil {
+				targetResult.TargetError = targetError
+				break
+			}
+			targetResult.Options = &target.GenericS3TargetResultOptions{
+				URL:    url,
+				Bucket: targetOptions.Bucket,
+				Key:    targetOptions.Key + "-" + jobTarget.OsbuildArtifact.ExportFilename,
+			}
+
 		case *target.AzureTargetOptions:
 			targetResult = target.NewAzureTargetResult(&artifact)
 			azureStorageClient, err := azure.NewStorageClient(targetOptions.StorageAccount, targetOptions.StorageAccessKey)
@@ -800,7 +1034,6 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 
 		case *target.GCPTargetOptions:
 			targetResult = target.NewGCPTargetResult(nil, &artifact)
-			ctx := context.Background()
 
 			g, err := impl.getGCP(targetOptions.Credentials)
 			if err != nil {
@@ -873,7 +1106,6 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 
 		case *target.AzureImageTargetOptions:
 			targetResult = target.NewAzureImageTargetResult(nil, &artifact)
-			ctx := context.Background()
 
 			if impl.AzureConfig.Creds == nil {
 				targetResult.TargetError = clienterrors.New(clienterrors.ErrorSharingTarget, "osbuild job has org.osbuild.azure.image target but this worker doesn't have azure credentials", nil)
@@ -1316,7 +1548,7 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			// TODO: get the container type from the metadata of the osbuild job
 			sourceRef := fmt.Sprintf("oci-archive:%s", sourcePath)
 
-			digest, err := client.UploadImage(context.Background(), sourceRef, "")
+			digest, err := client.UploadImage(ctx, sourceRef, "")
 
 			if err != nil {
 				logWithId.Infof("[container] 🙁 Upload of '%s' failed: %v", sourceRef, err)
@@ -1326,6 +1558,50 @@ func (impl *OSBuildJobImpl) Run(job worker.Job) error {
 			logWithId.Printf("[container] 🎉 Image uploaded (%s)!", digest.String())
 			targetResult.Options = &target.ContainerTargetResultOptions{URL: client.Target.String(), Digest: digest.String()}
 
+		case *target.PulpOSTreeTargetOptions:
+			targetResult = target.NewPulpOSTreeTargetResult(nil, &artifact)
+			client, err := impl.getPulpClient(targetOptions)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidConfig, err.Error(), nil)
+				break
+			}
+
+			archivePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+			logWithId.Infof("[Pulp] ⬆ Uploading the commit to repository %q", targetOptions.Repository)
+			repoURL, err := client.UploadAndDistributeCommit(ctx, archivePath, targetOptions.Repository, targetOptions.BasePath)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorUploadingImage, err.Error(), nil)
+				break
+			}
+			logWithId.Infof("[Pulp] 🎉 Commit imported, repository distributed at %s", repoURL)
+			targetResult.Options = &target.PulpOSTreeTargetResultOptions{RepoURL: repoURL}
+
+		case *target.SFTPTargetOptions:
+			targetResult = target.NewSFTPTargetResult(nil, &artifact)
+			uploader, err := impl.getSFTPUploader(targetOptions)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorInvalidTargetConfig, err.Error(), nil)
+				break
+			}
+
+			filename := jobTarget.ImageName
+			if filename == "" {
+				filename = jobTarget.OsbuildArtifact.ExportFilename
+			}
+			remotePath := path.Join(targetOptions.Path, filename)
+			imagePath := path.Join(outputDirectory, jobTarget.OsbuildArtifact.ExportName, jobTarget.OsbuildArtifact.ExportFilename)
+			logWithId.Infof("[SFTP] ⬆ Uploading the image to %s:%s", uploader.Address, remotePath)
+			checksum, err := uploader.Upload(ctx, imagePath, remotePath)
+			if err != nil {
+				targetResult.TargetError = clienterrors.New(clienterrors.ErrorUploadingImage, err.Error(), nil)
+				break
+			}
+			logWithId.Info("[SFTP] 🎉 Image uploaded")
+			targetResult.Options = &target.SFTPTargetResultOptions{
+				Path:     remotePath,
+				Checksum: "sha256:" + checksum,
+			}
+
 		default:
 			// TODO: we may not want to return completely here with multiple targets, because then no TargetErrors will be added to the JobError details
 			// Nevertheless, all target errors will be still in the OSBuildJobResult.
@@ -1,14 +1,28 @@
 package main_test
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/osbuild/image-builder/pkg/osbuild"
+	"github.com/osbuild/image-builder/pkg/upload/vmware"
 
 	main "github.com/ondrejbudai/osbuild-composer-public/cmd/osbuild-worker"
+	"github.com/ondrejbudai/osbuild-composer-public/public/target"
+	"github.com/ondrejbudai/osbuild-composer-public/public/upload/pulp"
 )
 
 func TestMakeJobErrorFromOsbuildOutput(t *testing.T) {
@@ -78,3 +92,222 @@ func TestMakeJobErrorFromOsbuildOutput(t *testing.T) {
 		require.Equal(t, testData.expected, wce.String())
 	}
 }
+
+func TestGetVMWareCredentials(t *testing.T) {
+	impl := &main.OSBuildJobImpl{
+		VMWareConfig: main.VMWareConfiguration{
+			Host:     "vcenter.example.com",
+			Username: "worker",
+			Password: "worker-password",
+		},
+	}
+
+	// cloud API targets use the vCenter and the credentials of the worker
+	creds := main.GetVMWareCredentials(impl, &target.VMWareTargetOptions{
+		Datacenter: "dc",
+		Cluster:    "cluster",
+		Datastore:  "ds",
+	})
+	require.Equal(t, vmware.Credentials{
+		Host:       "vcenter.example.com",
+		Username:   "worker",
+		Password:   "worker-password",
+		Datacenter: "dc",
+		Cluster:    "cluster",
+		Datastore:  "ds",
+	}, creds)
+
+	// weldr targets bring their own
+	creds = main.GetVMWareCredentials(impl, &target.VMWareTargetOptions{
+		Host:     "vcenter.example.org",
+		Username: "user",
+		Password: "password",
+	})
+	require.Equal(t, "vcenter.example.org", creds.Host)
+	require.Equal(t, "user", creds.Username)
+	require.Equal(t, "password", creds.Password)
+
+	// the credentials of the worker are never sent to another vCenter
+	creds = main.GetVMWareCredentials(impl, &target.VMWareTargetOptions{
+		Host: "vcenter.example.org",
+	})
+	require.Equal(t, "vcenter.example.org", creds.Host)
+	require.Empty(t, creds.Username)
+	require.Empty(t, creds.Password)
+}
+
+func TestGetPulpCredentials(t *testing.T) {
+	credsPath := filepath.Join(t.TempDir(), "pulp.json")
+	require.NoError(t, os.WriteFile(credsPath, []byte(`{"username": "worker", "password": "worker-password"}`), 0600))
+	impl := &main.OSBuildJobImpl{
+		PulpConfig: main.PulpConfiguration{
+			CredsFilePath: credsPath,
+			ServerAddress: "https://pulp.example.com",
+		},
+	}
+	workerCreds := &pulp.Credentials{
+		Username: "worker",
+		Password: "worker-password",
+	}
+
+	// cloud API targets use the server and the credentials of the worker
+	creds, err := main.GetPulpCredentials(impl, &target.PulpOSTreeTargetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, workerCreds, creds)
+
+	creds, err = main.GetPulpCredentials(impl, &target.PulpOSTreeTargetOptions{
+		ServerAddress: "https://pulp.example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, workerCreds, creds)
+
+	// weldr targets bring their own
+	creds, err = main.GetPulpCredentials(impl, &target.PulpOSTreeTargetOptions{
+		ServerAddress: "https://pulp.example.org",
+		Username:      "user",
+		Password:      "password",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &pulp.Credentials{
+		Username: "user",
+		Password: "password",
+	}, creds)
+
+	// the credentials of the worker are never sent to another server
+	creds, err = main.GetPulpCredentials(impl, &target.PulpOSTreeTargetOptions{
+		ServerAddress: "https://pulp.example.org",
+	})
+	require.NoError(t, err)
+	require.Nil(t, creds)
+}
+
+// fakeS3 is a stand-in for an S3-compatible object store like MinIO, it
+// only stores objects uploaded with path-style PUT requests
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[strings.TrimPrefix(r.URL.Path, "/")] = content
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestGetAWSForGenericS3Target(t *testing.T) {
+	creds := filepath.Join(t.TempDir(), "s3-creds")
+	require.NoError(t, os.WriteFile(creds, []byte("[default]\naws_access_key_id = key\naws_secret_access_key = secret\n"), 0600))
+
+	impl := &main.OSBuildJobImpl{
+		S3Config: main.S3Configuration{
+			CredentialsRefs: map[string]main.S3CredentialsRef{
+				"ceph":  {Creds: creds, Endpoint: "https://ceph.example.com"},
+				"minio": {Creds: creds, Endpoint: "https://minio.example.com"},
+				"any":   {Creds: creds},
+			},
+		},
+	}
+
+	_, err := main.GetAWSForGenericS3Target(impl, &target.GenericS3TargetOptions{Endpoint: "https://ceph.example.com", CredentialsRef: "ceph"})
+	require.NoError(t, err)
+	_, err = main.GetAWSForGenericS3Target(impl, &target.GenericS3TargetOptions{Endpoint: "https://minio.example.com", CredentialsRef: "minio"})
+	require.NoError(t, err)
+
+	// the credentials are never sent to another endpoint than the one
+	// they are restricted to
+	_, err = main.GetAWSForGenericS3Target(impl, &target.GenericS3TargetOptions{Endpoint: "https://minio.example.com", CredentialsRef: "ceph"})
+	require.ErrorContains(t, err, "cannot be used for https://minio.example.com")
+	// credentials without an endpoint are rejected by the config parser,
+	// they mustn't allow any endpoint either
+	_, err = main.GetAWSForGenericS3Target(impl, &target.GenericS3TargetOptions{Endpoint: "https://minio.example.com", CredentialsRef: "any"})
+	require.ErrorContains(t, err, "cannot be used for https://minio.example.com")
+
+	_, err = main.GetAWSForGenericS3Target(impl, &target.GenericS3TargetOptions{Endpoint: "https://minio.example.com", CredentialsRef: "unknown"})
+	require.ErrorContains(t, err, "unknown S3 credentials")
+	_, err = main.GetAWSForGenericS3Target(impl, &target.GenericS3TargetOptions{CredentialsRef: "minio"})
+	require.ErrorContains(t, err, "no S3 endpoint")
+}
+
+func TestUploadToGenericS3(t *testing.T) {
+	s3 := &fakeS3{objects: map[string][]byte{}}
+	srv := httptest.NewServer(s3)
+	defer srv.Close()
+
+	tmpdir := t.TempDir()
+	creds := filepath.Join(tmpdir, "s3-creds")
+	require.NoError(t, os.WriteFile(creds, []byte("[default]\naws_access_key_id = key\naws_secret_access_key = secret\n"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpdir, "output", "image"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpdir, "output", "image", "disk.qcow2"), []byte("image"), 0600))
+
+	impl := &main.OSBuildJobImpl{
+		S3Config: main.S3Configuration{
+			CredentialsRefs: map[string]main.S3CredentialsRef{
+				"minio": {Creds: creds, Endpoint: srv.URL},
+			},
+		},
+	}
+	a, err := main.GetAWSForGenericS3Target(impl, &target.GenericS3TargetOptions{Endpoint: srv.URL, CredentialsRef: "minio"})
+	require.NoError(t, err)
+
+	url, clientErr := main.UploadToS3(a, filepath.Join(tmpdir, "output"), "image", "images", "composer-api-1", "disk.qcow2", false)
+	require.Nil(t, clientErr)
+	require.Equal(t, []byte("image"), s3.objects["images/composer-api-1-disk.qcow2"])
+	require.True(t, strings.HasPrefix(url, srv.URL+"/images/composer-api-1-disk.qcow2?"), url)
+	require.Contains(t, url, "X-Amz-Signature=")
+}
+
+func TestGetSFTPUploader(t *testing.T) {
+	tmpdir := t.TempDir()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+	key := filepath.Join(tmpdir, "sftp-key")
+	require.NoError(t, os.WriteFile(key, pem.EncodeToMemory(block), 0600))
+	knownHosts := filepath.Join(tmpdir, "known_hosts")
+	require.NoError(t, os.WriteFile(knownHosts, nil, 0600))
+
+	impl := &main.OSBuildJobImpl{
+		SFTPConfig: main.SFTPConfiguration{
+			Key:        main.SFTPKey{PrivateKey: key, Username: "osbuild"},
+			KnownHosts: knownHosts,
+			Keys: map[string]main.SFTPKey{
+				"fileserver": {PrivateKey: key, Username: "images"},
+			},
+		},
+	}
+
+	uploader, err := main.GetSFTPUploader(impl, &target.SFTPTargetOptions{Host: "files.example.com", Path: "/srv"})
+	require.NoError(t, err)
+	require.Equal(t, "files.example.com:22", uploader.Address)
+	require.Equal(t, "osbuild", uploader.Config.User)
+
+	uploader, err = main.GetSFTPUploader(impl, &target.SFTPTargetOptions{Host: "files.example.com", Port: 2222, KeyRef: "fileserver"})
+	require.NoError(t, err)
+	require.Equal(t, "files.example.com:2222", uploader.Address)
+	require.Equal(t, "images", uploader.Config.User)
+
+	uploader, err = main.GetSFTPUploader(impl, &target.SFTPTargetOptions{Host: "files.example.com", Username: "user", KeyRef: "fileserver"})
+	require.NoError(t, err)
+	require.Equal(t, "user", uploader.Config.User)
+
+	_, err = main.GetSFTPUploader(impl, &target.SFTPTargetOptions{Host: "files.example.com", KeyRef: "unknown"})
+	require.ErrorContains(t, err, "unknown SFTP key")
+	_, err = main.GetSFTPUploader(impl, &target.SFTPTargetOptions{})
+	require.ErrorContains(t, err, "no SFTP host")
+
+	// the host keys of the server have to be known
+	impl.SFTPConfig.KnownHosts = ""
+	_, err = main.GetSFTPUploader(impl, &target.SFTPTargetOptions{Host: "files.example.com"})
+	require.ErrorContains(t, err, "no known host keys")
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -62,7 +63,7 @@ func setError(err error, result *worker.OSTreeResolveJobResult) {
 	}
 }
 
-func (impl *OSTreeResolveJobImpl) Run(job worker.Job) error {
+func (impl *OSTreeResolveJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 	var args worker.OSTreeResolveJob
 	err := job.Args(&args)
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/osbuild/image-builder/pkg/depsolvednf"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
@@ -27,7 +28,7 @@ func (impl *SearchPackagesJobImpl) search(repos []rpmmd.RepoConfig, modulePlatfo
 }
 
 // Run executes the search and returns the results
-func (impl *SearchPackagesJobImpl) Run(job worker.Job) error {
+func (impl *SearchPackagesJobImpl) Run(ctx context.Context, job worker.Job) error {
 	logWithId := logrus.WithField("jobId", job.Id())
 
 	var result worker.SearchPackagesJobResult
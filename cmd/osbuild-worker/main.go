@@ -11,9 +11,12 @@ import (
 	"log"
 	"net/url"
 	"os"
+	"os/signal"
 	"path"
 	"runtime/debug"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/coreos/go-systemd/v22/journal"
@@ -31,6 +34,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/upload/oci"
 	"github.com/ondrejbudai/osbuild-composer-public/public/cloud/awscloud"
 	"github.com/ondrejbudai/osbuild-composer-public/public/common"
+	"github.com/ondrejbudai/osbuild-composer-public/public/osbuildexecutor"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 )
 
@@ -49,8 +53,10 @@ type kojiServer struct {
 }
 
 // Represents the implementation of a job type as defined by the worker API.
+// The context passed to Run is canceled when the job is canceled on the
+// composer side; implementations should abort any work in progress and return.
 type JobImplementation interface {
-	Run(job worker.Job) error
+	Run(ctx context.Context, job worker.Job) error
 }
 
 func createTLSConfig(config *connectionConfig) (*tls.Config, error) {
@@ -81,19 +87,27 @@ func createTLSConfig(config *connectionConfig) (*tls.Config, error) {
 	}, nil
 }
 
+// errJobCanceled is the cancellation cause used when osbuild-composer reports
+// that the job the worker is running was canceled.
+var errJobCanceled = errors.New("job was canceled")
+
+// errJobHandedBack is the cancellation cause used when a draining worker
+// gives up on a job it is running and hands it back to osbuild-composer.
+var errJobHandedBack = errors.New("job was handed back")
+
 // Regularly ask osbuild-composer if the compose we're currently working on was
-// canceled and exit the process if it was.
-// It would be cleaner to kill the osbuild process using (`exec.CommandContext`
-// or similar), but osbuild does not currently support this. Exiting here will
-// make systemd clean up the whole cgroup and restart this service.
-func WatchJob(ctx context.Context, job worker.Job) {
+// canceled and cancel the job's context if it was. The job implementation is
+// responsible for stopping osbuild (or the remote executor) and aborting any
+// uploads in progress, after which the worker goes back to requesting jobs.
+func WatchJob(ctx context.Context, job worker.Job, cancel context.CancelCauseFunc) {
 	for {
 		select {
 		case <-time.After(15 * time.Second):
 			canceled, err := job.Canceled()
 			if err == nil && canceled {
-				logrus.Info("Job was canceled. Exiting.")
-				os.Exit(0)
+				logrus.Infof("Job '%s' was canceled. Stopping it.", job.Id())
+				cancel(errJobCanceled)
+				return
 			}
 		case <-ctx.Done():
 			return
@@ -129,11 +143,60 @@ func setProtection(protected bool) {
 	}
 }
 
+// Keeps the instance protected from scaling while at least one of the job
+// slots is running a job.
+type hostProtection struct {
+	mu     sync.Mutex
+	active int
+}
+
+func (p *hostProtection) acquire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.active++
+	if p.active == 1 {
+		setProtection(true)
+	}
+}
+
+func (p *hostProtection) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.active--
+	if p.active == 0 {
+		setProtection(false)
+	}
+}
+
+// Hand the job back to osbuild-composer once handoffCtx is done, so that
+// another worker can pick it up, and stop it afterwards. The job is requeued
+// before it is stopped, because stopping it makes the job implementation
+// report a failure.
+func handBackJob(handoffCtx context.Context, job worker.Job, cancel context.CancelCauseFunc) (stop func() bool) {
+	return context.AfterFunc(handoffCtx, func() {
+		err := job.Requeue()
+		if err != nil {
+			logrus.Errorf("Unable to hand back job '%s' (%s): %v", job.Id(), job.Type(), err)
+			return
+		}
+		cancel(errJobHandedBack)
+	})
+}
+
 // Requests and runs 1 job of specified type(s)
 // Returning an error here will result in the worker backing off for a while and retrying
-func RequestAndRunJob(client *worker.Client, acceptedJobTypes []string, jobImpls map[string]JobImplementation) error {
+//
+// Once drainCtx is done no new job is requested, once handoffCtx is done the
+// running job is handed back to osbuild-composer.
+func RequestAndRunJob(drainCtx, handoffCtx context.Context, client *worker.Client, acceptedJobTypes []string, jobImpls map[string]JobImplementation, protection *hostProtection) error {
 	logrus.Debug("Waiting for a new job...")
-	job, err := client.RequestJob(acceptedJobTypes, arch.Current().String())
+	job, err := client.RequestJob(drainCtx, acceptedJobTypes, arch.Current().String())
+	if err != nil && drainCtx.Err() != nil {
+		logrus.Debug("Worker is draining, not requesting any more jobs")
+		return nil
+	}
 	if err == worker.ErrClientRequestJobTimeout {
 		logrus.Debugf("Requesting job timed out: %v", err)
 		return nil
@@ -152,17 +215,29 @@ func RequestAndRunJob(client *worker.Client, acceptedJobTypes []string, jobImpls
 	// Depsolve requests needs reactivity, since setting the protection can take up to 6s to timeout if the worker isn't
 	// in an AWS env, disable this setting for them.
 	if job.Type() != worker.JobTypeDepsolve {
-		setProtection(true)
-		defer setProtection(false)
+		protection.acquire()
+		defer protection.release()
 	}
 
 	logrus.Infof("Running job '%s' (%s)\n", job.Id(), job.Type()) // DO NOT EDIT/REMOVE: used for Splunk dashboard
 
-	ctx, cancelWatcher := context.WithCancel(context.Background())
-	go WatchJob(ctx, job)
-
-	err = impl.Run(job)
-	cancelWatcher()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	go WatchJob(ctx, job, cancel)
+	stopHandBack := handBackJob(handoffCtx, job, cancel)
+
+	err = impl.Run(ctx, job)
+	stopHandBack()
+	cause := context.Cause(ctx)
+	cancel(nil)
+	if errors.Is(cause, errJobCanceled) {
+		logrus.Infof("Job '%s' (%s) canceled", job.Id(), job.Type())
+		// The job was stopped on purpose, pick up the next one immediately
+		return nil
+	}
+	if errors.Is(cause, errJobHandedBack) {
+		logrus.Infof("Job '%s' (%s) handed back", job.Id(), job.Type())
+		return nil
+	}
 	if err != nil {
 		logrus.Warnf("Job '%s' (%s) failed: %v", job.Id(), job.Type(), err) // DO NOT EDIT/REMOVE: used for Splunk dashboard
 		// Don't return this error so the worker picks up the next job immediately
@@ -173,6 +248,86 @@ func RequestAndRunJob(client *worker.Client, acceptedJobTypes []string, jobImpls
 	return nil
 }
 
+// A job slot requests and runs jobs of the accepted types one at a time.
+// The worker runs several slots in parallel; each of them has its own job
+// implementations, so that slots don't share any mutable state such as the
+// osbuild store or the dnf cache.
+type jobSlot struct {
+	name     string
+	jobImpls map[string]JobImplementation
+	// Called after every job. Returning an error stops the worker.
+	afterJob func() error
+}
+
+// Runs jobs until the worker is drained, in which case it returns nil.
+func (s *jobSlot) run(drainCtx, handoffCtx context.Context, client *worker.Client, protection *hostProtection) error {
+	acceptedJobTypes := []string{}
+	for jt := range s.jobImpls {
+		acceptedJobTypes = append(acceptedJobTypes, jt)
+	}
+
+	logrus.Debugf("Starting job slot %s", s.name)
+	for drainCtx.Err() == nil {
+		err := RequestAndRunJob(drainCtx, handoffCtx, client, acceptedJobTypes, s.jobImpls, protection)
+		if err != nil {
+			logrus.Warnf("Received error from RequestAndRunJob in job slot %s, backing off", s.name)
+			select {
+			case <-time.After(backoffDuration):
+			case <-drainCtx.Done():
+			}
+		}
+
+		if s.afterJob != nil {
+			err = s.afterJob()
+			if err != nil {
+				return fmt.Errorf("job slot %s: %w", s.name, err)
+			}
+		}
+	}
+
+	logrus.Debugf("Job slot %s drained", s.name)
+	return nil
+}
+
+// Waits until the worker has to drain, either because it received SIGTERM or
+// SIGINT or because osbuild-composer marked it as draining. Canceling drain
+// stops the job slots from requesting new jobs. Jobs which are still running
+// after the drain timeout, or when a second signal arrives, are handed back to
+// osbuild-composer by canceling handoff.
+func watchDrain(client *worker.Client, timeout time.Duration, drain, handoff context.CancelFunc) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+
+	select {
+	case sig := <-signals:
+		logrus.Infof("Received %v, draining the worker", sig)
+		err := client.Drain()
+		if err != nil {
+			logrus.Warnf("Unable to mark the worker as draining: %v", err)
+		}
+	case <-client.Draining():
+		logrus.Info("Worker marked as draining by osbuild-composer")
+	}
+	drain()
+
+	select {
+	case <-time.After(timeout):
+	case sig := <-signals:
+		logrus.Infof("Received %v, not waiting for running jobs", sig)
+	}
+	handoff()
+}
+
+// Returns the path a slot uses for a per-slot cache directory. The first slot
+// keeps using the path as it is, so that single slot workers reuse the caches
+// they had before.
+func slotPath(p string, slot int) string {
+	if slot == 0 {
+		return p
+	}
+	return fmt.Sprintf("%s-%d", p, slot)
+}
+
 var run = func() {
 	var unix bool
 	flag.BoolVar(&unix, "unix", false, "Interpret 'address' as a path to a unix domain socket instead of a network address")
@@ -237,6 +392,7 @@ var run = func() {
 		client = worker.NewClientUnix(worker.ClientConfig{
 			BaseURL:  address,
 			BasePath: config.BasePath,
+			Labels:   config.Labels,
 		})
 	} else if config.Authentication != nil {
 		var conf *tls.Config
@@ -282,6 +438,7 @@ var run = func() {
 			ClientSecret: clientSecret,
 			BasePath:     config.BasePath,
 			ProxyURL:     proxy,
+			Labels:       config.Labels,
 		})
 		if err != nil {
 			logrus.Fatalf("Error creating worker client: %v", err)
@@ -310,6 +467,7 @@ var run = func() {
 			TlsConfig: conf,
 			BasePath:  config.BasePath,
 			ProxyURL:  proxy,
+			Labels:    config.Labels,
 		})
 		if err != nil {
 			logrus.Fatalf("Error creating worker client: %v", err)
@@ -354,6 +512,7 @@ var run = func() {
 	var genericS3Bucket = ""
 	var genericS3CABundle = ""
 	var genericS3SkipSSLVerification = false
+	var genericS3CredentialsRefs = map[string]S3CredentialsRef{}
 	if config.GenericS3 != nil {
 		genericS3Credentials = config.GenericS3.Credentials
 		genericS3Endpoint = config.GenericS3.Endpoint
@@ -361,6 +520,14 @@ var run = func() {
 		genericS3Bucket = config.GenericS3.Bucket
 		genericS3CABundle = config.GenericS3.CABundle
 		genericS3SkipSSLVerification = config.GenericS3.SkipSSLVerification
+		for name, ref := range config.GenericS3.CredentialsRefs {
+			genericS3CredentialsRefs[name] = S3CredentialsRef{
+				Creds:               ref.Credentials,
+				Endpoint:            ref.Endpoint,
+				CABundle:            ref.CABundle,
+				SkipSSLVerification: ref.SkipSSLVerification,
+			}
+		}
 	}
 
 	var containersAuthFilePath string
@@ -406,6 +573,50 @@ var run = func() {
 		}
 	}
 
+	var vmwareConfig VMWareConfiguration
+	if config.VMWare != nil {
+		var creds struct {
+			Host     string `toml:"host"`
+			Username string `toml:"username"`
+			Password string `toml:"password"`
+		}
+		_, err := toml.DecodeFile(config.VMWare.Credentials, &creds)
+		if err != nil {
+			logrus.Fatalf("cannot load vmware credentials: %v", err)
+		}
+		vmwareConfig = VMWareConfiguration{
+			Host:     creds.Host,
+			Username: creds.Username,
+			Password: creds.Password,
+		}
+	}
+
+	var sftpConfig SFTPConfiguration
+	if config.SFTP != nil {
+		sftpConfig = SFTPConfiguration{
+			Key: SFTPKey{
+				PrivateKey: config.SFTP.PrivateKey,
+				Username:   config.SFTP.Username,
+			},
+			KnownHosts: config.SFTP.KnownHosts,
+			Keys:       map[string]SFTPKey{},
+		}
+		for name, key := range config.SFTP.Keys {
+			sftpConfig.Keys[name] = SFTPKey{
+				PrivateKey: key.PrivateKey,
+				Username:   key.Username,
+			}
+		}
+	}
+
+	var pulpConfig PulpConfiguration
+	if config.Pulp != nil {
+		pulpConfig = PulpConfiguration{
+			CredsFilePath: config.Pulp.Credentials,
+			ServerAddress: config.Pulp.ServerAddress,
+		}
+	}
+
 	var repositoryMTLSConfig *RepositoryMTLSConfig
 	if config.RepositoryMTLSConfig != nil {
 		baseURL, err := url.Parse(config.RepositoryMTLSConfig.BaseURL)
@@ -430,134 +641,206 @@ var run = func() {
 		}
 	}
 
-	// depsolve and search jobs can be done during other jobs
-	depsolveCtx, depsolveCtxCancel := context.WithCancel(context.Background())
-	solver := depsolvednf.NewBaseSolver(rpmmd_cache)
-	if config.DNFJson != "" {
-		solver.SetDepsolveDNFPath(config.DNFJson)
-	}
-	defer depsolveCtxCancel()
-	go func() {
-		jobImpls := map[string]JobImplementation{
-			worker.JobTypeDepsolve: &DepsolveJobImpl{
-				Solver:               solver,
-				RepositoryMTLSConfig: repositoryMTLSConfig,
-			},
-			worker.JobTypeSearchPackages: &SearchPackagesJobImpl{
-				Solver:               solver,
-				RepositoryMTLSConfig: repositoryMTLSConfig,
-			},
-			worker.JobTypeImageBuilderManifest: &ImageBuilderManifestJobImpl{
-				RepositoryMTLSConfig: repositoryMTLSConfig,
-			},
+	// Credentials for osbuild-worker-executor, used by the remote and
+	// qemu.kvm executors.
+	var executorTLSConfig *tls.Config
+	if config.OSBuildExecutor.CACertFile != "" {
+		executorTLSConfig, err = createTLSConfig(&connectionConfig{
+			CACertFile:     config.OSBuildExecutor.CACertFile,
+			ClientKeyFile:  config.OSBuildExecutor.ClientKeyFile,
+			ClientCertFile: config.OSBuildExecutor.ClientCertFile,
+		})
+		if err != nil {
+			logrus.Fatalf("Error creating TLS config for the executor: %v", err)
 		}
-		acceptedJobTypes := []string{}
-		for jt := range jobImpls {
-			acceptedJobTypes = append(acceptedJobTypes, jt)
+	}
+	executorToken := ""
+	if config.OSBuildExecutor.TokenFile != "" {
+		t, err := os.ReadFile(config.OSBuildExecutor.TokenFile)
+		if err != nil {
+			logrus.Fatalf("Could not read executor token: %v", err)
 		}
+		executorToken = strings.TrimSpace(string(t))
+	}
 
-		for {
-			err := RequestAndRunJob(client, acceptedJobTypes, jobImpls)
-			if err != nil {
-				logrus.Warn("Received error from RequestAndRunJob, backing off")
-				time.Sleep(backoffDuration)
-			}
+	// The pool is shared by all build slots, so that each entry of the
+	// remote hosts runs only one build at a time.
+	var remoteHosts *osbuildexecutor.RemoteHostPool
+	if config.OSBuildExecutor.Type == "remote" {
+		remoteHosts, err = osbuildexecutor.NewRemoteHostPool(config.OSBuildExecutor.Hosts, executorTLSConfig, executorToken)
+		if err != nil {
+			logrus.Fatalf("Could not set up the remote executor: %v", err)
+		}
+	}
 
-			select {
-			case <-depsolveCtx.Done():
-				return
-			default:
-				continue
-			}
+	var slots []*jobSlot
 
+	// cheap jobs get their own slots, so that they don't have to wait for
+	// long running builds to finish
+	for i := 0; i < config.Slots.Light; i++ {
+		solver := depsolvednf.NewBaseSolver(slotPath(rpmmd_cache, i))
+		if config.DNFJson != "" {
+			solver.SetDepsolveDNFPath(config.DNFJson)
 		}
-	}()
-
-	// non-depsolve job
-	jobImpls := map[string]JobImplementation{
-		worker.JobTypeOSBuild: &OSBuildJobImpl{
-			Store:  store,
-			Output: output,
-			OSBuildExecutor: ExecutorConfiguration{
-				Type:       config.OSBuildExecutor.Type,
-				IAMProfile: config.OSBuildExecutor.IAMProfile,
-				KeyName:    config.OSBuildExecutor.KeyName,
-			},
-			KojiServers: kojiServers,
-			GCPConfig:   gcpConfig,
-			AzureConfig: azureConfig,
-			OCIConfig:   ociConfig,
-			AWSCreds:    awsCredentials,
-			AWSS3Creds:  awsS3Credentials,
-			AWSBucket:   awsBucket,
-			S3Config: S3Configuration{
-				Creds:               genericS3Credentials,
-				Endpoint:            genericS3Endpoint,
-				Region:              genericS3Region,
-				Bucket:              genericS3Bucket,
-				CABundle:            genericS3CABundle,
-				SkipSSLVerification: genericS3SkipSSLVerification,
-			},
-			ContainersConfig: ContainersConfiguration{
-				AuthFilePath: containersAuthFilePath,
-				Domain:       containersDomain,
-				PathPrefix:   containersPathPrefix,
-				CertPath:     containersCertPath,
-				TLSVerify:    &containersTLSVerify,
+		slots = append(slots, &jobSlot{
+			name: fmt.Sprintf("light-%d", i),
+			jobImpls: map[string]JobImplementation{
+				worker.JobTypeDepsolve: &DepsolveJobImpl{
+					Solver:               solver,
+					RepositoryMTLSConfig: repositoryMTLSConfig,
+				},
+				worker.JobTypeSearchPackages: &SearchPackagesJobImpl{
+					Solver:               solver,
+					RepositoryMTLSConfig: repositoryMTLSConfig,
+				},
+				worker.JobTypeImageBuilderManifest: &ImageBuilderManifestJobImpl{
+					RepositoryMTLSConfig: repositoryMTLSConfig,
+				},
+				worker.JobTypeContainerResolve: &ContainerResolveJobImpl{
+					AuthFilePath: containersAuthFilePath,
+				},
+				worker.JobTypeOSTreeResolve: &OSTreeResolveJobImpl{
+					RepositoryMTLSConfig: repositoryMTLSConfig,
+				},
+				worker.JobTypeFileResolve: &FileResolveJobImpl{},
 			},
-			RepositoryMTLSConfig: repositoryMTLSConfig,
-		},
-		worker.JobTypeKojiInit: &KojiInitJobImpl{
-			KojiServers: kojiServers,
-		},
-		worker.JobTypeKojiFinalize: &KojiFinalizeJobImpl{
-			KojiServers: kojiServers,
-		},
-		worker.JobTypeContainerResolve: &ContainerResolveJobImpl{
-			AuthFilePath: containersAuthFilePath,
-		},
-		worker.JobTypeOSTreeResolve: &OSTreeResolveJobImpl{
-			RepositoryMTLSConfig: repositoryMTLSConfig,
-		},
-		worker.JobTypeFileResolve: &FileResolveJobImpl{},
-		worker.JobTypeAWSEC2Copy: &AWSEC2CopyJobImpl{
-			AWSCreds: awsCredentials,
-		},
-		worker.JobTypeAWSEC2Share: &AWSEC2ShareJobImpl{
-			AWSCreds: awsCredentials,
-		},
-		worker.JobTypeBootcInfoResolve: &BootcInfoResolveJobImpl{
-			CleanupImages: config.BootcInfoResolve != nil && config.BootcInfoResolve.CleanupImages,
-		},
+		})
 	}
 
-	acceptedJobTypes := []string{}
-	for jt := range jobImpls {
-		acceptedJobTypes = append(acceptedJobTypes, jt)
+	// With the store cache all build slots share the store, otherwise
+	// every slot has its own.
+	var cache *storeCache
+	if config.StoreCache != nil {
+		cache = newStoreCache(store, int64(config.StoreCache.MaxSize.Uint64()))
 	}
 
-	for {
-		err = RequestAndRunJob(client, acceptedJobTypes, jobImpls)
-		if err != nil {
-			logrus.Warn("Received error from RequestAndRunJob, backing off")
-			time.Sleep(backoffDuration)
+	for i := 0; i < config.Slots.Build; i++ {
+		slotStore := slotPath(store, i)
+		if cache != nil {
+			slotStore = store
+		}
+		slot := &jobSlot{
+			name: fmt.Sprintf("build-%d", i),
+			jobImpls: map[string]JobImplementation{
+				worker.JobTypeOSBuild: &OSBuildJobImpl{
+					Store:      slotStore,
+					StoreCache: cache,
+					Output:     output,
+					OSBuildExecutor: ExecutorConfiguration{
+						Type:        config.OSBuildExecutor.Type,
+						IAMProfile:  config.OSBuildExecutor.IAMProfile,
+						KeyName:     config.OSBuildExecutor.KeyName,
+						RemoteHosts: remoteHosts,
+						QEMU: osbuildexecutor.QEMUOptions{
+							Image:     config.OSBuildExecutor.Image,
+							Memory:    config.OSBuildExecutor.Memory,
+							CPUs:      config.OSBuildExecutor.CPUs,
+							Binary:    config.OSBuildExecutor.QEMUBinary,
+							Firmware:  config.OSBuildExecutor.Firmware,
+							TLSConfig: executorTLSConfig,
+							Token:     executorToken,
+						},
+					},
+					KojiServers: kojiServers,
+					GCPConfig:   gcpConfig,
+					AzureConfig: azureConfig,
+					OCIConfig:   ociConfig,
+					AWSCreds:    awsCredentials,
+					AWSS3Creds:  awsS3Credentials,
+					AWSBucket:   awsBucket,
+					S3Config: S3Configuration{
+						Creds:               genericS3Credentials,
+						Endpoint:            genericS3Endpoint,
+						Region:              genericS3Region,
+						Bucket:              genericS3Bucket,
+						CABundle:            genericS3CABundle,
+						SkipSSLVerification: genericS3SkipSSLVerification,
+						CredentialsRefs:     genericS3CredentialsRefs,
+					},
+					ContainersConfig: ContainersConfiguration{
+						AuthFilePath: containersAuthFilePath,
+						Domain:       containersDomain,
+						PathPrefix:   containersPathPrefix,
+						CertPath:     containersCertPath,
+						TLSVerify:    &containersTLSVerify,
+					},
+					PulpConfig:           pulpConfig,
+					VMWareConfig:         vmwareConfig,
+					SFTPConfig:           sftpConfig,
+					RepositoryMTLSConfig: repositoryMTLSConfig,
+				},
+				worker.JobTypeKojiInit: &KojiInitJobImpl{
+					KojiServers: kojiServers,
+				},
+				worker.JobTypeKojiFinalize: &KojiFinalizeJobImpl{
+					KojiServers: kojiServers,
+				},
+				worker.JobTypeAWSEC2Copy: &AWSEC2CopyJobImpl{
+					AWSCreds: awsCredentials,
+				},
+				worker.JobTypeAWSEC2Share: &AWSEC2ShareJobImpl{
+					AWSCreds: awsCredentials,
+				},
+				worker.JobTypeBootcInfoResolve: &BootcInfoResolveJobImpl{
+					CleanupImages: config.BootcInfoResolve != nil && config.BootcInfoResolve.CleanupImages,
+				},
+			},
 		}
 		if config.CleanStore {
-			err := os.RemoveAll(store)
-			if err != nil {
-				// Shut down the worker in case of failure, as sharing stores
-				// between jobs should be avoided at all costs.
-				logrus.Errorf("Unable to clean out store: %s", err)
-
-				err = awscloud.ShutdownSelf()
+			slot.afterJob = func() error {
+				err := os.RemoveAll(slotStore)
 				if err != nil {
-					logrus.Errorf("Unable to shut self down: %v", err)
+					return fmt.Errorf("unable to clean out store: %w", err)
 				}
-				stopSelf()
-				return
+				return nil
 			}
 		}
+		slots = append(slots, slot)
+	}
+
+	logrus.Infof("Running %d build and %d light job slots", config.Slots.Build, config.Slots.Light)
+
+	// All slots share the client and with it the worker registration and
+	// its heartbeat. Every job is watched by its own slot.
+	protection := &hostProtection{}
+	drainCtx, drain := context.WithCancel(context.Background())
+	defer drain()
+	handoffCtx, handoff := context.WithCancel(context.Background())
+	defer handoff()
+	go watchDrain(client, config.DrainTimeout, drain, handoff)
+
+	slotErrs := make(chan error)
+	for _, slot := range slots {
+		go func(slot *jobSlot) {
+			slotErrs <- slot.run(drainCtx, handoffCtx, client, protection)
+		}(slot)
+	}
+
+	// Slots stop with an error when cleaning the store failed. Shut down the
+	// worker in that case, as sharing stores between jobs should be avoided
+	// at all costs.
+	for range slots {
+		err = <-slotErrs
+		if err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = client.Deregister()
+		if err != nil {
+			logrus.Errorf("Unable to deregister the worker: %v", err)
+			return
+		}
+		logrus.Info("Worker drained and deregistered")
+		return
+	}
+	logrus.Errorf("Stopping the worker: %v", err)
+
+	err = awscloud.ShutdownSelf()
+	if err != nil {
+		logrus.Errorf("Unable to shut self down: %v", err)
 	}
+	stopSelf()
 }
 
 func stopSelf() {
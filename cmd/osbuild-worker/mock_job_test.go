@@ -58,6 +58,10 @@ func (j *mockJob) UploadArtifact(string, io.ReadSeeker) error {
 	return nil
 }
 
+func (j *mockJob) Requeue() error {
+	return nil
+}
+
 func (j *mockJob) Finish(result interface{}) error {
 	j.finishCalled = true
 	if j.finishErr != nil {
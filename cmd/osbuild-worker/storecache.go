@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/osbuildexecutor"
+	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
+)
+
+// storeCache is an osbuild store shared by all build slots of the worker,
+// so that the sources downloaded for one compose are reused by the next ones.
+// osbuild keeps the store consistent when several builds use it at the same
+// time and bounds the size of the objects it caches in it. The sources are
+// bounded here: when the store grows over maxSize, the least recently used
+// sources no running build refers to are removed.
+type storeCache struct {
+	dir     string
+	maxSize int64
+
+	mu sync.Mutex
+	// sources the running builds refer to by their checksum, with the
+	// number of builds using each of them
+	pinned map[string]int
+}
+
+func newStoreCache(dir string, maxSize int64) *storeCache {
+	return &storeCache{
+		dir:     dir,
+		maxSize: maxSize,
+		pinned:  make(map[string]int),
+	}
+}
+
+// Returns the checksums of all the sources of the manifest.
+func manifestSources(manifest []byte) ([]string, error) {
+	var m struct {
+		Sources map[string]struct {
+			Items map[string]json.RawMessage `json:"items"`
+		} `json:"sources"`
+	}
+	if err := json.Unmarshal(manifest, &m); err != nil {
+		return nil, fmt.Errorf("cannot read the sources of the manifest: %w", err)
+	}
+
+	var checksums []string
+	for _, source := range m.Sources {
+		for checksum := range source.Items {
+			checksums = append(checksums, checksum)
+		}
+	}
+	sort.Strings(checksums)
+	return checksums, nil
+}
+
+// Sources are stored as sources/<source type>/<checksum>. Entries which
+// aren't named after a checksum, e.g. the ostree repository, are never
+// touched by the cache.
+func isCachedSource(name string) bool {
+	return strings.Contains(name, ":")
+}
+
+// Returns the paths of the cached sources by checksum.
+func (c *storeCache) sources() map[string]string {
+	paths := make(map[string]string)
+	types, err := os.ReadDir(filepath.Join(c.dir, "sources"))
+	if err != nil {
+		return paths
+	}
+	for _, t := range types {
+		if !t.IsDir() {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(c.dir, "sources", t.Name()))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if isCachedSource(e.Name()) {
+				paths[e.Name()] = filepath.Join(c.dir, "sources", t.Name(), e.Name())
+			}
+		}
+	}
+	return paths
+}
+
+// acquire protects the sources of the manifest from being evicted until
+// the returned release function is called and reports how many of them were
+// already cached. release marks the sources as used and evicts the least
+// recently used ones if the store got too big.
+func (c *storeCache) acquire(manifest []byte) (release func(), stats worker.StoreCacheStats, err error) {
+	checksums, err := manifestSources(manifest)
+	if err != nil {
+		return nil, stats, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached := c.sources()
+	for _, checksum := range checksums {
+		c.pinned[checksum]++
+		if _, ok := cached[checksum]; ok {
+			stats.Hits++
+		} else {
+			stats.Misses++
+		}
+	}
+
+	release = func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		now := time.Now()
+		cached := c.sources()
+		for _, checksum := range checksums {
+			c.pinned[checksum]--
+			if c.pinned[checksum] == 0 {
+				delete(c.pinned, checksum)
+			}
+			if path, ok := cached[checksum]; ok {
+				if err := os.Chtimes(path, now, now); err != nil {
+					logrus.Warnf("Unable to mark source %s as used: %v", checksum, err)
+				}
+			}
+		}
+		c.evict()
+	}
+	return release, stats, nil
+}
+
+type cachedSource struct {
+	checksum string
+	path     string
+	size     int64
+	lastUsed time.Time
+}
+
+// evict removes the least recently used sources until the store fits into
+// maxSize, sources of running builds are kept. Needs to be called with the
+// lock held.
+func (c *storeCache) evict() {
+	size := osbuildexecutor.DiskUsage(c.dir)
+	if size <= c.maxSize {
+		return
+	}
+
+	var candidates []cachedSource
+	for checksum, path := range c.sources() {
+		if c.pinned[checksum] > 0 {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		candidates = append(candidates, cachedSource{
+			checksum: checksum,
+			path:     path,
+			size:     osbuildexecutor.DiskUsage(path),
+			lastUsed: info.ModTime(),
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].lastUsed.Before(candidates[j].lastUsed)
+	})
+
+	var evicted int
+	for _, source := range candidates {
+		if size <= c.maxSize {
+			break
+		}
+		if err := os.RemoveAll(source.path); err != nil {
+			logrus.Warnf("Unable to evict source %s from the store: %v", source.checksum, err)
+			continue
+		}
+		size -= source.size
+		evicted++
+	}
+	logrus.Infof("Evicted %d sources from the store, %d bytes are left, the limit is %d bytes", evicted, size, c.maxSize)
+	if size > c.maxSize {
+		logrus.Warnf("The store is still over its size limit, the running builds or osbuild's own cache need the space")
+	}
+}
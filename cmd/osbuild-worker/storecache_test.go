@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
+)
+
+func makeSourcesManifest(checksums ...string) []byte {
+	items := ""
+	for i, checksum := range checksums {
+		if i > 0 {
+			items += ","
+		}
+		items += fmt.Sprintf(`"%s": {"url": "https://example.com/%d.rpm"}`, checksum, i)
+	}
+	return []byte(fmt.Sprintf(`{"version": "2", "pipelines": [], "sources": {"org.osbuild.curl": {"items": {%s}}}}`, items))
+}
+
+// addSource puts a source of the given size into the store and marks it as
+// last used at the given time.
+func addSource(t *testing.T, store, checksum string, size int, lastUsed time.Time) string {
+	path := filepath.Join(store, "sources", "org.osbuild.files", checksum)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0600))
+	require.NoError(t, os.Chtimes(path, lastUsed, lastUsed))
+	return path
+}
+
+func TestStoreCacheHits(t *testing.T) {
+	store := t.TempDir()
+	addSource(t, store, "sha256:aaaa", 4096, time.Now())
+
+	cache := newStoreCache(store, 1<<30)
+	release, stats, err := cache.acquire(makeSourcesManifest("sha256:aaaa", "sha256:bbbb", "sha256:cccc"))
+	require.NoError(t, err)
+	assert.Equal(t, worker.StoreCacheStats{Hits: 1, Misses: 2}, stats)
+
+	// osbuild downloaded the missing sources
+	addSource(t, store, "sha256:bbbb", 4096, time.Now())
+	addSource(t, store, "sha256:cccc", 4096, time.Now())
+	release()
+
+	release, stats, err = cache.acquire(makeSourcesManifest("sha256:aaaa", "sha256:bbbb", "sha256:cccc"))
+	require.NoError(t, err)
+	assert.Equal(t, worker.StoreCacheStats{Hits: 3, Misses: 0}, stats)
+	release()
+
+	_, _, err = cache.acquire([]byte("not a manifest"))
+	assert.Error(t, err)
+}
+
+func TestStoreCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	store := t.TempDir()
+	now := time.Now()
+	oldest := addSource(t, store, "sha256:oldest", 64*1024, now.Add(-3*time.Hour))
+	old := addSource(t, store, "sha256:old", 64*1024, now.Add(-2*time.Hour))
+	pinned := addSource(t, store, "sha256:pinned", 64*1024, now.Add(-4*time.Hour))
+	recent := addSource(t, store, "sha256:recent", 64*1024, now.Add(-time.Hour))
+	// not named after a checksum, never evicted
+	repo := filepath.Join(store, "sources", "org.osbuild.ostree", "repo")
+	require.NoError(t, os.MkdirAll(repo, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "config"), make([]byte, 64*1024), 0600))
+
+	// room for about three of the sources and the directories
+	cache := newStoreCache(store, 300*1024)
+	releaseRunning, _, err := cache.acquire(makeSourcesManifest("sha256:pinned"))
+	require.NoError(t, err)
+
+	release, stats, err := cache.acquire(makeSourcesManifest("sha256:new"))
+	require.NoError(t, err)
+	assert.Equal(t, worker.StoreCacheStats{Hits: 0, Misses: 1}, stats)
+	added := addSource(t, store, "sha256:new", 64*1024, now)
+	release()
+
+	assert.NoFileExists(t, oldest)
+	assert.NoFileExists(t, old)
+	assert.FileExists(t, pinned)
+	assert.FileExists(t, recent)
+	assert.FileExists(t, added)
+	assert.DirExists(t, repo)
+
+	// releasing the sources marks them as used, the source which wasn't
+	// used for the longest time goes next
+	releaseRunning()
+	release, _, err = cache.acquire(makeSourcesManifest("sha256:newer"))
+	require.NoError(t, err)
+	newer := addSource(t, store, "sha256:newer", 64*1024, now)
+	release()
+
+	assert.FileExists(t, pinned)
+	assert.NoFileExists(t, recent)
+	assert.FileExists(t, added)
+	assert.FileExists(t, newer)
+}
@@ -29,7 +29,27 @@ const (
 	sqlListen   = `LISTEN jobs`
 	sqlUnlisten = `UNLISTEN jobs`
 
-	sqlEnqueue = `INSERT INTO jobs(id, type, args, queued_at, channel) VALUES ($1, $2, $3, statement_timestamp(), $4)`
+	// $6 delays the job by the given number of seconds
+	sqlEnqueue = `
+		INSERT INTO jobs(id, type, args, queued_at, channel, priority, not_before, required_labels)
+		VALUES ($1, $2, $3, statement_timestamp(), $4, $5,
+		  CASE WHEN $6::float8 > 0 THEN statement_timestamp() + make_interval(secs => $6::float8) END, $7)`
+
+	// Both dequeue queries take the same parameters:
+	//   $1: the token of the dequeued job
+	//   $2: the accepted job types
+	//   $3: whether fair-share scheduling is enabled
+	//   $4, $5: channel weights for fair-share scheduling, as parallel arrays
+	//           of channels and weights. Channels without a weight default to 1.
+	//   $6: base job types which are subject to quotas
+	//   $7, $8: max running jobs of channels, as parallel arrays
+	//   $9: max running jobs of channels which are not in $7
+	// sqlDequeue additionally takes the accepted channels as $10 and the
+	// id of the dequeuing worker as $11. Only jobs whose required labels are
+	// a subset of the worker's labels are dequeued, and none at all when the
+	// worker is draining.
+	// Quotas are best-effort: two concurrent dequeues of the last free slot
+	// of a channel can both succeed.
 	sqlDequeue = `
 		UPDATE jobs
 		SET token = $1, started_at = statement_timestamp()
@@ -38,7 +58,19 @@ const (
 		  FROM ready_jobs
 			  -- use ANY here, because "type in ()" doesn't work with bound parameters
 			  -- literal syntax for this is '{"a", "b"}': https://www.postgresql.org/docs/13/arrays.html
-		  WHERE type = ANY($2) AND channel = ANY($3)
+		  WHERE type = ANY($2) AND channel = ANY($10)
+		    AND required_labels <@ COALESCE((
+		      SELECT labels
+		      FROM workers
+		      WHERE worker_id = $11
+		    ), '{}')
+		    AND NOT COALESCE((
+		      SELECT draining
+		      FROM workers
+		      WHERE worker_id = $11
+		    ), FALSE)
+		    AND NOT ` + sqlOverQuota + `
+		  ORDER BY ` + sqlFairShareLoad + ` ASC, priority DESC, queued_at ASC
 		  LIMIT 1
 		  FOR UPDATE SKIP LOCKED
 		)
@@ -51,11 +83,46 @@ const (
 		  SELECT id
 		  FROM ready_jobs
 		  WHERE type = ANY($2)
+		    AND NOT ` + sqlOverQuota + `
+		  ORDER BY ` + sqlFairShareLoad + ` ASC, priority DESC, queued_at ASC
 		  LIMIT 1
 		  FOR UPDATE SKIP LOCKED
 		)
 		RETURNING id, type, args`
 
+	// The number of running jobs of a ready job's channel divided by the
+	// channel's weight, or 0 without fair-share scheduling.
+	sqlFairShareLoad = `
+		  CASE WHEN $3::boolean THEN (
+		    SELECT COUNT(*)
+		    FROM jobs running
+		    WHERE running.channel = ready_jobs.channel
+		      AND running.started_at IS NOT NULL AND running.finished_at IS NULL AND running.canceled = FALSE
+		  ) / COALESCE((
+		    SELECT w.weight
+		    FROM unnest($4::text[], $5::float8[]) AS w(channel, weight)
+		    WHERE w.channel = ready_jobs.channel
+		  ), 1) ELSE 0 END`
+
+	// Whether a ready job is subject to quotas and its channel already runs
+	// the maximum number of such jobs.
+	sqlOverQuota = `COALESCE(
+		    split_part(ready_jobs.type, ':', 1) = ANY($6::text[])
+		    AND ` + sqlMaxRunning + ` > 0
+		    AND (
+		      SELECT COUNT(*)
+		      FROM jobs running
+		      WHERE running.channel = ready_jobs.channel
+		        AND split_part(running.type, ':', 1) = ANY($6::text[])
+		        AND running.started_at IS NOT NULL AND running.finished_at IS NULL AND running.canceled = FALSE
+		    ) >= ` + sqlMaxRunning + `, FALSE)`
+
+	sqlMaxRunning = `COALESCE((
+		      SELECT l.max_running
+		      FROM unnest($7::text[], $8::int[]) AS l(channel, max_running)
+		      WHERE l.channel = ready_jobs.channel
+		    ), $9::int)`
+
 	sqlDequeueByID = `
 		UPDATE jobs
 		SET token = $1, started_at = statement_timestamp()
@@ -68,11 +135,25 @@ const (
 		)
 		RETURNING token, type, args, queued_at, started_at`
 
+	// $2 delays the job by the given number of seconds
 	sqlRequeue = `
 		UPDATE jobs
-		SET started_at = NULL, token = NULL, retries = retries + 1
+		SET started_at = NULL, token = NULL, retries = retries + 1,
+		  not_before = CASE WHEN $2::float8 > 0 THEN statement_timestamp() + make_interval(secs => $2::float8) END
 		WHERE id = $1 AND started_at IS NOT NULL AND finished_at IS NULL`
 
+	sqlRequeueNoRetry = `
+		UPDATE jobs
+		SET started_at = NULL, token = NULL, not_before = NULL
+		WHERE id = $1 AND started_at IS NOT NULL AND finished_at IS NULL`
+
+	// seconds until the next delayed job becomes ready, or NULL if there
+	// are no delayed jobs
+	sqlQueryNextDelayedJob = `
+		SELECT EXTRACT(EPOCH FROM MIN(not_before) - statement_timestamp())::float8
+		FROM jobs
+		WHERE started_at IS NULL AND canceled = FALSE AND not_before > statement_timestamp()`
+
 	sqlDelete = `
 		DELETE FROM jobs
 		WHERE id = $1`
@@ -92,6 +173,39 @@ const (
 
 	sqlQueryListJobs = `
 		SELECT id from jobs`
+	sqlCountJobs = `
+		SELECT
+		  COUNT(*) FILTER (WHERE started_at IS NULL),
+		  COUNT(*) FILTER (WHERE started_at IS NOT NULL)
+		FROM jobs
+		WHERE channel = $1 AND finished_at IS NULL AND canceled = FALSE
+		  AND (cardinality($2::text[]) = 0 OR split_part(type, ':', 1) = ANY($2))
+		  AND (NOT $3 OR NOT EXISTS (
+		    SELECT 1
+		    FROM job_dependencies
+		    WHERE dependency_id = jobs.id
+		  ))`
+	sqlListJobs = `
+		SELECT id, queued_at
+		FROM jobs
+		WHERE channel = $1
+		  AND (cardinality($2::text[]) = 0 OR split_part(type, ':', 1) = ANY($2))
+		  AND (NOT $3 OR NOT EXISTS (
+		    SELECT 1
+		    FROM job_dependencies
+		    WHERE dependency_id = jobs.id
+		  ))
+		  AND (cardinality($4::text[]) = 0 OR (CASE
+		    WHEN canceled THEN 'canceled'
+		    WHEN finished_at IS NOT NULL THEN 'finished'
+		    WHEN started_at IS NOT NULL THEN 'running'
+		    ELSE 'pending'
+		  END) = ANY($4))
+		  AND ($5::timestamp IS NULL OR queued_at > $5)
+		  AND ($6::timestamp IS NULL OR queued_at < $6)
+		  AND ($7::timestamp IS NULL OR (queued_at, id) < ($7, $8::uuid))
+		ORDER BY queued_at DESC, id DESC
+		LIMIT $9`
 	sqlQueryJob = `
 		SELECT type, args, channel, started_at, finished_at, retries, canceled
 		FROM jobs
@@ -119,12 +233,39 @@ const (
 		WHERE id = $1 AND finished_at IS NULL
 		RETURNING type, started_at`
 
+	sqlQueryJobExists = `
+		SELECT EXISTS (SELECT 1 FROM jobs WHERE id = $1)`
+	sqlCancelJobTree = `
+		WITH RECURSIVE tree(id) AS (
+		    SELECT $1::uuid
+		  UNION
+		    SELECT CASE WHEN d.job_id = tree.id THEN d.dependency_id ELSE d.job_id END
+		    FROM job_dependencies d
+		    JOIN tree ON d.job_id = tree.id OR d.dependency_id = tree.id
+		)
+		UPDATE jobs
+		SET canceled = TRUE
+		WHERE id IN (SELECT id FROM tree) AND finished_at IS NULL AND canceled = FALSE
+		RETURNING id, type`
+
 	sqlFailJob = `
 		UPDATE jobs
 		SET token = $2, started_at = now(), finished_at = now(), result = $3
 		WHERE id = $1 AND finished_at IS NULL AND started_at IS NULL AND token IS NULL
 		RETURNING id, type`
 
+	sqlExportJobs = `
+		SELECT id, token, type, channel, args, result, priority, retries, canceled,
+		  queued_at, not_before, started_at, finished_at, required_labels
+		FROM jobs`
+	sqlExportDependencies = `
+		SELECT job_id, dependency_id
+		FROM job_dependencies`
+	sqlImportJob = `
+		INSERT INTO jobs(id, token, type, channel, args, result, priority, retries, canceled,
+		  queued_at, not_before, started_at, finished_at, required_labels)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
 	sqlInsertHeartbeat = `
 		INSERT INTO heartbeats(token, id, heartbeat)
 		VALUES ($1, $2, now())`
@@ -148,14 +289,19 @@ const (
 		WHERE worker_id = $1`
 
 	sqlInsertWorker = `
-		INSERT INTO workers(worker_id, channel, arch, heartbeat)
-		VALUES($1, $2, $3, now())`
+		INSERT INTO workers(worker_id, channel, arch, labels, heartbeat)
+		VALUES($1, $2, $3, $4, now())`
 	sqlUpdateWorkerStatus = `
 		UPDATE workers
 		SET heartbeat = now()
+		WHERE worker_id = $1
+		RETURNING draining`
+	sqlSetWorkerDraining = `
+		UPDATE workers
+		SET draining = TRUE
 		WHERE worker_id = $1`
 	sqlQueryWorkers = `
-		SELECT worker_id, channel, arch
+		SELECT worker_id, channel, arch, labels, draining
 		FROM workers
 		WHERE age(now(), heartbeat) > $1`
 	sqlDeleteWorker = `
@@ -176,6 +322,12 @@ type DBJobQueue struct {
 	pool         *pgxpool.Pool
 	dequeuers    *dequeuers
 	stopListener func()
+
+	// arguments of sqlDequeue and sqlDequeueAnyChannel which depend on the
+	// scheduling configuration, see the description of sqlDequeue
+	schedulingArgs []any
+
+	retryBackoff jobqueue.RetryBackoff
 }
 
 // thread-safe list of dequeuers
@@ -220,11 +372,23 @@ func (d *dequeuers) notifyAll() {
 	}
 }
 
+// labelsArg returns labels as a query argument for a NOT NULL text[]
+// column, pgx encodes a nil slice as NULL.
+func labelsArg(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
+
 // Config allows more detailed customization of queue behavior
 type Config struct {
 	// Logger is used for all logging of the queue, when not provided, the stanard
 	// global logger (logrus) is used.
 	Logger jobqueue.SimpleLogger
+
+	// Scheduling configures the order in which ready jobs are dequeued.
+	Scheduling jobqueue.SchedulingConfig
 }
 
 // New creates a new DBJobQueue object for `url` with default configuration.
@@ -238,6 +402,11 @@ func New(url string) (*DBJobQueue, error) {
 
 // NewWithLogger creates a new DBJobQueue object for `url` with specific configuration.
 func NewWithConfig(url string, config Config) (*DBJobQueue, error) {
+	err := config.Scheduling.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduling configuration: %v", err)
+	}
+
 	pool, err := pgxpool.New(context.Background(), url)
 	if err != nil {
 		return nil, fmt.Errorf("error establishing connection: %v", err)
@@ -249,6 +418,30 @@ func NewWithConfig(url string, config Config) (*DBJobQueue, error) {
 		pool:         pool,
 		dequeuers:    newDequeuers(),
 		stopListener: cancel,
+		retryBackoff: config.Scheduling.RetryBackoff,
+	}
+
+	// Always pass non-nil slices, pgx encodes nil slices as NULL, which
+	// doesn't work with ANY() and unnest().
+	weightChannels, weights := []string{}, []float64{}
+	for channel, weight := range config.Scheduling.ChannelWeights {
+		weightChannels = append(weightChannels, channel)
+		weights = append(weights, weight)
+	}
+	quotaJobTypes := append([]string{}, config.Scheduling.Quotas.JobTypes...)
+	quotaChannels, quotaLimits := []string{}, []int{}
+	for channel := range config.Scheduling.Quotas.MaxRunningJobs {
+		quotaChannels = append(quotaChannels, channel)
+		quotaLimits = append(quotaLimits, config.Scheduling.Quotas.MaxRunning(channel))
+	}
+	q.schedulingArgs = []any{
+		config.Scheduling.FairShare(),
+		weightChannels,
+		weights,
+		quotaJobTypes,
+		quotaChannels,
+		quotaLimits,
+		max(config.Scheduling.Quotas.DefaultMaxRunningJobs, 0),
 	}
 
 	listenerReady := make(chan struct{})
@@ -323,6 +516,10 @@ func (q *DBJobQueue) Close() {
 }
 
 func (q *DBJobQueue) Enqueue(jobType string, args interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return q.EnqueueWithOptions(jobType, args, dependencies, channel, jobqueue.EnqueueOptions{})
+}
+
+func (q *DBJobQueue) EnqueueWithOptions(jobType string, args interface{}, dependencies []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("error connecting to database: %v", err)
@@ -341,7 +538,11 @@ func (q *DBJobQueue) Enqueue(jobType string, args interface{}, dependencies []uu
 	}()
 
 	id := uuid.New()
-	_, err = tx.Exec(context.Background(), sqlEnqueue, id, jobType, args, channel)
+	var delay float64
+	if !opts.NotBefore.IsZero() {
+		delay = time.Until(opts.NotBefore).Seconds()
+	}
+	_, err = tx.Exec(context.Background(), sqlEnqueue, id, jobType, args, channel, opts.Priority, delay, labelsArg(opts.RequiredLabels))
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("error enqueuing job: %v", err)
 	}
@@ -363,7 +564,7 @@ func (q *DBJobQueue) Enqueue(jobType string, args interface{}, dependencies []uu
 		return uuid.Nil, fmt.Errorf("unable to commit database transaction: %v", err)
 	}
 
-	q.logger.Info("Enqueued job", "job_type", jobType, "job_id", id.String(), "job_dependencies", fmt.Sprintf("%+v", dependencies))
+	q.logger.Info("Enqueued job", "job_type", jobType, "job_id", id.String(), "job_dependencies", fmt.Sprintf("%+v", dependencies), "job_priority", fmt.Sprintf("%d", opts.Priority))
 
 	return id, nil
 }
@@ -386,14 +587,47 @@ func (q *DBJobQueue) dequeueLoop(ctx context.Context, tryFn func(ctx context.Con
 			}
 			return uuid.Nil, nil, "", nil, fmt.Errorf("error dequeuing job: %v", err)
 		}
+
+		// Nothing notifies the dequeuers when a delayed job becomes ready,
+		// wake up on our own.
+		var wakeup <-chan time.Time
+		var timer *time.Timer
+		delay, err := q.nextDelayedJob(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				q.logger.Error(err, "Error querying the next delayed job")
+			}
+		} else if delay > 0 {
+			timer = time.NewTimer(delay)
+			wakeup = timer.C
+		}
+
 		select {
 		case <-c:
+		case <-wakeup:
 		case <-ctx.Done():
 			return uuid.Nil, nil, "", nil, jobqueue.ErrDequeueTimeout
 		}
+		if timer != nil {
+			timer.Stop()
+		}
 	}
 }
 
+// nextDelayedJob returns the time until the next delayed job becomes ready,
+// or 0 if there are no delayed jobs.
+func (q *DBJobQueue) nextDelayedJob(ctx context.Context) (time.Duration, error) {
+	var seconds *float64
+	err := q.pool.QueryRow(ctx, sqlQueryNextDelayedJob).Scan(&seconds)
+	if err != nil {
+		return 0, err
+	}
+	if seconds == nil {
+		return 0, nil
+	}
+	return time.Duration(*seconds * float64(time.Second)), nil
+}
+
 // tryDequeue is a helper function that tries to dequeue a job from the database.
 // It returns the job's id, dependencies, type, and arguments, or an error.
 // This method returns pgx.ErrNoRows if the method didn't manage to dequeue
@@ -457,7 +691,8 @@ func (q *DBJobQueue) tryDequeue(ctx context.Context, token, workerID uuid.UUID,
 func (q *DBJobQueue) Dequeue(ctx context.Context, workerID uuid.UUID, jobTypes, channels []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	token := uuid.New()
 	id, deps, jobType, args, err := q.dequeueLoop(ctx, func(ctx context.Context) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
-		return q.tryDequeue(ctx, token, workerID, sqlDequeue, token, jobTypes, channels)
+		args := append(append([]any{token, jobTypes}, q.schedulingArgs...), channels, workerID)
+		return q.tryDequeue(ctx, token, workerID, sqlDequeue, args...)
 	})
 	if err != nil {
 		return uuid.Nil, uuid.Nil, nil, "", nil, err
@@ -468,7 +703,8 @@ func (q *DBJobQueue) Dequeue(ctx context.Context, workerID uuid.UUID, jobTypes,
 func (q *DBJobQueue) DequeueAnyChannel(ctx context.Context, workerID uuid.UUID, jobTypes []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	token := uuid.New()
 	id, deps, jobType, args, err := q.dequeueLoop(ctx, func(ctx context.Context) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
-		return q.tryDequeue(ctx, token, workerID, sqlDequeueAnyChannel, token, jobTypes)
+		args := append([]any{token, jobTypes}, q.schedulingArgs...)
+		return q.tryDequeue(ctx, token, workerID, sqlDequeueAnyChannel, args...)
 	})
 	if err != nil {
 		return uuid.Nil, uuid.Nil, nil, "", nil, err
@@ -644,7 +880,8 @@ func (q *DBJobQueue) RequeueOrFinishJob(id uuid.UUID, maxRetries uint64, result
 			return false, fmt.Errorf("error finishing job %s: %w", id, err)
 		}
 	} else {
-		tag, err = tx.Exec(context.Background(), sqlRequeue, id)
+		delay := q.retryBackoff.Delay(retries + 1)
+		tag, err = tx.Exec(context.Background(), sqlRequeue, id, delay.Seconds())
 		if err != nil {
 			return false, fmt.Errorf("error requeueing job %s: %w", id, err)
 		}
@@ -673,6 +910,68 @@ func (q *DBJobQueue) RequeueOrFinishJob(id uuid.UUID, maxRetries uint64, result
 	}
 }
 
+func (q *DBJobQueue) RequeueJob(id uuid.UUID) error {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer func() {
+		err = tx.Rollback(context.Background())
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			q.logger.Error(err, "Error rolling back requeue job transaction", "job_id", id.String())
+		}
+	}()
+
+	var jobType string
+	var started, finished *time.Time
+	canceled := false
+	err = tx.QueryRow(context.Background(), sqlQueryJob, id).Scan(&jobType, nil, nil, &started, &finished, nil, &canceled)
+	if err == pgx.ErrNoRows {
+		return jobqueue.ErrNotExist
+	}
+	if err != nil {
+		return fmt.Errorf("error querying job %s: %w", id, err)
+	}
+	if canceled {
+		return jobqueue.ErrCanceled
+	}
+	if started == nil || finished != nil {
+		return jobqueue.ErrNotRunning
+	}
+
+	_, err = tx.Exec(context.Background(), sqlDeleteHeartbeat, id)
+	if err != nil {
+		return fmt.Errorf("error removing job %s from heartbeats: %w", id, err)
+	}
+
+	tag, err := tx.Exec(context.Background(), sqlRequeueNoRetry, id)
+	if err != nil {
+		return fmt.Errorf("error requeueing job %s: %w", id, err)
+	}
+	if tag.RowsAffected() != 1 {
+		return jobqueue.ErrNotExist
+	}
+
+	_, err = tx.Exec(context.Background(), sqlNotify)
+	if err != nil {
+		return fmt.Errorf("error notifying jobs channel: %w", err)
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+
+	q.logger.Info("Requeued job without retry", "job_type", jobType, "job_id", id.String())
+	return nil
+}
+
 func (q *DBJobQueue) CancelJob(id uuid.UUID) error {
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
@@ -690,11 +989,87 @@ func (q *DBJobQueue) CancelJob(id uuid.UUID) error {
 		return fmt.Errorf("error canceling job %s: %w", id, err)
 	}
 
+	// a canceled running job might free up a slot of its channel's quota
+	_, err = conn.Exec(context.Background(), sqlNotify)
+	if err != nil {
+		return fmt.Errorf("error notifying jobs channel: %w", err)
+	}
+
 	q.logger.Info("Cancelled job", "job_type", jobType, "job_id", id.String())
 
 	return nil
 }
 
+func (q *DBJobQueue) CancelJobTree(id uuid.UUID) ([]uuid.UUID, error) {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback(context.Background())
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			q.logger.Error(err, "Error rolling back cancel job tree transaction", "job_id", id.String())
+		}
+	}()
+
+	var exists bool
+	err = tx.QueryRow(context.Background(), sqlQueryJobExists, id).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("error querying job %s: %w", id, err)
+	}
+	if !exists {
+		return nil, jobqueue.ErrNotExist
+	}
+
+	rows, err := tx.Query(context.Background(), sqlCancelJobTree, id)
+	if err != nil {
+		return nil, fmt.Errorf("error canceling the job tree of %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	canceled := []uuid.UUID{}
+	jobTypes := []string{}
+	for rows.Next() {
+		var canceledID uuid.UUID
+		var jobType string
+		err = rows.Scan(&canceledID, &jobType)
+		if err != nil {
+			return nil, fmt.Errorf("error canceling the job tree of %s: %w", id, err)
+		}
+		canceled = append(canceled, canceledID)
+		jobTypes = append(jobTypes, jobType)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error canceling the job tree of %s: %w", id, rows.Err())
+	}
+	rows.Close()
+
+	// canceled running jobs might free up slots of their channel's quota
+	if len(canceled) > 0 {
+		_, err = tx.Exec(context.Background(), sqlNotify)
+		if err != nil {
+			return nil, fmt.Errorf("error notifying jobs channel: %w", err)
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+
+	for i, canceledID := range canceled {
+		q.logger.Info("Cancelled job", "job_type", jobTypes[i], "job_id", canceledID.String())
+	}
+
+	return canceled, nil
+}
+
 func (q *DBJobQueue) FailJob(id uuid.UUID, result interface{}) error {
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
@@ -843,7 +1218,7 @@ func (q *DBJobQueue) RefreshHeartbeat(token uuid.UUID) {
 	}
 }
 
-func (q *DBJobQueue) InsertWorker(channel, arch string) (uuid.UUID, error) {
+func (q *DBJobQueue) InsertWorker(channel, arch string, labels []string) (uuid.UUID, error) {
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
 		return uuid.Nil, err
@@ -851,7 +1226,7 @@ func (q *DBJobQueue) InsertWorker(channel, arch string) (uuid.UUID, error) {
 	defer conn.Release()
 
 	id := uuid.New()
-	_, err = conn.Exec(context.Background(), sqlInsertWorker, id, channel, arch)
+	_, err = conn.Exec(context.Background(), sqlInsertWorker, id, channel, arch, labelsArg(labels))
 	if err != nil {
 		q.logger.Error(err, "Error inserting worker")
 		return uuid.Nil, err
@@ -859,19 +1234,39 @@ func (q *DBJobQueue) InsertWorker(channel, arch string) (uuid.UUID, error) {
 	return id, nil
 }
 
-func (q *DBJobQueue) UpdateWorkerStatus(workerID uuid.UUID) error {
+func (q *DBJobQueue) UpdateWorkerStatus(workerID uuid.UUID) (bool, error) {
 	conn, err := q.pool.Acquire(context.Background())
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer conn.Release()
 
-	tag, err := conn.Exec(context.Background(), sqlUpdateWorkerStatus, workerID)
+	var draining bool
+	err = conn.QueryRow(context.Background(), sqlUpdateWorkerStatus, workerID).Scan(&draining)
+	if err == pgx.ErrNoRows {
+		q.logger.Error(nil, "No rows affected when refreshing updating status")
+		return false, jobqueue.ErrWorkerNotExist
+	}
 	if err != nil {
 		q.logger.Error(err, "Error updating  worker status")
+		return false, err
+	}
+	return draining, nil
+}
+
+func (q *DBJobQueue) SetWorkerDraining(workerID uuid.UUID) error {
+	conn, err := q.pool.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(), sqlSetWorkerDraining, workerID)
+	if err != nil {
+		q.logger.Error(err, "Error setting worker draining")
+		return err
 	}
 	if tag.RowsAffected() != 1 {
-		q.logger.Error(nil, "No rows affected when refreshing updating status")
 		return jobqueue.ErrWorkerNotExist
 	}
 	return nil
@@ -895,16 +1290,20 @@ func (q *DBJobQueue) Workers(olderThan time.Duration) ([]jobqueue.Worker, error)
 		var w uuid.UUID
 		var c string
 		var a string
-		err = rows.Scan(&w, &c, &a)
+		var l []string
+		var d bool
+		err = rows.Scan(&w, &c, &a, &l, &d)
 		if err != nil {
 			// Log the error and try to continue with the next row
 			q.logger.Error(err, "Unable to read token from heartbeats")
 			continue
 		}
 		workers = append(workers, jobqueue.Worker{
-			ID:      w,
-			Channel: c,
-			Arch:    a,
+			ID:       w,
+			Channel:  c,
+			Arch:     a,
+			Labels:   l,
+			Draining: d,
 		})
 	}
 	if rows.Err() != nil {
@@ -1048,6 +1447,84 @@ func (q *DBJobQueue) AllRootJobIDs(ctx context.Context) (rootJobs []uuid.UUID, e
 	return
 }
 
+func (q *DBJobQueue) CountJobs(ctx context.Context, filter jobqueue.JobFilter) (pending int, running int, err error) {
+	conn, err := q.pool.Acquire(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	jobTypes := append([]string{}, filter.JobTypes...)
+	err = conn.QueryRow(ctx, sqlCountJobs, filter.Channel, jobTypes, filter.RootOnly).Scan(&pending, &running)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error counting jobs: %w", err)
+	}
+	return pending, running, nil
+}
+
+func (q *DBJobQueue) ListJobs(ctx context.Context, query jobqueue.JobQuery) ([]uuid.UUID, *jobqueue.JobCursor, error) {
+	conn, err := q.pool.Acquire(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	// timestamp columns don't store a time zone, always use UTC
+	optionalTime := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		t = t.UTC()
+		return &t
+	}
+	jobTypes := append([]string{}, query.JobTypes...)
+	states := []string{}
+	for _, state := range query.States {
+		states = append(states, string(state))
+	}
+	var afterQueued *time.Time
+	var afterID *uuid.UUID
+	if query.After != nil {
+		afterQueued = optionalTime(query.After.QueuedAt)
+		afterID = &query.After.ID
+	}
+	// fetch one more job to know whether there's a next page
+	var limit *int
+	if query.Limit > 0 {
+		l := query.Limit + 1
+		limit = &l
+	}
+
+	rows, err := conn.Query(ctx, sqlListJobs, query.Channel, jobTypes, query.RootOnly, states,
+		optionalTime(query.QueuedAfter), optionalTime(query.QueuedBefore), afterQueued, afterID, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing jobs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	var next *jobqueue.JobCursor
+	var lastQueued time.Time
+	for rows.Next() {
+		var id uuid.UUID
+		var queued time.Time
+		err = rows.Scan(&id, &queued)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error listing jobs: %w", err)
+		}
+		if query.Limit > 0 && len(ids) == query.Limit {
+			next = &jobqueue.JobCursor{QueuedAt: lastQueued, ID: ids[len(ids)-1]}
+			break
+		}
+		ids = append(ids, id)
+		lastQueued = queued
+	}
+	if rows.Err() != nil {
+		return nil, nil, fmt.Errorf("error listing jobs: %w", rows.Err())
+	}
+	return ids, next, nil
+}
+
 // DeleteJob deletes a job and all of its dependencies from the database
 // If a dependency has multiple dependents it will only remove the parent job from
 // the dependents list for that job instead of removing it.
@@ -1144,3 +1621,156 @@ func (q *DBJobQueue) deleteJobDependencies(ctx context.Context, conn connection,
 	}
 	return nil
 }
+
+// ExportJobs implements jobqueue.JobExporter.
+func (q *DBJobQueue) ExportJobs(ctx context.Context, fn func(jobqueue.JobRecord) error) error {
+	conn, err := q.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback(context.Background())
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			q.logger.Error(err, "Error rolling back export transaction")
+		}
+	}()
+
+	dependencies := make(map[uuid.UUID][]uuid.UUID)
+	rows, err := tx.Query(ctx, sqlExportDependencies)
+	if err != nil {
+		return fmt.Errorf("error querying dependencies: %w", err)
+	}
+	for rows.Next() {
+		var id, dep uuid.UUID
+		err = rows.Scan(&id, &dep)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("error reading dependency: %w", err)
+		}
+		dependencies[id] = append(dependencies[id], dep)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return fmt.Errorf("error reading dependencies: %w", rows.Err())
+	}
+
+	rows, err = tx.Query(ctx, sqlExportJobs)
+	if err != nil {
+		return fmt.Errorf("error querying jobs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r jobqueue.JobRecord
+		var token *uuid.UUID
+		var args, result []byte
+		var notBefore, started, finished *time.Time
+		err = rows.Scan(&r.ID, &token, &r.Type, &r.Channel, &args, &result, &r.Priority, &r.Retries, &r.Canceled,
+			&r.QueuedAt, &notBefore, &started, &finished, &r.RequiredLabels)
+		if err != nil {
+			return fmt.Errorf("error reading job: %w", err)
+		}
+		if token != nil {
+			r.Token = *token
+		}
+		if args != nil {
+			r.Args = args
+		}
+		if result != nil {
+			r.Result = result
+		}
+		if notBefore != nil {
+			r.NotBefore = *notBefore
+		}
+		if started != nil {
+			r.StartedAt = *started
+		}
+		if finished != nil {
+			r.FinishedAt = *finished
+		}
+		r.Dependencies = dependencies[r.ID]
+
+		err = fn(r)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ImportJob implements jobqueue.JobImporter.
+func (q *DBJobQueue) ImportJob(ctx context.Context, r jobqueue.JobRecord) error {
+	conn, err := q.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("error connecting to database: %w", err)
+	}
+	defer conn.Release()
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback(context.Background())
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			q.logger.Error(err, "Error rolling back import transaction", "job_id", r.ID.String())
+		}
+	}()
+
+	// timestamp columns don't store a time zone, always use UTC
+	optionalTime := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		t = t.UTC()
+		return &t
+	}
+	var token *uuid.UUID
+	if r.Token != uuid.Nil {
+		token = &r.Token
+	}
+	var args, result []byte
+	if r.Args != nil {
+		args = r.Args
+	}
+	if r.Result != nil {
+		result = r.Result
+	}
+
+	_, err = tx.Exec(ctx, sqlImportJob, r.ID, token, r.Type, r.Channel, args, result, r.Priority, r.Retries, r.Canceled,
+		r.QueuedAt.UTC(), optionalTime(r.NotBefore), optionalTime(r.StartedAt), optionalTime(r.FinishedAt), labelsArg(r.RequiredLabels))
+	if err != nil {
+		return fmt.Errorf("error importing job %s: %w", r.ID, err)
+	}
+
+	for _, d := range r.Dependencies {
+		_, err = tx.Exec(ctx, sqlInsertDependency, r.ID, d)
+		if err != nil {
+			return fmt.Errorf("error inserting dependency %s of job %s: %w", d, r.ID, err)
+		}
+	}
+
+	if !r.StartedAt.IsZero() && r.FinishedAt.IsZero() && !r.Canceled {
+		_, err = tx.Exec(ctx, sqlInsertHeartbeat, r.Token, r.ID)
+		if err != nil {
+			return fmt.Errorf("error inserting the heartbeat of job %s: %w", r.ID, err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, sqlNotify)
+	if err != nil {
+		return fmt.Errorf("error notifying jobs channel: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+-- add the priority column
+ALTER TABLE jobs
+ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+
+-- We added a column, thus we have to recreate the view.
+CREATE OR REPLACE VIEW ready_jobs AS
+SELECT *
+FROM jobs
+WHERE started_at IS NULL
+  AND canceled = FALSE
+  AND id NOT IN (
+    SELECT job_id
+    FROM job_dependencies JOIN jobs ON dependency_id = id
+    WHERE finished_at IS NULL
+)
+ORDER BY priority DESC, queued_at ASC;
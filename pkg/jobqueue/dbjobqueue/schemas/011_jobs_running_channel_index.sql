@@ -0,0 +1,5 @@
+-- Fair-share scheduling counts the running jobs of each channel on every
+-- dequeue, make that cheap.
+CREATE INDEX jobs_running_channel_idx
+ON jobs(channel)
+WHERE started_at IS NOT NULL AND finished_at IS NULL AND canceled = FALSE;
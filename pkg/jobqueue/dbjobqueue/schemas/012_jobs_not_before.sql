@@ -0,0 +1,22 @@
+-- add the not_before column
+ALTER TABLE jobs
+ADD COLUMN not_before timestamp;
+
+CREATE INDEX jobs_delayed_idx
+ON jobs(not_before)
+WHERE not_before IS NOT NULL AND started_at IS NULL;
+
+-- We added a column, thus we have to recreate the view. Delayed jobs are not
+-- ready until their not_before time has passed.
+CREATE OR REPLACE VIEW ready_jobs AS
+SELECT *
+FROM jobs
+WHERE started_at IS NULL
+  AND canceled = FALSE
+  AND (not_before IS NULL OR not_before <= statement_timestamp())
+  AND id NOT IN (
+    SELECT job_id
+    FROM job_dependencies JOIN jobs ON dependency_id = id
+    WHERE finished_at IS NULL
+)
+ORDER BY priority DESC, queued_at ASC;
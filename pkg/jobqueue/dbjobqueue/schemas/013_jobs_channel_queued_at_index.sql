@@ -0,0 +1,4 @@
+-- Listing the jobs of a channel pages through them by the time they were
+-- queued, newest first.
+CREATE INDEX jobs_channel_queued_at_idx
+ON jobs(channel, queued_at DESC, id DESC);
@@ -0,0 +1,21 @@
+-- Capability labels advertised by workers, and the labels a worker must
+-- have to be handed a job.
+ALTER TABLE workers
+ADD COLUMN labels text[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE jobs
+ADD COLUMN required_labels text[] NOT NULL DEFAULT '{}';
+
+-- We added a column, thus we have to recreate the view.
+CREATE OR REPLACE VIEW ready_jobs AS
+SELECT *
+FROM jobs
+WHERE started_at IS NULL
+  AND canceled = FALSE
+  AND (not_before IS NULL OR not_before <= statement_timestamp())
+  AND id NOT IN (
+    SELECT job_id
+    FROM job_dependencies JOIN jobs ON dependency_id = id
+    WHERE finished_at IS NULL
+)
+ORDER BY priority DESC, queued_at ASC;
@@ -0,0 +1,4 @@
+-- Draining workers don't get new jobs and deregister once the jobs they
+-- run are done.
+ALTER TABLE workers
+ADD COLUMN draining boolean NOT NULL DEFAULT FALSE;
@@ -16,6 +16,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,11 +38,24 @@ type JobQueue interface {
 	// Returns the id of the new job, or an error.
 	Enqueue(jobType string, args interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error)
 
+	// Enqueues a job with additional options, see EnqueueOptions.
+	//
+	// Calling Enqueue() is equivalent to calling this method with a
+	// zero-value EnqueueOptions.
+	EnqueueWithOptions(jobType string, args interface{}, dependencies []uuid.UUID, channel string, opts EnqueueOptions) (uuid.UUID, error)
+
 	// Dequeues a job, blocking until one is available.
 	//
 	// Waits until a job with a type of any of `jobTypes` and any of `channels`
 	// is available, or `ctx` is canceled. If 'channels' is 'nil' or empty,
-	// no job will be matched and no job will be returned.
+	// no job will be matched and no job will be returned. If several jobs
+	// are available, the one with the highest priority is returned. Jobs
+	// with the same priority are returned in the order they were enqueued.
+	// A queue configured with SchedulingFairShare first picks the channel,
+	// and jobs of channels which exceed their quota are held back, see
+	// SchedulingConfig. Jobs which require labels are only handed to the
+	// worker with `workerID` if it registered all of them, see
+	// EnqueueOptions.RequiredLabels. Draining workers don't get any jobs.
 	//
 	// Returns the job's id, token, dependencies, type, and arguments, or an error. Arguments
 	// can be unmarshaled to the type given in Enqueue().
@@ -69,13 +86,27 @@ type JobQueue interface {
 	// Tries to requeue a running job by its ID
 	//
 	// If the job has reached the maxRetries number of retries already, finish the job instead.
+	// A requeued job is not dequeued again before the delay given by the
+	// queue's RetryBackoff has passed.
 	// `result` must fit the associated job type and must be serializable to JSON.
 	// Fills in result, and returns if the job was requeued, or an error.
 	RequeueOrFinishJob(id uuid.UUID, maxRetries uint64, result interface{}) (bool, error)
 
+	// Puts a running job back into the queue right away, e.g. because the
+	// worker running it is shutting down. Unlike RequeueOrFinishJob(), this
+	// doesn't count as a retry and the job isn't delayed.
+	RequeueJob(id uuid.UUID) error
+
 	// Cancel a job. Does nothing if the job has already finished.
 	CancelJob(id uuid.UUID) error
 
+	// CancelJobTree cancels all pending and running jobs which are
+	// connected to the job with `id` by dependencies, i.e., its
+	// dependencies, its dependents, and theirs, transitively. The jobs are
+	// canceled atomically. It returns the ids of the canceled jobs, which
+	// is empty if all jobs had already finished or were canceled.
+	CancelJobTree(id uuid.UUID) ([]uuid.UUID, error)
+
 	// Fail a job that didn't even start (e.g. no worker available)
 	FailJob(id uuid.UUID, result interface{}) error
 
@@ -101,11 +132,18 @@ type JobQueue interface {
 	// Reset the last job heartbeat time to time.Now()
 	RefreshHeartbeat(token uuid.UUID)
 
-	// Inserts the worker and creates a UUID for it
-	InsertWorker(channel, arch string) (uuid.UUID, error)
+	// Inserts the worker with the capability labels it advertises and
+	// creates a UUID for it
+	InsertWorker(channel, arch string, labels []string) (uuid.UUID, error)
+
+	// Reset the last worker's heartbeat time to time.Now(), returns whether
+	// the worker is draining
+	UpdateWorkerStatus(workerID uuid.UUID) (bool, error)
 
-	// Reset the last worker's heartbeat time to time.Now()
-	UpdateWorkerStatus(workerID uuid.UUID) error
+	// Marks the worker as draining. A draining worker doesn't get new jobs
+	// and is expected to finish or requeue the ones it runs, and to
+	// deregister afterwards.
+	SetWorkerDraining(workerID uuid.UUID) error
 
 	// Get a list of workers which haven't been updated in the specified time frame
 	Workers(olderThan time.Duration) ([]Worker, error)
@@ -116,6 +154,17 @@ type JobQueue interface {
 	// AllRootJobIDs returns a list of top level job UUIDs that the worker knows about
 	AllRootJobIDs(context.Context) ([]uuid.UUID, error)
 
+	// CountJobs returns the number of unfinished jobs matching `filter`,
+	// split into jobs that are waiting to be run (including the ones that
+	// wait for their dependencies) and jobs that are running. Canceled jobs
+	// are not counted.
+	CountJobs(ctx context.Context, filter JobFilter) (pending int, running int, err error)
+
+	// ListJobs returns the ids of the jobs matching `query`, most recently
+	// queued first. If more jobs match than query.Limit, it also returns a
+	// cursor to continue the listing with.
+	ListJobs(ctx context.Context, query JobQuery) ([]uuid.UUID, *JobCursor, error)
+
 	// DeleteJob deletes a job and all of its dependencies
 	DeleteJob(context.Context, uuid.UUID) error
 }
@@ -144,8 +193,281 @@ var (
 	ErrFinished       = errors.New("job is finished, but wasn't expected to be")
 )
 
-type Worker struct {
-	ID      uuid.UUID
+// EnqueueOptions holds optional parameters of a job, which influence how
+// it is scheduled.
+type EnqueueOptions struct {
+	// Priority of the job. Ready jobs with a higher priority are dequeued
+	// before jobs with a lower one. The default priority is 0, negative
+	// values are allowed.
+	Priority int
+
+	// NotBefore delays the job, it is not dequeued before this time. The
+	// zero value doesn't delay the job.
+	NotBefore time.Time
+
+	// RequiredLabels are capability labels a worker must have registered
+	// to be handed the job, e.g. "nested-virt" or "large-disk".
+	RequiredLabels []string
+}
+
+// HasLabels returns true if `labels` contains all of `required`.
+func HasLabels(labels, required []string) bool {
+	for _, l := range required {
+		if !slices.Contains(labels, l) {
+			return false
+		}
+	}
+	return true
+}
+
+// SchedulingPolicy determines which job is dequeued when several jobs are
+// ready.
+type SchedulingPolicy string
+
+const (
+	// SchedulingFIFO dequeues the job with the highest priority and, among
+	// jobs of the same priority, the one which was enqueued first. This is
+	// the default policy.
+	SchedulingFIFO SchedulingPolicy = "fifo"
+
+	// SchedulingFairShare balances jobs across channels. It dequeues a job
+	// from the channel with the fewest running jobs relative to its weight.
+	// Jobs of the same channel are dequeued in SchedulingFIFO order.
+	SchedulingFairShare SchedulingPolicy = "fair-share"
+)
+
+// SchedulingConfig configures how a job queue schedules ready jobs.
+type SchedulingConfig struct {
+	// Policy is the scheduling policy, SchedulingFIFO when empty.
+	Policy SchedulingPolicy
+
+	// ChannelWeights are only used by SchedulingFairShare. A channel with
+	// weight 2 is allowed to run twice as many jobs as a channel with
+	// weight 1 at the same time. Channels which are not in the map have a
+	// weight of 1.
+	ChannelWeights map[string]float64
+
+	// Quotas limit the number of jobs a channel can run at the same time.
+	Quotas QuotaConfig
+
+	// RetryBackoff delays jobs which are requeued by RequeueOrFinishJob().
+	RetryBackoff RetryBackoff
+}
+
+// RetryBackoff configures an exponential backoff for retried jobs. The first
+// retry of a job is delayed by Initial, each further retry by twice the
+// previous delay, but at most by Max. A zero Initial retries jobs
+// immediately, a zero Max doesn't limit the delay.
+type RetryBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Delay returns how long a job is delayed when it's retried for the
+// `retry`-th time, starting at 1.
+func (b RetryBackoff) Delay(retry uint64) time.Duration {
+	if b.Initial <= 0 || retry == 0 {
+		return 0
+	}
+
+	delay := b.Initial
+	for i := uint64(1); i < retry; i++ {
+		if b.Max > 0 && delay >= b.Max {
+			break
+		}
+		// stop doubling before overflowing
+		if delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		delay = b.Max
+	}
+	return delay
+}
+
+// QuotaConfig limits the number of running jobs per channel. A job which
+// would exceed the limit of its channel stays pending until another job of
+// the channel finishes. Limits of 0 or less mean unlimited.
+type QuotaConfig struct {
+	// JobTypes are the types of jobs the limits apply to. They are compared
+	// to the base type of a job, see BaseJobType().
+	JobTypes []string
+
+	// DefaultMaxRunningJobs is the limit of channels which are not in
+	// MaxRunningJobs.
+	DefaultMaxRunningJobs int
+
+	// MaxRunningJobs are the limits of individual channels.
+	MaxRunningJobs map[string]int
+}
+
+// AppliesTo returns true if jobs of `jobType` are subject to the quotas.
+func (c QuotaConfig) AppliesTo(jobType string) bool {
+	return slices.Contains(c.JobTypes, BaseJobType(jobType))
+}
+
+// MaxRunning returns the maximum number of running jobs of `channel`, or 0
+// if it's unlimited.
+func (c QuotaConfig) MaxRunning(channel string) int {
+	limit, ok := c.MaxRunningJobs[channel]
+	if !ok {
+		limit = c.DefaultMaxRunningJobs
+	}
+	return max(limit, 0)
+}
+
+// JobFilter selects jobs. JobTypes and RootOnly don't restrict the selection
+// when they have their zero value.
+type JobFilter struct {
+	// Channel of the jobs.
 	Channel string
-	Arch    string
+
+	// JobTypes are the base types of the jobs, see BaseJobType().
+	JobTypes []string
+
+	// RootOnly restricts the selection to jobs no other job depends on.
+	RootOnly bool
+}
+
+// JobState is the state of a job in the queue.
+type JobState string
+
+const (
+	// JobPending jobs haven't been dequeued yet, including jobs waiting
+	// for their dependencies or delayed jobs.
+	JobPending  JobState = "pending"
+	JobRunning  JobState = "running"
+	JobFinished JobState = "finished"
+	JobCanceled JobState = "canceled"
+)
+
+// JobCursor is the position of a job in a listing, see JobQuery.
+type JobCursor struct {
+	QueuedAt time.Time
+	ID       uuid.UUID
+}
+
+// JobQuery selects a page of jobs for ListJobs. Like in JobFilter, fields
+// with their zero value don't restrict the selection.
+type JobQuery struct {
+	JobFilter
+
+	// States the jobs are in.
+	States []JobState
+
+	// QueuedAfter and QueuedBefore select jobs which were queued in
+	// between, exclusively.
+	QueuedAfter  time.Time
+	QueuedBefore time.Time
+
+	// After continues a listing after the job the cursor points at.
+	After *JobCursor
+
+	// Limit is the maximum number of returned jobs.
+	Limit int
+}
+
+// BaseJobType returns the type of a job without its optional suffix, which
+// is separated by a colon. For example, the base type of "osbuild:x86_64" is
+// "osbuild".
+func BaseJobType(jobType string) string {
+	base, _, _ := strings.Cut(jobType, ":")
+	return base
+}
+
+// Validate returns an error if the policy is unknown or if a channel weight
+// isn't positive.
+func (c SchedulingConfig) Validate() error {
+	switch c.Policy {
+	case "", SchedulingFIFO, SchedulingFairShare:
+	default:
+		return fmt.Errorf("unknown scheduling policy %q", c.Policy)
+	}
+
+	for channel, weight := range c.ChannelWeights {
+		if weight <= 0 {
+			return fmt.Errorf("weight of channel %q must be positive, got %v", channel, weight)
+		}
+	}
+
+	if c.RetryBackoff.Initial < 0 || c.RetryBackoff.Max < 0 {
+		return fmt.Errorf("retry backoff must not be negative")
+	}
+
+	return nil
+}
+
+// FairShare returns true if jobs should be balanced across channels.
+func (c SchedulingConfig) FairShare() bool {
+	return c.Policy == SchedulingFairShare
+}
+
+// ChannelWeight returns the weight of `channel`.
+func (c SchedulingConfig) ChannelWeight(channel string) float64 {
+	if weight, ok := c.ChannelWeights[channel]; ok {
+		return weight
+	}
+	return 1
+}
+
+// JobRecord is the complete state of a job. It is used to move jobs between
+// queues, see JobExporter and JobImporter.
+type JobRecord struct {
+	ID           uuid.UUID
+	Token        uuid.UUID
+	Type         string
+	Channel      string
+	Args         json.RawMessage
+	Dependencies []uuid.UUID
+	Result       json.RawMessage
+	Priority     int
+	Retries      uint64
+	Canceled     bool
+
+	RequiredLabels []string
+
+	QueuedAt   time.Time
+	NotBefore  time.Time
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// State returns the state the job is in.
+func (r JobRecord) State() JobState {
+	switch {
+	case r.Canceled:
+		return JobCanceled
+	case !r.FinishedAt.IsZero():
+		return JobFinished
+	case !r.StartedAt.IsZero():
+		return JobRunning
+	default:
+		return JobPending
+	}
+}
+
+// JobExporter is implemented by job queues which can export all their jobs.
+type JobExporter interface {
+	// ExportJobs calls `fn` for every job in the queue, in no particular
+	// order. It stops at the first error returned by `fn`.
+	ExportJobs(ctx context.Context, fn func(JobRecord) error) error
+}
+
+// JobImporter is implemented by job queues which can import jobs exported by
+// a JobExporter, keeping their ids, timestamps, and results.
+type JobImporter interface {
+	// ImportJob inserts `job` into the queue. All of its dependencies must
+	// have been imported before. A running job gets a fresh heartbeat, so
+	// that it's requeued if no worker reports back for it.
+	ImportJob(ctx context.Context, job JobRecord) error
+}
+
+type Worker struct {
+	ID       uuid.UUID
+	Channel  string
+	Arch     string
+	Labels   []string
+	Draining bool
 }
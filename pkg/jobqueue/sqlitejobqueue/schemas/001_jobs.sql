@@ -0,0 +1,64 @@
+-- All timestamps are stored as nanoseconds since the Unix epoch.
+
+CREATE TABLE jobs(
+        id TEXT PRIMARY KEY,
+        token TEXT,
+        type TEXT NOT NULL,
+        args TEXT,
+        result TEXT,
+        channel TEXT NOT NULL DEFAULT '',
+        priority INTEGER NOT NULL DEFAULT 0,
+        retries INTEGER NOT NULL DEFAULT 0,
+        queued_at INTEGER NOT NULL,
+        not_before INTEGER,
+        started_at INTEGER,
+        finished_at INTEGER,
+        canceled INTEGER NOT NULL DEFAULT 0,
+
+        CONSTRAINT not_finished_when_not_started
+          CHECK (finished_at IS NULL OR started_at IS NOT NULL),
+
+        CONSTRAINT chronologic_started_at
+          CHECK (started_at IS NULL OR queued_at <= started_at),
+
+        CONSTRAINT chronologic_finished_at
+          CHECK (finished_at IS NULL OR started_at <= finished_at),
+
+        CONSTRAINT token_is_set_when_started
+          CHECK (started_at IS NULL OR token IS NOT NULL)
+);
+
+CREATE INDEX jobs_token_idx ON jobs(token);
+
+CREATE INDEX jobs_pending_idx
+ON jobs(priority DESC, queued_at ASC)
+WHERE started_at IS NULL AND canceled = 0;
+
+CREATE INDEX jobs_running_channel_idx
+ON jobs(channel)
+WHERE started_at IS NOT NULL AND finished_at IS NULL;
+
+CREATE TABLE job_dependencies(
+        job_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+        dependency_id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX job_dependencies_job_idx ON job_dependencies(job_id);
+CREATE INDEX job_dependencies_dependency_idx ON job_dependencies(dependency_id);
+
+CREATE TABLE workers(
+        worker_id TEXT PRIMARY KEY,
+        channel TEXT NOT NULL DEFAULT '',
+        arch TEXT NOT NULL,
+        heartbeat INTEGER NOT NULL
+);
+
+CREATE TABLE heartbeats(
+        token TEXT PRIMARY KEY,
+        id TEXT NOT NULL REFERENCES jobs(id) ON DELETE CASCADE,
+        worker_id TEXT REFERENCES workers(worker_id) ON DELETE CASCADE,
+        heartbeat INTEGER NOT NULL
+);
+
+CREATE INDEX heartbeats_id_idx ON heartbeats(id);
+CREATE INDEX heartbeats_worker_idx ON heartbeats(worker_id);
@@ -0,0 +1,7 @@
+-- Capability labels advertised by workers, and the labels a worker must
+-- have to be handed a job. Both are JSON arrays of strings.
+ALTER TABLE workers
+ADD COLUMN labels TEXT NOT NULL DEFAULT '[]';
+
+ALTER TABLE jobs
+ADD COLUMN required_labels TEXT NOT NULL DEFAULT '[]';
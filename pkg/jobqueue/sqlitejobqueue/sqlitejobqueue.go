@@ -0,0 +1,1656 @@
+// Package sqlitejobqueue implements the interfaces in package jobqueue backed
+// by an embedded SQLite database.
+//
+// It is meant for deployments which run composer on a single host. The
+// database must not be shared by several running queues, because waiting
+// dequeuers are only notified about changes made by the queue they are
+// waiting on.
+package sqlitejobqueue
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/public/common/slogger"
+)
+
+//go:embed schemas/*.sql
+var schemas embed.FS
+
+const (
+	sqlEnqueue = `
+		INSERT INTO jobs(id, type, args, queued_at, channel, priority, not_before, required_labels)
+		VALUES (:id, :type, :args, :now, :channel, :priority, :not_before, :required_labels)`
+
+	// A job is ready when it isn't running, canceled, or delayed and all of
+	// its dependencies have finished.
+	sqlReady = `
+		started_at IS NULL AND canceled = 0
+		AND (not_before IS NULL OR not_before <= :now)
+		AND NOT EXISTS (
+		  SELECT 1
+		  FROM job_dependencies JOIN jobs dependency ON dependency_id = dependency.id
+		  WHERE job_id = jobs.id AND dependency.finished_at IS NULL
+		)`
+
+	// :types and :channels are JSON arrays. Only jobs whose required
+	// labels were all registered by the worker :worker_id are returned,
+	// and none at all when the worker is draining. :any_channel disables
+	// the channel, label, and draining filters.
+	sqlQueryReadyJobs = `
+		SELECT id, type, channel
+		FROM jobs
+		WHERE ` + sqlReady + `
+		  AND type IN (SELECT value FROM json_each(:types))
+		  AND (:any_channel OR (
+		    channel IN (SELECT value FROM json_each(:channels))
+		    AND NOT EXISTS (
+		      SELECT 1
+		      FROM json_each(jobs.required_labels) required
+		      WHERE required.value NOT IN (
+		        SELECT label.value
+		        FROM workers, json_each(workers.labels) label
+		        WHERE workers.worker_id = :worker_id
+		      )
+		    )
+		    AND NOT EXISTS (
+		      SELECT 1
+		      FROM workers
+		      WHERE worker_id = :worker_id AND draining = 1
+		    )
+		  ))
+		ORDER BY priority DESC, queued_at ASC, rowid ASC`
+
+	sqlQueryReadyJob = `
+		SELECT type, args
+		FROM jobs
+		WHERE id = :id AND ` + sqlReady
+
+	sqlQueryRunningJobs = `
+		SELECT id, type, channel
+		FROM jobs
+		WHERE started_at IS NOT NULL AND finished_at IS NULL AND canceled = 0`
+
+	sqlStartJob = `
+		UPDATE jobs
+		SET token = :token, started_at = :now
+		WHERE id = :id AND started_at IS NULL`
+
+	sqlQueryNextDelayedJob = `
+		SELECT MIN(not_before)
+		FROM jobs
+		WHERE started_at IS NULL AND canceled = 0 AND not_before > :now`
+
+	sqlRequeue = `
+		UPDATE jobs
+		SET started_at = NULL, token = NULL, retries = retries + 1, not_before = :not_before
+		WHERE id = :id AND started_at IS NOT NULL AND finished_at IS NULL`
+
+	sqlRequeueNoRetry = `
+		UPDATE jobs
+		SET started_at = NULL, token = NULL, not_before = NULL
+		WHERE id = ? AND started_at IS NOT NULL AND finished_at IS NULL`
+
+	sqlDelete = `
+		DELETE FROM jobs
+		WHERE id = ?`
+
+	sqlInsertDependency  = `INSERT INTO job_dependencies VALUES (?, ?)`
+	sqlQueryDependencies = `
+		SELECT dependency_id
+		FROM job_dependencies
+		WHERE job_id = ?`
+	sqlQueryDependents = `
+		SELECT job_id
+		FROM job_dependencies
+		WHERE dependency_id = ?`
+	sqlDeleteDependencies = `
+		DELETE FROM job_dependencies
+		WHERE job_id = ? AND dependency_id = ?`
+
+	sqlQueryRootJobs = `
+		SELECT id
+		FROM jobs
+		WHERE NOT EXISTS (
+		  SELECT 1
+		  FROM job_dependencies
+		  WHERE dependency_id = jobs.id
+		)`
+	sqlQueryUnfinishedJobs = `
+		SELECT type, started_at
+		FROM jobs
+		WHERE channel = :channel AND finished_at IS NULL AND canceled = 0
+		  AND (NOT :root_only OR NOT EXISTS (
+		    SELECT 1
+		    FROM job_dependencies
+		    WHERE dependency_id = jobs.id
+		  ))`
+	sqlListJobs = `
+		SELECT id, queued_at
+		FROM jobs
+		WHERE channel = :channel
+		  AND (json_array_length(:types) = 0 OR (CASE
+		    WHEN instr(type, ':') > 0 THEN substr(type, 1, instr(type, ':') - 1)
+		    ELSE type
+		  END) IN (SELECT value FROM json_each(:types)))
+		  AND (NOT :root_only OR NOT EXISTS (
+		    SELECT 1
+		    FROM job_dependencies
+		    WHERE dependency_id = jobs.id
+		  ))
+		  AND (json_array_length(:states) = 0 OR (CASE
+		    WHEN canceled THEN 'canceled'
+		    WHEN finished_at IS NOT NULL THEN 'finished'
+		    WHEN started_at IS NOT NULL THEN 'running'
+		    ELSE 'pending'
+		  END) IN (SELECT value FROM json_each(:states)))
+		  AND (:queued_after IS NULL OR queued_at > :queued_after)
+		  AND (:queued_before IS NULL OR queued_at < :queued_before)
+		  AND (:after_queued IS NULL OR (queued_at, id) < (:after_queued, :after_id))
+		ORDER BY queued_at DESC, id DESC
+		LIMIT :limit`
+	sqlQueryJob = `
+		SELECT type, args, channel
+		FROM jobs
+		WHERE id = ?`
+	sqlQueryJobState = `
+		SELECT type, started_at, finished_at, retries, canceled
+		FROM jobs
+		WHERE id = ?`
+	sqlQueryJobStatus = `
+		SELECT type, channel, result, queued_at, started_at, finished_at, canceled
+		FROM jobs
+		WHERE id = ?`
+	sqlQueryRunningId = `
+		SELECT id
+		FROM jobs
+		WHERE token = ? AND finished_at IS NULL AND canceled = 0`
+	sqlUpdateJob = `
+		UPDATE jobs
+		SET result = ?
+		WHERE id = ? AND finished_at IS NULL`
+	sqlFinishJob = `
+		UPDATE jobs
+		SET finished_at = :now, result = :result
+		WHERE id = :id AND finished_at IS NULL`
+	sqlCancelJob = `
+		UPDATE jobs
+		SET canceled = 1
+		WHERE id = ? AND finished_at IS NULL
+		RETURNING type`
+	sqlQueryJobExists = `
+		SELECT EXISTS (SELECT 1 FROM jobs WHERE id = ?)`
+	sqlCancelJobTree = `
+		WITH RECURSIVE tree(id) AS (
+		    SELECT ?
+		  UNION
+		    SELECT CASE WHEN d.job_id = tree.id THEN d.dependency_id ELSE d.job_id END
+		    FROM job_dependencies d
+		    JOIN tree ON d.job_id = tree.id OR d.dependency_id = tree.id
+		)
+		UPDATE jobs
+		SET canceled = 1
+		WHERE id IN (SELECT id FROM tree) AND finished_at IS NULL AND canceled = 0
+		RETURNING id, type`
+	sqlFailJob = `
+		UPDATE jobs
+		SET token = :token, started_at = :now, finished_at = :now, result = :result
+		WHERE id = :id AND finished_at IS NULL AND started_at IS NULL AND token IS NULL
+		RETURNING type`
+
+	sqlExportJobs = `
+		SELECT id, token, type, channel, args, result, priority, retries, canceled,
+		  queued_at, not_before, started_at, finished_at, required_labels
+		FROM jobs`
+	sqlExportDependencies = `
+		SELECT job_id, dependency_id
+		FROM job_dependencies`
+	sqlImportJob = `
+		INSERT INTO jobs(id, token, type, channel, args, result, priority, retries, canceled,
+		  queued_at, not_before, started_at, finished_at, required_labels)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	sqlInsertHeartbeat = `
+		INSERT INTO heartbeats(token, id, worker_id, heartbeat)
+		VALUES (:token, :id, :worker_id, :now)`
+	sqlQueryHeartbeats = `
+		SELECT token
+		FROM heartbeats
+		WHERE heartbeat < ?`
+	sqlRefreshHeartbeat = `
+		UPDATE heartbeats
+		SET heartbeat = ?
+		WHERE token = ?`
+	sqlDeleteHeartbeat = `
+		DELETE FROM heartbeats
+		WHERE id = ?`
+	sqlQueryHeartbeatsForWorker = `
+		SELECT count(token)
+		FROM heartbeats
+		WHERE worker_id = ?`
+
+	sqlInsertWorker = `
+		INSERT INTO workers(worker_id, channel, arch, labels, heartbeat)
+		VALUES (?, ?, ?, ?, ?)`
+	sqlUpdateWorkerStatus = `
+		UPDATE workers
+		SET heartbeat = ?
+		WHERE worker_id = ?
+		RETURNING draining`
+	sqlSetWorkerDraining = `
+		UPDATE workers
+		SET draining = 1
+		WHERE worker_id = ?`
+	sqlQueryWorkers = `
+		SELECT worker_id, channel, arch, labels, draining
+		FROM workers
+		WHERE heartbeat < ?`
+	sqlDeleteWorker = `
+		DELETE FROM workers
+		WHERE worker_id = ?`
+)
+
+// connection unifies sql.DB and sql.Tx, see connection in dbjobqueue.
+type connection interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type SQLiteJobQueue struct {
+	logger     jobqueue.SimpleLogger
+	db         *sql.DB
+	dequeuers  *dequeuers
+	scheduling jobqueue.SchedulingConfig
+}
+
+// thread-safe list of dequeuers
+type dequeuers struct {
+	list  *list.List
+	mutex sync.Mutex
+}
+
+func newDequeuers() *dequeuers {
+	return &dequeuers{
+		list: list.New(),
+	}
+}
+
+func (d *dequeuers) pushBack(c chan struct{}) *list.Element {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.list.PushBack(c)
+}
+
+func (d *dequeuers) remove(e *list.Element) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.list.Remove(e)
+}
+
+func (d *dequeuers) notifyAll() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	cur := d.list.Front()
+	for cur != nil {
+		listenerChan := cur.Value.(chan struct{})
+
+		// notify in a non-blocking way
+		select {
+		case listenerChan <- struct{}{}:
+		default:
+		}
+		cur = cur.Next()
+	}
+}
+
+// Config allows more detailed customization of queue behavior
+type Config struct {
+	// Logger is used for all logging of the queue, when not provided, the
+	// standard global logger (logrus) is used.
+	Logger jobqueue.SimpleLogger
+
+	// Scheduling configures the order in which ready jobs are dequeued.
+	Scheduling jobqueue.SchedulingConfig
+}
+
+// New creates a new SQLiteJobQueue object for the database at `path` with
+// default configuration. The database is created if it doesn't exist.
+func New(path string) (*SQLiteJobQueue, error) {
+	return NewWithConfig(path, Config{})
+}
+
+// NewWithConfig creates a new SQLiteJobQueue object for the database at
+// `path` with specific configuration. The database is created if it doesn't
+// exist and its schema is migrated to the latest version.
+func NewWithConfig(path string, config Config) (*SQLiteJobQueue, error) {
+	err := config.Scheduling.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduling configuration: %v", err)
+	}
+
+	if config.Logger == nil {
+		config.Logger = slogger.NewLogrusLogger(logrus.StandardLogger())
+	}
+
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=1&_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate", path)
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %v", err)
+	}
+
+	// SQLite allows only a single writer at a time, serialize all access
+	// in the pool instead of retrying on SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+
+	err = migrate(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error migrating database %s: %v", path, err)
+	}
+
+	return &SQLiteJobQueue{
+		logger:     config.Logger,
+		db:         db,
+		dequeuers:  newDequeuers(),
+		scheduling: config.Scheduling,
+	}, nil
+}
+
+// migrate applies all schemas which are newer than the database's
+// user_version, in the order of their numeric prefix.
+func migrate(db *sql.DB) error {
+	var version int
+	err := db.QueryRow(`PRAGMA user_version`).Scan(&version)
+	if err != nil {
+		return err
+	}
+
+	files, err := fs.Glob(schemas, "schemas/*.sql")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(path.Base(file), "_")
+		schemaVersion, err := strconv.Atoi(prefix)
+		if err != nil {
+			return fmt.Errorf("invalid schema file name %s: %v", file, err)
+		}
+		if schemaVersion <= version {
+			continue
+		}
+
+		schema, err := schemas.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(string(schema))
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("error applying %s: %v", file, err)
+		}
+		_, err = tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, schemaVersion))
+		if err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+		version = schemaVersion
+	}
+
+	return nil
+}
+
+func (q *SQLiteJobQueue) Close() {
+	err := q.db.Close()
+	if err != nil {
+		q.logger.Error(err, "Error closing the database")
+	}
+}
+
+// rollback is deferred by all methods which start a transaction.
+func (q *SQLiteJobQueue) rollback(tx *sql.Tx, msg string, args ...string) {
+	err := tx.Rollback()
+	if err != nil && !errors.Is(err, sql.ErrTxDone) {
+		q.logger.Error(err, msg, args...)
+	}
+}
+
+func (q *SQLiteJobQueue) Enqueue(jobType string, args interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return q.EnqueueWithOptions(jobType, args, dependencies, channel, jobqueue.EnqueueOptions{})
+}
+
+func (q *SQLiteJobQueue) EnqueueWithOptions(jobType string, args interface{}, dependencies []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	encodedArgs, err := json.Marshal(args)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("error marshaling job arguments: %v", err)
+	}
+
+	tx, err := q.db.Begin()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("error starting database transaction: %v", err)
+	}
+	defer q.rollback(tx, "Error rolling back enqueue transaction")
+
+	id := uuid.New()
+	var notBefore sql.NullInt64
+	if !opts.NotBefore.IsZero() {
+		notBefore = sql.NullInt64{Int64: opts.NotBefore.UnixNano(), Valid: true}
+	}
+	requiredLabels, err := encodeLabels(opts.RequiredLabels)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	_, err = tx.Exec(sqlEnqueue,
+		sql.Named("id", id),
+		sql.Named("type", jobType),
+		sql.Named("args", string(encodedArgs)),
+		sql.Named("now", time.Now().UnixNano()),
+		sql.Named("channel", channel),
+		sql.Named("priority", opts.Priority),
+		sql.Named("not_before", notBefore),
+		sql.Named("required_labels", requiredLabels),
+	)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("error enqueuing job: %v", err)
+	}
+
+	for _, d := range dependencies {
+		_, err = tx.Exec(sqlInsertDependency, id, d)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("error inserting dependency: %v", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("unable to commit database transaction: %v", err)
+	}
+	q.dequeuers.notifyAll()
+
+	q.logger.Info("Enqueued job", "job_type", jobType, "job_id", id.String(), "job_dependencies", fmt.Sprintf("%+v", dependencies), "job_priority", fmt.Sprintf("%d", opts.Priority))
+
+	return id, nil
+}
+
+// dequeueLoop implements the polling loop pattern for dequeuing jobs.
+// It registers as a dequeuer for the duration of the call and returns
+// the job's id, dependencies, type, and arguments, or an error.
+func (q *SQLiteJobQueue) dequeueLoop(ctx context.Context, tryFn func(ctx context.Context) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error)) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+	c := make(chan struct{}, 1)
+	el := q.dequeuers.pushBack(c)
+	defer q.dequeuers.remove(el)
+	for {
+		id, dependencies, jobType, args, err := tryFn(ctx)
+		if err == nil {
+			return id, dependencies, jobType, args, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+				return uuid.Nil, nil, "", nil, jobqueue.ErrDequeueTimeout
+			}
+			return uuid.Nil, nil, "", nil, fmt.Errorf("error dequeuing job: %v", err)
+		}
+
+		// Nothing notifies the dequeuers when a delayed job becomes ready,
+		// wake up on our own.
+		var wakeup <-chan time.Time
+		var timer *time.Timer
+		delay, err := q.nextDelayedJob(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				q.logger.Error(err, "Error querying the next delayed job")
+			}
+		} else if delay > 0 {
+			timer = time.NewTimer(delay)
+			wakeup = timer.C
+		}
+
+		select {
+		case <-c:
+		case <-wakeup:
+		case <-ctx.Done():
+			return uuid.Nil, nil, "", nil, jobqueue.ErrDequeueTimeout
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// nextDelayedJob returns the time until the next delayed job becomes ready,
+// or 0 if there are no delayed jobs.
+func (q *SQLiteJobQueue) nextDelayedJob(ctx context.Context) (time.Duration, error) {
+	now := time.Now()
+	var notBefore sql.NullInt64
+	err := q.db.QueryRowContext(ctx, sqlQueryNextDelayedJob, sql.Named("now", now.UnixNano())).Scan(&notBefore)
+	if err != nil {
+		return 0, err
+	}
+	if !notBefore.Valid {
+		return 0, nil
+	}
+	return time.Unix(0, notBefore.Int64).Sub(now), nil
+}
+
+// readyJob is a candidate for dequeuing, or a running job when counting
+// the running jobs of channels.
+type readyJob struct {
+	id      uuid.UUID
+	jobType string
+	channel string
+}
+
+// tryDequeue tries to dequeue one of the ready jobs of `jobTypes` in
+// `channels`, or in any channel if `anyChannel` is set. It returns the job's
+// id, dependencies, type, and arguments, or sql.ErrNoRows if there is no
+// job which could be dequeued.
+func (q *SQLiteJobQueue) tryDequeue(ctx context.Context, token, workerID uuid.UUID, jobTypes, channels []string, anyChannel bool) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+	// never pass null to json_each()
+	encodedTypes, err := json.Marshal(append([]string{}, jobTypes...))
+	if err != nil {
+		return uuid.Nil, nil, "", nil, err
+	}
+	encodedChannels, err := json.Marshal(append([]string{}, channels...))
+	if err != nil {
+		return uuid.Nil, nil, "", nil, err
+	}
+
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, nil, "", nil, fmt.Errorf("error starting a new transaction when dequeueing: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back dequeuing transaction")
+
+	now := time.Now()
+	ready, err := queryReadyJobs(ctx, tx, sqlQueryReadyJobs,
+		sql.Named("now", now.UnixNano()),
+		sql.Named("types", string(encodedTypes)),
+		sql.Named("channels", string(encodedChannels)),
+		sql.Named("any_channel", anyChannel),
+		sql.Named("worker_id", workerID),
+	)
+	if err != nil {
+		return uuid.Nil, nil, "", nil, fmt.Errorf("error querying ready jobs: %w", err)
+	}
+
+	var running []readyJob
+	if q.scheduling.FairShare() || len(q.scheduling.Quotas.JobTypes) > 0 {
+		running, err = queryReadyJobs(ctx, tx, sqlQueryRunningJobs)
+		if err != nil {
+			return uuid.Nil, nil, "", nil, fmt.Errorf("error querying running jobs: %w", err)
+		}
+	}
+
+	j := q.pickJob(ready, running)
+	if j == nil {
+		return uuid.Nil, nil, "", nil, sql.ErrNoRows
+	}
+
+	args, dependencies, err := q.startJob(ctx, tx, j.id, token, workerID, now)
+	if err != nil {
+		return uuid.Nil, nil, "", nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return uuid.Nil, nil, "", nil, fmt.Errorf("error committing the transaction for dequeueing job %s: %w", j.id.String(), err)
+	}
+
+	q.logger.Info("Dequeued job", "job_type", j.jobType, "job_id", j.id.String(), "job_dependencies", fmt.Sprintf("%+v", dependencies))
+
+	return j.id, dependencies, j.jobType, args, nil
+}
+
+// queryReadyJobs returns the jobs selected by `query`, which must select
+// their id, type, and channel.
+func queryReadyJobs(ctx context.Context, conn connection, query string, args ...any) ([]readyJob, error) {
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []readyJob
+	for rows.Next() {
+		var j readyJob
+		err = rows.Scan(&j.id, &j.jobType, &j.channel)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// pickJob returns the job of `ready` which should be dequeued according to
+// the scheduling configuration, or nil if the quotas hold back all of them.
+// `ready` must be ordered by priority and age, `running` are the jobs that are
+// currently running.
+func (q *SQLiteJobQueue) pickJob(ready, running []readyJob) *readyJob {
+	load := make(map[string]int)
+	quotaRunning := make(map[string]int)
+	for _, r := range running {
+		load[r.channel] += 1
+		if q.scheduling.Quotas.AppliesTo(r.jobType) {
+			quotaRunning[r.channel] += 1
+		}
+	}
+
+	var best *readyJob
+	var bestLoad float64
+	for i := range ready {
+		j := &ready[i]
+		if q.scheduling.Quotas.AppliesTo(j.jobType) {
+			limit := q.scheduling.Quotas.MaxRunning(j.channel)
+			if limit > 0 && quotaRunning[j.channel] >= limit {
+				continue
+			}
+		}
+		if !q.scheduling.FairShare() {
+			return j
+		}
+
+		// ties are broken by the order of `ready`
+		jobLoad := float64(load[j.channel]) / q.scheduling.ChannelWeight(j.channel)
+		if best == nil || jobLoad < bestLoad {
+			best, bestLoad = j, jobLoad
+		}
+	}
+	return best
+}
+
+// startJob marks the pending job `id` as running, and returns its arguments
+// and dependencies.
+func (q *SQLiteJobQueue) startJob(ctx context.Context, tx *sql.Tx, id, token, workerID uuid.UUID, now time.Time) (json.RawMessage, []uuid.UUID, error) {
+	res, err := tx.ExecContext(ctx, sqlStartJob,
+		sql.Named("id", id),
+		sql.Named("token", token),
+		sql.Named("now", now.UnixNano()),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error starting job %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return nil, nil, jobqueue.ErrNotPending
+	}
+
+	_, err = tx.ExecContext(ctx, sqlInsertHeartbeat,
+		sql.Named("token", token),
+		sql.Named("id", id),
+		sql.Named("worker_id", uuid.NullUUID{UUID: workerID, Valid: workerID != uuid.Nil}),
+		sql.Named("now", now.UnixNano()),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error inserting the job's heartbeat: %w", err)
+	}
+
+	var jobType, channel string
+	var args []byte
+	err = tx.QueryRowContext(ctx, sqlQueryJob, id).Scan(&jobType, &args, &channel)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying the job's arguments: %w", err)
+	}
+
+	dependencies, err := q.jobDependencies(ctx, tx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying the job's dependencies: %w", err)
+	}
+
+	return args, dependencies, nil
+}
+
+func (q *SQLiteJobQueue) Dequeue(ctx context.Context, workerID uuid.UUID, jobTypes, channels []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+	token := uuid.New()
+	id, deps, jobType, args, err := q.dequeueLoop(ctx, func(ctx context.Context) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+		return q.tryDequeue(ctx, token, workerID, jobTypes, channels, false)
+	})
+	if err != nil {
+		return uuid.Nil, uuid.Nil, nil, "", nil, err
+	}
+	return id, token, deps, jobType, args, nil
+}
+
+func (q *SQLiteJobQueue) DequeueAnyChannel(ctx context.Context, workerID uuid.UUID, jobTypes []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+	token := uuid.New()
+	id, deps, jobType, args, err := q.dequeueLoop(ctx, func(ctx context.Context) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+		return q.tryDequeue(ctx, token, workerID, jobTypes, nil, true)
+	})
+	if err != nil {
+		return uuid.Nil, uuid.Nil, nil, "", nil, err
+	}
+	return id, token, deps, jobType, args, nil
+}
+
+func (q *SQLiteJobQueue) DequeueByID(ctx context.Context, id, workerID uuid.UUID) (uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
+	// Return early if the context is already canceled.
+	if err := ctx.Err(); err != nil {
+		return uuid.Nil, nil, "", nil, jobqueue.ErrDequeueTimeout
+	}
+
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, nil, "", nil, fmt.Errorf("error starting a new transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back dequeuing by id transaction", "job_id", id.String())
+
+	now := time.Now()
+	var jobType string
+	var args []byte
+	err = tx.QueryRowContext(ctx, sqlQueryReadyJob, sql.Named("id", id), sql.Named("now", now.UnixNano())).Scan(&jobType, &args)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, nil, "", nil, jobqueue.ErrNotPending
+	} else if err != nil {
+		return uuid.Nil, nil, "", nil, fmt.Errorf("error dequeuing job: %w", err)
+	}
+
+	token := uuid.New()
+	_, dependencies, err := q.startJob(ctx, tx, id, token, workerID, now)
+	if err != nil {
+		return uuid.Nil, nil, "", nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return uuid.Nil, nil, "", nil, fmt.Errorf("error committing a transaction: %w", err)
+	}
+
+	q.logger.Info("Dequeued job", "job_type", jobType, "job_id", id.String(), "job_dependencies", fmt.Sprintf("%+v", dependencies))
+
+	return token, dependencies, jobType, args, nil
+}
+
+// runningJob returns the type and number of retries of the running job `id`,
+// or an error if it isn't running.
+func (q *SQLiteJobQueue) runningJob(tx *sql.Tx, id uuid.UUID) (string, uint64, error) {
+	var jobType string
+	var started, finished sql.NullInt64
+	var retries uint64
+	var canceled bool
+	err := tx.QueryRow(sqlQueryJobState, id).Scan(&jobType, &started, &finished, &retries, &canceled)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", 0, jobqueue.ErrNotExist
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("error querying job %s: %w", id, err)
+	}
+	if canceled {
+		return "", 0, jobqueue.ErrCanceled
+	}
+	if !started.Valid || finished.Valid {
+		return "", 0, jobqueue.ErrNotRunning
+	}
+	return jobType, retries, nil
+}
+
+// encodeResult returns `result` as JSON, or NULL if it is nil.
+func encodeResult(result interface{}) (sql.NullString, error) {
+	if result == nil {
+		return sql.NullString{}, nil
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("error marshaling result: %v", err)
+	}
+	return sql.NullString{String: string(encoded), Valid: true}, nil
+}
+
+func (q *SQLiteJobQueue) UpdateJobResult(id uuid.UUID, result interface{}) error {
+	encodedResult, err := encodeResult(result)
+	if err != nil {
+		return err
+	}
+
+	tx, err := q.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back update job result transaction", "job_id", id.String())
+
+	_, _, err = q.runningJob(tx, id)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(sqlUpdateJob, encodedResult, id)
+	if err != nil {
+		return fmt.Errorf("error updating job %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return jobqueue.ErrNotExist
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+	return nil
+}
+
+func (q *SQLiteJobQueue) RequeueOrFinishJob(id uuid.UUID, maxRetries uint64, result interface{}) (bool, error) {
+	encodedResult, err := encodeResult(result)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := q.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back retry job transaction", "job_id", id.String())
+
+	jobType, retries, err := q.runningJob(tx, id)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := tx.Exec(sqlDeleteHeartbeat, id)
+	if err != nil {
+		return false, fmt.Errorf("error removing job %s from heartbeats: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return false, jobqueue.ErrNotExist
+	}
+
+	now := time.Now()
+	if retries >= maxRetries {
+		res, err = tx.Exec(sqlFinishJob,
+			sql.Named("id", id),
+			sql.Named("result", encodedResult),
+			sql.Named("now", now.UnixNano()),
+		)
+		if err != nil {
+			return false, fmt.Errorf("error finishing job %s: %w", id, err)
+		}
+	} else {
+		var notBefore sql.NullInt64
+		if delay := q.scheduling.RetryBackoff.Delay(retries + 1); delay > 0 {
+			notBefore = sql.NullInt64{Int64: now.Add(delay).UnixNano(), Valid: true}
+		}
+		res, err = tx.Exec(sqlRequeue, sql.Named("id", id), sql.Named("not_before", notBefore))
+		if err != nil {
+			return false, fmt.Errorf("error requeueing job %s: %w", id, err)
+		}
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return false, jobqueue.ErrNotExist
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+	q.dequeuers.notifyAll()
+
+	if retries >= maxRetries {
+		q.logger.Info("Finished job", "job_type", jobType, "job_id", id.String())
+		return false, nil
+	} else {
+		q.logger.Info("Requeued job", "job_type", jobType, "job_id", id.String())
+		return true, nil
+	}
+}
+
+func (q *SQLiteJobQueue) RequeueJob(id uuid.UUID) error {
+	tx, err := q.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back requeue job transaction", "job_id", id.String())
+
+	jobType, _, err := q.runningJob(tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(sqlDeleteHeartbeat, id)
+	if err != nil {
+		return fmt.Errorf("error removing job %s from heartbeats: %w", id, err)
+	}
+
+	res, err := tx.Exec(sqlRequeueNoRetry, id)
+	if err != nil {
+		return fmt.Errorf("error requeueing job %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return jobqueue.ErrNotExist
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+	q.dequeuers.notifyAll()
+
+	q.logger.Info("Requeued job without retry", "job_type", jobType, "job_id", id.String())
+	return nil
+}
+
+func (q *SQLiteJobQueue) CancelJob(id uuid.UUID) error {
+	tx, err := q.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back cancel job transaction", "job_id", id.String())
+
+	var jobType string
+	err = tx.QueryRow(sqlCancelJob, id).Scan(&jobType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jobqueue.ErrNotRunning
+	}
+	if err != nil {
+		return fmt.Errorf("error canceling job %s: %w", id, err)
+	}
+
+	// a canceled job doesn't have a heartbeat anymore
+	_, err = tx.Exec(sqlDeleteHeartbeat, id)
+	if err != nil {
+		return fmt.Errorf("error removing job %s from heartbeats: %w", id, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+
+	// a canceled running job might free up a slot of its channel's quota
+	q.dequeuers.notifyAll()
+
+	q.logger.Info("Cancelled job", "job_type", jobType, "job_id", id.String())
+
+	return nil
+}
+
+func (q *SQLiteJobQueue) CancelJobTree(id uuid.UUID) ([]uuid.UUID, error) {
+	tx, err := q.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back cancel job tree transaction", "job_id", id.String())
+
+	var exists bool
+	err = tx.QueryRow(sqlQueryJobExists, id).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("error querying job %s: %w", id, err)
+	}
+	if !exists {
+		return nil, jobqueue.ErrNotExist
+	}
+
+	rows, err := tx.Query(sqlCancelJobTree, id)
+	if err != nil {
+		return nil, fmt.Errorf("error canceling the job tree of %s: %w", id, err)
+	}
+	canceled := []uuid.UUID{}
+	jobTypes := []string{}
+	for rows.Next() {
+		var canceledID uuid.UUID
+		var jobType string
+		err = rows.Scan(&canceledID, &jobType)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error canceling the job tree of %s: %w", id, err)
+		}
+		canceled = append(canceled, canceledID)
+		jobTypes = append(jobTypes, jobType)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error canceling the job tree of %s: %w", id, rows.Err())
+	}
+
+	// canceled jobs don't have a heartbeat anymore
+	for _, canceledID := range canceled {
+		_, err = tx.Exec(sqlDeleteHeartbeat, canceledID)
+		if err != nil {
+			return nil, fmt.Errorf("error removing job %s from heartbeats: %w", canceledID, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+
+	// canceled running jobs might free up slots of their channel's quota
+	if len(canceled) > 0 {
+		q.dequeuers.notifyAll()
+	}
+
+	for i, canceledID := range canceled {
+		q.logger.Info("Cancelled job", "job_type", jobTypes[i], "job_id", canceledID.String())
+	}
+
+	return canceled, nil
+}
+
+func (q *SQLiteJobQueue) FailJob(id uuid.UUID, result interface{}) error {
+	encodedResult, err := encodeResult(result)
+	if err != nil {
+		return err
+	}
+
+	var jobType string
+	err = q.db.QueryRow(sqlFailJob,
+		sql.Named("id", id),
+		sql.Named("token", uuid.New()),
+		sql.Named("result", encodedResult),
+		sql.Named("now", time.Now().UnixNano()),
+	).Scan(&jobType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jobqueue.ErrNotRunning
+	}
+	if err != nil {
+		return fmt.Errorf("error failing job %s: %w", id, err)
+	}
+
+	// dependents of the failed job might be ready now
+	q.dequeuers.notifyAll()
+
+	q.logger.Info("Job set to failed", "job_type", jobType, "job_id", id.String())
+
+	return nil
+}
+
+// timeFromNullInt64 converts a nullable timestamp of the database, returning
+// the zero time for NULL.
+func timeFromNullInt64(t sql.NullInt64) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+	return time.Unix(0, t.Int64)
+}
+
+// encodeLabels encodes labels as a JSON array for the labels and
+// required_labels columns.
+func encodeLabels(labels []string) (string, error) {
+	encoded, err := json.Marshal(append([]string{}, labels...))
+	if err != nil {
+		return "", fmt.Errorf("error marshaling labels: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// decodeLabels is the inverse of encodeLabels, it returns nil for an empty
+// array.
+func decodeLabels(encoded string) ([]string, error) {
+	var labels []string
+	err := json.Unmarshal([]byte(encoded), &labels)
+	if err != nil || len(labels) == 0 {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (q *SQLiteJobQueue) JobStatus(id uuid.UUID) (jobType string, channel string, result json.RawMessage, queued, started, finished time.Time, canceled bool, deps []uuid.UUID, dependents []uuid.UUID, err error) {
+	var queuedAt int64
+	var startedAt, finishedAt sql.NullInt64
+	var rp []byte
+	err = q.db.QueryRow(sqlQueryJobStatus, id).Scan(&jobType, &channel, &rp, &queuedAt, &startedAt, &finishedAt, &canceled)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = jobqueue.ErrNotExist
+		return
+	} else if err != nil {
+		return
+	}
+	queued = time.Unix(0, queuedAt)
+	started = timeFromNullInt64(startedAt)
+	finished = timeFromNullInt64(finishedAt)
+	if rp != nil {
+		result = rp
+	}
+
+	deps, err = q.jobDependencies(context.Background(), q.db, id)
+	if err != nil {
+		return
+	}
+
+	dependents, err = q.jobDependents(context.Background(), q.db, id)
+	return
+}
+
+// Job returns all the parameters that define a job (everything provided during Enqueue).
+func (q *SQLiteJobQueue) Job(id uuid.UUID) (jobType string, args json.RawMessage, dependencies []uuid.UUID, channel string, err error) {
+	var ap []byte
+	err = q.db.QueryRow(sqlQueryJob, id).Scan(&jobType, &ap, &channel)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = jobqueue.ErrNotExist
+		return
+	} else if err != nil {
+		return
+	}
+	args = ap
+
+	dependencies, err = q.jobDependencies(context.Background(), q.db, id)
+	return
+}
+
+// Find job by token, this will return an error if the job hasn't been dequeued
+func (q *SQLiteJobQueue) IdFromToken(token uuid.UUID) (id uuid.UUID, err error) {
+	err = q.db.QueryRow(sqlQueryRunningId, token).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, jobqueue.ErrNotExist
+	} else if err != nil {
+		return uuid.Nil, fmt.Errorf("Error retrieving id: %w", err)
+	}
+
+	return
+}
+
+// Get a list of tokens which haven't been updated in the specified time frame
+func (q *SQLiteJobQueue) Heartbeats(olderThan time.Duration) (tokens []uuid.UUID) {
+	rows, err := q.db.Query(sqlQueryHeartbeats, time.Now().Add(-olderThan).UnixNano())
+	if err != nil {
+		q.logger.Error(err, "Error querying heartbeats")
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t uuid.UUID
+		err = rows.Scan(&t)
+		if err != nil {
+			// Log the error and try to continue with the next row
+			q.logger.Error(err, "Unable to read token from heartbeats")
+			continue
+		}
+		tokens = append(tokens, t)
+	}
+	if rows.Err() != nil {
+		q.logger.Error(rows.Err(), "Error reading tokens from heartbeats")
+	}
+
+	return
+}
+
+// Reset the last heartbeat time to time.Now()
+func (q *SQLiteJobQueue) RefreshHeartbeat(token uuid.UUID) {
+	res, err := q.db.Exec(sqlRefreshHeartbeat, time.Now().UnixNano(), token)
+	if err != nil {
+		q.logger.Error(err, "Error refreshing heartbeat")
+		return
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		q.logger.Error(nil, "No rows affected when refreshing heartbeat", "job_token", token.String())
+	}
+}
+
+func (q *SQLiteJobQueue) InsertWorker(channel, arch string, labels []string) (uuid.UUID, error) {
+	encodedLabels, err := encodeLabels(labels)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	id := uuid.New()
+	_, err = q.db.Exec(sqlInsertWorker, id, channel, arch, encodedLabels, time.Now().UnixNano())
+	if err != nil {
+		q.logger.Error(err, "Error inserting worker")
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (q *SQLiteJobQueue) UpdateWorkerStatus(workerID uuid.UUID) (bool, error) {
+	var draining bool
+	err := q.db.QueryRow(sqlUpdateWorkerStatus, time.Now().UnixNano(), workerID).Scan(&draining)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, jobqueue.ErrWorkerNotExist
+	}
+	if err != nil {
+		q.logger.Error(err, "Error updating worker status")
+		return false, err
+	}
+	return draining, nil
+}
+
+func (q *SQLiteJobQueue) SetWorkerDraining(workerID uuid.UUID) error {
+	res, err := q.db.Exec(sqlSetWorkerDraining, workerID)
+	if err != nil {
+		q.logger.Error(err, "Error setting worker draining")
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return jobqueue.ErrWorkerNotExist
+	}
+	return nil
+}
+
+func (q *SQLiteJobQueue) Workers(olderThan time.Duration) ([]jobqueue.Worker, error) {
+	rows, err := q.db.Query(sqlQueryWorkers, time.Now().Add(-olderThan).UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workers := make([]jobqueue.Worker, 0)
+	for rows.Next() {
+		var w jobqueue.Worker
+		var labels string
+		err = rows.Scan(&w.ID, &w.Channel, &w.Arch, &labels, &w.Draining)
+		if err == nil {
+			w.Labels, err = decodeLabels(labels)
+		}
+		if err != nil {
+			// Log the error and try to continue with the next row
+			q.logger.Error(err, "Unable to read worker from workers")
+			continue
+		}
+		workers = append(workers, w)
+	}
+	if rows.Err() != nil {
+		q.logger.Error(rows.Err(), "Error reading workers")
+		return nil, rows.Err()
+	}
+
+	return workers, nil
+}
+
+func (q *SQLiteJobQueue) DeleteWorker(workerID uuid.UUID) error {
+	tx, err := q.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back delete worker transaction", "worker_id", workerID.String())
+
+	var count int
+	err = tx.QueryRow(sqlQueryHeartbeatsForWorker, workerID).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	// If worker has any active jobs, refuse to remove the worker
+	if count != 0 {
+		return jobqueue.ErrActiveJobs
+	}
+
+	res, err := tx.Exec(sqlDeleteWorker, workerID)
+	if err != nil {
+		q.logger.Error(err, "Error deleting worker")
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		return jobqueue.ErrWorkerNotExist
+	}
+
+	return tx.Commit()
+}
+
+func (q *SQLiteJobQueue) jobDependencies(ctx context.Context, conn connection, id uuid.UUID) ([]uuid.UUID, error) {
+	return queryIDs(ctx, conn, sqlQueryDependencies, id)
+}
+
+func (q *SQLiteJobQueue) jobDependents(ctx context.Context, conn connection, id uuid.UUID) ([]uuid.UUID, error) {
+	return queryIDs(ctx, conn, sqlQueryDependents, id)
+}
+
+// queryIDs returns the ids selected by `query`, never nil.
+func queryIDs(ctx context.Context, conn connection, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return ids, nil
+}
+
+// AllRootJobIDs returns a list of top level job UUIDs that the worker knows about
+func (q *SQLiteJobQueue) AllRootJobIDs(ctx context.Context) ([]uuid.UUID, error) {
+	return queryIDs(ctx, q.db, sqlQueryRootJobs)
+}
+
+func (q *SQLiteJobQueue) CountJobs(ctx context.Context, filter jobqueue.JobFilter) (pending int, running int, err error) {
+	rows, err := q.db.QueryContext(ctx, sqlQueryUnfinishedJobs,
+		sql.Named("channel", filter.Channel),
+		sql.Named("root_only", filter.RootOnly),
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error counting jobs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jobType string
+		var started sql.NullInt64
+		err = rows.Scan(&jobType, &started)
+		if err != nil {
+			return 0, 0, fmt.Errorf("error counting jobs: %w", err)
+		}
+		if len(filter.JobTypes) > 0 && !slices.Contains(filter.JobTypes, jobqueue.BaseJobType(jobType)) {
+			continue
+		}
+		if started.Valid {
+			running += 1
+		} else {
+			pending += 1
+		}
+	}
+	if rows.Err() != nil {
+		return 0, 0, fmt.Errorf("error counting jobs: %w", rows.Err())
+	}
+	return pending, running, nil
+}
+
+func (q *SQLiteJobQueue) ListJobs(ctx context.Context, query jobqueue.JobQuery) ([]uuid.UUID, *jobqueue.JobCursor, error) {
+	// never pass null to json_each()
+	encodedTypes, err := json.Marshal(append([]string{}, query.JobTypes...))
+	if err != nil {
+		return nil, nil, err
+	}
+	encodedStates, err := json.Marshal(append([]jobqueue.JobState{}, query.States...))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	optionalTime := func(t time.Time) sql.NullInt64 {
+		if t.IsZero() {
+			return sql.NullInt64{}
+		}
+		return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+	}
+	var afterQueued sql.NullInt64
+	var afterID string
+	if query.After != nil {
+		afterQueued = optionalTime(query.After.QueuedAt)
+		afterID = query.After.ID.String()
+	}
+	// fetch one more job to know whether there's a next page, -1 means
+	// no limit
+	limit := -1
+	if query.Limit > 0 {
+		limit = query.Limit + 1
+	}
+
+	rows, err := q.db.QueryContext(ctx, sqlListJobs,
+		sql.Named("channel", query.Channel),
+		sql.Named("types", string(encodedTypes)),
+		sql.Named("root_only", query.RootOnly),
+		sql.Named("states", string(encodedStates)),
+		sql.Named("queued_after", optionalTime(query.QueuedAfter)),
+		sql.Named("queued_before", optionalTime(query.QueuedBefore)),
+		sql.Named("after_queued", afterQueued),
+		sql.Named("after_id", afterID),
+		sql.Named("limit", limit),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing jobs: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	var next *jobqueue.JobCursor
+	var lastQueued int64
+	for rows.Next() {
+		var id uuid.UUID
+		var queued int64
+		err = rows.Scan(&id, &queued)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error listing jobs: %w", err)
+		}
+		if query.Limit > 0 && len(ids) == query.Limit {
+			next = &jobqueue.JobCursor{QueuedAt: time.Unix(0, lastQueued), ID: ids[len(ids)-1]}
+			break
+		}
+		ids = append(ids, id)
+		lastQueued = queued
+	}
+	if rows.Err() != nil {
+		return nil, nil, fmt.Errorf("error listing jobs: %w", rows.Err())
+	}
+	return ids, next, nil
+}
+
+// DeleteJob deletes a job and all of its dependencies from the database
+// If a dependency has multiple dependents it will only remove the parent job from
+// the dependents list for that job instead of removing it.
+//
+// This assumes that the jobs have been created correctly, and that they have
+// no dependency loops. Shared Dependents are ok, but a job cannot have a dependency
+// on any of its parents (this should never happen).
+func (q *SQLiteJobQueue) DeleteJob(ctx context.Context, id uuid.UUID) error {
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back delete transaction")
+
+	// Start it off with an empty parent
+	err = q.deleteJobs(ctx, tx, uuid.UUID{}, id)
+	if err != nil {
+		return fmt.Errorf("Error deleting job %s: %w", id.String(), err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %v", err)
+	}
+
+	q.logger.Info("Deleted job", "job_id", id.String())
+
+	return nil
+}
+
+// deleteJobs will delete jobs as far down the list as possible
+// missing dependencies are ignored, it deletes as much as it can.
+// This function is recursive, the first call to it should be with
+// the parent set to uuid.UUID{}
+func (q *SQLiteJobQueue) deleteJobs(ctx context.Context, conn connection, parent, id uuid.UUID) error {
+	// Delete parent:id dependencies if they exist
+	if parent != uuid.Nil {
+		_, err := conn.ExecContext(ctx, sqlDeleteDependencies, parent, id)
+		if err != nil {
+			q.logger.Error(err, "Error deleting dependency")
+			return err
+		}
+	}
+
+	// Get the list of dependents for this id
+	dependents, err := q.jobDependents(ctx, conn, id)
+	if err != nil {
+		return err
+	}
+
+	// If this is > 0 then we are done, cannot delete further
+	if len(dependents) > 0 {
+		return nil
+	}
+
+	// Nothing depends on this job, recursively remove the dependencies
+	deps, err := q.jobDependencies(ctx, conn, id)
+	if err != nil {
+		return err
+	}
+	for _, d := range deps {
+		_ = q.deleteJobs(ctx, conn, id, d) // Recursively delete dependencies
+	}
+
+	// the CASCADE constraint will also delete any entries from the
+	// job_dependencies and heartbeats tables
+	_, err = conn.ExecContext(ctx, sqlDelete, id)
+	if err != nil {
+		q.logger.Error(err, "Error deleting job")
+		return err
+	}
+	return nil
+}
+
+// ExportJobs implements jobqueue.JobExporter.
+func (q *SQLiteJobQueue) ExportJobs(ctx context.Context, fn func(jobqueue.JobRecord) error) error {
+	// read everything before calling fn, which might use the queue, too
+	records, err := q.exportJobs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		err = fn(r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *SQLiteJobQueue) exportJobs(ctx context.Context) ([]jobqueue.JobRecord, error) {
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back export transaction")
+
+	dependencies := make(map[uuid.UUID][]uuid.UUID)
+	rows, err := tx.QueryContext(ctx, sqlExportDependencies)
+	if err != nil {
+		return nil, fmt.Errorf("error querying dependencies: %w", err)
+	}
+	for rows.Next() {
+		var id, dep uuid.UUID
+		err = rows.Scan(&id, &dep)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("error reading dependency: %w", err)
+		}
+		dependencies[id] = append(dependencies[id], dep)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error reading dependencies: %w", rows.Err())
+	}
+
+	rows, err = tx.QueryContext(ctx, sqlExportJobs)
+	if err != nil {
+		return nil, fmt.Errorf("error querying jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var records []jobqueue.JobRecord
+	for rows.Next() {
+		var r jobqueue.JobRecord
+		var token uuid.NullUUID
+		var args, result []byte
+		var queued int64
+		var notBefore, started, finished sql.NullInt64
+		var requiredLabels string
+		err = rows.Scan(&r.ID, &token, &r.Type, &r.Channel, &args, &result, &r.Priority, &r.Retries, &r.Canceled,
+			&queued, &notBefore, &started, &finished, &requiredLabels)
+		if err != nil {
+			return nil, fmt.Errorf("error reading job: %w", err)
+		}
+		r.RequiredLabels, err = decodeLabels(requiredLabels)
+		if err != nil {
+			return nil, fmt.Errorf("error reading labels of job %s: %w", r.ID, err)
+		}
+		r.Token = token.UUID
+		if args != nil {
+			r.Args = args
+		}
+		if result != nil {
+			r.Result = result
+		}
+		r.QueuedAt = time.Unix(0, queued)
+		r.NotBefore = timeFromNullInt64(notBefore)
+		r.StartedAt = timeFromNullInt64(started)
+		r.FinishedAt = timeFromNullInt64(finished)
+		r.Dependencies = dependencies[r.ID]
+		records = append(records, r)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("error reading jobs: %w", rows.Err())
+	}
+	return records, nil
+}
+
+// ImportJob implements jobqueue.JobImporter.
+func (q *SQLiteJobQueue) ImportJob(ctx context.Context, r jobqueue.JobRecord) error {
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting database transaction: %w", err)
+	}
+	defer q.rollback(tx, "Error rolling back import transaction", "job_id", r.ID.String())
+
+	optionalTime := func(t time.Time) sql.NullInt64 {
+		if t.IsZero() {
+			return sql.NullInt64{}
+		}
+		return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+	}
+	optionalJSON := func(data json.RawMessage) sql.NullString {
+		return sql.NullString{String: string(data), Valid: data != nil}
+	}
+	requiredLabels, err := encodeLabels(r.RequiredLabels)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, sqlImportJob, r.ID, uuid.NullUUID{UUID: r.Token, Valid: r.Token != uuid.Nil}, r.Type, r.Channel,
+		optionalJSON(r.Args), optionalJSON(r.Result), r.Priority, r.Retries, r.Canceled,
+		r.QueuedAt.UnixNano(), optionalTime(r.NotBefore), optionalTime(r.StartedAt), optionalTime(r.FinishedAt), requiredLabels)
+	if err != nil {
+		return fmt.Errorf("error importing job %s: %w", r.ID, err)
+	}
+
+	for _, d := range r.Dependencies {
+		_, err = tx.ExecContext(ctx, sqlInsertDependency, r.ID, d)
+		if err != nil {
+			return fmt.Errorf("error inserting dependency %s of job %s: %w", d, r.ID, err)
+		}
+	}
+
+	if !r.StartedAt.IsZero() && r.FinishedAt.IsZero() && !r.Canceled {
+		_, err = tx.ExecContext(ctx, sqlInsertHeartbeat,
+			sql.Named("token", r.Token),
+			sql.Named("id", r.ID),
+			sql.Named("worker_id", uuid.NullUUID{}),
+			sql.Named("now", time.Now().UnixNano()),
+		)
+		if err != nil {
+			return fmt.Errorf("error inserting the heartbeat of job %s: %w", r.ID, err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("unable to commit database transaction: %w", err)
+	}
+	q.dequeuers.notifyAll()
+
+	return nil
+}
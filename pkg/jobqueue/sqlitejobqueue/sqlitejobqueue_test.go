@@ -0,0 +1,70 @@
+package sqlitejobqueue_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue/sqlitejobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/public/jobqueue/jobqueuetest"
+)
+
+func TestJobQueueInterface(t *testing.T) {
+	jobqueuetest.TestJobQueue(t, func() (jobqueue.JobQueue, func(), error) {
+		q, err := sqlitejobqueue.New(path.Join(t.TempDir(), "jobs.sqlite"))
+		if err != nil {
+			return nil, nil, err
+		}
+		return q, q.Close, nil
+	})
+}
+
+func TestScheduling(t *testing.T) {
+	jobqueuetest.TestScheduling(t, func(config jobqueue.SchedulingConfig) (jobqueue.JobQueue, func(), error) {
+		q, err := sqlitejobqueue.NewWithConfig(path.Join(t.TempDir(), "jobs.sqlite"), sqlitejobqueue.Config{Scheduling: config})
+		if err != nil {
+			return nil, nil, err
+		}
+		return q, q.Close, nil
+	})
+}
+
+func TestInvalidSchedulingConfig(t *testing.T) {
+	q, err := sqlitejobqueue.NewWithConfig(path.Join(t.TempDir(), "jobs.sqlite"), sqlitejobqueue.Config{
+		Scheduling: jobqueue.SchedulingConfig{Policy: "lottery"},
+	})
+	require.Error(t, err)
+	require.Nil(t, q)
+}
+
+func TestReopen(t *testing.T) {
+	dbPath := path.Join(t.TempDir(), "jobs.sqlite")
+
+	q, err := sqlitejobqueue.New(dbPath)
+	require.NoError(t, err)
+	dep, err := q.Enqueue("octopus", nil, nil, "")
+	require.NoError(t, err)
+	id, err := q.Enqueue("clownfish", "🐠", []uuid.UUID{dep}, "org-A")
+	require.NoError(t, err)
+	q.Close()
+
+	// the schema is not migrated again and the jobs are still there
+	q, err = sqlitejobqueue.New(dbPath)
+	require.NoError(t, err)
+	defer q.Close()
+	jobType, args, deps, channel, err := q.Job(id)
+	require.NoError(t, err)
+	require.Equal(t, "clownfish", jobType)
+	require.JSONEq(t, `"🐠"`, string(args))
+	require.Equal(t, []uuid.UUID{dep}, deps)
+	require.Equal(t, "org-A", channel)
+}
+
+func TestNonExistentDirectory(t *testing.T) {
+	q, err := sqlitejobqueue.New("/non-existant-directory/jobs.sqlite")
+	require.Error(t, err)
+	require.Nil(t, q)
+}
@@ -0,0 +1,59 @@
+// Package artifacts implements the storage of the artifacts workers upload
+// to composer, e.g. the images of the composes without an upload target.
+//
+// Artifacts are addressed by keys made of slash-separated path elements,
+// like "<job id>/disk.qcow2". Two implementations exist: Local keeps the
+// artifacts in a directory of composer's host, S3 keeps them in a bucket of
+// an S3-compatible object store, so that several composer replicas can share
+// them.
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+var ErrNotFound = errors.New("artifact not found")
+
+type Store interface {
+	// Put stores the content of r under key, replacing the artifact which
+	// might exist there already.
+	Put(ctx context.Context, key string, r io.Reader) error
+
+	// Get opens the artifact stored under key and returns its size. The
+	// caller has to close the returned reader. It can seek, so that parts
+	// of large artifacts are read without what comes before them. The
+	// error wraps ErrNotFound if the artifact doesn't exist.
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, int64, error)
+
+	// Stat returns the size of the artifact stored under key. The error
+	// wraps ErrNotFound if the artifact doesn't exist.
+	Stat(ctx context.Context, key string) (int64, error)
+
+	// Delete removes the artifact stored under key together with all the
+	// artifacts under the "key/" prefix. Deleting artifacts which don't
+	// exist isn't an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the distinct first path elements of the keys of all the
+	// stored artifacts.
+	List(ctx context.Context) ([]string, error)
+
+	// Location returns where the artifact stored under key lives, a path
+	// for the local store and an s3:// URL for S3. The artifact doesn't
+	// need to exist.
+	Location(key string) string
+}
+
+// Keys are relative, slash-separated paths without "." and ".." elements,
+// names of uploaded artifacts come from the workers and must not be able to
+// escape their job's prefix.
+func checkKey(key string) error {
+	if !fs.ValidPath(key) || key == "." {
+		return fmt.Errorf("invalid artifact key: %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package artifacts_test
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/artifacts"
+)
+
+// testStore verifies that a Store implementation satisfies the interface
+func testStore(t *testing.T, store artifacts.Store) {
+	ctx := context.Background()
+
+	read := func(key string) string {
+		r, size, err := store.Get(ctx, key)
+		require.NoError(t, err)
+		defer r.Close()
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(content)), size)
+		return string(content)
+	}
+
+	names, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	require.NoError(t, store.Put(ctx, "job-1/disk.qcow2", strings.NewReader("disk image")))
+	require.NoError(t, store.Put(ctx, "job-1/manifest.json", strings.NewReader("{}")))
+	require.NoError(t, store.Put(ctx, "job-2/disk.raw", strings.NewReader("raw")))
+	require.NoError(t, store.Put(ctx, "ComposeRequest/job-1.json", strings.NewReader(`{"distribution":"fedora"}`)))
+
+	require.Equal(t, "disk image", read("job-1/disk.qcow2"))
+	require.Equal(t, "{}", read("job-1/manifest.json"))
+
+	// read from an offset
+	r, _, err := store.Get(ctx, "job-1/disk.qcow2")
+	require.NoError(t, err)
+	defer r.Close()
+	offset, err := r.Seek(5, io.SeekStart)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), offset)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "image", string(content))
+	offset, err = r.Seek(-5, io.SeekEnd)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), offset)
+	content, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "image", string(content))
+
+	// replace an existing artifact
+	require.NoError(t, store.Put(ctx, "job-2/disk.raw", strings.NewReader("another raw")))
+	require.Equal(t, "another raw", read("job-2/disk.raw"))
+
+	size, err := store.Stat(ctx, "job-2/disk.raw")
+	require.NoError(t, err)
+	require.Equal(t, int64(len("another raw")), size)
+
+	_, err = store.Stat(ctx, "job-2/missing")
+	require.ErrorIs(t, err, artifacts.ErrNotFound)
+	_, _, err = store.Get(ctx, "job-3/disk.raw")
+	require.ErrorIs(t, err, artifacts.ErrNotFound)
+
+	names, err = store.List(ctx)
+	require.NoError(t, err)
+	sort.Strings(names)
+	require.Equal(t, []string{"ComposeRequest", "job-1", "job-2"}, names)
+
+	require.NoError(t, store.Delete(ctx, "job-1"))
+	require.NoError(t, store.Delete(ctx, "ComposeRequest/job-1.json"))
+	_, err = store.Stat(ctx, "job-1/disk.qcow2")
+	require.ErrorIs(t, err, artifacts.ErrNotFound)
+	_, err = store.Stat(ctx, "ComposeRequest/job-1.json")
+	require.ErrorIs(t, err, artifacts.ErrNotFound)
+	require.Equal(t, "another raw", read("job-2/disk.raw"))
+
+	// deleting what doesn't exist is fine
+	require.NoError(t, store.Delete(ctx, "job-1"))
+
+	for _, key := range []string{"", ".", "../escape", "job-2/../../escape", "/absolute"} {
+		require.Error(t, store.Put(ctx, key, strings.NewReader("bad")), key)
+		_, _, err = store.Get(ctx, key)
+		require.Error(t, err, key)
+		require.Error(t, store.Delete(ctx, key), key)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	store := artifacts.NewLocal(dir)
+	testStore(t, store)
+	require.Equal(t, dir+"/job-2/disk.raw", store.Location("job-2/disk.raw"))
+}
+
+func TestLocalMissingDir(t *testing.T) {
+	store := artifacts.NewLocal(t.TempDir() + "/artifacts")
+	names, err := store.List(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
@@ -0,0 +1,299 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/transfermanager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+type S3Config struct {
+	// Endpoint of an S3-compatible object store, e.g. MinIO or Ceph. AWS
+	// is used when it's empty.
+	Endpoint string
+	Region   string
+	Bucket   string
+	// Prefix of the object keys of all the artifacts, so that the bucket
+	// can be shared with other data.
+	Prefix string
+
+	// Static credentials. The default AWS credential chain is used when
+	// AccessKeyID is empty.
+	AccessKeyID     string
+	SecretAccessKey string
+
+	CABundle            string
+	SkipSSLVerification bool
+}
+
+// S3 stores the artifacts as objects of an S3 bucket, the keys are object
+// keys relative to the configured prefix.
+type S3 struct {
+	client   *s3.Client
+	uploader *transfermanager.Client
+	bucket   string
+	prefix   string
+}
+
+var _ Store = &S3{}
+
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("no bucket set for the S3 artifact store")
+	}
+
+	optFns := []func(*config.LoadOptions) error{
+		config.WithRegion(cfg.Region),
+	}
+	if cfg.AccessKeyID != "" {
+		optFns = append(optFns, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")))
+	}
+
+	if cfg.CABundle != "" {
+		caBundle, err := os.Open(cfg.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		defer caBundle.Close()
+		optFns = append(optFns, config.WithCustomCABundle(caBundle))
+	}
+
+	if cfg.SkipSSLVerification {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} // #nosec G402
+		optFns = append(optFns, config.WithHTTPClient(&http.Client{
+			Transport: transport,
+		}))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			// object stores other than AWS don't usually support
+			// virtual-hosted-style bucket addressing
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &S3{
+		client:   client,
+		uploader: transfermanager.New(client),
+		bucket:   cfg.Bucket,
+		prefix:   strings.Trim(cfg.Prefix, "/"),
+	}, nil
+}
+
+func (s *S3) objectKey(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return path.Join(s.prefix, key)
+}
+
+// isNotFound checks for the errors of the S3 API for a missing object, HEAD
+// requests don't have a body and only return "NotFound".
+func isNotFound(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchKey", "NotFound":
+			return true
+		}
+	}
+	return false
+}
+
+// Put uploads the artifact in parts if it's larger than a single part, so
+// that its size doesn't need to be known in advance.
+func (s *S3) Put(ctx context.Context, key string, r io.Reader) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	_, err := s.uploader.UploadObject(ctx, &transfermanager.UploadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(key)),
+		Body:   r,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot upload artifact %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get only looks the object up, its content is fetched with ranged GET
+// requests on the first read and after seeking.
+func (s *S3) Get(ctx context.Context, key string) (io.ReadSeekCloser, int64, error) {
+	size, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &s3Object{
+		ctx:  ctx,
+		s:    s,
+		key:  key,
+		size: size,
+	}, size, nil
+}
+
+func (s *S3) Stat(ctx context.Context, key string) (int64, error) {
+	if err := checkKey(key); err != nil {
+		return 0, err
+	}
+
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if isNotFound(err) {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+	} else if err != nil {
+		return 0, fmt.Errorf("cannot stat artifact %s: %w", key, err)
+	}
+	return aws.ToInt64(out.ContentLength), nil
+}
+
+// Delete removes the objects one by one, DeleteObjects requires checksums
+// which not all S3-compatible stores implement.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	objectKeys := []string{s.objectKey(key)}
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.objectKey(key) + "/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot list artifacts under %s: %w", key, err)
+		}
+		for _, obj := range page.Contents {
+			objectKeys = append(objectKeys, aws.ToString(obj.Key))
+		}
+	}
+
+	for _, k := range objectKeys {
+		_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(k),
+		})
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("cannot delete artifact %s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+func (s *S3) List(ctx context.Context) ([]string, error) {
+	prefix := ""
+	if s.prefix != "" {
+		prefix = s.prefix + "/"
+	}
+
+	var names []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(s.bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list artifacts: %w", err)
+		}
+		for _, p := range page.CommonPrefixes {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(aws.ToString(p.Prefix), prefix), "/"))
+		}
+		for _, obj := range page.Contents {
+			names = append(names, strings.TrimPrefix(aws.ToString(obj.Key), prefix))
+		}
+	}
+	return names, nil
+}
+
+func (s *S3) Location(key string) string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, s.objectKey(key))
+}
+
+// s3Object reads an object from its current offset to the end with a single
+// GET request, which is only closed when seeking to another offset.
+type s3Object struct {
+	ctx    context.Context
+	s      *S3
+	key    string
+	size   int64
+	offset int64
+	body   io.ReadCloser
+}
+
+func (o *s3Object) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+	if o.body == nil {
+		out, err := o.s.client.GetObject(o.ctx, &s3.GetObjectInput{
+			Bucket: aws.String(o.s.bucket),
+			Key:    aws.String(o.s.objectKey(o.key)),
+			Range:  aws.String(fmt.Sprintf("bytes=%d-", o.offset)),
+		})
+		if isNotFound(err) {
+			return 0, fmt.Errorf("%w: %s", ErrNotFound, o.key)
+		} else if err != nil {
+			return 0, fmt.Errorf("cannot get artifact %s: %w", o.key, err)
+		}
+		o.body = out.Body
+	}
+	n, err := o.body.Read(p)
+	o.offset += int64(n)
+	return n, err
+}
+
+func (o *s3Object) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("cannot seek to negative offset %d", offset)
+	}
+	if offset != o.offset && o.body != nil {
+		o.body.Close()
+		o.body = nil
+	}
+	o.offset = offset
+	return offset, nil
+}
+
+func (o *s3Object) Close() error {
+	if o.body == nil {
+		return nil
+	}
+	err := o.body.Close()
+	o.body = nil
+	return err
+}
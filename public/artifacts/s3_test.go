@@ -0,0 +1,200 @@
+package artifacts_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/artifacts"
+)
+
+// fakeS3 is a stand-in for an S3-compatible object store like MinIO. It
+// implements just the path-style object and listing requests of a single
+// bucket the S3 store sends, ranges of objects only with a start offset.
+type fakeS3 struct {
+	bucket string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+type listBucketResult struct {
+	XMLName        xml.Name `xml:"ListBucketResult"`
+	Name           string
+	Prefix         string
+	KeyCount       int
+	IsTruncated    bool
+	Contents       []listObject
+	CommonPrefixes []listPrefix
+}
+
+type listObject struct {
+	Key  string
+	Size int
+}
+
+type listPrefix struct {
+	Prefix string
+}
+
+func newFakeS3(t *testing.T, bucket string) (*fakeS3, *httptest.Server) {
+	s := &fakeS3{
+		bucket:  bucket,
+		objects: map[string][]byte{},
+	}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.bucket {
+		s.error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key == "" {
+		if r.Method != http.MethodGet || r.URL.Query().Get("list-type") != "2" {
+			s.error(w, http.StatusNotImplemented, "NotImplemented")
+			return
+		}
+		s.list(w, r.URL.Query().Get("prefix"), r.URL.Query().Get("delimiter"))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			s.error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		s.objects[key] = content
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		content, ok := s.objects[key]
+		if !ok {
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			s.error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		status := http.StatusOK
+		if start, ok := strings.CutPrefix(r.Header.Get("Range"), "bytes="); ok {
+			offset, err := strconv.Atoi(strings.TrimSuffix(start, "-"))
+			if err != nil || offset > len(content) {
+				s.error(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange")
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(content)-1, len(content)))
+			content = content[offset:]
+			status = http.StatusPartialContent
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(content)
+		}
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.error(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (s *fakeS3) list(w http.ResponseWriter, prefix, delimiter string) {
+	result := listBucketResult{
+		Name:   s.bucket,
+		Prefix: prefix,
+	}
+
+	seen := map[string]bool{}
+	for key, content := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				p := key[:len(prefix)+i+len(delimiter)]
+				if !seen[p] {
+					seen[p] = true
+					result.CommonPrefixes = append(result.CommonPrefixes, listPrefix{p})
+				}
+				continue
+			}
+		}
+		result.Contents = append(result.Contents, listObject{Key: key, Size: len(content)})
+	}
+	sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+	sort.Slice(result.CommonPrefixes, func(i, j int) bool { return result.CommonPrefixes[i].Prefix < result.CommonPrefixes[j].Prefix })
+	result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func (s *fakeS3) error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, "<Error><Code>"+code+"</Code><Message>"+code+"</Message></Error>")
+}
+
+func (s *fakeS3) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.objects))
+	for k := range s.objects {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newTestS3Store(t *testing.T, endpoint, prefix string) *artifacts.S3 {
+	store, err := artifacts.NewS3(artifacts.S3Config{
+		Endpoint:        endpoint,
+		Region:          "us-east-1",
+		Bucket:          "artifacts",
+		Prefix:          prefix,
+		AccessKeyID:     "access-key",
+		SecretAccessKey: "secret-key",
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func TestS3(t *testing.T) {
+	_, srv := newFakeS3(t, "artifacts")
+	store := newTestS3Store(t, srv.URL, "")
+	testStore(t, store)
+	require.Equal(t, "s3://artifacts/job-2/disk.raw", store.Location("job-2/disk.raw"))
+}
+
+func TestS3Prefix(t *testing.T) {
+	fake, srv := newFakeS3(t, "artifacts")
+	store := newTestS3Store(t, srv.URL, "/composer/artifacts/")
+	testStore(t, store)
+	require.Equal(t, []string{"composer/artifacts/job-2/disk.raw"}, fake.keys())
+	require.Equal(t, "s3://artifacts/composer/artifacts/job-2/disk.raw", store.Location("job-2/disk.raw"))
+}
+
+func TestS3NoBucket(t *testing.T) {
+	_, err := artifacts.NewS3(artifacts.S3Config{Region: "us-east-1"})
+	require.Error(t, err)
+}
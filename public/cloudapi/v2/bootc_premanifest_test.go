@@ -18,6 +18,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/distro"
 	"github.com/osbuild/image-builder/pkg/manifest"
 	v2 "github.com/ondrejbudai/osbuild-composer-public/public/cloudapi/v2"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/common"
 	"github.com/ondrejbudai/osbuild-composer-public/public/jobqueue/fsjobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/target"
@@ -273,7 +274,7 @@ func TestHandleBootcPreManifest_Errors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ws := newTestWorkerServer(t)
-			preManifestJobID, err := ws.EnqueueBootcPreManifestJob(tt.job, nil, "")
+			preManifestJobID, err := ws.EnqueueBootcPreManifestJob(tt.job, nil, "", jobqueue.EnqueueOptions{})
 			require.NoError(t, err)
 			jobID, token, _, _, _, err := ws.RequestJob(
 				context.Background(), "",
@@ -317,7 +318,7 @@ func enqueuePreManifestWithResolvedDep(t *testing.T, ws *worker.Server, arch str
 	infoResolveJob := &worker.BootcInfoResolveJob{
 		Specs: specs,
 	}
-	infoResolveJobID, err := ws.EnqueueBootcInfoResolveJob(arch, infoResolveJob, "")
+	infoResolveJobID, err := ws.EnqueueBootcInfoResolveJob(arch, infoResolveJob, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	preManifestJob := &worker.BootcPreManifestJob{
@@ -330,6 +331,7 @@ func enqueuePreManifestWithResolvedDep(t *testing.T, ws *worker.Server, arch str
 	}
 	preManifestJobID, err := ws.EnqueueBootcPreManifestJob(
 		preManifestJob, []uuid.UUID{infoResolveJobID}, "",
+		jobqueue.EnqueueOptions{},
 	)
 	require.NoError(t, err)
 
@@ -756,7 +758,7 @@ func TestSerializeManifest_PreManifestErrors(t *testing.T) {
 				Seed:                       42,
 				BootcInfoResolveDynArgsIdx: common.ToPtr(0),
 			}
-			preManifestJobID, err := workerServer.EnqueueBootcPreManifestJob(preManifestJob, nil, "")
+			preManifestJobID, err := workerServer.EnqueueBootcPreManifestJob(preManifestJob, nil, "", jobqueue.EnqueueOptions{})
 			require.NoError(t, err)
 
 			jobID, token, _, _, _, err := workerServer.RequestJob(
@@ -776,6 +778,7 @@ func TestSerializeManifest_PreManifestErrors(t *testing.T) {
 				&worker.ManifestJobByID{},
 				[]uuid.UUID{preManifestJobID},
 				"",
+				jobqueue.EnqueueOptions{},
 			)
 			require.NoError(t, err)
 
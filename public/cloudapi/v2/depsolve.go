@@ -14,6 +14,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/reporegistry"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 )
 
@@ -112,7 +113,7 @@ func (request *DepsolveRequest) Depsolve(df *distrofactory.Factory, rr *reporegi
 		Arch:             distroArch.Name(),
 		Releasever:       distro.Releasever(),
 		SbomType:         sbom.StandardTypeNone,
-	}, "")
+	}, "", jobqueue.EnqueueOptions{Priority: worker.JobPriorityInteractive})
 	if err != nil {
 		return nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
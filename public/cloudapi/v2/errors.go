@@ -58,6 +58,9 @@ const (
 	ErrorDistroMissing                ServiceErrorCode = 45
 	ErrorIsoPayloadReferenceForbidden ServiceErrorCode = 46
 	ErrorBootcOnlyImageType           ServiceErrorCode = 47
+	ErrorComposeQuotaExceeded         ServiceErrorCode = 48
+	ErrorComposeFinished              ServiceErrorCode = 49
+	ErrorS3CredentialsForbidden       ServiceErrorCode = 50
 
 	// Internal errors, these are bugs
 	ErrorFailedToInitializeBlueprint              ServiceErrorCode = 1000
@@ -86,6 +89,7 @@ const (
 	ErrorDeletingJob                              ServiceErrorCode = 1023
 	ErrorDeletingArtifacts                        ServiceErrorCode = 1024
 	ErrorGettingImageTypes                        ServiceErrorCode = 1025
+	ErrorCancelingJob                             ServiceErrorCode = 1026
 
 	// Errors contained within this file
 	ErrorUnspecified          ServiceErrorCode = 10000
@@ -153,6 +157,9 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorDistroMissing, http.StatusBadRequest, "Invalid request, distribution is required for this compose request"},
 		serviceError{ErrorIsoPayloadReferenceForbidden, http.StatusBadRequest, "iso_payload_reference must not be set for non-ISO bootc image types"},
 		serviceError{ErrorBootcOnlyImageType, http.StatusBadRequest, "bootable-container-iso image type requires a bootc compose request (use 'bootc' instead of 'distribution')"},
+		serviceError{ErrorComposeQuotaExceeded, http.StatusTooManyRequests, "Tenant has reached the maximum number of unfinished composes"},
+		serviceError{ErrorComposeFinished, http.StatusBadRequest, "Compose has already finished"},
+		serviceError{ErrorS3CredentialsForbidden, http.StatusForbidden, "Tenant is not allowed to use the S3 credentials"},
 
 		serviceError{ErrorFailedToInitializeBlueprint, http.StatusInternalServerError, "Failed to initialize blueprint"},
 		serviceError{ErrorFailedToGenerateManifestSeed, http.StatusInternalServerError, "Failed to generate manifest seed"},
@@ -179,6 +186,7 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorDeletingJob, http.StatusBadRequest, "Unable to delete job"},
 		serviceError{ErrorDeletingArtifacts, http.StatusInternalServerError, "Unable to delete job artifacts"},
 		serviceError{ErrorGettingImageTypes, http.StatusInternalServerError, "Unable to get list of image types"},
+		serviceError{ErrorCancelingJob, http.StatusInternalServerError, "Unable to cancel job"},
 
 		serviceError{ErrorUnspecified, http.StatusInternalServerError, "Unspecified internal error "},
 		serviceError{ErrorNotHTTPError, http.StatusInternalServerError, "Error is not an instance of HTTPError"},
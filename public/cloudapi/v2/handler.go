@@ -2,13 +2,11 @@
 package v2
 
 import (
-	"cmp"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"os"
-	"path"
 	"slices"
 	"sort"
 	"strconv"
@@ -26,8 +24,8 @@ import (
 	"github.com/osbuild/image-builder/pkg/osbuild"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
 	"github.com/osbuild/image-builder/pkg/sbom"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/common"
-	"github.com/ondrejbudai/osbuild-composer-public/public/jsondb"
 	"github.com/ondrejbudai/osbuild-composer-public/public/target"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker/clienterrors"
@@ -143,6 +141,16 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		return HTTPError(ErrorDistroAndBootcMissing)
 	}
 
+	err = h.server.checkComposeQuota(ctx.Request().Context(), channel)
+	if err != nil {
+		return err
+	}
+
+	err = h.server.checkS3CredentialsRefs(request, channel)
+	if err != nil {
+		return err
+	}
+
 	var irs []imageRequest
 	if request.Distribution != nil {
 		if request.HasImageType(ImageTypesBootableContainerIso) {
@@ -154,27 +162,32 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 		}
 	}
 
+	var opts jobqueue.EnqueueOptions
+	if request.Priority != nil {
+		opts.Priority = *request.Priority
+	}
+
 	var id uuid.UUID
 	if request.Koji != nil {
 		if request.Koji.TaskId < 0 {
 			return fmt.Errorf("invalid Koji task ID: %d", request.Koji.TaskId)
 		}
-		id, err = h.server.enqueueKojiCompose(uint64(request.Koji.TaskId), request.Koji.Server, request.Koji.Name, request.Koji.Version, request.Koji.Release, irs, channel) // nolint: gosec
+		id, err = h.server.enqueueKojiCompose(uint64(request.Koji.TaskId), request.Koji.Server, request.Koji.Name, request.Koji.Version, request.Koji.Release, irs, channel, opts) // nolint: gosec
 		if err != nil {
 			return err
 		}
 	} else if h.server.config.ImageBuilderManifestGeneration {
-		id, err = h.server.enqueueComposeIBCLI(irs, channel)
+		id, err = h.server.enqueueComposeIBCLI(irs, channel, opts)
 		if err != nil {
 			return err
 		}
 	} else if request.Bootc != nil {
-		id, err = h.server.enqueueBootcCompose(request, channel)
+		id, err = h.server.enqueueBootcCompose(request, channel, opts)
 		if err != nil {
 			return err
 		}
 	} else {
-		id, err = h.server.enqueueCompose(irs, channel)
+		id, err = h.server.enqueueCompose(irs, channel, opts)
 		if err != nil {
 			return err
 		}
@@ -183,7 +196,7 @@ func (h *apiHandlers) PostCompose(ctx echo.Context) error {
 	ctx.Logger().Infof("Job ID %s enqueued for operationID %s", id, ctx.Get(common.OperationIDKey))
 
 	// Save the request in the artifacts directory, log errors but continue
-	if err := saveComposeRequest(h.server.workers.ArtifactsDir(), id, request); err != nil {
+	if err := h.server.workers.SaveComposeRequest(id, request); err != nil {
 		ctx.Logger().Warnf("Failed to save compose request: %v", err)
 	}
 
@@ -268,6 +281,18 @@ func imageTypeFromApiImageType(it ImageTypes) string {
 	return ""
 }
 
+// targetResultOptions returns the options of a target result, or empty
+// options if the result has none. Failed uploads don't return any options.
+func targetResultOptions[T any, PT interface {
+	*T
+	target.TargetResultOptions
+}](t *target.TargetResult) PT {
+	if options, ok := t.Options.(PT); ok {
+		return options
+	}
+	return new(T)
+}
+
 func (h *apiHandlers) targetResultToUploadStatus(jobId uuid.UUID, t *target.TargetResult) (*UploadStatus, error) {
 	var uploadType UploadTypes
 	var fromErr error
@@ -313,6 +338,40 @@ func (h *apiHandlers) targetResultToUploadStatus(jobId uuid.UUID, t *target.Targ
 		fromErr = uploadOptions.FromOCIUploadStatus(OCIUploadStatus{
 			Url: ociOptions.URL,
 		})
+	case target.TargetNamePulpOSTree:
+		uploadType = UploadTypesPulpOstree
+		pulpOptions := targetResultOptions[target.PulpOSTreeTargetResultOptions](t)
+		fromErr = uploadOptions.FromPulpOSTreeUploadStatus(PulpOSTreeUploadStatus{
+			RepoUrl: pulpOptions.RepoURL,
+		})
+	case target.TargetNameVMWare:
+		uploadType = UploadTypesVmware
+		vmwareOptions := targetResultOptions[target.VMWareTargetResultOptions](t)
+		status := VMwareUploadStatus{
+			ImageName:  vmwareOptions.ImageName,
+			Host:       vmwareOptions.Host,
+			Datacenter: vmwareOptions.Datacenter,
+			Datastore:  vmwareOptions.Datastore,
+		}
+		if vmwareOptions.Folder != "" {
+			status.Folder = common.ToPtr(vmwareOptions.Folder)
+		}
+		fromErr = uploadOptions.FromVMwareUploadStatus(status)
+	case target.TargetNameGenericS3:
+		uploadType = UploadTypesGenericS3
+		genericS3Options := targetResultOptions[target.GenericS3TargetResultOptions](t)
+		fromErr = uploadOptions.FromGenericS3UploadStatus(GenericS3UploadStatus{
+			Url:    genericS3Options.URL,
+			Bucket: genericS3Options.Bucket,
+			Key:    genericS3Options.Key,
+		})
+	case target.TargetNameSFTP:
+		uploadType = UploadTypesSftp
+		sftpOptions := targetResultOptions[target.SFTPTargetResultOptions](t)
+		fromErr = uploadOptions.FromSFTPUploadStatus(SFTPUploadStatus{
+			Path:     sftpOptions.Path,
+			Checksum: sftpOptions.Checksum,
+		})
 	case target.TargetNameWorkerServer:
 		uploadType = UploadTypesLocal
 		workerServerOptions := t.Options.(*target.WorkerServerTargetResultOptions)
@@ -342,30 +401,104 @@ func (h *apiHandlers) targetResultToUploadStatus(jobId uuid.UUID, t *target.Targ
 	return us, nil
 }
 
-// GetComposeList returns a list of the root job UUIDs
-func (h *apiHandlers) GetComposeList(ctx echo.Context) error {
-	jobs, err := h.server.workers.AllRootJobIDs(ctx.Request().Context())
+// GetComposeList returns the statuses of the tenant's composes. Paged lists
+// are ordered by creation time, most recent first. Without a limit, all
+// composes are returned ordered by their id, like before paging was added.
+func (h *apiHandlers) GetComposeList(ctx echo.Context, params GetComposeListParams) error {
+	channel, err := h.server.getTenantChannel(ctx)
 	if err != nil {
-		return HTTPErrorWithInternal(ErrorGettingComposeList, err)
+		return HTTPErrorWithInternal(ErrorTenantNotFound, err)
 	}
 
-	// Gather up the details of each job
-	stats := []ComposeStatus{}
-	for _, jid := range jobs {
-		s, err := h.getJobIDComposeStatus(jid)
+	offset := 0
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+
+	query := jobqueue.JobQuery{
+		JobFilter: jobqueue.JobFilter{Channel: channel},
+	}
+	if params.CreatedAfter != nil {
+		query.QueuedAfter = *params.CreatedAfter
+	}
+	// Whether a finished compose succeeded depends on its results, which
+	// the job queue doesn't know about. Narrow the selection down as much
+	// as possible and check the status of each compose below.
+	if params.Status != nil {
+		switch *params.Status {
+		case ComposeStatusValuePending:
+			query.States = []jobqueue.JobState{jobqueue.JobPending, jobqueue.JobRunning}
+		case ComposeStatusValueSuccess:
+			query.States = []jobqueue.JobState{jobqueue.JobFinished}
+		case ComposeStatusValueFailure:
+			query.States = []jobqueue.JobState{jobqueue.JobFinished, jobqueue.JobCanceled}
+		}
+	}
+
+	if params.Limit == nil {
+		stats, err := h.composeStatuses(ctx, query, params.Status, 0, 0)
 		if err != nil {
-			// TODO log this error?
-			continue
+			return HTTPErrorWithInternal(ErrorGettingComposeList, err)
 		}
-		stats = append(stats, s)
+		slices.SortFunc(stats, func(a, b ComposeStatus) int {
+			return strings.Compare(a.Id, b.Id)
+		})
+		return ctx.JSON(http.StatusOK, stats[min(offset, len(stats)):])
 	}
-	slices.SortFunc(stats, func(a, b ComposeStatus) int {
-		return cmp.Compare(a.Id, b.Id)
-	})
 
+	query.Limit = offset + *params.Limit
+	stats, err := h.composeStatuses(ctx, query, params.Status, offset, *params.Limit)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingComposeList, err)
+	}
 	return ctx.JSON(http.StatusOK, stats)
 }
 
+// composeStatuses pages through the composes of the query until it found
+// `limit` of them with the status `status` after skipping `offset`. A limit of
+// 0 returns all of them.
+func (h *apiHandlers) composeStatuses(ctx echo.Context, query jobqueue.JobQuery, status *ComposeStatusValue, offset, limit int) ([]ComposeStatus, error) {
+	stats := []ComposeStatus{}
+	for {
+		jobs, next, err := h.server.workers.ListComposes(ctx.Request().Context(), query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, jid := range jobs {
+			// skip without looking at the compose if it doesn't have to
+			// be filtered by its status
+			if status == nil && offset > 0 {
+				offset--
+				continue
+			}
+
+			s, err := h.getJobIDComposeStatus(jid)
+			if err != nil {
+				// TODO log this error?
+				continue
+			}
+			if status != nil && s.Status != *status {
+				continue
+			}
+			if offset > 0 {
+				offset--
+				continue
+			}
+
+			stats = append(stats, s)
+			if len(stats) == limit {
+				return stats, nil
+			}
+		}
+
+		if next == nil {
+			return stats, nil
+		}
+		query.After = next
+	}
+}
+
 // DeleteCompose deletes a compose by UUID
 func (h *apiHandlers) DeleteCompose(ctx echo.Context, jobId uuid.UUID) error {
 	return h.server.EnsureJobChannel(h.deleteComposeImpl)(ctx, jobId)
@@ -394,6 +527,47 @@ func (h *apiHandlers) deleteComposeImpl(ctx echo.Context, jobId uuid.UUID) error
 	})
 }
 
+// PostComposeCancel cancels all unfinished jobs of a compose
+func (h *apiHandlers) PostComposeCancel(ctx echo.Context, jobId uuid.UUID) error {
+	return h.server.EnsureJobChannel(h.postComposeCancelImpl)(ctx, jobId)
+}
+
+func (h *apiHandlers) postComposeCancelImpl(ctx echo.Context, jobId uuid.UUID) error {
+	jobType, err := h.server.workers.JobType(jobId)
+	if err != nil {
+		return HTTPError(ErrorComposeNotFound)
+	}
+
+	var jobInfo *worker.JobInfo
+	switch jobType {
+	case worker.JobTypeOSBuild:
+		var result worker.OSBuildJobResult
+		jobInfo, err = h.server.workers.OSBuildJobInfo(jobId, &result)
+	case worker.JobTypeKojiFinalize:
+		var result worker.KojiFinalizeJobResult
+		jobInfo, err = h.server.workers.KojiFinalizeJobInfo(jobId, &result)
+	default:
+		return HTTPError(ErrorInvalidJobType)
+	}
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingOSBuildJobStatus, err)
+	}
+	if !jobInfo.JobStatus.Finished.IsZero() && !jobInfo.JobStatus.Canceled {
+		return HTTPError(ErrorComposeFinished)
+	}
+
+	_, err = h.server.workers.CancelJobTree(jobId)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorCancelingJob, err)
+	}
+
+	response, err := h.getJobIDComposeStatus(jobId)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
 func (h *apiHandlers) GetComposeStatus(ctx echo.Context, jobId uuid.UUID) error {
 	return h.server.EnsureJobChannel(h.getComposeStatusImpl)(ctx, jobId)
 }
@@ -436,7 +610,7 @@ func (h *apiHandlers) getJobIDComposeStatus(jobId uuid.UUID) (ComposeStatus, err
 				if err != nil {
 					return ComposeStatus{}, HTTPErrorWithInternal(ErrorUnknownUploadTarget, err)
 				}
-				us.Status = uploadStatusFromJobStatus(jobInfo.JobStatus, result.JobError)
+				us.Status = uploadStatusFromTargetResult(jobInfo.JobStatus, result.JobError, tr)
 				statuses[idx] = *us
 			}
 
@@ -500,7 +674,7 @@ func (h *apiHandlers) getJobIDComposeStatus(jobId uuid.UUID) (ComposeStatus, err
 						if err != nil {
 							return ComposeStatus{}, HTTPErrorWithInternal(ErrorUnknownUploadTarget, err)
 						}
-						us.Status = uploadStatusFromJobStatus(buildInfo.JobStatus, result.JobError)
+						us.Status = uploadStatusFromTargetResult(buildInfo.JobStatus, result.JobError, tr)
 						statuses = append(statuses, *us)
 					}
 				}
@@ -702,9 +876,13 @@ func (h *apiHandlers) getComposeMetadataImpl(ctx echo.Context, jobId uuid.UUID)
 	}
 
 	// Get the original compose request, if present
-	request, err := readComposeRequest(h.server.workers.ArtifactsDir(), jobId)
+	var request *ComposeRequest
+	var savedRequest ComposeRequest
+	exists, err := h.server.workers.ReadComposeRequest(jobId, &savedRequest)
 	if err != nil {
 		ctx.Logger().Warnf("Failed to read compose request: %v", err)
+	} else if exists {
+		request = &savedRequest
 	}
 
 	if buildInfo.JobStatus.Finished.IsZero() {
@@ -718,12 +896,14 @@ func (h *apiHandlers) getComposeMetadataImpl(ctx echo.Context, jobId uuid.UUID)
 	}
 
 	if buildInfo.JobStatus.Canceled || !result.Success {
-		// job canceled or failed, empty response
+		// job canceled or failed, only the report of how far the build
+		// got
 		return ctx.JSON(200, ComposeMetadata{
-			Href:    fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/metadata", jobId),
-			Id:      jobId.String(),
-			Kind:    "ComposeMetadata",
-			Request: request,
+			Href:        fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/metadata", jobId),
+			Id:          jobId.String(),
+			Kind:        "ComposeMetadata",
+			Request:     request,
+			BuildReport: osbuildReportToBuildReport(result.OSBuildReport),
 		})
 	}
 
@@ -752,11 +932,12 @@ func (h *apiHandlers) getComposeMetadataImpl(ctx echo.Context, jobId uuid.UUID)
 	packages := stagesToPackageMetadata(rpmStagesMd)
 
 	resp := &ComposeMetadata{
-		Href:     fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/metadata", jobId),
-		Id:       jobId.String(),
-		Kind:     "ComposeMetadata",
-		Packages: &packages,
-		Request:  request,
+		Href:        fmt.Sprintf("/api/image-builder-composer/v2/composes/%v/metadata", jobId),
+		Id:          jobId.String(),
+		Kind:        "ComposeMetadata",
+		Packages:    &packages,
+		Request:     request,
+		BuildReport: osbuildReportToBuildReport(result.OSBuildReport),
 	}
 
 	if ostreeCommitMetadata != nil {
@@ -766,6 +947,43 @@ func (h *apiHandlers) getComposeMetadataImpl(ctx echo.Context, jobId uuid.UUID)
 	return ctx.JSON(200, resp)
 }
 
+func osbuildReportToBuildReport(report *worker.OSBuildReport) *BuildReport {
+	if report == nil {
+		return nil
+	}
+
+	pipelines := make([]PipelineReport, 0, len(report.Pipelines))
+	for _, pl := range report.Pipelines {
+		stages := make([]StageReport, 0, len(pl.Stages))
+		for _, stage := range pl.Stages {
+			stages = append(stages, StageReport{
+				Name:     stage.Name,
+				Started:  stage.Started,
+				Finished: stage.Finished,
+				Duration: float32(stage.Finished.Sub(stage.Started).Seconds()),
+			})
+		}
+		pipelines = append(pipelines, PipelineReport{
+			Name:     pl.Name,
+			Started:  pl.Started,
+			Finished: pl.Finished,
+			Duration: float32(pl.Finished.Sub(pl.Started).Seconds()),
+			Stages:   stages,
+		})
+	}
+
+	buildReport := &BuildReport{
+		Pipelines: pipelines,
+	}
+	if report.PeakStoreSize > 0 {
+		buildReport.PeakStoreSize = common.ToPtr(report.PeakStoreSize)
+	}
+	if report.PeakOutputSize > 0 {
+		buildReport.PeakOutputSize = common.ToPtr(report.PeakOutputSize)
+	}
+	return buildReport
+}
+
 func stagesToPackageMetadata(stages []osbuild.RPMStageMetadata) []PackageMetadata {
 	packages := make([]PackageMetadata, 0)
 	for _, md := range stages {
@@ -1257,15 +1475,6 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 	if osbuildResult.TargetResults == nil {
 		return HTTPError(ErrorMalformedOSBuildJobResult)
 	}
-	// Only single upload target is allowed, therefore only a single upload target result is allowed as well
-	if len(osbuildResult.TargetResults) != 1 {
-		return HTTPError(ErrorSeveralUploadTargets)
-	}
-	var us *UploadStatus
-	us, err = h.targetResultToUploadStatus(jobId, osbuildResult.TargetResults[0])
-	if err != nil {
-		return HTTPErrorWithInternal(ErrorUnknownUploadTarget, err)
-	}
 
 	var osbuildJob worker.OSBuildJob
 	err = h.server.workers.OSBuildJob(jobId, &osbuildJob)
@@ -1273,8 +1482,26 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 		return HTTPErrorWithInternal(ErrorComposeNotFound, err)
 	}
 
-	if len(osbuildJob.Targets) != 1 {
-		return HTTPError(ErrorSeveralUploadTargets)
+	// The worker reports a result for every target, in the order of the
+	// targets.
+	if len(osbuildJob.Targets) != len(osbuildResult.TargetResults) {
+		return HTTPError(ErrorMalformedOSBuildJobResult)
+	}
+
+	var img AWSEC2CloneCompose
+	err = ctx.Bind(&img)
+	if err != nil {
+		return err
+	}
+
+	idx := cloneSourceTarget(osbuildResult.TargetResults, img.Region)
+	if idx < 0 {
+		return HTTPError(ErrorUnsupportedImage)
+	}
+	var us *UploadStatus
+	us, err = h.targetResultToUploadStatus(jobId, osbuildResult.TargetResults[idx])
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorUnknownUploadTarget, err)
 	}
 
 	// the id of the last job in the dependency chain which users should wait on
@@ -1285,11 +1512,6 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 		if err != nil {
 			return err
 		}
-		var img AWSEC2CloneCompose
-		err = ctx.Bind(&img)
-		if err != nil {
-			return err
-		}
 
 		shareAmi := options.Ami
 		shareRegion := img.Region
@@ -1327,7 +1549,7 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 					TargetRegion: img.Region,
 					TargetName:   fmt.Sprintf("composer-api-%s", uuid.New().String()),
 				}
-				finalJob, err = h.server.workers.EnqueueAWSEC2CopyJob(copyJob, finalJob, channel)
+				finalJob, err = h.server.workers.EnqueueAWSEC2CopyJob(copyJob, finalJob, channel, jobqueue.EnqueueOptions{})
 				if err != nil {
 					return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 				}
@@ -1335,7 +1557,7 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 		}
 
 		var shares []string
-		awsT, ok := (osbuildJob.Targets[0].Options).(*target.AWSTargetOptions)
+		awsT, ok := (osbuildJob.Targets[idx].Options).(*target.AWSTargetOptions)
 		if !ok {
 			return HTTPError(ErrorUnknownUploadTarget)
 		}
@@ -1351,7 +1573,7 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 				Region:            shareRegion,
 				ShareWithAccounts: shares,
 			}
-			finalJob, err = h.server.workers.EnqueueAWSEC2ShareJob(shareJob, finalJob, channel)
+			finalJob, err = h.server.workers.EnqueueAWSEC2ShareJob(shareJob, finalJob, channel, jobqueue.EnqueueOptions{})
 			if err != nil {
 				return HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 			}
@@ -1367,6 +1589,25 @@ func (h *apiHandlers) postCloneComposeImpl(ctx echo.Context, jobId uuid.UUID) er
 	})
 }
 
+// cloneSourceTarget returns the index of the AMI to clone among the target
+// results, preferring one in the region of the clone. It returns -1 if no
+// AMI was uploaded.
+func cloneSourceTarget(results []*target.TargetResult, region string) int {
+	idx := -1
+	for i, tr := range results {
+		if tr.Name != target.TargetNameAWS || tr.TargetError != nil {
+			continue
+		}
+		if idx < 0 {
+			idx = i
+		}
+		if options, ok := tr.Options.(*target.AWSTargetResultOptions); ok && options.Region == region {
+			return i
+		}
+	}
+	return idx
+}
+
 func (h *apiHandlers) GetCloneStatus(ctx echo.Context, jobId uuid.UUID) error {
 	return h.server.EnsureJobChannel(h.getCloneStatus)(ctx, jobId)
 }
@@ -1427,6 +1668,19 @@ func (h *apiHandlers) getCloneStatus(ctx echo.Context, jobId uuid.UUID) error {
 	})
 }
 
+// uploadStatusFromTargetResult returns the status of a single upload of a
+// job with several targets. A failed upload fails the whole job, but it
+// doesn't fail the other uploads of the job.
+func uploadStatusFromTargetResult(js *worker.JobStatus, je *clienterrors.Error, tr *target.TargetResult) UploadStatusValue {
+	if tr.TargetError != nil {
+		return UploadStatusValue(Failure)
+	}
+	if je != nil && je.ID == clienterrors.ErrorTargetError {
+		je = nil
+	}
+	return uploadStatusFromJobStatus(js, je)
+}
+
 // TODO: determine upload status based on the target results, not job results
 func uploadStatusFromJobStatus(js *worker.JobStatus, je *clienterrors.Error) UploadStatusValue {
 	if je != nil || js.Canceled {
@@ -1831,60 +2085,39 @@ func (h *apiHandlers) getComposeDownloadImpl(ctx echo.Context, jobId uuid.UUID)
 		return HTTPErrorWithInternal(ErrorArtifactNotFound, err)
 	}
 
-	// Building only supports one target, but that may change, so make sure to check.
+	// Only the local target keeps the image on the composer, any other
+	// targets of the compose are skipped.
 	// NOTE: TargetResults isn't populated until it is finished
-	if len(osbuildResult.TargetResults) != 1 {
-		msg := fmt.Errorf("%#v", osbuildResult.TargetResults)
-		//return HTTPError(ErrorSeveralUploadTargets)
-		return HTTPErrorWithInternal(ErrorSeveralUploadTargets, msg)
+	var tr *target.TargetResult
+	for _, result := range osbuildResult.TargetResults {
+		if result.Name == target.TargetNameWorkerServer && result.TargetError == nil {
+			tr = result
+			break
+		}
 	}
-	tr := osbuildResult.TargetResults[0]
-	if tr.OsbuildArtifact == nil {
-		return HTTPError(ErrorArtifactNotFound)
+	if tr == nil || tr.OsbuildArtifact == nil {
+		err := fmt.Errorf("compose %s has no local upload target", jobId)
+		return HTTPErrorWithInternal(ErrorArtifactNotFound, err)
 	}
 
 	// NOTE: This also returns an error if the job isn't finished or it cannot find the file
-	file, err := h.server.workers.JobArtifactLocation(jobId, tr.OsbuildArtifact.ExportFilename)
+	reader, size, err := h.server.workers.JobArtifact(jobId, tr.OsbuildArtifact.ExportFilename)
 	if err != nil {
 		return HTTPErrorWithInternal(ErrorArtifactNotFound, err)
 	}
-	return ctx.Attachment(file, fmt.Sprintf("%s-%s", jobId, tr.OsbuildArtifact.ExportFilename))
-}
-
-// saveComposeRequest stores the compose request's json on disk
-// This is saved in the ComposeRequest directory of the artifacts directory
-// If no artifacts directory has been configured it saves nothing and silently returns
-func saveComposeRequest(artifactsDir string, id uuid.UUID, request ComposeRequest) error {
-	if artifactsDir == "" {
-		return nil
-	}
-	p := path.Join(artifactsDir, "ComposeRequest")
-	err := os.MkdirAll(p, 0700)
-	if err != nil {
-		return err
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
 	}
-	db := jsondb.New(p, 0700)
-	return db.Write(id.String(), request)
-}
 
-// readComposeRequest reads the compose request's json on disk
-// This reads the original compose request json from the ComposeRequest directory of
-// the artifacts directory.
-// If no artifacts directory had been setup it silently returns nothing
-func readComposeRequest(artifactsDir string, id uuid.UUID) (*ComposeRequest, error) {
-	if artifactsDir == "" {
-		return nil, nil
-	}
-	p := path.Join(artifactsDir, "ComposeRequest")
-	err := os.MkdirAll(p, 0700)
-	if err != nil {
-		return nil, err
-	}
-	db := jsondb.New(p, 0700)
-	var request ComposeRequest
-	exists, err := db.Read(id.String(), &request)
-	if !exists {
-		return nil, err
+	filename := fmt.Sprintf("%s-%s", jobId, tr.OsbuildArtifact.ExportFilename)
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	// ServeContent answers range requests, so that downloads of large
+	// images can be resumed
+	if rs, ok := reader.(io.ReadSeeker); ok {
+		http.ServeContent(ctx.Response(), ctx.Request(), filename, jobInfo.JobStatus.Finished, rs)
+		return nil
 	}
-	return &request, err
+	ctx.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(size, 10))
+	return ctx.Stream(http.StatusOK, echo.MIMEOctetStream, reader)
 }
@@ -202,6 +202,132 @@ func newOCITarget(options UploadOptions, imageType distro.ImageType) (*target.Ta
 	return t, nil
 }
 
+func newPulpOSTreeTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var pulpUploadOptions PulpOSTreeUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &pulpUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	var serverAddress string
+	if pulpUploadOptions.ServerAddress != nil {
+		serverAddress = *pulpUploadOptions.ServerAddress
+	}
+	var repository string
+	if pulpUploadOptions.Repository != nil {
+		repository = *pulpUploadOptions.Repository
+	}
+
+	t := target.NewPulpOSTreeTarget(&target.PulpOSTreeTargetOptions{
+		ServerAddress: serverAddress,
+		Repository:    repository,
+		BasePath:      pulpUploadOptions.Basepath,
+	})
+	t.ImageName = fmt.Sprintf("composer-api-%s", uuid.New().String())
+	return t, nil
+}
+
+func newVMWareTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var vmwareUploadOptions VMwareUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &vmwareUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	var folder string
+	if vmwareUploadOptions.Folder != nil {
+		folder = *vmwareUploadOptions.Folder
+	}
+
+	// The vCenter and its credentials are always the ones of the worker
+	// configuration, so that they don't end up in the job queue.
+	t := target.NewVMWareTarget(&target.VMWareTargetOptions{
+		Datacenter: vmwareUploadOptions.Datacenter,
+		Cluster:    vmwareUploadOptions.Cluster,
+		Datastore:  vmwareUploadOptions.Datastore,
+		Folder:     folder,
+	})
+	if vmwareUploadOptions.ImageName != nil {
+		t.ImageName = *vmwareUploadOptions.ImageName
+	} else {
+		t.ImageName = fmt.Sprintf("composer-api-%s", uuid.New().String())
+	}
+	return t, nil
+}
+
+func newGenericS3Target(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var genericS3UploadOptions GenericS3UploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &genericS3UploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	var region string
+	if genericS3UploadOptions.Region != nil {
+		region = *genericS3UploadOptions.Region
+	}
+
+	key := fmt.Sprintf("composer-api-%s", uuid.New().String())
+	t := target.NewGenericS3Target(&target.GenericS3TargetOptions{
+		Endpoint:       genericS3UploadOptions.Endpoint,
+		Region:         region,
+		Bucket:         genericS3UploadOptions.Bucket,
+		Key:            key,
+		CredentialsRef: genericS3UploadOptions.CredentialsRef,
+	})
+	t.ImageName = key
+	return t, nil
+}
+
+func newSFTPTarget(options UploadOptions, imageType distro.ImageType) (*target.Target, error) {
+	var sftpUploadOptions SFTPUploadOptions
+	jsonUploadOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONMarshallingError)
+	}
+	err = json.Unmarshal(jsonUploadOptions, &sftpUploadOptions)
+	if err != nil {
+		return nil, HTTPError(ErrorJSONUnMarshallingError)
+	}
+
+	sftpOptions := &target.SFTPTargetOptions{
+		Host: sftpUploadOptions.Host,
+		Path: sftpUploadOptions.Path,
+	}
+	if sftpUploadOptions.Port != nil {
+		sftpOptions.Port = *sftpUploadOptions.Port
+	}
+	if sftpUploadOptions.Username != nil {
+		sftpOptions.Username = *sftpUploadOptions.Username
+	}
+	if sftpUploadOptions.KnownHosts != nil {
+		sftpOptions.KnownHosts = *sftpUploadOptions.KnownHosts
+	}
+	if sftpUploadOptions.KeyRef != nil {
+		sftpOptions.KeyRef = *sftpUploadOptions.KeyRef
+	}
+
+	t := target.NewSFTPTarget(sftpOptions)
+	if sftpUploadOptions.Filename != nil {
+		t.ImageName = *sftpUploadOptions.Filename
+	} else {
+		t.ImageName = imageType.Filename()
+	}
+	return t, nil
+}
+
 // Returns the name of the default target for a given image type name or error
 // if the image type name is unknown.
 func getDefaultTarget(imageType ImageTypes) (UploadTypes, error) {
@@ -332,6 +458,52 @@ func targetSupportMap() map[UploadTypes]map[ImageTypes]bool {
 		UploadTypesOciObjectstorage: {
 			ImageTypesOci: true,
 		},
+		UploadTypesPulpOstree: {
+			ImageTypesEdgeCommit: true,
+			ImageTypesIotCommit:  true,
+		},
+		UploadTypesVmware: {
+			ImageTypesVsphere:    true,
+			ImageTypesVsphereOva: true,
+		},
+		UploadTypesGenericS3: {
+			ImageTypesBootableContainerIso:       true,
+			ImageTypesEdgeCommit:                 true,
+			ImageTypesEdgeInstaller:              true,
+			ImageTypesGuestImage:                 true,
+			ImageTypesImageInstaller:             true,
+			ImageTypesIotCommit:                  true,
+			ImageTypesIotInstaller:               true,
+			ImageTypesIotRawImage:                true,
+			ImageTypesLiveInstaller:              true,
+			ImageTypesMinimalRaw:                 true,
+			ImageTypesNetworkInstaller:           true,
+			ImageTypesEverythingNetworkInstaller: true,
+			ImageTypesServerNetworkInstaller:     true,
+			ImageTypesPxeTarXz:                   true,
+			ImageTypesVsphereOva:                 true,
+			ImageTypesVsphere:                    true,
+			ImageTypesWsl:                        true,
+		},
+		UploadTypesSftp: {
+			ImageTypesBootableContainerIso:       true,
+			ImageTypesEdgeCommit:                 true,
+			ImageTypesEdgeInstaller:              true,
+			ImageTypesGuestImage:                 true,
+			ImageTypesImageInstaller:             true,
+			ImageTypesIotCommit:                  true,
+			ImageTypesIotInstaller:               true,
+			ImageTypesIotRawImage:                true,
+			ImageTypesLiveInstaller:              true,
+			ImageTypesMinimalRaw:                 true,
+			ImageTypesNetworkInstaller:           true,
+			ImageTypesEverythingNetworkInstaller: true,
+			ImageTypesServerNetworkInstaller:     true,
+			ImageTypesPxeTarXz:                   true,
+			ImageTypesVsphereOva:                 true,
+			ImageTypesVsphere:                    true,
+			ImageTypesWsl:                        true,
+		},
 		UploadTypesLocal: {
 			ImageTypesAws:                        true,
 			ImageTypesAwsCvm:                     true,
@@ -381,8 +553,9 @@ func (ir *ImageRequest) GetTargets(imageType distro.ImageType) ([]*target.Target
 			if err != nil {
 				return nil, err
 			}
-			// prepend the top-level target
-			targets = append([]*target.Target{trgt}, targets...)
+			// keep the order of the request, the upload statuses are
+			// reported in the order of the targets
+			targets = append(targets, trgt)
 		}
 	}
 
@@ -422,6 +595,18 @@ func getTarget(targetType UploadTypes, options UploadOptions, imageType distro.I
 	case UploadTypesOciObjectstorage:
 		irTarget, err = newOCITarget(options, imageType)
 
+	case UploadTypesPulpOstree:
+		irTarget, err = newPulpOSTreeTarget(options, imageType)
+
+	case UploadTypesVmware:
+		irTarget, err = newVMWareTarget(options, imageType)
+
+	case UploadTypesGenericS3:
+		irTarget, err = newGenericS3Target(options, imageType)
+
+	case UploadTypesSftp:
+		irTarget, err = newSFTPTarget(options, imageType)
+
 	case UploadTypesLocal:
 		irTarget = target.NewWorkerServerTarget()
 		irTarget.ImageName = imageType.Filename()
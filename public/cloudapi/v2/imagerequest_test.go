@@ -101,6 +101,18 @@ func TestGetTargets(t *testing.T) {
 			includeDefault: false,
 			expected:       []target.TargetName{target.TargetNameWorkerServer},
 		},
+		"guest:local+s3": {
+			imageType:      ImageTypesGuestImage,
+			targets:        []UploadTypes{UploadTypesLocal, UploadTypesAwsS3},
+			includeDefault: false,
+			expected:       []target.TargetName{target.TargetNameWorkerServer, target.TargetNameAWSS3},
+		},
+		"guest:s3+local+default": {
+			imageType:      ImageTypesGuestImage,
+			targets:        []UploadTypes{UploadTypesAwsS3, UploadTypesLocal},
+			includeDefault: true,
+			expected:       []target.TargetName{target.TargetNameAWSS3, target.TargetNameWorkerServer, target.TargetNameAWSS3},
+		},
 		"guest:azure:fail": {
 			imageType: ImageTypesGuestImage,
 			targets:   []UploadTypes{UploadTypesAzure},
@@ -141,6 +153,61 @@ func TestGetTargets(t *testing.T) {
 			expected:  []target.TargetName{""},
 			fail:      true,
 		},
+		"edge:pulp": {
+			imageType: ImageTypesEdgeCommit,
+			targets:   []UploadTypes{UploadTypesPulpOstree},
+			expected:  []target.TargetName{target.TargetNamePulpOSTree},
+		},
+		"iot:pulp+default": {
+			imageType:      ImageTypesIotCommit,
+			targets:        []UploadTypes{UploadTypesPulpOstree},
+			includeDefault: true,
+			expected:       []target.TargetName{target.TargetNamePulpOSTree, target.TargetNameAWSS3},
+		},
+		"vsphere:vmware": {
+			imageType: ImageTypesVsphere,
+			targets:   []UploadTypes{UploadTypesVmware},
+			expected:  []target.TargetName{target.TargetNameVMWare},
+		},
+		"vsphere-ova:vmware": {
+			imageType: ImageTypesVsphereOva,
+			targets:   []UploadTypes{UploadTypesVmware},
+			expected:  []target.TargetName{target.TargetNameVMWare},
+		},
+		"guest:generic-s3": {
+			imageType: ImageTypesGuestImage,
+			targets:   []UploadTypes{UploadTypesGenericS3},
+			expected:  []target.TargetName{target.TargetNameGenericS3},
+		},
+		"guest:sftp": {
+			imageType: ImageTypesGuestImage,
+			targets:   []UploadTypes{UploadTypesSftp},
+			expected:  []target.TargetName{target.TargetNameSFTP},
+		},
+		"aws:sftp:fail": {
+			imageType: ImageTypesAws,
+			targets:   []UploadTypes{UploadTypesSftp},
+			expected:  []target.TargetName{""},
+			fail:      true,
+		},
+		"aws:generic-s3:fail": {
+			imageType: ImageTypesAws,
+			targets:   []UploadTypes{UploadTypesGenericS3},
+			expected:  []target.TargetName{""},
+			fail:      true,
+		},
+		"guest:vmware:fail": {
+			imageType: ImageTypesGuestImage,
+			targets:   []UploadTypes{UploadTypesVmware},
+			expected:  []target.TargetName{""},
+			fail:      true,
+		},
+		"guest:pulp:fail": {
+			imageType: ImageTypesGuestImage,
+			targets:   []UploadTypes{UploadTypesPulpOstree},
+			expected:  []target.TargetName{""},
+			fail:      true,
+		},
 		"network-installer:default": {
 			imageType:      ImageTypesNetworkInstaller,
 			targets:        nil,
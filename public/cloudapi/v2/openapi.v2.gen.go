@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
@@ -451,8 +452,12 @@ const (
 	UploadTypesAzure            UploadTypes = "azure"
 	UploadTypesContainer        UploadTypes = "container"
 	UploadTypesGcp              UploadTypes = "gcp"
+	UploadTypesGenericS3        UploadTypes = "generic.s3"
 	UploadTypesLocal            UploadTypes = "local"
 	UploadTypesOciObjectstorage UploadTypes = "oci.objectstorage"
+	UploadTypesPulpOstree       UploadTypes = "pulp.ostree"
+	UploadTypesSftp             UploadTypes = "sftp"
+	UploadTypesVmware           UploadTypes = "vmware"
 )
 
 // Valid indicates whether the value is a known member of the UploadTypes enum.
@@ -468,10 +473,18 @@ func (e UploadTypes) Valid() bool {
 		return true
 	case UploadTypesGcp:
 		return true
+	case UploadTypesGenericS3:
+		return true
 	case UploadTypesLocal:
 		return true
 	case UploadTypesOciObjectstorage:
 		return true
+	case UploadTypesPulpOstree:
+		return true
+	case UploadTypesSftp:
+		return true
+	case UploadTypesVmware:
+		return true
 	default:
 		return false
 	}
@@ -826,6 +839,16 @@ type BtrfsVolume struct {
 // BtrfsVolumeType defines model for BtrfsVolume.Type.
 type BtrfsVolumeType string
 
+// BuildReport Timing and disk usage of the osbuild run of the compose
+type BuildReport struct {
+	// PeakOutputSize Peak size of the osbuild output in bytes, not set if it was not measured
+	PeakOutputSize *int64 `json:"peak_output_size,omitempty"`
+
+	// PeakStoreSize Peak size of the osbuild store in bytes, not set if it was not measured
+	PeakStoreSize *int64           `json:"peak_store_size,omitempty"`
+	Pipelines     []PipelineReport `json:"pipelines"`
+}
+
 // CACertsCustomization defines model for CACertsCustomization.
 type CACertsCustomization struct {
 	PemCerts []string `json:"pem_certs"`
@@ -896,9 +919,11 @@ type ComposeManifests struct {
 
 // ComposeMetadata defines model for ComposeMetadata.
 type ComposeMetadata struct {
-	Href string `json:"href"`
-	Id   string `json:"id"`
-	Kind string `json:"kind"`
+	// BuildReport Timing and disk usage of the osbuild run of the compose
+	BuildReport *BuildReport `json:"build_report,omitempty"`
+	Href        string       `json:"href"`
+	Id          string       `json:"id"`
+	Kind        string       `json:"kind"`
 
 	// OstreeCommit ID (hash) of the built commit
 	OstreeCommit *string `json:"ostree_commit,omitempty"`
@@ -921,6 +946,11 @@ type ComposeRequest struct {
 	ImageRequest   *ImageRequest       `json:"image_request,omitempty"`
 	ImageRequests  *[]ImageRequest     `json:"image_requests,omitempty"`
 	Koji           *Koji               `json:"koji,omitempty"`
+
+	// Priority Priority of the jobs of this compose. Jobs with a higher priority are
+	// started before jobs with a lower priority, jobs with the same priority
+	// are started in the order they were submitted. Defaults to 0.
+	Priority *int `json:"priority,omitempty"`
 }
 
 // ComposeSBOMs defines model for ComposeSBOMs.
@@ -1364,6 +1394,31 @@ type GCPUploadStatus struct {
 	ProjectId string `json:"project_id"`
 }
 
+// GenericS3UploadOptions Options for uploading an image to a bucket of an S3-compatible object
+// store, e.g. Ceph or MinIO. The credentials are part of the
+// configuration of the worker, the request only refers to them by name.
+type GenericS3UploadOptions struct {
+	Bucket string `json:"bucket"`
+
+	// CredentialsRef Name of the credentials for the object store in the configuration
+	// of the worker.
+	CredentialsRef string `json:"credentials_ref"`
+	Endpoint       string `json:"endpoint"`
+
+	// Region Region to sign the requests for, most S3-compatible object stores
+	// ignore it. Defaults to us-east-1.
+	Region *string `json:"region,omitempty"`
+}
+
+// GenericS3UploadStatus defines model for GenericS3UploadStatus.
+type GenericS3UploadStatus struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+
+	// Url Presigned URL of the uploaded image, it expires after a while.
+	Url string `json:"url"`
+}
+
 // Group defines model for Group.
 type Group struct {
 	// Gid Group id of the group to create (optional)
@@ -1448,11 +1503,15 @@ type ImageSBOMSbomType string
 
 // ImageStatus defines model for ImageStatus.
 type ImageStatus struct {
-	Error          *ComposeStatusError `json:"error,omitempty"`
-	Progress       *Progress           `json:"progress,omitempty"`
-	Status         ImageStatusValue    `json:"status"`
-	UploadStatus   *UploadStatus       `json:"upload_status,omitempty"`
-	UploadStatuses *[]UploadStatus     `json:"upload_statuses,omitempty"`
+	Error        *ComposeStatusError `json:"error,omitempty"`
+	Progress     *Progress           `json:"progress,omitempty"`
+	Status       ImageStatusValue    `json:"status"`
+	UploadStatus *UploadStatus       `json:"upload_status,omitempty"`
+
+	// UploadStatuses The status of every upload target, in the order of the
+	// upload_targets of the image request followed by the target of its
+	// upload_options.
+	UploadStatuses *[]UploadStatus `json:"upload_statuses,omitempty"`
 }
 
 // ImageStatusValue defines model for ImageStatusValue.
@@ -1722,6 +1781,16 @@ type Partition struct {
 	union json.RawMessage
 }
 
+// PipelineReport defines model for PipelineReport.
+type PipelineReport struct {
+	// Duration Duration of the pipeline in seconds
+	Duration float32       `json:"duration"`
+	Finished time.Time     `json:"finished"`
+	Name     string        `json:"name"`
+	Stages   []StageReport `json:"stages"`
+	Started  time.Time     `json:"started"`
+}
+
 // Progress defines model for Progress.
 type Progress struct {
 	// Done Amount of completed steps in the build.
@@ -1738,7 +1807,11 @@ type PulpOSTreeUploadOptions struct {
 	Basepath string `json:"basepath"`
 
 	// Repository Repository to import the ostree commit to
-	Repository    *string `json:"repository,omitempty"`
+	Repository *string `json:"repository,omitempty"`
+
+	// ServerAddress Pulp server to import the ostree commit to, defaults to the one
+	// the worker is configured with. The worker's credentials are only
+	// sent to that server.
 	ServerAddress *string `json:"server_address,omitempty"`
 }
 
@@ -1791,6 +1864,41 @@ type Repository struct {
 	Rhsm *bool `json:"rhsm,omitempty"`
 }
 
+// SFTPUploadOptions Options for uploading an image to a file server over SFTP. The worker
+// authenticates with a private key from its configuration, the request
+// only refers to it by name. An interrupted upload is resumed.
+type SFTPUploadOptions struct {
+	// Filename Name of the uploaded image, defaults to the name of the file the
+	// image type produces.
+	Filename *string `json:"filename,omitempty"`
+	Host     string  `json:"host"`
+
+	// KeyRef Name of the private key in the configuration of the worker, the
+	// default key of the worker is used if it's not set.
+	KeyRef *string `json:"key_ref,omitempty"`
+
+	// KnownHosts Host keys of the server, in the format of an OpenSSH known_hosts
+	// file. The server has to present one of them or a key the worker
+	// knows.
+	KnownHosts *string `json:"known_hosts,omitempty"`
+
+	// Path Directory on the server to upload the image to
+	Path string `json:"path"`
+	Port *int   `json:"port,omitempty"`
+
+	// Username Defaults to the user name of the key in the configuration of the
+	// worker.
+	Username *string `json:"username,omitempty"`
+}
+
+// SFTPUploadStatus defines model for SFTPUploadStatus.
+type SFTPUploadStatus struct {
+	Checksum string `json:"checksum"`
+
+	// Path Path of the uploaded image on the server
+	Path string `json:"path"`
+}
+
 // SSHKey defines model for SSHKey.
 type SSHKey struct {
 	// Key Adds the key to the user's authorized_keys file
@@ -1829,6 +1937,15 @@ type Services struct {
 	Masked *[]string `json:"masked,omitempty"`
 }
 
+// StageReport defines model for StageReport.
+type StageReport struct {
+	// Duration Duration of the stage in seconds
+	Duration float32   `json:"duration"`
+	Finished time.Time `json:"finished"`
+	Name     string    `json:"name"`
+	Started  time.Time `json:"started"`
+}
+
 // SubManConfig defines model for SubManConfig.
 type SubManConfig struct {
 	Rhsm      *SubManRHSMConfig      `json:"rhsm,omitempty"`
@@ -1950,6 +2067,31 @@ type User struct {
 	Password *string `json:"password,omitempty"`
 }
 
+// VMwareUploadOptions Options for uploading a vsphere or vsphere-ova image to vSphere. The
+// vCenter and its credentials are taken from the configuration of the
+// worker, they are never part of the request.
+type VMwareUploadOptions struct {
+	Cluster    string `json:"cluster"`
+	Datacenter string `json:"datacenter"`
+	Datastore  string `json:"datastore"`
+
+	// Folder The folder of the datacenter to put the virtual machine in.
+	Folder *string `json:"folder,omitempty"`
+
+	// ImageName Name of the uploaded virtual machine. If name is omitted from the
+	// request, a random one based on a UUID is generated.
+	ImageName *string `json:"image_name,omitempty"`
+}
+
+// VMwareUploadStatus defines model for VMwareUploadStatus.
+type VMwareUploadStatus struct {
+	Datacenter string  `json:"datacenter"`
+	Datastore  string  `json:"datastore"`
+	Folder     *string `json:"folder,omitempty"`
+	Host       string  `json:"host"`
+	ImageName  string  `json:"image_name"`
+}
+
 // VolumeGroup defines model for VolumeGroup.
 type VolumeGroup struct {
 	LogicalVolumes []LogicalVolume `json:"logical_volumes"`
@@ -1977,6 +2119,21 @@ type Page = string
 // Size defines model for size.
 type Size = string
 
+// GetComposeListParams defines parameters for GetComposeList.
+type GetComposeListParams struct {
+	// Limit Maximum number of composes to return, all of them if not specified.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Offset Number of composes to skip.
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Status Only list composes with this status.
+	Status *ComposeStatusValue `form:"status,omitempty" json:"status,omitempty"`
+
+	// CreatedAfter Only list composes created after this time.
+	CreatedAfter *time.Time `form:"created_after,omitempty" json:"created_after,omitempty"`
+}
+
 // GetDistributionParams defines parameters for GetDistribution.
 type GetDistributionParams struct {
 	// ImageType Filter by image type. Multiple values can be specified.
@@ -2349,6 +2506,84 @@ func (t *CloneStatus_Options) MergePulpOSTreeUploadStatus(v PulpOSTreeUploadStat
 	return err
 }
 
+// AsVMwareUploadStatus returns the union data inside the CloneStatus_Options as a VMwareUploadStatus
+func (t CloneStatus_Options) AsVMwareUploadStatus() (VMwareUploadStatus, error) {
+	var body VMwareUploadStatus
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromVMwareUploadStatus overwrites any union data inside the CloneStatus_Options as the provided VMwareUploadStatus
+func (t *CloneStatus_Options) FromVMwareUploadStatus(v VMwareUploadStatus) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeVMwareUploadStatus performs a merge with any union data inside the CloneStatus_Options, using the provided VMwareUploadStatus
+func (t *CloneStatus_Options) MergeVMwareUploadStatus(v VMwareUploadStatus) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsGenericS3UploadStatus returns the union data inside the CloneStatus_Options as a GenericS3UploadStatus
+func (t CloneStatus_Options) AsGenericS3UploadStatus() (GenericS3UploadStatus, error) {
+	var body GenericS3UploadStatus
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromGenericS3UploadStatus overwrites any union data inside the CloneStatus_Options as the provided GenericS3UploadStatus
+func (t *CloneStatus_Options) FromGenericS3UploadStatus(v GenericS3UploadStatus) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeGenericS3UploadStatus performs a merge with any union data inside the CloneStatus_Options, using the provided GenericS3UploadStatus
+func (t *CloneStatus_Options) MergeGenericS3UploadStatus(v GenericS3UploadStatus) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsSFTPUploadStatus returns the union data inside the CloneStatus_Options as a SFTPUploadStatus
+func (t CloneStatus_Options) AsSFTPUploadStatus() (SFTPUploadStatus, error) {
+	var body SFTPUploadStatus
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromSFTPUploadStatus overwrites any union data inside the CloneStatus_Options as the provided SFTPUploadStatus
+func (t *CloneStatus_Options) FromSFTPUploadStatus(v SFTPUploadStatus) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeSFTPUploadStatus performs a merge with any union data inside the CloneStatus_Options, using the provided SFTPUploadStatus
+func (t *CloneStatus_Options) MergeSFTPUploadStatus(v SFTPUploadStatus) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
 // AsLocalUploadStatus returns the union data inside the CloneStatus_Options as a LocalUploadStatus
 func (t CloneStatus_Options) AsLocalUploadStatus() (LocalUploadStatus, error) {
 	var body LocalUploadStatus
@@ -2929,6 +3164,84 @@ func (t *UploadOptions) MergePulpOSTreeUploadOptions(v PulpOSTreeUploadOptions)
 	return err
 }
 
+// AsVMwareUploadOptions returns the union data inside the UploadOptions as a VMwareUploadOptions
+func (t UploadOptions) AsVMwareUploadOptions() (VMwareUploadOptions, error) {
+	var body VMwareUploadOptions
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromVMwareUploadOptions overwrites any union data inside the UploadOptions as the provided VMwareUploadOptions
+func (t *UploadOptions) FromVMwareUploadOptions(v VMwareUploadOptions) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeVMwareUploadOptions performs a merge with any union data inside the UploadOptions, using the provided VMwareUploadOptions
+func (t *UploadOptions) MergeVMwareUploadOptions(v VMwareUploadOptions) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsGenericS3UploadOptions returns the union data inside the UploadOptions as a GenericS3UploadOptions
+func (t UploadOptions) AsGenericS3UploadOptions() (GenericS3UploadOptions, error) {
+	var body GenericS3UploadOptions
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromGenericS3UploadOptions overwrites any union data inside the UploadOptions as the provided GenericS3UploadOptions
+func (t *UploadOptions) FromGenericS3UploadOptions(v GenericS3UploadOptions) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeGenericS3UploadOptions performs a merge with any union data inside the UploadOptions, using the provided GenericS3UploadOptions
+func (t *UploadOptions) MergeGenericS3UploadOptions(v GenericS3UploadOptions) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsSFTPUploadOptions returns the union data inside the UploadOptions as a SFTPUploadOptions
+func (t UploadOptions) AsSFTPUploadOptions() (SFTPUploadOptions, error) {
+	var body SFTPUploadOptions
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromSFTPUploadOptions overwrites any union data inside the UploadOptions as the provided SFTPUploadOptions
+func (t *UploadOptions) FromSFTPUploadOptions(v SFTPUploadOptions) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeSFTPUploadOptions performs a merge with any union data inside the UploadOptions, using the provided SFTPUploadOptions
+func (t *UploadOptions) MergeSFTPUploadOptions(v SFTPUploadOptions) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
 func (t UploadOptions) MarshalJSON() ([]byte, error) {
 	b, err := t.union.MarshalJSON()
 	return b, err
@@ -3121,6 +3434,84 @@ func (t *UploadStatus_Options) MergePulpOSTreeUploadStatus(v PulpOSTreeUploadSta
 	return err
 }
 
+// AsVMwareUploadStatus returns the union data inside the UploadStatus_Options as a VMwareUploadStatus
+func (t UploadStatus_Options) AsVMwareUploadStatus() (VMwareUploadStatus, error) {
+	var body VMwareUploadStatus
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromVMwareUploadStatus overwrites any union data inside the UploadStatus_Options as the provided VMwareUploadStatus
+func (t *UploadStatus_Options) FromVMwareUploadStatus(v VMwareUploadStatus) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeVMwareUploadStatus performs a merge with any union data inside the UploadStatus_Options, using the provided VMwareUploadStatus
+func (t *UploadStatus_Options) MergeVMwareUploadStatus(v VMwareUploadStatus) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsGenericS3UploadStatus returns the union data inside the UploadStatus_Options as a GenericS3UploadStatus
+func (t UploadStatus_Options) AsGenericS3UploadStatus() (GenericS3UploadStatus, error) {
+	var body GenericS3UploadStatus
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromGenericS3UploadStatus overwrites any union data inside the UploadStatus_Options as the provided GenericS3UploadStatus
+func (t *UploadStatus_Options) FromGenericS3UploadStatus(v GenericS3UploadStatus) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeGenericS3UploadStatus performs a merge with any union data inside the UploadStatus_Options, using the provided GenericS3UploadStatus
+func (t *UploadStatus_Options) MergeGenericS3UploadStatus(v GenericS3UploadStatus) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsSFTPUploadStatus returns the union data inside the UploadStatus_Options as a SFTPUploadStatus
+func (t UploadStatus_Options) AsSFTPUploadStatus() (SFTPUploadStatus, error) {
+	var body SFTPUploadStatus
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromSFTPUploadStatus overwrites any union data inside the UploadStatus_Options as the provided SFTPUploadStatus
+func (t *UploadStatus_Options) FromSFTPUploadStatus(v SFTPUploadStatus) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeSFTPUploadStatus performs a merge with any union data inside the UploadStatus_Options, using the provided SFTPUploadStatus
+func (t *UploadStatus_Options) MergeSFTPUploadStatus(v SFTPUploadStatus) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
 // AsLocalUploadStatus returns the union data inside the UploadStatus_Options as a LocalUploadStatus
 func (t UploadStatus_Options) AsLocalUploadStatus() (LocalUploadStatus, error) {
 	var body LocalUploadStatus
@@ -3167,13 +3558,16 @@ type ServerInterface interface {
 	PostCompose(ctx echo.Context) error
 	// The list of composes
 	// (GET /composes/)
-	GetComposeList(ctx echo.Context) error
+	GetComposeList(ctx echo.Context, params GetComposeListParams) error
 	// Delete a compose
 	// (DELETE /composes/{id})
 	DeleteCompose(ctx echo.Context, id openapi_types.UUID) error
 	// The status of a compose
 	// (GET /composes/{id})
 	GetComposeStatus(ctx echo.Context, id openapi_types.UUID) error
+	// Cancel a compose
+	// (POST /composes/{id}/cancel)
+	PostComposeCancel(ctx echo.Context, id openapi_types.UUID) error
 	// Clone an existing compose
 	// (POST /composes/{id}/clone)
 	PostCloneCompose(ctx echo.Context, id openapi_types.UUID) error
@@ -3255,8 +3649,38 @@ func (w *ServerInterfaceWrapper) GetComposeList(ctx echo.Context) error {
 
 	ctx.Set(BearerScopes, []string{})
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetComposeListParams
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "limit", ctx.QueryParams(), &params.Limit, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "offset", ctx.QueryParams(), &params.Offset, runtime.BindQueryParameterOptions{Type: "integer", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "status", ctx.QueryParams(), &params.Status, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter status: %s", err))
+	}
+
+	// ------------- Optional query parameter "created_after" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "created_after", ctx.QueryParams(), &params.CreatedAfter, runtime.BindQueryParameterOptions{Type: "string", Format: "date-time"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter created_after: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetComposeList(ctx)
+	err = w.Handler.GetComposeList(ctx, params)
 	return err
 }
 
@@ -3296,6 +3720,24 @@ func (w *ServerInterfaceWrapper) GetComposeStatus(ctx echo.Context) error {
 	return err
 }
 
+// PostComposeCancel converts echo context to params.
+func (w *ServerInterfaceWrapper) PostComposeCancel(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(BearerScopes, []string{})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostComposeCancel(ctx, id)
+	return err
+}
+
 // PostCloneCompose converts echo context to params.
 func (w *ServerInterfaceWrapper) PostCloneCompose(ctx echo.Context) error {
 	var err error
@@ -3552,6 +3994,7 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.GET(baseURL+"/composes/", wrapper.GetComposeList)
 	router.DELETE(baseURL+"/composes/:id", wrapper.DeleteCompose)
 	router.GET(baseURL+"/composes/:id", wrapper.GetComposeStatus)
+	router.POST(baseURL+"/composes/:id/cancel", wrapper.PostComposeCancel)
 	router.POST(baseURL+"/composes/:id/clone", wrapper.PostCloneCompose)
 	router.GET(baseURL+"/composes/:id/download", wrapper.GetComposeDownload)
 	router.GET(baseURL+"/composes/:id/logs", wrapper.GetComposeLogs)
@@ -3571,242 +4014,274 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+y993MbObI4/q+g+Nlvef3MJAYFV129R1GJyhYVLB1dOnAGJCHNAGMAQ4ra5//9WwiT",
-	"SAyDbO/t3vMPd2txEBvdje5Ghz8KDvUDShARvPDxj0IAGfSRQMz8NUTyvy7iDsOBwJQUPhYu4RABTFz0",
-	"UigW0Av0Aw9lmo+hF6LCx8JG4du3YgHLPl9DxKaFYoFAX35RLYsF7oyQD2UXMQ3k71wwTIaqG8evlrnP",
-	"Q7+PGKADgAXyOcAEIOiMgBkwvZpogHg11WruelTbRev5Fn1UQ7fuuvvtWtujBLUl+LiaCLoulsuE3iWj",
-	"AWICy4UMoMdRsRCkfvqjwNBQ7WduomKBjyBDjxMsRo/QcWhoDsbsrPDxn4WNWr3R3Nza3qlu1ApfigUF",
-	"CetY5gfIGJyqvTP0NcQMuXIYs4YvcTPaf0KOkP30/m4Cj0L3QoGev3mD8cILKCxNEBeljULxz9x2scAJ",
-	"DPiIikd92uk1+dNS9HV+VXaA2de6DIxdAUWoqSQDKOjj7Iqgj0tVZ7te3dqpb201mztNt9G3QWxNEM9s",
-	"Rs5bXIID3fr3oEAQ9j3saBIewNATcbssSXcGgCMBBAXqM/hdjBAwXYAi3vdFAIFHybAIaH8QcgcK5IKb",
-	"q9MewRwwJEJGkFsGHcEBegkwg3Jo4OPhSIA+ApxSghgQI0jAgDJAxQgxEKq99YiAbIgEL/dIjyRrESxE",
-	"clo+okwgJmcDqckAJG6P4OyEmAO5dg59BCBXU8m/09OBZLbkiPqUegiS7z/U1Y4zDxVD5tlZcXoK2cg6",
-	"PnNGWCBHhAx1yIAuRZYsEqS7Ax8J6EIBwYBRH2AfDhEHHu4zqHh2dtXq86NczwIE/aPwG0ODwsfC/6sk",
-	"913FcPRKRw5xPQ30wr/Nru0MBurCka2AnAhIPsIVlowQZsBFAmKPFyxgiTjOgt2qJsXUeb9sbz5uNpYe",
-	"tupnPYrXkKHvodzRNEDscfw4RARp1M5QceFWYmJ2R+0RpRwpdL89Awqg4EgOcwuSUYrAxYMBYogIMEBQ",
-	"7p4DSoBaMIDyf2OIPdj3UI+4KEDExWQoW8hx54bTNIRI6EtwqEXd1lIQSVilxhH7WZxLaqUDNYWmUeTq",
-	"s5YMBfghVzwkJPhrKMUe1XCIx4gAhjgNmYPAkNEwKCv2ISeRjID6WEgupVBYdpFHh7iQPIVB4lIfUIJA",
-	"H3Lkyh1CcHPT2QOY94jZIXLNBtOXlVqY7TbwqJM6qfQGT82XaJMBo2MsNxkt/1EtvwgmI8T0EWpU5yMa",
-	"eq7afAQXSGS3IeYCMbW+IzqRdOBhLgD0PBAtg3/skZEQAf9YqbjU4WUfO4xyOhBlh/oVREohrzgerkB5",
-	"9hVzjf73GKPJP9RPJcfDJQ8KxMX/g6/RPfsoJ3qMJ3mnQC5XHP0kQU+oADxADh5g5BYBFvJHF7mhkzmQ",
-	"HDjMAl2yXhRK+rBfwum+i7Eriy4rgHt2Kdc0dCC5MsMcqhltolTYj5fwiN35RXX25JLSzd6wmAZqutv9",
-	"mlOC/Vqj1Ghs1Es7VadZ2tyo1aubaLu6g2q21QlEIBEL1iUXoRuttiqDggNMXHXWmkI1T7mkTEBvFVyM",
-	"8FDgMSq5mCFHUDatDELiQh8RAT0+97U0opOSoCU5dUkveQZITWcLDZr9zdKGUx+UGi6sluBmrVaq9qub",
-	"1Vp9x91yt5Yy+gRi82c7h4FLLoS8uz/LIVdhOTOLTA1gW8KuF6KAYSLWvIocSgTExOijM3dO9C0SEQQF",
-	"yO9L9k303SyRAnoAMjGAjhTwY51hkTgQj2vTJZyQC+rjVxhfrIuGirfdznablTEsSoyLuWB0ftfXUjqW",
-	"33A/VKQrKAg5iqVNRyukZdAZAA8NBEB+IKbq04hy0SN6YDDBnqcoic/T9gC5lMFSfcdGwIjIC9p99Kkb",
-	"GlV7JbCeqfY2mCrM5TZDg/MsyV5/lxvtyxuYC+h5yF31OM0oml1aZk/tY0ZKIwB62AjygR6FF6UKILHD",
-	"VT/3ofM8gczlCu5QwD72sJgqeK6zOtvCImqcO4FoLbkQ+15Y2VYzRoxb5YsW4MgfIwZMC0CUjSaDUFvl",
-	"rfJW9e0ibR4drclMoIOYWE7/rbZslplKU6Tm+9gG+b3kowS+wxAUsbgYsyG8Dh+KhpzajsPF/Hn5APxZ",
-	"tSWDpU3PD2TLgUuXtTzYu1AtsZVmDuTPPwoA8anLUW1AUIuYcoF8i9grRVI6AEkb4EsRMqCYiNQS37QY",
-	"M6l1STZOtq94JjjoXHaBT11k1f0HmKEJ9Lw1VmI6RDw0HwoJC11v17lcU94ldoWqTckAD5VuF106RsWd",
-	"18uGBEcX4EIFPWqnzLeKpymqfHTRGDtLlLp0B6A7FIETMqmGelNAiTeVl+Ag9OI7FLlDVOLYDzylQ5Qi",
-	"PsqU+j9zWVZcNK5wF1o3GHVcusO44bdi4RkxgpaiwYluZXQ/Dy1rf6pbfSsWaIAId2CwMqJdBIh0261L",
-	"ffkwoQ4Dk+GjwuWMbQCGgpa8sT9nIegiDzkCjKS0rkWYZyPVR5JIPDJyy+BdNNA7/V2KOAxOQEg8xHmP",
-	"CKUaQIaUGk0Z8ClDGQrHUqvBzgg4kCOpGcTjnN6elcE7NTb0JnDKeyTkiMvfiwBJzX4yQopxmSkIBehF",
-	"MJgevwzeMTh5B1RPubJ4+bxHbIPkrDNrxWBwUigWNPxiUH6xKp4B5TjvNrpKfZVEP2FYIPmPChJOZRr6",
-	"ZdW/7FayHNrYPc6pQBLEUMhvPAKCUMIigAL0Q+y5QGAflVcXdWJ0ildnvdnYiPvLhro66p7N3c8sWN7v",
-	"cr4bR0zyhKXL70btZB8+ekbTfHbL+Qg8oylfFTTd7tEJskJDwviVkqXUfR21+1YshFwzHPva5Nfvuf9u",
-	"uE0z+rZIalP3t0Vw1MqUuqKXyQwaz7LynAsFtKuFcuUR/1ejQw4CD8qR0Yuwceqc+1Pdf7MjQTDErqRl",
-	"aEw5cyZcRtV7EiXoYlD4+M95GT7+BROBhkpafikNaSn5dbNR+PZFqye2N1jEfMyluM2BHjS+vNQqMQHU",
-	"EVBdaT4UmcVVNxsNGwgCKEY2lUKMQKxOe9l9KnbiT83vcyPaEfFiQvQTbhamYQRT2esngnRG51C7/rIM",
-	"exMpM4uCPibRO/Mi4omaqfOMWH/W0lIZQ7ZUQUp1LsZzL1l8IlSu8R4TdXOBY8Q5zS/nHvmoUajsvEZ9",
-	"Br9L/ZkyARgkQ8TfKzNywKigDvUUK5ISSfq0/1mo1T4KJygUC9tV8w/sw0D9c7233xW5e7ThNJeX/HR1",
-	"+0Y0woPqtR6DjAWsOQSTPI4LhqBv3e4Tp+RRQOxR9cuSJUbTHHcvzq/jTpL0qYedqdUoexkKSZ2xQR3o",
-	"tqCzFzFqeRkDyaN5EXDJKKAAkEy14E0cKR7FTwZA0B6ReDscCR5LflLS8aHADvS8qcQ4gpSt3rAduRMP",
-	"y6Giyc3MDiWcekYGMZzuYyEMlWF0nr8xKrmN2eU85qwLxRQEZ3lKMtNC4kwJQnMH34cchczL4l/CLiKD",
-	"tuOSMkPuCGpjtqMvv4qLuaiwEfK2K9sV/aBYkSNSXqG8koEWw1Yz/QwdGatfCnIZzdVDudaqYTB0Rsh5",
-	"tncdBkMlKKV3uXQxOSfoIwE9TJ7tkPIxY5TxsjZuBozK4yhTNqxE/f5bCsX/iIyftV5YrdY2IXNG/4if",
-	"ZJeBTU/iYS7mFxGvQX4uO4gIytX8/82QhyBH/9guaVJPzQzl/2829C9qfbuQo4vuKmtRhs3HERUD/GK3",
-	"WXF5qByolpBhMZX3sUApeUL5PERYmue1kG+pZJjKYVMfo9vZ6DCPi9GDc2+MGB5MbZ9nnyCWUNuNkUbW",
-	"sBguM9IPbRxTy4zYjSzzkg8i6EYST6QrFy0QybOEt/QLKx2AZPEpmw50Xf1+LSUnQdMifYKCqvnGKrQ+",
-	"oja7zrWZ4B0HsgGIn8FsQ1q1I6kVaa8gqRxlpDvORyXk1prNjR3QarVa7fr5K2xveA97nY3z6/2m/K1z",
-	"zg5P9tnZPf5wdnYzCY/gVevYvzqlnderQe3rXs3da75Wd69fKpsvtjXNv27J7WzYRWHOJ5TZ3ijNI7pp",
-	"ALiATN1kYgR+2/ytCH5r/laUcuxvtf5vsdWhjwAXVN5/kPcIJAARh00DecdFI5XBhRghNsEpY0UfAaF0",
-	"IleLyIkK0yNxvzRNpv3pkBb6Zp0BhpgA9dGgp1Vut6G1JJ+3YPXKNn5KhWO5B0PsuY8MKb8Rm61P+7hA",
-	"DzjZ90AQ9zFmC22P1KaLuG25R+5GiGinASSKug3k6e6Y6xHUg4/sLtkj5GCCPG/26exrCKdlTCuavZf6",
-	"clOZP0pqhI+a0Vsf2DCnjwGcehR+774HSp8yY6XaRQ+lUhRTG+50L97xVAOJrMoSpGATw2V+JCzROXLa",
-	"AVBE1taK3Ks2EIEL4k3BGHrYQJBSIVuX4lFKmEupMPavWhumi6CZgeAPGXPO6S6awIrVgg14N+yPqRdq",
-	"9jOjQGbUwRnHs/hbrNzzaCQ71dst8pJzk5RFPB6kaCykLhpgYuz1sSfN71Izfh95X0n1G+RPbSPyjK6b",
-	"C5vbPMCsrVkHkIlHPYkNArF9VvvwHd509hRYDy+vU7bbMjigDOxddFO/FbUcNMBIcg5IomdzSUfKXXSE",
-	"wO81MEIvwMVDLN7PzKXe4jMMRq3Arv3IAWOvMNk2ASIwSBCRYUIrNh8gfVir668zmGqzRRrYRsbqvuxh",
-	"sU7P+q9Mle9Uakk2ZLC+uq7pcYz8x/iFN2VLKJVKu/uHnXPQ3r+67hx02q3r/VKp1OuRs06nXd1rt1t9",
-	"PGxNOrutYeemUy6Xez1SKpX2z/dmunyHu32yOOvuU7EEu9RVwlNi6lp0bJZYBGU3TP9yhXhAiYlS8LwV",
-	"Rr1QK7uKWdu34pzHkJtlqBu1Omo0N7dKaHunX9qoufUSbDQ3S43a5maz2WhUq9Xqci19FZE+3l3izPT2",
-	"TS1qn3GZ0tNqeO4hDwmU50s1UkNa8CNHbX3GxF3ueK2gpZoW9QxWNNLr67j/QSett3RqVOrVNqVaW3YS",
-	"ke6KfmBq5uj8l9C3HnLxHuiQ/9CDUT53Sp60GlDMEubs4IgNoIP++Gbj8c/0CS99eaZPWO3F7gRoFrQQ",
-	"FGeQ4AHi4ofCw08P+v3AmLW9x6Mv3pkJXviRG6NSFkWPDvV9LKx+s7+PIJfC2iDWcQQwzYtvcCDT5gZM",
-	"HC9Uys75/u1Va00nshgQtjde7Qy/IgVemdYWG3oK8FfJmAtlBkKFjlBLjnbGqbNY6Mfuql++zUoZ/bQr",
-	"60ovpnLHcS+rkT3W5eJmoLMnxUuGHMpcgEnKyl4G11IcxVzJihnpsUeU04H2AVVGPEZ9AFPDjjHUaqLW",
-	"Q5UCvIrxvB9p5wt3rBqt7SVrcY5NObhmLx82Ql5pu5Ab5LEiZqkIlRivZjqvfkXMDvNWbqojTKMLZ/fi",
-	"7MfeE9FW5pUiORdwqRP66slc6kMqnlYr45qPxI8t2rE4zQZWGjAJmDHuJfvJDCEPldYzUnYxATwEuQBi",
-	"QtVAvKi8ZqJBtOkGkTFmlMjx1UNiqkWPQEeExiQiv0cuYGreVfmXOlQ5fb4O9HYZ4EfIrDYpgMfjLt9a",
-	"LNCku6I1sT5PLNJIv+J6JO4nA63WJwPIWxXcPXsOZqDsBlc5l33GKLM8wpoAv49/zErxmdcMyK3PBDZB",
-	"3jSeW4DeT0rH5qHjIC73MoDYC5nUpE1gnNxQyoIeN5zjjElAw9zOFsTEzcUVRDa/OIIqNxhNR6TY3NIi",
-	"y2T8sjtrksz6fKh3YzYtm5/UE6ea9aOAQ+tThscfkwejeccgRj1wfdoFqg0eYCdya4gnVYG/y56azAbt",
-	"KpjZ0vdEYC44lvg8zMPAjPV3xnRKuWKaVlDBoYWFw+GaM+gYPat+tww2KV64xpMcHpr7ffYxU/4ecfxI",
-	"WJ+L7Ew2Exk2DY7Z7WgmJnrGOebT3rk9ZDTHcO1PTfxixZzHxwVQm422LkZbtmKbEp1W8CP4i7gRqKfe",
-	"x2EwtD/36s/Ru7C9zXd5Iph3yV+uBj/d1eCHeQlw7j1+rw/AvzNsKBvC+KMiEB8XO4DvK3f1dJtMFFvK",
-	"nQsTkNXWpHqJOOqRTO90uKC8rF0UcOqNkQkJFwyjMYrHL4NWDF9vWlTu+jz5nDyKw7GJKsd+QFnK5+tf",
-	"c57q/0o8DnrEMO+E6a4G11luaQ2sykR5/VUjtX58FOYbYr9W9ItcJXhr5aGWh14tHKFz2V0n1ipy6pyj",
-	"6jxPnb9UwFU6jvtXHNbfNg4rG36VmG5TT5wB5WLI9NPq6sLNr1iuv0QsV+Ls8+df6YrsVr7XeyQizYsu",
-	"wIIjb6DydU31YISqXDmJQ1DWcqd8SCjrEUimJiuWBHT61ULFBTiI8/dqzdHEjxyJyAHDjDm3HcwBHhLK",
-	"onQGK7Hb/4BQtFRGkKX90m2/I7hs9ct/9WAxKdfMKa86+GQFkUjfgZaRzXufyfNohKekw9yMHIlHoyON",
-	"o+ClRdnrusb/J+kD9s4PwBgyLCmgCMQUk6F2u9JR6IIm4RRO1E/SwNXR/qnVwToHXJdeOMQkbyML1GTr",
-	"eIbuV31Fm8limEpoltVG85KZFd/4jvb2l6HIe2mlTHDcFmP73RxlRllNIDCzr2IWoF8y55O48sx4P/2Z",
-	"j7pt6vuGhyx0eorWZFPKE60pPzQ0VvneEh+KCA8Zegwgi9LbLqblfdUeRHHPQHcEKY0QoBecNtulA1lW",
-	"CCBNdqOjSOPgURNMit2/TBRpstSFoaRbzebbQknT0QNz8aQuZm8MJ52BcBxKaiJLfxaAV40p3TO2gB/h",
-	"6opjW9aKBGy6LPLsnHkMmAax9pbyaNX5GVOC7jBQHIyu4AuaWngOfGIuuBc/un3HU+kaIa9tKrFCpNOP",
-	"KqSJr+pM1q1VUpOmmfibk5POJVbNy08KZ5OKrpah1KEuyrMr6C8JbWWuqISMFliEAw8KyTesXi/aFgWi",
-	"NsA4ZEv5KYlIy8wUNf2IvJ3V/d/PV9mEEh92ypu2Yal6VLSnvzoIPU9qQ1H2q+R+9TGhcVaszFy50yg3",
-	"L+M+OvO8ZRIiX3SvGUrHeAjkS6iguc1Udir/H68g1/5EGzDqho7lHe1Sf5gPwL9CLjiCAuwTgVjAsFS+",
-	"MQlf7EEXWQk6+wisJd4IYErzxERptjpbUxZWb80a9mWGn8QepDlEmPd7TmaVaRITpKIDdFq9j5IKP2oH",
-	"K+P3/iNzoywl/BTNp6XXTJRAQvvW4dIUkhouPUvOcLH3xI9ybXGM1DKfHDHtkCF7wFSyUws2ruaZ4ejM",
-	"XKb5zMB2BFNb/jf4JmtQf48/0sHexbo5ITp7F8ZwCyjpU8iWZYdw8aM/GD5qcCsF7NGHzqMU2HPOFYfk",
-	"MQj7j89o+jiCfLS8FSYcOUbtXNxSSntJfMj8jQVJKDWJUC2WIzZG7DE3J/oc8quXhfUA2tUGgTgrHOBI",
-	"qJS+uZr8Mv1Fh3OnjA2LMs5Zd/HXz9TzE7W6JU5Av7IE/coSZCOYBcmBHu1lbOSv6b0ZasUE9KciKwDV",
-	"Nhpbje36ZmM7u9LQLPUHZxR6zE0plOxU6oXu/HYHfEH0ZWqXOiSyO4FB6p1FJ9ofQfXyYHIWJ2vLPqyg",
-	"FyFR82UgATUeKMTlExhYH1c82EeeneF/Z+4mC2n8CkDNPjUmfqyKpy+3D0Q4ZEdA21v8r7xWa+a1+rYA",
-	"tN3UqG+CarQs5c+rk95SJlUK+U+LfMhToo01kWJqvGSUFDwF8ggS68EuI1Atm1U3zk46EPLgiAjWjAfO",
-	"hftDlGlsDaDvYuICGCeGIUhMKHsG2jVZp4UBr5QgHUYkV+UIIBgcDLCjzFc9IkaUo7hHhug5EgKTYSyb",
-	"yZFskp3d4pI2G8meRYDnstFH0youBIPAm6rkYOnqT8mkOS7mC0g0Gj4SeNT7ZW7oSi+sVuuO7qP+jf5Z",
-	"0b/5kD/rX778r/7lrNXWP/wvDjgSH/Wv6t/69+WOsDZcOGxffo/LeD90npHIN37JW1WKuVII7F63zvda",
-	"V3ugq/N8AMeDnINdNUR5tuaM+aNkZlizvk6cfGImniB2+JNMU1VUc0Gb+kEoENgnQ0yisJ0euY4LgKiB",
-	"ZkryTLAYGUXksH0JjLdtlNrC5G7JOi7o0CNdkCtxPkxuydiTIqrV0yPvTPgTK8EAl/SRhyF29Ym/i8Tr",
-	"KFWMSTaTrHqdWj5JEbB5UMot6u+p6ijxnqIbPe1NmYKvpHoDT1VYLQYlNPlZ5OhRApAy6CIEYgdxj4Zu",
-	"eUjp0IRhmBQxqqJKJa7IY4ogZSvwKCEi9AQumZXHOUYcj3LERaQ5GPojv5tCORF6asSMu72XYHYk7yJZ",
-	"2WUWyChco9SgnY0YuKh9g6i5krvkKFlMtqGvQs9yj6iYN4MkCurGLTiVWjHWdsw0RnS7jTLn+FBwABn6",
-	"2CMAlMA7qQF9/AP5EHvY/fbuI2hJwRliD0DXZYhzrfMyFDDElZ6dPGbIIcDMtrTkaaBXBO+ghx30P6nQ",
-	"m3dlM7O5H1u635pr0FObIfLm9qcl5SBUgkHwPzAIeEBFeWg6RX3SS1Iq9rrQMPuP6j7Jdc2AwJXSvxUG",
-	"LvUhJh//0P+VEyryBN0QCwT0r+D3gGEfsun7+ck9T08Y5W0zNy0Upu8sRBLSeydFqncza7JT3WLUjGpl",
-	"aeagUphBMu2RCL69GdlVIdwcVhRiYTTCh1UPr2AMKh/nwazeExWA0z/+lGKn8b3742ojqbtZjv84mzsD",
-	"cgcRFxJR6jOI3VK9Wm9u1Jdq6anhistKLR1GNqo1hIfFeQwNW9JWrMT69zs1UevvrbkMl7/FzQz49vIw",
-	"nZT/8hoSdNRtiS6o4hJdrS+s4h29H7XXfuZc9CkVq3Y+iDtYhcS5OdYup2WcxZa9hKh2i2B9kN7ZGkuw",
-	"RtRdMjrGXDsig5ur05UC46yrSwfk/3wftrd6lumX3qV+3+qt96d4oqXraxvjeXXuecIYKdUmi7FxMkkK",
-	"KEm3mi7PJztgebH6mGA/9HtEZ5ZzQX+aamfJ8deo7TR2NrdqO5t5Vk4trj/SYKWUEllNKuluyu/aZWtl",
-	"FFMpBXQ/pasowTXw0GwBX5PFQCA/Sp/XIxBwFEAmmaNp7SKpcWlhV12wWHBAJySaogzOzPg9khRHNXNE",
-	"OSblf+NlRN+iXH6Snz4rUwBDPcLDQN/4a/hAa1hdq3GXXqQZKskQwAyWfomoUWVSmPdWxAHyMFmqNZpt",
-	"mrhZEHUz2t3I6FmxE7weJZ3aUiWkiAuhla2XdbSWIGRRMXeL+VZ/jB2iTCftp/4vtTxGqfhXao0wSRyq",
-	"DRvzGSzcEEX+wUnODdcMqn5JBlQO75EAqRWF/GwXYC+Mw+yJqrUL6KBHOPXTZKhMy1KN9KEKA4jRLJoz",
-	"g2g9YoBQTlnj451H6GA1w/M+9VfIGBI9Kb6T7RVevTOqT7lQXCeVU9x/AambnWUWUAbtbEhS93Lvs8q1",
-	"E1NWau88cF+WG7XV3tNLKs6gvwUFE/LJkUpR5Gyxcq6M2GcgYHQoNaKlLoNRu5Vzc6RWbDJzxJx3tQGy",
-	"KfdmOq9x982Os5CnRflBsiBfKxVHsRAlBi5Ei9b/jkofmHwdc3SRrT2+pvyaVCRfq2S6qmpprXjpCcTk",
-	"bTWOPAhj96aEZdizi8MJXyX+vw+5errXj1OPypd01eBYKWw+2h+6dykV2oUiMS9qzSw6PA8NoSNBEaIB",
-	"LhQLo2mfKW2KUGLnWEYwynnBjfzx0pKP5fV2o7pV32psbNca6SB6LdTYlCb0kvPydK6Ogw4AF7rA5wgK",
-	"oJ34UCp6ioYiCIX9iHKVVVtkbo5LKCSUYAd6IGozD/DsfGUdBWdNch0/zc6gdfcCqE/gd8WB5Qzyt9St",
-	"JTVOEnqeRp+Mt0b6fddHOVfAWedsP3MHzK8eBoFn0sNUqCOQMAkTVnc7TZHnnJ8C9PH3e4DmUOdix9wU",
-	"8VlBc5l19I4HXcHXO4lkTIft5cejSBVCsxkOBxqTjJ9RLM+NMYx+U9KlHbPTgTRLsTvi/I9JSr6UUjGD",
-	"7+mn5lgtiEbQWk0uc1z+hhuJFG9fSjyEfS0p452pJ52KIJ5N01eOEl3MfTDl7t5ufsu33CRqceqm1ZcJ",
-	"nPCSo6NnJ7w0giU2CrH5K/VPDoP4z1d9K6v/Rn3VvxEMtjKtsn9wGMAg4HM/Rj/Yc9ZLALtD+aNJtGn+",
-	"ipJ3mB+S4PNiYaie/IdOPPIwRFzEdkTtOJzugKlIxtd/JMPLv2cbMzhJhqPCGj5fKBY8PM5OpFR26JU0",
-	"vzavyZkWaIzYVIwwGZZsn7XHpPUTdeRWgxdUEpCVXl4LxcKYB1LvSP5VomNYKBYm3MuRkySen5hSQTOO",
-	"R3P5KN7w/NpJpwiYiQkNXVoiVFXccNdLHxQSKAQi7uqBmCdx0oF1bFeBFEQtAp36nQPIhiYho9EIJUKr",
-	"xFUM6CwHKlssJC6QWkjmEiGU++IfA8oc9LaQCzNBXG4kGVp/KbmoHw5XyyB2YhJqviGXWjLtgU671PZo",
-	"6JZ2IUcLQhiyPWvVWrW6U90qV60Pi4oC7CmhnukTtuSDkj+Pwv4qmbQgf559TmjUbDJkKlQlWUd9Y6lN",
-	"1Sw/maoY1XFIYlgiqHzJOZsoxfXsC4okXpM+kagUxnOu6kRzON0yb/g8ZVgXiVkBOjacitz3s0PmZGWX",
-	"9+cQ5WSqMprC/BdBBfRsn2agYPK7B5p9q/GizsVcb/5iQaUUWc95ZNEYeVCOPLwfIx/gxfiUbZ67brSm",
-	"1qs7LXmzeUZTFaAwz5m6yBjPoibAg1MaZp2fQ6sy60EyDO0h1pG7gE4Bo9hsHyVmx6Lx9GWyFUGgjxwq",
-	"5V7zPFwEPOxz9DVUVjyC1DM/4MihxIUmNWFKlEPk8aZbvrk+KG1/rwPaKR1KdS6vCMs6Lr2xJujpMU21",
-	"GOPpe3r7d3TxXVqPJ7vXxUV53u75apJ2/bAcolGSTB1/GTs5zrrPEOqiJyslJBVJZ4hL/Z4/Yq22aukg",
-	"M4MNGhftznfyuniEPE6XG/azyhukebazJTMRiAjrA2gL3Fyd6kcN5SupHLXTdccGSDhS9I6M/WXQkXJ9",
-	"ZAn6V8i8f8Up4fWzUbFH9CtJJnefetOKrIWUeNMcB0sdJmM1AcmxEFb5e6CpMgB+N4f8EVRrm9VGv+bC",
-	"TbTTbPTdeqO/3d+uwe16EzXh1pZb629WBwP4vqgDOfoMEmdU8vBzOqw1GU/FssbZR6VG9b43H7qbbZFT",
-	"CGw+YcgK3UwOoMVBRntIIOar95LJCBnQaN+xdIIe4EMCh4iB3x1IXA8FmLwH2EVEYDEFOGVaEFT54Wi/",
-	"pWwRTtCmhIc+YkCq6zqJ8WyGRsiB42F5nWTbjBDpkRiXYjyQgn+EWDk1PlePhJuN6/wrFcjJL7T8q5Ly",
-	"37CSsv0YrAaCHJl1yWbyl1NMRl20sgWr4iqLHlrbovCWfjY6NWbYHy5WGAOjxDkjAJfBAfYQGHq03zde",
-	"0rHhstgjaFgG71QyRD4q/de7Ge4ufHuOgdyMDHExlSjDwIJ1dUxYQt+D5FmXkNCpuVNJ7KJh0gy2DO6w",
-	"5zqQuUZWj7ZjdtMob2yU57ZSL9fh273czHml8qLMOzBZUUFpxwL7ebH0i2sco4DmjOthB5lUVKsKvRm7",
-	"iqVqoS/VIes3+/2TQYOVBMt5W4bOtrUI5G9xqbTTSfT0kRMRDAlUOl9JUOrx70aV9WtR5aX5muNdeOi7",
-	"zeVAN+3sKQjsk62O1ypbPA/9BSwgovmoKQhVWaTW6eHFx6NW90g5l2QrUY9grbn5sVlrbm1vu6juuo1G",
-	"Y2fLqW25jY2tWnNzu7652a9V69tVuNnf3KpuDapwY2er2tiqo4Yr/7EJGwNr5HouJb2NWvBQO/Ys4P/f",
-	"QzDmDWkZ3RTjQ1ZHGmWZWrmA5Wxc8bKqjOmqscva6mYmp7KVUFKuMKtRSDfsp/xi5p8K+qt612QGslc2",
-	"uwy9QGuT3xUZBjmyR+3vmi9KKUzyvhh/tETlsKtT6ZoTuUly5GWrbLrGjU8whCKdUVWqz7GiP5qoDSW+",
-	"LTSNzyZVjHZrPe4ZgObp/6oCxUpGgLilbTqVGzYnP6dLBo+ByuC5CqacQRJn/ORRstNs8tdHo1uuNlpu",
-	"wtRo2bNB1m9JzJrav32iy2XzaNx5fDbvfot9z+IXQvtkqyFsxq5c7pFWVJRM5XzWkuQ7U1TlXRG8S+ps",
-	"qL9MfY93INmHsnv2SB8lqp8SPFX2aD2ir4XIbPAOZa6OCQsYcpBrStFrR1Ll9gu5iv6V6n6fjq3huanq",
-	"L39e0Ze1i7ysltZmGAxN3SYTPWhOI+FEsUEjx4aRFICZiXS5PATPaJrkVMBDkvigKBU5a4LJSAylUlxg",
-	"+vLwElze7J522uBk/x7snl60T9TnHukR/1PnfPew5XQdurvf2jsdbN8fPaPX403oemf3ky14eNjxjqEn",
-	"to+fai+V3drJh1Fn0AlfDkVw+7SFeuT0arh3s7X5BK+bwe1e0z84O64Hz4igq4pz7X/9+un5fPqJjz7X",
-	"6KfPk/3Xm25/o31+1h60D4fPn7c/1Xrk9eGZdZw2O6h+qk3YSd+DoTu6+YBvIWntcX9j+37/K+83Wzf1",
-	"LVfcsLP6p3v3brhz9eEzvhzcbl/1yMnu03W1Pr7dvXDPuvy+vnMK22SzE2xcjIPtzj6tdND+7f3GV799",
-	"cdmCJ9X+8VE9HAwb7RA98w/X3R6ZfLq7Ru3Tl/DhdPPi7DO9uDyZjM8+DV76w43Pe9vj8KF6Ip4qzvlR",
-	"7QWG1Reft8Kdo+MAPY8vLq9evB6ZfhVP04cBo7cYHUyDycNw/GkiCDnbrgy7+2Hl+Paa3VebNX//5nqr",
-	"7fS3Gs/O0cH1weDs2SPPh5UeqQ5uGq0r2Kw2juovT9Vn0Uf18Ylz+ZleXoQnu7f8qDuuVm8O71vTSxRO",
-	"P2xvOTeV+/3R2dZzvXt78tQjm6jzMJzis4vqxNu4P9y7OnFCb/LMd1ofQu95uEGv+w1ef/UfxpfVrUN6",
-	"/XLXqD3Bk+Zd98P56AGhHtnerH6mt6O+s3ESdD88DR7oE2f74mH7sn/z8OF+fLB9FTD3rsWejvrHz7Xj",
-	"4Oqk9XI9euGfWnx3dLjRI9XT8KV2B892q8Nap3npnLnHFefrE61uOw572v0c4pc7hps43Dn7HGx/va4M",
-	"uq/nPnc7Q7Jd+fpw0iN4+1PoDcKtrfDr6K4yEbW+IFgMr/jXp9HLWfh0f9N46DdGz+Jge3RyU/n8eatR",
-	"+zo6bZ5MWletT63dHhF7B4cPd1djx98fnuydbZx0W9sP/u1zv348Or0+2zj9vDuFdxsjh3it6Hfn6HgM",
-	"/dsnt90c94jjOx/wp+OL3d2z3Xar1TjA+/voaNNno4OjrfCWfzo9O6tV75vOw4i83G8ftHxFQ+3DyfZB",
-	"e/Lc6ZHdSefw4BM9brd4e3f3vt2a7LePhvvtg0ar1R4+f0p6fzi/b1W2du+DoTftth7uj0ZP05NRj1Q+",
-	"DDZfLwe34/5Rrbr/tf7c2bo42D2vktPPH3ZvNvxw3P3w9Trs1u9O2W7drx+GnghOrvaPT06F39zf65EN",
-	"dvj6uUWvN6bBzn1n+7S155612xfTp9YTp3c321v3N2H7Q6VPntg1uqqdXl20B9PL9tbm3c52E1/c9ojf",
-	"7H7o8097k6127ZR5buuscbYX0unDRheLQ/jQOPl0eis+XO/DjQbm993D9tMr3bq8376tH188N6s9Mvx6",
-	"N9yunVf6fm3/tbt1vV2/29/rb3jjp0bHG78MO19P0HBj4/Xz/YvP7rsPx8ftwfh18ME7726GL8OjHnl6",
-	"qRxXp95D7RT3D9nmYas1vdi5uWOth+6ke1bdd56utyf7bfLy3N0Lp1/9u8nt+Hz3c7jfud2+QPX7HjnD",
-	"NxuD4/Nt7m7tBfzgpXn24bNLzsin7ocj9nR9ebJX9++Y13LJ/vXIvb/dfnp4Du5Ge1Ner+zsoIseGT1X",
-	"2SmZVp/OJ88wHFTwzfaFs/l5fPb8dHp1djxs3uzcnkyPw7s78Tr5TJ7Ozpt3Vwe7X08a/IH6Z2c9MhD9",
-	"66OND81p/+qu0qqPd/vw5equJrZuXs+fnFf03H3Yx/D0fOe0cuQctztXG58Otje3a3tuy9s/2HF75Lk2",
-	"/ITvu59aEB5Xj49br0fjq+er49PT4Unt/tM9Pjq/ndZE/Xh6MOAM+s1Jt313MRhdos70dPf64bhHxiw4",
-	"9y77aMCvd5pb14Pa7nknHL4+sHbz9mWve/L8MLwabdwejrudT6Q9fX3+NN3cv6l9vQzwXXNH8qjRZefz",
-	"Azuhzkn95LS7U8Gvx5+urzzxdNb6R4/843JwvdUj6nbZP99bdPXklHyhDD1y7tkv6V8FzpYVOFvyIKST",
-	"1fBUJlswwJ6OBEgcd1MyRY7MstiV9hz6crwg8ajlJm1xSiqCXAo0HCiVK53ROIBM9MjvUfDJe2sljrkw",
-	"8KiiJF2z2syPfUnLPpaBnLeyFdMedrtHJ1owXEOvtoqSLdeN3UuiV5eQI/aOAxiKEWX4FblKn5nPk8f5",
-	"qITcWrO5sQNarVarXT9/he0N72Gvs3F+vd+Uv3Va3Tssni+OGjfbW419l+/ekKno1/uT8dVweOR98vr3",
-	"n70tslEd7+SUtLSm27vhughrpP7ooALOR2ojA8qydkrXx2R5kC5X7mTGdXge6EjSozEC8j8vaPctJSTy",
-	"iyu0JHorLc3QYJx6m6v9SdiVQVe/D3DwX2ASPxyosEDVvAj6oVDJGAZJymg+E8O5gmf7z61XkfIcX1au",
-	"YvZs1y9aoZ85kBvDFRPNpOPs63vnB2tWr4ieTr6rbMXKOdJ+QK4z0J9GybDskQ9RHTvXfoGSju6y8UOS",
-	"oC1dDRkoL3m+9mJ8yJ9XXYtsu3QlOi3culCxXg1pc9m8oXCFClF6hLQtTN+FDmLCXaOzbL7ImpZjJpyn",
-	"OZ3h3Tx4LymslCky9EaL49ww+auf3ej8Y1Ao6KOp2Qxn3iwXX/Gzp2AfWmP64zT009ZZi3ystq5Ltq2x",
-	"hPR7wwx/MHW4Zm4X5Y8oScAx5SlcwAUKeOS4ocPFrbGEsdPxjOum/BnAeODVhpvhkK7Opqen+LK46Fb2",
-	"xaTQnSlYNnMIjsBjnXTdyFeZhDscOQyJkvyUEtpVZAZlVnJXEadWE+i8BXQV26Y2kuaI4/G7Y5SVMSWF",
-	"d/bSrFQlg0gTUylyDZPXm3IU7lMqZkSAZANmHSXl+1raWCXwLXIWygyUl+k6avyo3cgeA0Zfpot8LVQ+",
-	"OZOPVjU2MWq6dGIqDHqmFlrHTDTjeWSHPmVDSFKPBenIjEa1XsvLWO2MHq1ulzPLj+2/SkGaGq8R4YxU",
-	"UdWFO1HnGe0lx6WSjZzl+k+8pIEHh1HSRDZydBpPPXdq4ijPIfQ4NRUwDYrxmeUsPfJsyvuUWpLC0rK8",
-	"uFIks0rgiilQkpPpYx6D4l3qjGRxhRMNf/2Anw+QlU4iXpPycPvuNb0ZJ2a4aga9i7O8MHNCKcaWomyb",
-	"vHqdKvK4RphF1G1JoAURgV7VgqAIIgIQNcpYEqplQpkYlaCPGHZgOaDUKxMRlCkbFoqFjUWf1zI9pAtd",
-	"5juuRa2KkWypGPbNdTuj+950K/tQnjZZLWRt/umeTFfwM2jddffbtdk8Rkv7dOvrdZnLOrt0jteQofW6",
-	"xOXs1+tmiWpa1mUuNGBZhzwPi29f7Hw5sq4N8VjyorkkTyq7KuaAj2jouYAh5YfbVxWDLwZKy58/JJ0z",
-	"S4UACZWkx3L2ZaDG9REkxuUfeh6wNAQa83iPQIb0taCtZ3PzwrituUPGmCrnR/36LBfcIyz0kK4fzNCA",
-	"MlQEE6RjeMzVpLAZqBxCcnd9BOAERvU2sACYk3eiRwLKOe7raBMfvyiPc19dreoZ3JwHEHSobH6SW8a0",
-	"k+elkYqVX83VKA2uODfMyiS1Yo/ZfJJrENSKPWboacVes0Ev65LGit3m4wiVK9P66X/iBEKrpNczOcx0",
-	"fj17ep9i5NAWoc2XGQRbM+EPCwnJy+qTSak2h7drb+g7s9/Z/fpmhvySe3Xlp2co83qc0yDKvZDOT0Ad",
-	"XDYsRmdyLpgi+XagGcP0OnlLGQ2DrK0zuajVx5U0ozlNcyVL/Dk7PNlnZ/f4w9nZzSQ8gletY//qlHZe",
-	"rwa1r3s1d6/5Wt29fqlsviwKC0xHnSK2YddgjH47n2Mmcm3XDQAXkKlwEDECv23+VgS/NX9TcVa/1fq/",
-	"xYXz+wjIA1FxQj0CCUDEYdNAKGurHqkMLiQfnuBUvf0+AkJlanV1LZ6kTlOPxP2yely+Zr6qK3PahXOO",
-	"kkz45aMOv1zd6J0Ne7VgxPqBo3btRs+QrvT7uz1UZ4gIYgq0eACoj4VA7vvcyLxfZWZyysx4Y395Pj7D",
-	"AGeRx4Z9KTywVHBSZKYC6EKCRbZuZQ0c4l0r2qt6dVhMuxKJNNLuIsg08+urfx1E9HN8dy31PtVSauu6",
-	"XTzqSIig8O2bstfo9HGzwe46y7qg5k1XVTvQwS8mS1y5kAnkMDa7VgCdEQI1lehCWQTit//JZFKG6rN6",
-	"cDd9eeW0094/7+6XauVqeSR8T+tdQgHjorurpjf5CBlQ5QQADHDKs/xjoVbQ5RyJ/PCxUC9XyxsFXXJM",
-	"ganieJQgXvkDu98U+7cVvDg0mKqvfVX6Api7WiJWYkfVmZLUU5IKY1WJLY38rqu4pt6gKVOYneTpVDmr",
-	"JeYrKQG5OjFkXDKy4+qltOWKu5EEEkAGfSSUtvzPOV6+F2fbjRYvKBiqkhuYKGYqRpFD/kcd75igtbbq",
-	"aMaUvVY2anXUaG5uldD2Tr+0UXPrJdhobpYatc3NZrPRqFar1eVRf1IjYuYdTx1GrVpNRTWbLDRxyrQn",
-	"U3EzWdBCiTYFJYXOWcikYSJRpPEDpzZZMecn7RCtNxnMANjVU2/8/Klboaqm94yUmwPWC9Gz13/+7Dck",
-	"8VSQGBggpsozx7itV9L4M1byTOiEzBxB8884/RuCXgIdO6syrQLqOCGTlJZm4YqKI+b9zy+SRuIYNnUZ",
-	"p5mQYl4xPqlxKtEfquQYt4Xb61z7EBA0iboWQUDl1nEU5MtNXR/1UjpGDEbMXfF7Y6VA0BmlK4LHNgs+",
-	"z7guKReGVxsmg7jYpe70x1G8Hj3y+viWvaMlM/s2x282fvTsHdd29OajSi6t5Gnk/tuYDovg84vz/OI8",
-	"K3MewzRsnIZXlgpO0dtl1ENZ/6ZRhvBYfioaxqJCuJVWL6V6OJZfKAMD5b1jl4n0wKe66O7PEypS01jg",
-	"PLvNXzT2i8bWvN3nUShDaZGa4iJJMDYXN/l7onyo21pqZlINEBI1XBQg4iIiwBPtW+5pPUJyU6+gX0Rz",
-	"CQrMuv7ztQu9ZQ2sfC0jgowGyy914xdD+lsxpFluogIev8tAsoZNJALZEmNIuhzFeuzq/5pBJAOpBczq",
-	"F5f6xaX+1kYRq44iJSdt7E1bRiw2CtlkLfEnxaz+QlzkJ9hXUpBRA//ZFpbU/HH4hwWlVKEvNEkqkvZV",
-	"PSxdETLH7iLQi6iox8fsemZBuzL3avyoCWy0+S2jmUuwZGpxLyAAl06I1LBzNfY900BhNYjyLWvCGmCC",
-	"+Sh1iy+4kKNx1tcgko5/uws5U9Qlc8zxPH1MoC37jR2NkxpM5v1SO4nF8P91R/+6o/8emkSarcRcRTs+",
-	"Jtg8z688k/z/LVrHHLsCC3UOLBJVo2jsJpwqEswECsI+DYXJEsNDTyy0R8rl/1JKlhtUJZxyeKBEATv/",
-	"UwVkCdUuI07oQWYKO4LfxYiGw5FxUz3uXpy/L//HXfwS/WPgLCYjHxI8QFwsp6W45QrkdIVEyAhXaayi",
-	"fmox6l3QiF/EkIqSR02J27ixQxVhxTVkzPFFJX6hAGkXEVOxVSeFgKRi/i5Fw5WbC0jxLAbBL3pcSo8J",
-	"sPIEk/RxryqY/M1pLUseKxBdKlXqYpqLc+dbpewekRBHL/LGTF9ETJEfcoE26fPI683QWuyOpHzdFlFG",
-	"tM5fhLGcMCJY/RLYfwns/8kC+xxvWs7veJ/6+QJGJCxAoEOSsjWy+RK5oUdmmkMWt1HltJOK3rlPBLsX",
-	"Z2te/nJNOo5JszkQjfF/5KlA7TaH06mP/9eu/2TTs6TgooBTb4wqSeHYhWbmPdN+N1Vn9mcYbaN51vKK",
-	"q/6E6fPttVGbJC+PStD2Z1+V0Qn+cpCbvzD/Pg/W5gxVZmWmA0FjijTONOlEU+n7au7i2Es1/NmeZXNz",
-	"2Qgl1QZkMnP9zQQL45CkbHdxtWnXurspcKMMW3NnV/lDV6D+tuohLrv905H7M+nILDd+XP56lVtf5YHb",
-	"KW/awndml3GgkjqC/jStwIGz0BM48JDOIMGjaKokAXi0yq8hUi8MkWAix3g0AUqWpf3TVI6HE75e6cf8",
-	"Zadzt7194ZkMcDlLj1PymUSca+3gy59Ez3GOuCUk7Sa55P4UDSUzuc4UGJK/nZZioGaksjgRaYZ+Fe9Q",
-	"kyxk+GqphtPPMArbDpMmFVVeNy+OPdVOh3b+TMRL9mATNWL/UgOMXzLOv8cooBH+72cSgDECyTs8zsAR",
-	"YVNCZssDKiGJixBGd65eWVLNUN6Ark2l19tc2TsHmebfpbbX/2QlPPco1QeQ/u0XFf+i4nWoGM1jkKTc",
-	"OEw6/4a8ME2+E+9nguLnN2qWongBwATIIYyN7+9oRV24HQl6ndi5ks5dnG87ymZC/kmGI3sq7T/ZfJST",
-	"89lyWLoliFai8yhE9qSMYP0nmpR4tKhfBqW/qUGpGydcN0iE3MwbLCUpkSiTrl0vKE5kOCednEFMwO8m",
-	"cTKm5L3JZziXHAMGuKwq2Y7wQOeUhQGuKK2+pPwfECsZWzSrjGuFecW8K+AQk+GiCbiAQ/Sd0yjYEgFc",
-	"6kOVyl9Ps2ycL9/+/wAAAP//Md6BgTIWAQA=",
+	"H4sIAAAAAAAC/+y9CXMbt5Yo/FdQfPnKyZi7qM1Vt2YoaqN2i5JsK3QpYDdIQuoG2g00KSrj//4Vtt6I",
+	"5iLbuckdv3pzY7GxHBwcHByc9c+SQ/2AEkQ4K737sxTAEPqIo1D/NULivy5iTogDjikpvStdwRECmLjo",
+	"uVQuoWfoBx7KNJ9AL0Kld6VG6evXcgmLPl8iFM5K5RKBvvgiW5ZLzBkjH4oufBaI3xkPMRnJbgy/WOa+",
+	"iPwBCgEdAsyRzwAmAEFnDPSAaWjMADE09XohPLLtIni+mo9y6PaH3kGn2fEoQR2BPiYngq6LBZjQuwpp",
+	"gEKOBSBD6DFULgWpn/4shWgk1zM3UbnExjBED1PMxw/QcWikN0avrPTu91KjudHa3Nre2a03mqXP5ZLE",
+	"hHUs/QMMQziTaw/RlwiHyBXDaBg+x83o4BE5XPRT67sNPArdS4l69uoFxoCXUFSZIsYrjVL5r1x2ucQI",
+	"DNiY8ge122mY/FnFfJ2Hyo4wO6zL0NjjkEfqlGQQBX2chQj6uFJ3djbq27sb29ubm7ubbmtgw9iaKM4t",
+	"RsxbXkIDvY1vIYEgGnjYUUd4CCOPx+2yR7o7BAxxwCmQn8GvfIyA7gLk4f2tDCDwKBmVAR0MI+ZAjlxw",
+	"e33WJ5iBEPEoJMitgi5nAD0HOIRiaODj0ZiDAQKMUoJCwMeQgCENAeVjFIJIrq1POAxHiLNqn/RJAgsP",
+	"IySmZWMachSK2UBqMgCJ2yc4OyFmQMDOoI8AZHIq8Xd6OpDMlmzRgFIPQfLtm7radhaRYhR6dlacnkI0",
+	"so4fOmPMkcOjEHXJkC4lliwRpLsDH3HoQg7BMKQ+wD4cIQY8PAih5NlZqOXnBwHPAgL9s/RLiIald6X/",
+	"V0vuu5rm6LWuGOJmFijAv+ZhO4eBvHBEKyAmAoKPMEklY4RD4CIOscdKFrQYjrNgtbJJObXfzztbD1ut",
+	"pZst+1m34iUK0bec3PEsQOHD5GGECFKknTnFpTtBidkVdcaUMiTJ/e4cSISCYzHMHUhGKQMXD4coRISD",
+	"IYJi9QxQAiTAAIr/m0DswYGH+sRFASIuJiPRgo8tw6kzhEjkC3RIoO6apc9zeCtrGrHvxYU4rXQop1Bn",
+	"FLlqrwVDAX7EJA+JCP4SCbFHNhzhCSIgRIxGoYPAKKRRUJXsQ0wiGAH1MRdcSpKw6CK2DjEueEoIiUt9",
+	"QAkCA8iQK1YIwe1tdx9g1id6hcjVC0xfVhIw223gUSe1U+kFnukvZpFBSCdYLNKA/yDBL4PpGIVqCxWp",
+	"szGNPBcMUniBRHQbYcZRKOE7plNxDjzMOICeBwwY7F2fjDkP2LtazaUOq/rYCSmjQ151qF9DpBKxmuPh",
+	"GhR7X9PX6H9PMJr+S/5UcTxc8SBHjP8/+GLu2Qcx0UM8yRuJcgGx+UmgnlAOWIAcPMTILQPMxY8uciMn",
+	"syEFeMgjXbBeFInzYb+E030XU1eWXFZAdx6UGxo5kFzrYY7kjBaYWDSIQXjA7jxQ3X0BUrrZK4BpoU13",
+	"Z9B0KnDQbFVarcZGZbfubFa2Gs2N+hbaqe+ipg06jggkfAFcAgjVaDWoNAkOMXHlXqsTqnjKFQ059Fah",
+	"RUOHHE9QxcUhcjgNZ7VhRFzoI8Khx+a+VsZ0WuG0IqauKJBzSNp0ttFwc7BVaTgbw0rLhfUK3Go2K/VB",
+	"fave3Nh1t93tpYw+wdj83s5R4JILoejuz3LIVVhODsjUADYQ9rwIBSEmfM2ryKGEQ0z0ezR355hvRkTg",
+	"FCB/INg3UXezIAroARjyIXR4KfVmWCQOxOPa3hJOxDj18QuML9ZFQ8XL7mS75WUMyyPGxYyHdH7VN0I6",
+	"Ft/wIBI/iVVHDMXSpqMepFXQHQIPDTlAfsBn8tOYMt4namAwxZ4nTxKbP9tD5NIQVjZ2bQcYEXFBuw8+",
+	"dSP91F4JreeyvQ2nknKZTdHgPIljr76LhQ7EDcw49DzkrrqdehTFLi2zp9aRk9IIgB7WgnygRmFlECJJ",
+	"Ha78eQCdpykMXSbxDjkcYA/zWZ+sCZ0NMHMa53bAwFKIsW/FlQ2aCQqZVb5oA4b8CQqBbgGI1NFkCGq7",
+	"ul3drr9epC06R2syE+igkC8//+2OaJaZSp1IxfexDfP7yUeBfCdEkMfiYsyG8Dp8yAw5s22Hi9nT8gHY",
+	"k2xLhkubXhyKlkOXLmt5uH8pW2LrmTnE3vdDQLzrYlQbEiQQM8aRbxF7MeNCnEjaAF+IkAHFhKdAfBUw",
+	"elIrSDZOdiB5JjjsXvWAT11kffsPcYim0PPWgER3MDy0GAsJC11v1YVcU9wl9gdVh5IhHsm3nbl09BN3",
+	"/l02IthcgAsf6Kad6KN4mjyVDy6aYGfJoy7dAagOZeBEYYgI92aAEm8mLsFh5MV3KHJHqMKwH3jyDVHR",
+	"Q6BQPv9zl2XNRZMac6F1gabj0hXGDb+WS08oJGgpGZyqVvrt56Fl7c9Uq6/lEg0QYQ4MVia0ywCRXqd9",
+	"pS6fkMvNwGT0IGk5oxuAEacVb+LPaQh6yEMOB2MhrSsR5klL9UYSiUcWurw3ZqA36rsQcUI4BRHxEGN9",
+	"wsdI6wzEM5qGwKchypxwLF412BkDBzIkXgbxOGd351XwRo4NvSmcsT6JGGLi9zJA4mU/HSMCkikIBeiZ",
+	"hzA9fhW8CeH0DZA9BWQx+KxPbIMUwJnVYoRwWiqXFP5iVH62PjwDynDRbXSd+ioO/TTEHIl/1BB3arPI",
+	"r8r+VbeW5dBa73FBORIohlx8YwYJXAqLAHIwiLDnAo59VF1d1InJKYbOerOFY+YvG+r6uHc+dz+HwfJ+",
+	"V/PdGAoFT1gKfs+0E33Y+AnNitktY2PwhGZsVdT0esenyIoNgeMXSpae7hvT7mu5FDEUFsMmvn7L/XfL",
+	"bC+jr4ukNnl/WwRH9ZiSV/QymUHRWVaeEzpi+7NQQG74vxwdMhB4UIyMnrmNUxfcn/L+y48EwQi74ixD",
+	"rcqZU+GGVNqTKEGXw9K73+dl+PgXTDgaSWn5uTKileTXrVbp62f1PLHZYFHoY8YEtwFq0PjyklBiAqjD",
+	"obzSfMgzwNW3Wi0bCgLIx5aZIB+D+DntZdcp2Yk/07/PjWgnxMspUSbcLE4jg1PR6weiNPfmkKv+vIx6",
+	"EykzS4I+JsbOvOjwmGZyPw3rz2paahMYLn0gpTqX47mXAJ8IlWvYY0w3FzhanFP8cs7IR/WDys5r5Gfw",
+	"q3g/05ALxfcIsd+kGjkIKacO9SQrEhJJerd/LzWb77gTlMqlnbr+B/ZhIP+5nu13Re5uFpzm8oKfrq7f",
+	"MCPcy17rMchYwHr3p4XHMR4i6FuX+8goeRDWJyp/WQKimeakd3lxE3cSR5962JlZlbJXERenM1aoA9UW",
+	"dPcNoxaXMRA8mpUBE4wCcgDJTAnexEEsZTIAnPaJoNvRmLNY8hOSjg85dqDnzQTFESR19ZrtiJV4WAxl",
+	"JtczO5Qw6mkZRHO6d6UokorRef4WUsFt9CrnPq+NxRQG8zwlmWnh4UwJQnMbLyxDUehl6S9hF0ah7bik",
+	"GiJ3DJUy21GXX83FjNfCMfJ2ajs1ZVCsiREpq1FWy2ArxDZk5c+R1vqlMJd5uXqoUFs1CkbOGDlP9q6j",
+	"YCQFpfQqlwJTsIM+4tDD5MmOKR+HIQ1ZVSk3g5CK7ajScFQz/f47RAH9l1F+NvtRvd7cgqEz/ldskl2G",
+	"NjWJhxmfByKGQXyuOohwyuT8/x0iD0GG/rVTUUc9NTMU/7vVUr9I+PYgQ5e9VWCRis2HMeVD/GzXWTGx",
+	"qQzIljDEfCbuY45S8oT0eTBUWuS1UKypDDEVw5bezd3O+g3zsJg8GPMmKMTDme1z3gSx5LTdamlkDY3h",
+	"MiX9CLtFMiN2jWZe8EEEXSPxmLdy2YKRIk14W1lY6RAkwKd0OtB15dBScuI0LdInJCibN1Y562PqI7vh",
+	"QUzwhgHRAMRmMNuQ1teReBUpryDxOMpId4yNK8htbm42dkG73W53Ni5eYKfh3e93Gxc3B5vit+5FeHR6",
+	"EJ5/wm/Pz2+n0TG8bp/412e0+3I9bH7Zb7r7my/1vZvn2tazDaZ565ZYTsMuCjM2paHNRqmN6LoBYByG",
+	"8ibjY/DL1i9l8MvmL2Uhx/7SHPwSax2EExKn4v6DrE8gAYg44SwQd5wZqQou+RiFU5xSVgwQ4PJN5CoR",
+	"OXnC9Encr09sK2Bj5Hnz4J/RESZAftTkaesc2chaHJ/XUPXKOn5KuWO5B4Wq4SFE0m/EputTPi7QA07W",
+	"HgjiPlptofSRcrykbbVPPgg9jXQaQLys2kCW7o6ZGkEafER3wR4hA1PkeXnT2ZcIzqqY1hR7rwzEojJ/",
+	"VOQI7xSjtxrYMKMPAZwJc+03rnso31N6rFQ7YygVophccLd3+YalGghilZogiZsYL/MjCX+V2GlHaIa0",
+	"xrMm1qoUROBSaFgn0MMag5Ry0boSj1LBTEiFsX/V2jhdhM0MBr/LmHNOd2YCK1XzcMh60WBCvchH8+Sd",
+	"fQ7mHM/ib/HjnpmR7KeewCLOTVIa8XiQstaQumiIidbXx540v4qX8W/G+yoU+1k8te2QZ966hbi5K0LM",
+	"2i/rAIb8QU1iw0Csn1U+fEfC3Uqg9ejqJvnGquCQhmD/spf6razkoCFGgnNAYszm4hxJd9ExAr82wRg9",
+	"AxePMP8tN5e0xWcYjITA/voRA8ZeYaJtgkRAw8wxTM6KzQdIbdbq79ccpdp0kRq3Rlk9ED1Kn5cRg/ya",
+	"AclKDIItigdRaDkJN9iXPJe4ynAQMTiKyZkyxdPDKPZx004Q8+oKBJ8eaMSDiD/YAwyuEHwC4lN+dNVL",
+	"vDwHM45Y2WwowEN5X0PleOYjyKIQuWn5XKmebPKehEdKBuuCIzt9b2hwgDy8js7jSvfQ+7Ys5CCZwEYB",
+	"Vrv7mj7nyH+IbfwpbVKlUtk7OOpegM7B9U33sNtp3xxUKpV+n5x3u536fqfTHuBRe9rda4+6t91qtdrv",
+	"k0qlcnCxn+vyDQEXCXDW1aeiSfaoK8XnRNm5aBMs0ShSc5z+5RqxgBIdp+J5K4x6KSG7ji83oWDNIhu7",
+	"GSyLAA0kIjQqaGd3UGk03Y0KbG1uVVrNra3NzVarXq/Xl+tpVnnUxatL3Nlev6hF7TNOc2pahc995CGO",
+	"irzpxnJIC30UKC6eMHGXu95LbMmmZTWDlYwUfF33P2in1ZLOtFJltUXJ1paVmKO7oiegnNns/5LzrYZc",
+	"vAY6Yt91Y6TXpbwRrCo0DcKcJQSFQ+igP7/abvkn+oiX+h7QRyzXYncD1QAtRMU5JHiIGP+u+PDTg347",
+	"MnKLS0ZfvDIdvvI9F2ZewUY8WijIpSSpr+USZTxE6MGhvo+51en61zFk49+MhCGm4kA3L7/C+1DpqjBx",
+	"vEi+lC8O7q7ba3ogxji0EKiOpFjx8F7r1l+/Ltqz62TMheIGobJNmipyHsHl0iD2df78NS+gDNJ+0CuZ",
+	"28WK415WC02sCIibCeMMpyBEjlBRYZIy0VTBjXjLYCalxczTo0+kx4oEhkkNcEh9AFPDTjBUOgalxJDa",
+	"k1UsLwOj2lm4YtlobRdri2d1yjs6e28Jg0hlp1QYIbQiZcnwppiucp1Xv13yw7yWEecV7blDqb+Y4/1I",
+	"B9o1ArPEWfyEDrQSE4IxHo1RCMyQAIaoT6SeE4nYiyEN9Si6vUenqebl1Lc4GtJ87BMYImDG0sonGroy",
+	"OhPNwBSFUj2hgqeqYF9pFaWSu55TCzXq5ZIPn7EvHqONel0axNVfFfnnnCIyOe69vcvz73sRmw2f1zuI",
+	"uYBLncgXY0qVgwxZV/ouxW1je6bajlJ5zQGTmDTtwXWQzBCxSCoWxlL1zIEwNXHAp1QOxMrSMc0Moh6X",
+	"iExwSIkYX765Uy36BDo80lpH8V2TlZq3VF6D9MX0xWqG1wtZ3+NRYBOzWDzu8qXFEmO6K1qTNxTJnYo1",
+	"rAiP4BDJQKv1ySDyTuZPyO+DHii7wFX25SAMaWjxc9AxtO/+zD+TMgZDyKyWONtLSTeeA0CtJ6XGYpHj",
+	"ICbWMoTYi0KpNlKxp6XPKYaTajh3fyQxQ3MrWxB2Ohe6owdJghQL4z1V0JfN89Mo/znNDWq0/lm3Kuma",
+	"Ec6q+ifpRSBnfcfhyDYz99hDYpOd970LqQduznpAtsFD7BjPoXhSGVu/zJqrF2h/4+olfUuQ84JtifdD",
+	"295yBpacdUJr5KyogiMLC4ejNWdQYbDWB/Qy3KR44RpWbzzSUlDeX0D8bji+eQ3NBU8nizG2A01jdlW1",
+	"TjuQ8z97v39hj8ousA35Mx0iXNP78W4B1vIJDcpmyVZqkwLmCq46fxNPHelNIdwq7B4V6rNxvbC3+SZn",
+	"H236/+nN88O9eb6bIw5j3sO3utn8OyPzslHC3yvI92FxjMWBjAhJt8kEiqY8JjEB2TeteIQjhvok0zsd",
+	"kSsuaxcFjHoTpLMu8BCjCYrHr4J2jF9vVpYRMSz5HI/G4EQnbsB+QMOUW+Ufc8EgfyROPX2imXfCdFfD",
+	"a55bWmMXM4GUf9dgyO8f6PyK8MoVXY9XiY9ceajl0Y0LR+he9dYJZzR+03OnusgZ7m8V05hOlfAz1PEf",
+	"G+qYjXBMFNwpG3JAGR+Fyna9unDzM1zybxEumfjT/fVXujx2K9/rfWKO5mUPYM6QN5Qp8WZqMEJlOqrE",
+	"5y6ruZNuWjQUPqYznXhOIDpt25GhNw5i7DcJs5n4gSFufJz0mHPLwQzgEaGhyRiyErv9D4j2TCXdWdov",
+	"3fYb4jdXv/xXj8cUcs3c41XFd60gEqk70DKyNqiqm7Okhaekw9yMDPEH/UaaoDDDD62xaD3tYpf0AfsX",
+	"h2ACQyxOQBnwmbBWiSY60QOnScSSY/qJM3B9fHBmjWEoQNeVF40wKVrIgmeydTx97le1NWYng6mcgdnX",
+	"aFG+wPIrrY2vt5+pn1dMtshsYezfzFFyj9UEA7l1lbMI/ZzZn8RXKrsHf6npu0N9n5KlK4xhsj3Kk1dT",
+	"cfR1/OR7TQg2IiwK0UMAQ5NBevFZPpDtgUktAFRHkHoRAvSM02q7dKzYCjHayWpUoHYcn63jtbH7twnU",
+	"TkBdGK29vbn5umjtdIDOXMi2i8NXRmznMBxHa+vg7R+F4FXDtve1LuB7eJPjWJe14gHWXRY5T+eMAcIR",
+	"nJqYothpXKVATQm6o0ByMLqCu3UK8AL8xFxwPza6fYOpdI2ocmGH8xBPZ/ilYfqqziS2WyX7b5qJvzr/",
+	"71zu4qIUwDCft3e1JMAOdVGRXkF9Sc5W5opKjtECjXDgQS74htU3SOmigGkDdMyDkJ+SoM/MTKbpO+Tt",
+	"rh5icrHKIqT4sFvdsg1LpVHRnmHuMPI88RrSDVL3q48JjRPPZeYqnEY6w2n/3Jx5S+ccv+zdhCgdRsWR",
+	"L7CC5hZT2639f6wmVCgF8eYi56zNJUd+mM9xcY1ccAw5OCAchUGIxeMbk+jZHteUlaCzRmD5LUaYfHli",
+	"Il+2KiFaFlevTcz3OcdPYhfdgkNY9HtB8qJZEnYnvZUkYdF34hS+U25oOrTke6YfWnrwU2c+Lb1mAnGS",
+	"s28dLn1CUsOlZykYLvae+F6uLY6WWubzj6YdMkQPmMonbKHG1Twz5HRx89zAdgKTS/43OH8rVH+LP5LQ",
+	"5a+ZdqW7f6kVt4CSAYXhsgQsLn7wh6MHhW75AHvwofMgBPaCfcUReQiiwcMTmj0Ix9/lrTBhyNHPzsUt",
+	"Q0p5EoAz19aHJBIviUgCK1QxKHwoLDswR/zSsrAeQntKIRAnXgQM8SiYw2LqJb/s/QJlxoSUsmFRUkfr",
+	"Kv7+ybB+4KtuiRPQz0RcPxNx2Q7MgvxbBZGT6aDJVA5YEzGZXlKz0dpu7WxstXaykEYa1O+ctOuhMGtX",
+	"slLxLnTnlztkCwKcU6tUUce9KQxSdhZVy2IMpeVBpwVPYMsaVtAzF6T5PBSImgwl4bIpDKzGFQ8OkGdn",
+	"+N+YHs1yNH7GeGdNjYkfq+Tpy/UDhobsBGizxf9MHbdm6rivC1DbS436KqwasMTildwiaMZVuZws8iFL",
+	"iTY2RKfHS0ZJ4ZMjjyC+Hu4QWWNWROYnHXKxcYQHawZcF+L9npK1kb6HiSsCXDTMBPEpDZ+Ack1myswk",
+	"jHZABlsJqBwOeAiHQpcl1FfC8E4ZintkDj1DnGMyimUzMZJNsrNrXNJqI9GzDPBcwQczreRCMAi8mcy/",
+	"ly6wlkxa4GK+4Iia4Y3AI8YqDl0RTo8bjuoj/41+r6nffMie1C+f/1f9ct7uqB/+FwcM8XfqV/lv9Xup",
+	"/BpaOOpcfYvL+CBynhAvVn5BosRcIQT2btoX++3rfdBTqXSA40HGwJ4copov66T/qOgZ1ixhFed3ycUT",
+	"xA5/gmnKooUuECrYiCNwQEaYmLCdPrmJa+zIgXJVr0Qwl36IHHWugPa2NdljdHqkrOOCHEvXvEucD5Nb",
+	"MvakMOWw+uSNDn8KKzDAFbXlIqJQ/gu9MeK1ns7kc0qgXqdcVlJnbx6VYonqe6oAUbwmc6OnvSlT+BWn",
+	"XuNT1i6MUQl1CiQxusmxUwU9hEDsIO7RyK2OKB3pMAydhUkWLaqZPkzXGcsWuZJCRORxXNGQm+bA8ShD",
+	"jJuXgz5/5Ff1j5g8FWHG3X4TaHYE7yJZ2SWPZBStUc3TzkY0XuS6gWku4JWjZCnZRr6SPKt9ImPeNJFI",
+	"rGu34FT20vi1o6fRotudSU7lQ84ADNG7PgGgAt6IF9C7P5EPsYfdr2/egbYQnCH2RMq9EDGm3rwhCkLE",
+	"5Ds7nssRQ4DcspTkqbFXBm+ghx30P6nQmzdVPbO+H9uq35owqKn1EEVz+7OKdBCqwCD4HxgELKC8OtKd",
+	"TJ80SPKJvS429PpNaTUBVw4FrpD+rThwqQ8xefen+q+YUB5P0IswR0D9Cn4NQuzDcPbb/OSepyY0qRH1",
+	"TQu57pvHSHL03giR6k0OJvupW0yaphydYg4qYJaIMFiN335OdpUEN0cVpXIpRw+rbl5JK1TezaNZ2hMl",
+	"gtM//pB6wvG9+/3Kj8m7WYz/kE9OApmDiAsJrwxCiN3KRn1js7Gx9JWeGq68rJrZESIoxM669XFtYftK",
+	"elIXpUpxpSlHCpdKHtCyRW+joopMYSnzS2hEYDYNURmg6qgKOigYC8o9x6R7qbz7nBC5iHAMPcnY0t6A",
+	"fZJ5oOlfgRBxUVhOF61ULozSIGbMFT4YzFK3bpGglGyLXJU1djIFot0wlzYuptcTF9iVqEhSYumwx2Rp",
+	"fZJZW/4Oc1AwtqtBXYvOx1zXbKOaPU3LIniKZI5r+bu88PCIpPEul1gGPmXcuvtqyaxPlFcmwDwbMh+x",
+	"CoKicnB+xfGHpccixkG5FAun+Q1b4YgUHfyEUopSyK4WL3gVIoE8VRbaHjgoK4HKas2IATjkSKg4p2Ps",
+	"2d8/1jDBGAUCOOuyjfZ6jWfF4iTCei1Kv53YBX6lOuvHb9ZUbsut9LkBX1+brZuKbFiD/ZluS7REMmLZ",
+	"Re4yJYwZ7sC0VxEojA8o5at2Pow7WJ+Pc3OsXctSu5Eus5HKdotwfZhe2RogFJwdOsFMhSiI47PSWbBC",
+	"l05o8uO9W1/rc6p8QJZGhEgvkB/io1ouJSYLbVarzxkutfnCsC5ttkgy8sqUKOnauKIDFiK3ToPSJyqt",
+	"qytu6qSdJcFuq7nb2t3abu5uFdk/FCd9oMFKKXmyUlHSXde+t7+6xZwq2UhKLpJP2sBD+er5Or8JR77J",
+	"XdsnEDAUwBDyuLWLGMdECTdS9MacATolZooqONfj90lSmVzPYRI8i//GYJhvdJjktnmSSkKZIycK1Ftg",
+	"jegIhasbOe5SETtzSjIHIEeln81plDlW5v2YdR7Oh9XyBZuco6ab1vuMtQYmDo9Ro6TzSovpkyqkVasY",
+	"b2AJojCgrAAc/dFAZDopGfcPCV5IKf8jBSNMsnYrled8bhs3QiZyIMnG4+pB5S/JgH2SeloqFUJxHhyw",
+	"H8UJOIgsdA/osE8Y9dPHUBqdUIiAD2WAUExmZs4MofWJRkI1ZaeLV27IwWqgYwPqr5BLyMjOb0R7SVdv",
+	"tFKkWiqvk0Uv7r/gqOuVZQCogk42WLF3tf9RMLXkZKXWzgL3ebm5S649DVI5R/4WEkyOT4HYiowb1spZ",
+	"dGJvoiCkoxCx5c7Ept3KWXtSEOucPTHnXW2AbLbTXGdUwLfVV7GdwhdxluXU5WyOL/PuzN4HmaMTPzmH",
+	"1BOJxeLbSzUWbTFn8RCGk6/LcFdM6WnSGmXpYa0MQuWSKRlQMhhV/zZFkXSaoblDGwst0iV5TeE65jEp",
+	"V+tV3Kk9DK073fY4CsVVOjGOz7FXZsLP7HVH4JStkrZEmMYeYpv6g3SBXzWmX0jCD3b/HJFVUHl+JVYR",
+	"pVAym+ehEXQEKiI0xKVyaTwbhFIJRCixs1MttRU4nhg34rRYZnE6adS3N7ZbjZ1mK605KE7OjZ4LDOYX",
+	"cjuEOY7LvZUKTuV7jFJBnyp/uX2LCnVstoQCBZ7skFAiTATAtJlHeHa+qgretZa/iD1KcmTduwTyE/hV",
+	"Xg9iBvFb6koVz2ESeR4czDmZpd1SfFRwP513zw8yF9Q89MKQqrNa1ajDEdd5Xlb3lk8dzzn3Kujjb3dc",
+	"Lzidi+MJUofPipqrbHxKPOgKISpJAHY62rg4jI4hrtkMg0NFSdo9MhY2RS5S/ZsUfe2UnY7/W0rdhvM/",
+	"xL3SL54cvac9ZOI3ixlBXVqFzHEpJLG883pQ4iHssKRsDsqtvpRKfJDPwVo1+XnmPuhCuK+3GhSrlZI3",
+	"e+qmVZcJnLKKo4L+p6wyhpVwHGH9V+qfDAbxny/qVpb/NX3lvxEMtjOtsn8wGAjzytyP5gd7NRuBYJFY",
+	"I86irP/STcwPSc6McmkkPZVGTjzySIhBsflD/jfTAVOejK/+SIYXf+cbh3CaDEe5NetHqVzy8CQ7kdQn",
+	"QK+i+LV2gsm0kJIfH2Myqtg+K0dv6yfqiKUGz6jCYVh5fhHehiwQj6LkXxU6gaVyacq8AjlJ0PmpLiKY",
+	"85ecS6PzCq+RbjqzSXZ8Frm0QqisxeWuM0+5FBHIOSLu6vHjp3GulHUUa4EQRC0CnfydARiOdB5Z/VwV",
+	"BC3V5iFQyVlkKnChmBFPpMwlQijz+b+GNHTQ6yLF9ARxIbJkaPWl4qJBNFot8eGpzpb8ihSQybSHKltc",
+	"R3hiVERqtgWRV9mezXqzXt+tb1frti7qBNjtSCKXqyWNnfh5HA1WSQAI2VPeCtpq2mTIVIRdAsfGciOQ",
+	"Bj+ZqmwqPCWhdwYrnwv2xpQ+yBt+dUZ7mUFI5qfPTy5/LpuWRcMXG5hE4vwVsGOjKRN1lB2yoFqHuD9H",
+	"qCDBHn4p+MIph57tUw4LclI9hR7PdC4XBiGVSzIT0npW6kVjFGHZBKY8mNCFxfSUbV4IN1rz1as6LTEo",
+	"PaGZjKua50w9pDV7pgnw4IxGPGc5LVk94MkosmeGMF5OKnOVZLMDlOhEyzpAIRStCAID5FAh92qvlrLI",
+	"hs6ESoTI78qIz5BDiQt1RtWUKIfIw22ventzWNn5Vr9ZUSnSgV5RebZ1IhHil6CnxtR15HSAwtndPzEy",
+	"YWmlvuxaF5fre73Dvs41+N1SH5vcvnLYxDc77z9AqIserSchqVWeO1zy9+IRm81ViwrqGWzYuOx0v5HX",
+	"xSMUcbrCaMVVDKTapmjLwcQR4VbrbFt6NEiLi3TxlvEl6YqkQ8QdIXobS0QVdIVcbzRBf0Sh90dc70PZ",
+	"tMp9okw4mZSjYrBYWyj0KwV+4Sq6z6oCEmMhLNOOQV1CBvyqN/kdqDe36q1B04VbaHezNXA3WoOdwU4T",
+	"7mxsok24ve02B1v14RD+VlbxZ4MQEmdc8fBTOho/GU+G4MdJk8WL6rf+fMaBbIuCEqHzeY5W6KZTly2O",
+	"jdxHHIW+NOZMx0ijRrm8pvOKAR8SOEIh+NWBxPVQgIUPrnSz4TOAU6oF4cEPpb55rjw36FDCIh+FwBHE",
+	"JXOv5xPLQgYcD4vrJNtmjEifxLQU04EQ/A1hFVT/Xj2ANx+O/ncqnBZngJwD6pFJfbQp9r9iJsmT3uXF",
+	"TdxJHBvqYWdmzdJxFaU9h5ELVFuRrUPbT5ICPmXAqDrZIsWflCOIgxgw5gRNIeLeGo05K4hAcyghyEkl",
+	"AxYr8bAYykyeONIxavj10ko/QUjF5V+UJmBtLKYwOO+vaWZatJ3ZbbAqCApk1iWLKQannIy6CLIFUDGZ",
+	"/BOtrVF4TT/bOdVq2O8uVmgFo6A5LQBXgYh5BSOPDgY6uCNWXJb7BI2q4I3M4crGlf96k+Pu3LenRilM",
+	"JBNXytItFsHV1dFUAw+SJ2W2VBUFUrk3zTBpBlsFH7DnOjB0taxulqNX06o2GtW5pWxUN+DrXfD0fqXS",
+	"Oc17V1lJQb6OOfaLUoBk0l7OfUcBLRjXww7SGfRWFXozepW5byzyxXNokUPoIjJYSbCc12WoJIGLUP4a",
+	"f0/7OdEDFiUygATKN1+FU+qxbyaV9WsUFmUnnONdeOS7m8uRrtvZM6fYJ1udrmWRCxb5C1iAOfOmKYhk",
+	"zbv22dHlu+N271h6vmS2gI1hc3Pr3WZzc3tnx0UbrttqtXa3nea222psNze3dja2tgbN+sZOHW4Ntrbr",
+	"28M6bOxu11vbG6jlin9swdawVF7nJL3utOCR8jpawP+/5cDIr+Wl56Ycb/LXcmI9XL2wcT4dwtfyChXM",
+	"70z58sVtVTOdCt56ULKlrecLVhkF07w5NhdQEbukYaI1N6nzSyJ/oDSBQ0wwG6vLO5azXMhRRTLoBeqD",
+	"dC0i+5vcqKZW8pDpcTgyy7bZJlX1vlXBLHrEq0FSyy4nKI1Btm5MyoFqNdbViwYpb6p5G85gVZ+szED2",
+	"eqJXkReoZ/43RRpDhuxZYPb0F/laT/KIaS/G5C1of+emaxgVJl3jVAe1aedPHiJkHvOcFps3HnQUoO19",
+	"4wVANVoyetkEpsf2KCpcL5PYHRmXaqoxqIg65a+pPr9hcxFPQpPRJ0y69uqXkwIl/6SxWVfy6YTNvlgJ",
+	"M7f1RSokWXtpJT1S3NI2ncyKXpCZ2iXDh0Dmrl6Fps8hiXNdMz1kLu35g1ZPrDZaYapwA3Y+vchrUpKn",
+	"1m+f6GrZPIoOReKyFXwrYyOzfbLVjlbGNFHtk7YpxymrHajr4o0uJ/ZGxOjGFabkX7qy1RuQrEOqzvtk",
+	"gBLtgXy7yLoJakRfvUOyYauxQ2YQIkccGAcBrApFKLd2yGTeC6ExGtCJPcwvqXv215U7W7u82WoJ3UbB",
+	"SFcsdDKXd8IzY51YgRosKX2Wi+S6OhIWpSSbkAjyi92YMJnT4mWEzor4f3sHR90LcHV0Ba5u9866HXB6",
+	"8AnsnV12TuXnPukT/333Yu+o7fQcunfQ3j8b7nw6fkIvJ1vQ9c4/Tbfh0VHXO4Ee3zl5bD7X9pqnb8fd",
+	"YTd6PuLB3eM26pOz69H+7fbWI7zZDO72N/3D85ON4AkRdF1zbvwvX94/Xczes/HHJn3/cXrwctsbNDoX",
+	"551h52j09HHnfbNPXu6fwq7TCQ/r75vT8HTgwcgd377Fd5C095nf2Pl08IUNNtu3G9suvw3PN95/cj+M",
+	"dq/ffsRXw7ud6z453Xu8qW9M7vYu3fMe+7SxewY7ZKsbNC4nwU73gNa66ODuU+OL37m8asPT+uDkeCMa",
+	"jlqdCD2xtze9Ppm+/3CDOmfP0f3Z1uX5R3p5dTqdnL8fPg9GjY/7O5Povn7KH2vOxXHzGUb1Z5+1o93j",
+	"kwA9TS6vrp+9Ppl94Y+z+2FI7zA6nAXT+9Hk/ZQTcr5TG/UOotrJ3U34qb7Z9A9ub7Y7zmC79eQcH94c",
+	"Ds+fPPJ0VOuT+vC21b6Gm/XW8cbzY/2JD9DG5NS5+kivLqPTvTt23JvU67dHn9qzKxTN3u5sO7e1Twfj",
+	"8+2njd7d6WOfbKHu/WiGzy/rU6/x6Wj/+tSJvOkT222/jbynUYPeDFps48W/n1zVt4/ozfOHVvMRnm5+",
+	"6L29GN8j1Cc7W/WP9G48cBqnQe/t4/CePrLwgN/vXA1u799+mhzuXAeh+6EdPh4PTp6aJ8H1afv5ZvzM",
+	"3rfZ3vio0Sf1s+i5+QGe79VHze7mlXPuntScL4+0vuM44ePexwg/fwjxJo52zz8GO19uasPey4XP3O6I",
+	"7NS+3J/2Cd55H3nDaHs7+jL+UJvy5oATzEfX7Mvj+Pk8evx027oftMZP/HBnfHpb+/hxu9X8Mj7bPJ22",
+	"r9vv23t9wvcPj+4/XE8c/2B0un/eOO21d+79u6fBxsn47Oa8cfZxbwY/NMYO8drmd+f4ZAL9u0e3sznp",
+	"E8d33uL3J5d7e+d7nXa7dYgPDtDxlh+OD4+3ozv2/uz8vFn/tOncj8nzp53Dti/PUOdounPYmT51+2Rv",
+	"2j06fE9POm3W2dv71GlPDzrHo4POYavd7oye3ie93158ate29z4FI2/Wa99/Oh4/zk7HfVJ7O9x6uRre",
+	"TQbHzfrBl42n7vbl4d5FnZx9fLt32/CjSe/tl5uot/HhLNzb8DeOIo8Hp9cHJ6dn3N882O+TRnj08rFN",
+	"bxqzYPdTd+esve+edzqXs8f2I6Mfbne2P91Gnbe1AXkMb9B18+z6sjOcXXW2tz7s7mziy7s+8Td7bwfs",
+	"/f50u9M8Cz23fd4634/o7L7Rw/wI3rdO35/d8bc3B7DRwuxT76jz+EK3rz7t3G2cXD5t1vtk9OXDaKd5",
+	"URv4zYOX3vbNzsaHg/1Bw5s8trre5HnU/XKKRo3Gy8dPz374qXd/ctIZTl6Gb72L3lb0PDruk8fn2kl9",
+	"5t03z/DgKNw6ardnl7u3H8L2fW/aO68fOI83O9ODDnl+6u1Hsy/+h+nd5GLvY3TQvdu5RBuf+uQc3zaG",
+	"Jxc7zN3eD9jh8+b5248uOSfve2+Pw8ebq9P9Df9D6LVdcnAzdj/d7TzePwUfxvsztlHb3UWXfTJ+qodn",
+	"ZFZ/vJg+wWhYw7c7l87Wx8n50+PZ9fnJaPN29+50dhJ9+MBfph/J4/nF5ofrw70vpy12T/3z8z4Z8sHN",
+	"cePt5mxw/aHW3pjsDeDz9Ycm3759uXh0XtBT7/4Aw7OL3bPasXPS6V433h/ubO009922d3C46/bJU3P0",
+	"Hn/qvW9DeFI/OWm/HE+un65Pzs5Gp81P7z/h44u7WZNvnMwOhyyE/ua01/lwORxfoe7sbO/m/qRPJmFw",
+	"4V0N0JDd7G5u3wybexfdaPRyH3Y27573e6dP96PrcePuaNLrvied2cvT+9nWwW3zy1WAP2zuCh41vup+",
+	"vA9PqXO6cXrW263hl5P3N9cefzxv/6tP/nU1vNnuE3m7HFzsL7p6Coqd0RA9MObZL+mfpT2XlfZcYlNU",
+	"adpYKoe78LlQwSSJ73dKpiiQWRZ7Y19AX4wXJE7ZTCfsT0YGkAmBhgH5fEvn8g9gyPvkV6P9+M1ag2ou",
+	"AYqppUzXrLP2fY2xWXsrKDC3rpjwt3d4c/WjE4XI7K36hU3F/4hJ0w/jPoERHyPCY5swHwMIghBPIJcu",
+	"TzpAiLPsKyWTCKRPcplAMI/zgIhsQDIOMIwC8WxQoCpUCqO0a3tCFAeUpO1G+UQOeR1BOlhVYkKaaxJ/",
+	"dxMOkyc3WTi0+sWh06btXI5pnjuIwVku7YctccXyPCZpxNtSlliysfSJXrfslPkeO4TgIcD8DTOZSPPr",
+	"lfDHzpxzgBM6JQ9i1RZmcEyZnDiJSZXjxBF+isnpVDXSxtk7BqkR+0RMrkhSE6oMIKZAR9Wm3sC+8v8Q",
+	"y+QpChajMeuS0lsChK0Quc3NzcYuaLfb7c7GxQvsNLz7/W7j4uZgU/zWvTz/8oU83b2Efr3nHm3dXtIv",
+	"p2cMDkbHm51d+vQB191x09s9Oj3xVk/RHJdbSlI8Gp2XPg2pOHyaWUaNhZNacY4co4OO2VuzWS758FkE",
+	"+pfebW1ubmxqR33xd8Pm/BsxFNqP2n7uOKVK+hg/7YVE2if2vDpFy8lpueQxKxfnf074Z5FGLW3umbPX",
+	"oI1B3Wm1mrs7Q6fhNFq7cDgYtpyd3d2t4WC32WpuQ9RqoNZWa3ewu9FyYGt3c3e3Mdje2WwOdtYuvmRl",
+	"WVl6KNr42iJ2ZMuWnTJ0WRHXOz5Fs6W3zZx/rMUJzXVj19g0lbxhwq9kTEP8glypSJtPTb7ScWz3PmD+",
+	"dHncut3Zbh24bO+WzPhgYzCdXI9Gx957b/Dpo7dNGvXJrl3ktGc4F7URBbyxxljtAhurC49mt0LmSFuK",
+	"eTlTcQagHhKCoDZgsr8uG8prqvYV17NrC7lKqgdVk6TaEZPrE7irgp7ybWDgv4QLhHZ6kPkWZPMyGERc",
+	"XkXDpEpPPlZ7hai8H1siMBX1tqxCYH5v168TqFw0hIeXxism6uKMC16J+pnrFQxUY7JvqxS4clrq75Be",
+	"WoiM5iKzRm2a0uGu/eVGuqpL47vknV4KDRnKCD+2NjAir/GqsIi2SyFRmbjXxYr1TZKy8X6DaVuaaf8K",
+	"u3Y4quosNNUw8AuM3D/aLG09N2mD1xwmV6lurEZIW7PUa9ZBIXfX6CyaL7KHFRj65pmXqk72gJdOni+Q",
+	"+0qb4dwwxdDnFzoHPIw4fVCusAkNr/RIz++CfWjFMh5mkZ+2r1o0XHLpqtz4GiCkfRtyZ1LXkM5d0zIo",
+	"RZxGR5dWdAHjKGBGXFdHxppQIo48y8XviJ8BjAdebbjccXJVJng1xefFBaOz3hmlXq7Ydm4THC7ezYK6",
+	"tKCaSRbLkBMiXhGfUmo3GZ5LQyvflGlHrEbMeRvmKtZJZeYsUKjFzmemokBKj9bdT99JUj2TPkwVEx9A",
+	"iY4WG1DKc7JUsgANR0UGQNnSfM7fVsZjPDNQUZUm0/hBxRI8BCF9ni1yuJW50HUtFdlYJypQZf9TuXBy",
+	"dby7eqIVfDXK4pqAJGXuT4fntuobzaJqS874wRp7kwM/tuBKFedMuw5zkQqNMr5wJXI/zVoK4mrCsbNc",
+	"gxmDNPTgyCT8D8cO4DSeOzWxydEPPUYB9KZwxjSJsRw4S7c8W64NJe+7FJVWxcWVOjIr7JkprlmQi26e",
+	"guJVahWmGUDhX3lxFiNkpZ2IYZJhDt8M06tpIsdVM+RdzvPCzA6lGFvqZNsEmBvsoxd9uayhlDbdlkTb",
+	"Eh4oqBZExhJunMNY1hZQrxIa8nEF+ijEDqwGlHpVwgNhiymVS41Fn9cyHvAUDopVtqZVVv98e9NJQ126",
+	"7dUOoNhtslregnnLAJmt4NPY/tA76DTzmTaX9ultrNdlrmLK0jlEgpT1unRM3pL1ullC25d1mYsPXdah",
+	"yJtzqXfx+RSui4aCpPLLus1bl4Q7c7EFCYIRnghGOZcjVZYtwQywMY08F4RIRooNpAvm5VDqcuYpSKWc",
+	"lUHqXOa4tBCmUPpjBnwEiQ5KhZ4HLA11WnORzDVE6s5Sxrm5eWHcVl9wE0xleI7xGb0c9kkYCdOAsDiE",
+	"aCiz5E+RijLX96Y8akB8lqsTUXJTaApZYg4wI294nwSUMZl1XbzT8bOMifTlvS+97PROAE5H0qQoWHl8",
+	"sItU1qlsTqs5w6fRFWcvXPm8r9gjX6hhjdO+Yo/cYV+xVz4se91zu2K39LFdFWPWPPerH9oVO8zn4JDe",
+	"5uvn9Ywzg66SN1snJ1aJs+2pMcsmGMQQ9Occ6a+ZLDOMCCnKiJnJlTx3otZe0DemtbbHxOSG/Fx44xen",
+	"NquyjTgfmMlbls7tRR1cVaPpAlECgZEXVHVi83Jp4gs6FoMo6lQjMlVTTyYiseNX21DWqV0Q0ijIquUT",
+	"UUh+XOntOfeWX8lodBEenR6E55/w2/Pz22l0DK/bJ/71Ge2+XA+bX/ab7v7mS33v5rm29byamjFiKGzY",
+	"34hagzCfytFEkKoGQGoQtXvFL1u/lMEvm7/IdAa/NAe/iDvFxIqKvZPh+H0CCUDECWfSbcKMVAWX4jKZ",
+	"YobS3bis1uCqSr1JFec+iftlX8rFuo9VIwZtssx3cWABOr+bwE4q1Vvi1jLpyR/lDd4nkw4ismgHcZWX",
+	"Si7Og8MnpG0oywzV0p1iJnsRkbsuXRXHuLrYvFUcL2I8n8ero3+02eQghw4ic132k98LeknqmO+kfrb0",
+	"GVLPtRlAZTYe+c0sL4FJoDiIVBzOBIcym7kPnbEKVcsb82/0k5atW53Q6siTm0/VBRQNMQPUx5zrUnpq",
+	"1/SWlONigSpVkimmCcGtKPSLGZD8DsYZ+FcpJJXXXKa3xom3NtmTZUekSODL0sLiTV+wvSs4KU3UNMvc",
+	"lNavuZVDVWqAsnHiyKBvCdJSEZhz2NLZkx5U9qTV7b7ZrFWWm2b9vE92qlYzpKK1wa/2TBsxSQrfLE3a",
+	"vxUm1vlZ3LqguLU38Zfn+tcyWJ54bNSXogNL3Xh5fQvyBRHBnGUzVYEjvGc9HAw5UYj5rCeISBHtHoKh",
+	"OrgD+a9Dcy+ffLgplUuS3KSeVbWLRxUGiNLXr1LTPqTzUGpzvcwmJ/1p5a2ocleoC5RVS5k8DIqMS+0A",
+	"OmMEmjJPpdTlxn7X0+m0CuVn6eys+7LaWbdzcNE7qDSr9eqY+57cHswlMi57e3J6XesgBLKIKYABTgWG",
+	"vys1RR8aICI+iGwX9WpDu3xJNInapwSx2p/Y/Sr+HtnK7B5pSk0qDkCgnwuCsBILmBIg5PUhs1DJohla",
+	"uYGJ40Vuyv+XhpKyE8c8WQ9LUL58qCBXFZ0QzEn+3HUVKB0Bcc88ggIYQh9xqef8fU5G3I8r+RjgOQVi",
+	"jWJ7S++MM5feIplCJiFrpY9XjCnLqRvNDdTa3NquoJ3dQaXRdDcqsLW5VWk1t7Y2N1uter1eX560R6iL",
+	"Qu3KIjejWa+nkpLpJLJxxnORDUn8lgC08LmfwpIk5yxm0jgRJNL6jlPrihvzk3aJUippygDYVVM3fvzU",
+	"7YiPAadCUJW0KAFRs2/8+NlvSeKsJ4U/FAraADFtK0hafwUk0jM4twWbf8Xu3xL0HKjUV0i0AdRxolCc",
+	"tDQLl6fYMO/fP3/9nEpBkyt7AoFkXjE9yXFq5g8h2WgJLZctT9Xxg4CgqelaBgHl6k2jcnQxXU1cOgtN",
+	"UAgNc5f8XqtwkaiFpZwycZhW6LJ5xnVFGde8WjMZxPgedWff78Sr0Y3j49evX/PM7Oscv2l879m7rm3r",
+	"9Ufpd248ff5dTCc0+PnJef4GnKfV3P3xU98gAgnXUQ8U+CJ/X0SMo5mBiP2TOKFmYjbOx2pLBTnjBWN6",
+	"SEXPzFRDi+W5cqw2KPeJR/UrA07EFxqCoXSorYog+A+Yj2nEwR8e9jH/oyztTGZ0qe4JEY9Conq6KK7U",
+	"IRgndqtqhKS7er0wDkLkqKpkjla/ZQZVeRJEgEvIuFwEMAwOuBQJIxLgopSgkTm5VHuJrj4NZR1mNVo5",
+	"rn4lZibomYPAFLgHf9DhkCH+B4gIxx4YoqkcCZI+0QDbl2qXXlVLmeB8ifR6rqJMgPLoTO+X8nwQsyhM",
+	"m+AdnCuUXjVy7pcIhbNE0JVQl6yybaNeT8W3NOr1+uLwlq/lPNgXVnDZEw6KwFH4tcOTnr6+yvSXIk5O",
+	"Eng8vdxF+RBXwkMRHLFlZa0rL2PeWQkeQ8uqFrIEjGMfFYGlmz/I5nYsiSoIrUq9Uak3bur1d/L/35fK",
+	"qznk/tBnSIrcLZwwz4h+3so/3wNrvgfmSShzFxrFhovElWaLCxG/J+oKKd9rnoq5IA0XCSMpIhw80oFF",
+	"slcjJLL9ChoJMxenQMP1n6+PUEtWyCrWSxjMKLT8VFD8ZEj/KIaU5yYyPdU3qVTX0KIalC1Rn6allvXY",
+	"1f81FWoGUwuY1U8u9ZNL/aPVqFYtgpCcag4kDvIWKFPld+ERoxzJBD8yfCzWrSqmJX5KCVZCmDLVUIRr",
+	"qHirxyqZGdLPeYNgoWtYrFVVkKwtfzmm209mlmHy5m2KNIa81AH7t/A4Y8J2Urpk6IUIurOYbn7ywZ98",
+	"cB0lqmFeixigp8NTCvifJ2vDCcJsn3d1Ep4/4JT9ka3Hb/ywjDgHupm/+0SaRrSlSbX1y/GwOghUhDMl",
+	"IeASMpN/qNwnNPZazBS2U24eFrYpuq/1ak3B+zfilz/AkJbCjBz4rzalpeaPU13YzBpSVT3V/g2YgQGS",
+	"165SF9pZtXBZrUnv1Sw8edSuLHS2vtcENlbyNXNaJb1DAtCzNs4uOLYunRJx/ApNIfu6gaRqYOpiKnko",
+	"bxnSYTSYJc4qfRJ7VCo7BvhDGkgWH/uyLB0lD6pu1yeqobIcqPSNC99tBu71FU1Jx3+cqJMp9p8hq3ie",
+	"ASbQlnzffmziKmumaKQKzTL7/fMp91OE+YconNJsLOZiKtwwoeZ5/ujpotCvUU4VsccC1RTmiUaqrNXr",
+	"jMojmEnCBAdU+8SHiEUeX2g+FeD/1F0tt7sJPBXwQEECdv4nU0sSqnyRHZHn1iSZ/FWY+UdjHRwqSvz9",
+	"Vv2PEzQE+cfIWXyMfEjwEOm0nQvPUtxyheN0LU37TMZamH4SGOlwpsU9komZAQfiU9xYxODQ0I8TS+rt",
+	"c9EQE2F45iDte2wSPMlMz5DU9N8VM1x1c8FRPI9R8PM8Lj2PCbKKBJP0dq8qmPzDz1r2eKxw6FIl9Baf",
+	"Od2wQKoXJhUE0LO4MdMXkfHfAcryy0w4hT5rsZ+7DKJYdDIMnD8PxvKDYXD1U2D/KbD/Jwvsc7xpOb9j",
+	"A+oXCxhGWIBAJQIBvb3Lc+BSJ/LFopbIDX2Saw7DuE3vav+jlhwWWpL3Ls/XvPwFTMryo9gcMGP8HzHC",
+	"yNUWcDr58f/a9Z8sOn8UXBQw6k1QbeBFKAgx4Wll/Lxae1+334ub/xglsZlnrXCL+g+Yvlg/bNokOY9l",
+	"1ZW/+qo0O/gz8mL+wvzn+DXpPZSlIkKVfik+kdrnMp3EO31fzV0c+6mG2hH+xx2U/Fy2g5JqAzJZz/9h",
+	"gkUSC5BUvweudXUi4bXOXj63d7U/5Z/066qbuOz2T2eiyKV6t9z4avIVb32ZY3+3umWLC8+DcSgrNQmD",
+	"TeoBB84jj+PAQyqpJDNh+kuDKFQWBh35bgHt9xL0sUostE5SxEVgp/Pivx7w9ChFoMflDnR1rbVW8Pkv",
+	"Os9x/v0lRzqm9L/ohZKZXFVhiMg/7pWisaalsri6WOb8St4hJ1nI8CWo9pAn2wqTJjURhVWYoy3VTuUM",
+	"+ZGEl6zBJmrEYQgaGT9lnH+PUkAR/D9PJQBjAhJ3eJz30lBTcsyWZ+qAROUjIU585yrI4otB3oCu7Umv",
+	"lrmyNxDSzb/p2b7xFz/CC7dSfgDp336e4p+neJ1TjOYpSJzcOP9O8Q15qZt8I93nsi3NL1SDInkBwASI",
+	"IbSO75+oRV24HIF6VTSrlq4LVaw7ylaZ+kGKI3uZsr9YfVRQT8uyWaplElYvY6mNPikjWP+FKiVmgPqp",
+	"UPqHKpR6cTE7TUTIzdhgKUmJRJlSeAqguLaBJWsCJuBXXUsJU/JbUvsxm3UNBrgq+Acb46EqMwMDrApC",
+	"VqT/AworWhcd1ibN0vzDXNTyEk4cCyaQdbq+cRqJW8KBS32ISTzNsnE+f/3/BwDBYejjZDgBAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
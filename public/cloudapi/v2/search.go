@@ -10,6 +10,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/distrofactory"
 	"github.com/osbuild/image-builder/pkg/reporegistry"
 	"github.com/osbuild/image-builder/pkg/rpmmd"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 )
 
@@ -44,7 +45,7 @@ func (request *SearchPackagesRequest) Search(df *distrofactory.Factory, rr *repo
 		ModulePlatformID: distro.ModulePlatformID(),
 		Arch:             distroArch.Name(),
 		Releasever:       distro.Releasever(),
-	}, "")
+	}, "", jobqueue.EnqueueOptions{Priority: worker.JobPriorityInteractive})
 	if err != nil {
 		return nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
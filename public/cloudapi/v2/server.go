@@ -82,6 +82,22 @@ type ServerConfig struct {
 	ImageBuilderManifestGeneration bool
 
 	BootcUseRemoteContainerSource bool
+
+	// Maximum number of unfinished composes of a tenant channel. Channels
+	// which are not in MaxPendingComposes use DefaultMaxPendingComposes. A
+	// limit of 0 means unlimited.
+	DefaultMaxPendingComposes int
+	MaxPendingComposes        map[string]int
+
+	// Capability labels a worker must have to run the osbuild jobs of an
+	// image type, by the distro's image type name.
+	ImageTypeWorkerLabels map[string][]string
+
+	// Names of the workers' S3 credentials the generic S3 upload targets
+	// of a tenant channel may refer to. Channels which are not in
+	// S3CredentialsRefs use DefaultS3CredentialsRefs.
+	DefaultS3CredentialsRefs []string
+	S3CredentialsRefs        map[string][]string
 }
 
 func NewServer(workers *worker.Server, distros *distrofactory.Factory, repos *reporegistry.RepoRegistry, config ServerConfig) *Server {
@@ -220,7 +236,7 @@ func (mjd manifestJobDependencies) IDs() []uuid.UUID {
 // enqueueResolveJobs adds all the necessary content resolve jobs for the
 // manifest to the queue and returns a [manifestJobDependencies] that holds
 // resolve job IDs by type.
-func (s *Server) enqueueResolveJobs(manifestSource *manifest.Manifest, it distro.ImageType, channel string) (manifestJobDependencies, error) {
+func (s *Server) enqueueResolveJobs(manifestSource *manifest.Manifest, it distro.ImageType, channel string, opts jobqueue.EnqueueOptions) (manifestJobDependencies, error) {
 	var jobDependencies manifestJobDependencies
 
 	arch := it.Arch()
@@ -236,7 +252,7 @@ func (s *Server) enqueueResolveJobs(manifestSource *manifest.Manifest, it distro
 		Arch:             arch.Name(),
 		Releasever:       distribution.Releasever(),
 		SbomType:         sbom.StandardTypeSpdx,
-	}, channel)
+	}, channel, opts)
 	if err != nil {
 		return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -258,7 +274,7 @@ func (s *Server) enqueueResolveJobs(manifestSource *manifest.Manifest, it distro
 			PipelineSpecs: pipelineSpecs,
 		}
 
-		containerResolveJobID, err := s.workers.EnqueueContainerResolveJob(&job, nil, channel)
+		containerResolveJobID, err := s.workers.EnqueueContainerResolveJob(&job, nil, channel, opts)
 		if err != nil {
 			return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
@@ -285,7 +301,7 @@ func (s *Server) enqueueResolveJobs(manifestSource *manifest.Manifest, it distro
 			}
 
 		}
-		ostreeResolveJobID, err := s.workers.EnqueueOSTreeResolveJob(&worker.OSTreeResolveJob{Specs: workerResolveSpecs}, channel)
+		ostreeResolveJobID, err := s.workers.EnqueueOSTreeResolveJob(&worker.OSTreeResolveJob{Specs: workerResolveSpecs}, channel, opts)
 		if err != nil {
 			return jobDependencies, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
@@ -297,7 +313,72 @@ func (s *Server) enqueueResolveJobs(manifestSource *manifest.Manifest, it distro
 	return jobDependencies, nil
 }
 
-func (s *Server) enqueueCompose(irs []imageRequest, channel string) (uuid.UUID, error) {
+// checkComposeQuota returns an error if the tenant `channel` already has the
+// maximum number of unfinished composes.
+func (s *Server) checkComposeQuota(ctx context.Context, channel string) error {
+	limit, ok := s.config.MaxPendingComposes[channel]
+	if !ok {
+		limit = s.config.DefaultMaxPendingComposes
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	count, err := s.workers.CountUnfinishedComposes(ctx, channel)
+	if err != nil {
+		return HTTPErrorWithInternal(ErrorGettingComposeList, err)
+	}
+	if count >= limit {
+		return HTTPErrorWithDetails(ErrorComposeQuotaExceeded, nil, fmt.Sprintf("%d composes are unfinished, the limit is %d", count, limit))
+	}
+	return nil
+}
+
+// osbuildJobOptions returns opts with the worker labels the osbuild jobs of
+// `imageType` require.
+func (s *Server) osbuildJobOptions(opts jobqueue.EnqueueOptions, imageType string) jobqueue.EnqueueOptions {
+	opts.RequiredLabels = s.config.ImageTypeWorkerLabels[imageType]
+	return opts
+}
+
+// checkS3CredentialsRefs returns an error if a generic S3 upload target of the
+// request refers to worker credentials the tenant `channel` may not use. The
+// check has to happen before the jobs are enqueued, the worker uploads with
+// any credentials it knows.
+func (s *Server) checkS3CredentialsRefs(request ComposeRequest, channel string) error {
+	allowed, ok := s.config.S3CredentialsRefs[channel]
+	if !ok {
+		allowed = s.config.DefaultS3CredentialsRefs
+	}
+
+	var irs []ImageRequest
+	if request.ImageRequest != nil {
+		irs = append(irs, *request.ImageRequest)
+	}
+	if request.ImageRequests != nil {
+		irs = append(irs, *request.ImageRequests...)
+	}
+	for _, ir := range irs {
+		if ir.UploadTargets == nil {
+			continue
+		}
+		for _, ut := range *ir.UploadTargets {
+			if ut.Type != UploadTypesGenericS3 {
+				continue
+			}
+			options, err := ut.UploadOptions.AsGenericS3UploadOptions()
+			if err != nil {
+				return HTTPError(ErrorJSONUnMarshallingError)
+			}
+			if !slices.Contains(allowed, options.CredentialsRef) {
+				return HTTPErrorWithDetails(ErrorS3CredentialsForbidden, nil, fmt.Sprintf("credentials_ref %q", options.CredentialsRef))
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) enqueueCompose(irs []imageRequest, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
 	var id uuid.UUID
 	if len(irs) != 1 {
 		return id, HTTPError(ErrorInvalidNumberOfImageBuilds)
@@ -310,13 +391,13 @@ func (s *Server) enqueueCompose(irs []imageRequest, channel string) (uuid.UUID,
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
 
-	dependencies, err := s.enqueueResolveJobs(manifestSource, ir.imageType, channel)
+	dependencies, err := s.enqueueResolveJobs(manifestSource, ir.imageType, channel, opts)
 	if err != nil {
 		logrus.Warningf("ErrorEnqueueingJob, failed creating resolve jobs: %v", err)
 		return id, err
 	}
 
-	manifestJobID, err := s.workers.EnqueueManifestJobByID(&worker.ManifestJobByID{}, dependencies.IDs(), channel)
+	manifestJobID, err := s.workers.EnqueueManifestJobByID(&worker.ManifestJobByID{}, dependencies.IDs(), channel, opts)
 	if err != nil {
 		logrus.Warningf("ErrorEnqueueingJob, failed creating manifest job (ByID): %v", err)
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -324,6 +405,7 @@ func (s *Server) enqueueCompose(irs []imageRequest, channel string) (uuid.UUID,
 
 	id, err = s.workers.EnqueueOSBuildAsDependency(
 		ir.imageType.Arch().Name(), &worker.OSBuildJob{Targets: ir.targets}, []uuid.UUID{manifestJobID}, channel,
+		s.osbuildJobOptions(opts, ir.imageType.Name()),
 	)
 	if err != nil {
 		logrus.Warningf("ErrorEnqueueingJob, failed creating osbuild job: %v", err)
@@ -342,7 +424,7 @@ func (s *Server) enqueueCompose(irs []imageRequest, channel string) (uuid.UUID,
 	return id, nil
 }
 
-func (s *Server) enqueueComposeIBCLI(irs []imageRequest, channel string) (uuid.UUID, error) {
+func (s *Server) enqueueComposeIBCLI(irs []imageRequest, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
 	logrus.Warnf("using experimental job type: %s", worker.JobTypeImageBuilderManifest)
 	var osbuildJobID uuid.UUID
 	if len(irs) != 1 {
@@ -381,7 +463,7 @@ func (s *Server) enqueueComposeIBCLI(irs []imageRequest, channel string) (uuid.U
 		ExtraEnv: []string{"XDG_CACHE_HOME=/var/cache/osbuild-composer/rpmmd"},
 	}
 
-	manifestJobID, err := s.workers.EnqueueImageBuilderManifestJob(&manifestJob, channel)
+	manifestJobID, err := s.workers.EnqueueImageBuilderManifestJob(&manifestJob, channel, opts)
 	if err != nil {
 		return osbuildJobID, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -389,6 +471,7 @@ func (s *Server) enqueueComposeIBCLI(irs []imageRequest, channel string) (uuid.U
 
 	osbuildJobID, err = s.workers.EnqueueOSBuildAsDependency(
 		arch.Name(), &worker.OSBuildJob{Targets: ir.targets}, []uuid.UUID{manifestJobID}, channel,
+		s.osbuildJobOptions(opts, imageType.Name()),
 	)
 	if err != nil {
 		return osbuildJobID, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -398,7 +481,7 @@ func (s *Server) enqueueComposeIBCLI(irs []imageRequest, channel string) (uuid.U
 	return osbuildJobID, nil
 }
 
-func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, release string, irs []imageRequest, channel string) (uuid.UUID, error) {
+func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, release string, irs []imageRequest, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
 	var id uuid.UUID
 	kojiDirectory := "osbuild-cg/osbuild-composer-koji-" + uuid.New().String()
 
@@ -407,7 +490,7 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 		Name:    name,
 		Version: version,
 		Release: release,
-	}, channel)
+	}, channel, opts)
 	if err != nil {
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -422,13 +505,13 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
 
-		dependencies, err := s.enqueueResolveJobs(manifestSource, ir.imageType, channel)
+		dependencies, err := s.enqueueResolveJobs(manifestSource, ir.imageType, channel, opts)
 		if err != nil {
 			logrus.Warningf("ErrorEnqueueingJob, failed creating resolve jobs: %v", err)
 			return id, err
 		}
 
-		manifestJobID, err := s.workers.EnqueueManifestJobByID(&worker.ManifestJobByID{}, dependencies.IDs(), channel)
+		manifestJobID, err := s.workers.EnqueueManifestJobByID(&worker.ManifestJobByID{}, dependencies.IDs(), channel, opts)
 		if err != nil {
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
@@ -462,7 +545,7 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 			ManifestDynArgsIdx: common.ToPtr(1),
 			DepsolveDynArgsIdx: common.ToPtr(2),
 			ImageBootMode:      ir.imageType.BootMode().String(),
-		}, []uuid.UUID{initID, manifestJobID, dependencies.depsolveJobID}, channel)
+		}, []uuid.UUID{initID, manifestJobID, dependencies.depsolveJobID}, channel, s.osbuildJobOptions(opts, ir.imageType.Name()))
 		if err != nil {
 			return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 		}
@@ -488,7 +571,7 @@ func (s *Server) enqueueKojiCompose(taskID uint64, server, name, version, releas
 		KojiDirectory: kojiDirectory,
 		TaskID:        taskID,
 		StartTime:     uint64(time.Now().Unix()), // nolint: gosec
-	}, initID, buildIDs, channel)
+	}, initID, buildIDs, channel, opts)
 	if err != nil {
 		return id, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -562,7 +645,7 @@ func buildBootcManifestSource(
 	return manifestSource, imgType, nil
 }
 
-func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uuid.UUID, error) {
+func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
 	var ir ImageRequest
 	if request.ImageRequest != nil {
 		ir = *request.ImageRequest
@@ -675,7 +758,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 
 	bootcInfoResolveJobID, err := s.workers.EnqueueBootcInfoResolveJob(ir.Architecture, &worker.BootcInfoResolveJob{
 		Specs: bootcInfoResolveSpecs,
-	}, channel)
+	}, channel, opts)
 	if err != nil {
 		return uuid.Nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -694,7 +777,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 	if request.Bootc.BuildReference != nil {
 		preManifestArgs.BuildInfoIdx = common.ToPtr(1)
 	}
-	preManifestJobID, err := s.workers.EnqueueBootcPreManifestJob(preManifestArgs, preManifestDeps, channel)
+	preManifestJobID, err := s.workers.EnqueueBootcPreManifestJob(preManifestArgs, preManifestDeps, channel, opts)
 	if err != nil {
 		return uuid.Nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -707,6 +790,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 		},
 		[]uuid.UUID{preManifestJobID},
 		channel,
+		opts,
 	)
 	if err != nil {
 		return uuid.Nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -728,6 +812,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 		&worker.ManifestJobByID{},
 		dependencies.IDs(),
 		channel,
+		opts,
 	)
 	if err != nil {
 		return uuid.Nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
@@ -738,7 +823,7 @@ func (s *Server) enqueueBootcCompose(request ComposeRequest, channel string) (uu
 		// Targets are empty — filled by worker from BootcPreManifest dynargs.
 		ManifestDynArgsIdx:    common.ToPtr(0), // dynArgs[0] = ManifestByID result
 		PreManifestDynArgsIdx: common.ToPtr(1), // dynArgs[1] = BootcPreManifest result
-	}, []uuid.UUID{manifestJobID, preManifestJobID}, channel)
+	}, []uuid.UUID{manifestJobID, preManifestJobID}, channel, s.osbuildJobOptions(opts, imageTypeName))
 	if err != nil {
 		return uuid.Nil, HTTPErrorWithInternal(ErrorEnqueueingJob, err)
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/distro/test_distro"
 	"github.com/osbuild/image-builder/pkg/osbuild"
 	v2 "github.com/ondrejbudai/osbuild-composer-public/public/cloudapi/v2"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/target"
 	"github.com/ondrejbudai/osbuild-composer-public/public/test"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
@@ -602,7 +603,7 @@ func TestKojiJobTypeValidation(t *testing.T) {
 		Version: "42",
 		Release: "1",
 	}
-	initID, err := workers.EnqueueKojiInit(&initJob, "")
+	initID, err := workers.EnqueueKojiInit(&initJob, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	manifest, err := json.Marshal(osbuild.Manifest{})
@@ -627,7 +628,7 @@ func TestKojiJobTypeValidation(t *testing.T) {
 			// TODO: use dependent depsolve and manifests jobs instead
 			Manifest: manifest,
 		}
-		buildID, err := workers.EnqueueOSBuildAsDependency(fmt.Sprintf("fake-arch-%d", idx), &buildJob, []uuid.UUID{initID}, "")
+		buildID, err := workers.EnqueueOSBuildAsDependency(fmt.Sprintf("fake-arch-%d", idx), &buildJob, []uuid.UUID{initID}, "", jobqueue.EnqueueOptions{})
 		require.NoError(t, err)
 
 		buildJobs[idx] = buildJob
@@ -645,7 +646,7 @@ func TestKojiJobTypeValidation(t *testing.T) {
 		TaskID:        0,
 		StartTime:     uint64(time.Now().Unix()), // nolint: gosec
 	}
-	finalizeID, err := workers.EnqueueKojiFinalize(&finalizeJob, initID, buildJobIDs, "")
+	finalizeID, err := workers.EnqueueKojiFinalize(&finalizeJob, initID, buildJobIDs, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	// ----- Jobs queued - Test API endpoints (status, manifests, logs) ----- //
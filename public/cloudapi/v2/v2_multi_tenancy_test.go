@@ -310,6 +310,30 @@ func TestMultitenancy(t *testing.T) {
 		c.jobIDs = getAllJobsOfCompose(t, q, id)
 	}
 
+	// the compose list only contains the composes of the tenant
+	for _, orgID := range []string{"42", "123", "bad-org"} {
+		resp := test.APICall{
+			Handler:        handler,
+			Method:         http.MethodGet,
+			Context:        reqContext(orgID),
+			Path:           "/api/image-builder-composer/v2/composes/",
+			ExpectedStatus: http.StatusOK,
+		}.Do(t)
+		var list []v2.ComposeStatus
+		require.NoError(t, json.Unmarshal(resp.Body, &list))
+		var listed []string
+		for _, s := range list {
+			listed = append(listed, s.Id)
+		}
+		var expected []string
+		for _, c := range composes {
+			if c.orgID == orgID {
+				expected = append(expected, c.id.String())
+			}
+		}
+		require.Equal(t, expected, listed)
+	}
+
 	// Run the composes in a LIFO way
 	for i := len(composes) - 1; i >= 0; i -= 1 {
 		c := composes[i]
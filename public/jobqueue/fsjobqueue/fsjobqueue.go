@@ -12,9 +12,11 @@
 package fsjobqueue
 
 import (
+	"bytes"
 	"container/list"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
 	"sync"
@@ -36,7 +38,8 @@ type fsJobQueue struct {
 
 	db *jsondb.JSONDatabase
 
-	// List of pending job
+	// List of pending jobs, ordered by priority (highest first) and by
+	// the time they became pending. Elements are of type pendingJob.
 	pending *list.List
 
 	// Set of goroutines waiting for new pending jobs
@@ -60,12 +63,41 @@ type fsJobQueue struct {
 
 	workerIDByToken map[uuid.UUID]uuid.UUID // token -> workerID
 	workers         map[uuid.UUID]worker
+
+	// Currently running jobs, used for fair-share scheduling and quotas.
+	running map[uuid.UUID]runningJob // job id -> job
+
+	scheduling jobqueue.SchedulingConfig
+}
+
+// Config allows more detailed customization of queue behavior
+type Config struct {
+	// Scheduling configures the order in which ready jobs are dequeued.
+	Scheduling jobqueue.SchedulingConfig
+}
+
+// pendingJob is an element of `fsJobQueue.pending`. The priority, channel,
+// type, and delay are kept alongside the id so that the list can be kept
+// ordered and scheduled without reading every job from the database.
+type pendingJob struct {
+	id        uuid.UUID
+	priority  int
+	channel   string
+	jobType   string
+	notBefore time.Time
+}
+
+type runningJob struct {
+	channel string
+	jobType string
 }
 
 type worker struct {
 	Channel   string    `json:"channel"`
 	Arch      string    `json:"arch"`
+	Labels    []string  `json:"labels,omitempty"`
 	Heartbeat time.Time `json:"heartbeat"`
+	Draining  bool      `json:"draining,omitempty"`
 	Tokens    map[uuid.UUID]struct{}
 }
 
@@ -81,8 +113,12 @@ type job struct {
 	Dependents   []uuid.UUID     `json:"dependents"`
 	Result       json.RawMessage `json:"result,omitempty"`
 	Channel      string          `json:"channel"`
+	Priority     int             `json:"priority,omitempty"`
+
+	RequiredLabels []string `json:"required_labels,omitempty"`
 
 	QueuedAt   time.Time `json:"queued_at,omitempty"`
+	NotBefore  time.Time `json:"not_before,omitempty"`
 	StartedAt  time.Time `json:"started_at,omitempty"`
 	FinishedAt time.Time `json:"finished_at,omitempty"`
 	ExpiresAt  time.Time `json:"expires_at,omitempty"`
@@ -95,6 +131,17 @@ type job struct {
 // access to `dir`. If `dir` contains jobs created from previous runs, they are
 // loaded and rescheduled to run if necessary.
 func New(dir string) (*fsJobQueue, error) {
+	return NewWithConfig(dir, Config{})
+}
+
+// NewWithConfig creates a new fsJobQueue object for `dir` with specific
+// configuration, see New().
+func NewWithConfig(dir string, config Config) (*fsJobQueue, error) {
+	err := config.Scheduling.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid scheduling configuration: %v", err)
+	}
+
 	q := &fsJobQueue{
 		db:              jsondb.New(dir, 0600),
 		pending:         list.New(),
@@ -104,6 +151,8 @@ func New(dir string) (*fsJobQueue, error) {
 		listeners:       make(map[chan struct{}]struct{}),
 		workers:         make(map[uuid.UUID]worker),
 		workerIDByToken: make(map[uuid.UUID]uuid.UUID),
+		running:         make(map[uuid.UUID]runningJob),
+		scheduling:      config.Scheduling,
 	}
 
 	// Look for jobs that are still pending and build the dependant map.
@@ -134,6 +183,7 @@ func New(dir string) (*fsJobQueue, error) {
 			} else {
 				q.jobIdByToken[j.Token] = j.Id
 				q.heartbeats[j.Token] = time.Now()
+				q.running[j.Id] = runningJob{channel: j.Channel, jobType: j.Type}
 			}
 		}
 
@@ -147,6 +197,10 @@ func New(dir string) (*fsJobQueue, error) {
 }
 
 func (q *fsJobQueue) Enqueue(jobType string, args interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+	return q.EnqueueWithOptions(jobType, args, dependencies, channel, jobqueue.EnqueueOptions{})
+}
+
+func (q *fsJobQueue) EnqueueWithOptions(jobType string, args interface{}, dependencies []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -157,6 +211,10 @@ func (q *fsJobQueue) Enqueue(jobType string, args interface{}, dependencies []uu
 		Dependencies: dependencies,
 		QueuedAt:     time.Now(),
 		Channel:      channel,
+		Priority:     opts.Priority,
+		NotBefore:    opts.NotBefore,
+
+		RequiredLabels: opts.RequiredLabels,
 	}
 
 	var err error
@@ -230,17 +288,30 @@ func (q *fsJobQueue) dequeueLoop(ctx context.Context, wID uuid.UUID, matches fun
 			break
 		}
 
+		// Nothing notifies the listeners when a delayed job becomes ready,
+		// wake up on our own.
+		var wakeup <-chan time.Time
+		var timer *time.Timer
+		if delay := q.nextDelayedJob(); delay > 0 {
+			timer = time.NewTimer(delay)
+			wakeup = timer.C
+		}
+
 		// Unlock the mutex while polling channels, so that multiple goroutines
 		// can wait at the same time.
 		q.mu.Unlock()
 		select {
 		case <-c:
+		case <-wakeup:
 		case <-ctx.Done():
 			// there's defer q.mu.Unlock(), so let's lock
 			q.mu.Lock()
 			return uuid.Nil, uuid.Nil, nil, "", nil, jobqueue.ErrDequeueTimeout
 		}
 		q.mu.Lock()
+		if timer != nil {
+			timer.Stop()
+		}
 	}
 
 	j.StartedAt = time.Now()
@@ -248,6 +319,7 @@ func (q *fsJobQueue) dequeueLoop(ctx context.Context, wID uuid.UUID, matches fun
 	j.Token = uuid.New()
 	q.jobIdByToken[j.Token] = j.Id
 	q.heartbeats[j.Token] = time.Now()
+	q.running[j.Id] = runningJob{channel: j.Channel, jobType: j.Type}
 	if _, ok := q.workers[wID]; ok {
 		q.workers[wID].Tokens[j.Token] = struct{}{}
 		q.workerIDByToken[j.Token] = wID
@@ -263,7 +335,10 @@ func (q *fsJobQueue) dequeueLoop(ctx context.Context, wID uuid.UUID, matches fun
 
 func (q *fsJobQueue) Dequeue(ctx context.Context, wID uuid.UUID, jobTypes, channels []string) (uuid.UUID, uuid.UUID, []uuid.UUID, string, json.RawMessage, error) {
 	return q.dequeueLoop(ctx, wID, func(j *job) bool {
-		return jobMatchesCriteria(j, jobTypes, channels)
+		// unknown workers, e.g. uuid.Nil, don't have any labels and
+		// aren't draining
+		w := q.workers[wID]
+		return !w.Draining && jobMatchesCriteria(j, jobTypes, channels) && jobqueue.HasLabels(w.Labels, j.RequiredLabels)
 	})
 }
 
@@ -282,7 +357,7 @@ func (q *fsJobQueue) DequeueByID(ctx context.Context, id, wID uuid.UUID) (uuid.U
 		return uuid.Nil, nil, "", nil, err
 	}
 
-	if !j.StartedAt.IsZero() {
+	if !j.StartedAt.IsZero() || time.Now().Before(j.NotBefore) {
 		return uuid.Nil, nil, "", nil, jobqueue.ErrNotPending
 	}
 
@@ -301,6 +376,7 @@ func (q *fsJobQueue) DequeueByID(ctx context.Context, id, wID uuid.UUID) (uuid.U
 	j.Token = uuid.New()
 	q.jobIdByToken[j.Token] = j.Id
 	q.heartbeats[j.Token] = time.Now()
+	q.running[j.Id] = runningJob{channel: j.Channel, jobType: j.Type}
 	if _, ok := q.workers[wID]; ok {
 		q.workers[wID].Tokens[j.Token] = struct{}{}
 		q.workerIDByToken[j.Token] = wID
@@ -361,12 +437,7 @@ func (q *fsJobQueue) RequeueOrFinishJob(id uuid.UUID, maxRetries uint64, result
 		return false, jobqueue.ErrNotRunning
 	}
 
-	delete(q.jobIdByToken, j.Token)
-	delete(q.heartbeats, j.Token)
-	if wID, ok := q.workerIDByToken[j.Token]; ok {
-		delete(q.workers[wID].Tokens, j.Token)
-		delete(q.workerIDByToken, j.Token)
-	}
+	q.releaseToken(j)
 
 	if j.Retries >= maxRetries {
 		j.FinishedAt = time.Now()
@@ -393,11 +464,18 @@ func (q *fsJobQueue) RequeueOrFinishJob(id uuid.UUID, maxRetries uint64, result
 			}
 		}
 		delete(q.dependants, id)
+
+		// the job might have held back a job of its channel
+		q.notifyListeners()
 		return false, nil
 	} else {
 		j.Token = uuid.Nil
 		j.StartedAt = time.Time{}
 		j.Retries += 1
+		j.NotBefore = time.Time{}
+		if delay := q.scheduling.RetryBackoff.Delay(j.Retries); delay > 0 {
+			j.NotBefore = time.Now().Add(delay)
+		}
 
 		// Write the job before updating in-memory state, so that the latter
 		// doesn't become corrupt when writing fails.
@@ -406,20 +484,55 @@ func (q *fsJobQueue) RequeueOrFinishJob(id uuid.UUID, maxRetries uint64, result
 			return false, fmt.Errorf("cannot write job: %v", err)
 		}
 
-		// add the job to the list of pending ones
-		q.pending.PushBack(j.Id)
-
-		// notify all listeners in a non-blocking way
-		for c := range q.listeners {
-			select {
-			case c <- struct{}{}:
-			default:
-			}
-		}
+		q.pushPendingJob(j)
 		return true, nil
 	}
 }
 
+func (q *fsJobQueue) RequeueJob(id uuid.UUID) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	j, err := q.readJob(id)
+	if err != nil {
+		return err
+	}
+
+	if j.Canceled {
+		return jobqueue.ErrCanceled
+	}
+
+	if j.StartedAt.IsZero() || !j.FinishedAt.IsZero() {
+		return jobqueue.ErrNotRunning
+	}
+
+	q.releaseToken(j)
+
+	j.Token = uuid.Nil
+	j.StartedAt = time.Time{}
+	j.NotBefore = time.Time{}
+
+	err = q.db.Write(j.Id.String(), j)
+	if err != nil {
+		return fmt.Errorf("cannot write job: %v", err)
+	}
+
+	q.pushPendingJob(j)
+	return nil
+}
+
+// releaseToken forgets the token of a running job, which stops or
+// requeues it. `q.mu` must be locked when this method is called.
+func (q *fsJobQueue) releaseToken(j *job) {
+	delete(q.jobIdByToken, j.Token)
+	delete(q.heartbeats, j.Token)
+	delete(q.running, j.Id)
+	if wID, ok := q.workerIDByToken[j.Token]; ok {
+		delete(q.workers[wID].Tokens, j.Token)
+		delete(q.workerIDByToken, j.Token)
+	}
+}
+
 func (q *fsJobQueue) CancelJob(id uuid.UUID) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
@@ -433,20 +546,85 @@ func (q *fsJobQueue) CancelJob(id uuid.UUID) error {
 		return jobqueue.ErrNotRunning
 	}
 
+	err = q.cancelJob(j)
+	if err != nil {
+		return err
+	}
+
+	// a canceled running job might have held back a job of its channel
+	q.notifyListeners()
+
+	return nil
+}
+
+func (q *fsJobQueue) CancelJobTree(id uuid.UUID) ([]uuid.UUID, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	root, err := q.readJob(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// read the whole tree first, so that nothing is canceled if a job
+	// can't be read
+	tree := []*job{root}
+	seen := map[uuid.UUID]bool{id: true}
+	for i := 0; i < len(tree); i++ {
+		for _, next := range slices.Concat(tree[i].Dependencies, tree[i].Dependents) {
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+
+			j, err := q.readJob(next)
+			if errors.Is(err, jobqueue.ErrNotExist) {
+				// the job might have been deleted already, see DeleteJob
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			tree = append(tree, j)
+		}
+	}
+
+	canceled := []uuid.UUID{}
+	for _, j := range tree {
+		if j.Canceled || !j.FinishedAt.IsZero() {
+			continue
+		}
+		err = q.cancelJob(j)
+		if err != nil {
+			return canceled, err
+		}
+		canceled = append(canceled, j.Id)
+	}
+
+	if len(canceled) > 0 {
+		q.notifyListeners()
+	}
+
+	return canceled, nil
+}
+
+// cancelJob marks an unfinished job as canceled. It must be called with
+// q.mu held.
+func (q *fsJobQueue) cancelJob(j *job) error {
 	// if the cancelled job is pending, remove it from the list
 	if j.StartedAt.IsZero() {
-		q.removePendingJob(id)
+		q.removePendingJob(j.Id)
 	}
 
 	j.Canceled = true
 
 	delete(q.heartbeats, j.Token)
+	delete(q.running, j.Id)
 
-	err = q.db.Write(id.String(), j)
+	err := q.db.Write(j.Id.String(), j)
 	if err != nil {
-		return fmt.Errorf("error writing job %s: %v", id, err)
+		return fmt.Errorf("error writing job %s: %v", j.Id, err)
 	}
-
 	return nil
 }
 
@@ -549,7 +727,7 @@ func (q *fsJobQueue) RefreshHeartbeat(token uuid.UUID) {
 	}
 }
 
-func (q *fsJobQueue) InsertWorker(channel, arch string) (uuid.UUID, error) {
+func (q *fsJobQueue) InsertWorker(channel, arch string, labels []string) (uuid.UUID, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
@@ -557,22 +735,38 @@ func (q *fsJobQueue) InsertWorker(channel, arch string) (uuid.UUID, error) {
 	q.workers[wID] = worker{
 		Channel:   channel,
 		Arch:      arch,
+		Labels:    labels,
 		Heartbeat: time.Now(),
 		Tokens:    make(map[uuid.UUID]struct{}),
 	}
 	return wID, nil
 }
 
-func (q *fsJobQueue) UpdateWorkerStatus(wID uuid.UUID) error {
+func (q *fsJobQueue) UpdateWorkerStatus(wID uuid.UUID) (bool, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
 	worker, ok := q.workers[wID]
 	if !ok {
-		return jobqueue.ErrWorkerNotExist
+		return false, jobqueue.ErrWorkerNotExist
 	}
 
 	worker.Heartbeat = time.Now()
+	q.workers[wID] = worker
+	return worker.Draining, nil
+}
+
+func (q *fsJobQueue) SetWorkerDraining(wID uuid.UUID) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	worker, ok := q.workers[wID]
+	if !ok {
+		return jobqueue.ErrWorkerNotExist
+	}
+
+	worker.Draining = true
+	q.workers[wID] = worker
 	return nil
 }
 
@@ -585,9 +779,11 @@ func (q *fsJobQueue) Workers(olderThan time.Duration) ([]jobqueue.Worker, error)
 	for wID, w := range q.workers {
 		if now.Sub(w.Heartbeat) > olderThan {
 			workers = append(workers, jobqueue.Worker{
-				ID:      wID,
-				Channel: w.Channel,
-				Arch:    w.Arch,
+				ID:       wID,
+				Channel:  w.Channel,
+				Arch:     w.Arch,
+				Labels:   w.Labels,
+				Draining: w.Draining,
 			})
 		}
 	}
@@ -641,16 +837,7 @@ func (q *fsJobQueue) maybeEnqueue(j *job, updateDependants bool) error {
 	}
 
 	if depsFinished {
-		// add the job to the list of pending ones
-		q.pending.PushBack(j.Id)
-
-		// notify all listeners in a non-blocking way
-		for c := range q.listeners {
-			select {
-			case c <- struct{}{}:
-			default:
-			}
-		}
+		q.pushPendingJob(j)
 	} else if updateDependants {
 		for _, id := range j.Dependencies {
 			q.dependants[id] = append(q.dependants[id], j.Id)
@@ -660,6 +847,37 @@ func (q *fsJobQueue) maybeEnqueue(j *job, updateDependants bool) error {
 	return nil
 }
 
+// pushPendingJob adds `j` to the list of pending jobs, behind all jobs with
+// the same or a higher priority, and notifies all listeners.
+// `q.mu` must be locked when this method is called.
+func (q *fsJobQueue) pushPendingJob(j *job) {
+	pj := pendingJob{id: j.Id, priority: j.Priority, channel: j.Channel, jobType: j.Type, notBefore: j.NotBefore}
+
+	el := q.pending.Back()
+	for el != nil && el.Value.(pendingJob).priority < pj.priority {
+		el = el.Prev()
+	}
+	if el == nil {
+		q.pending.PushFront(pj)
+	} else {
+		q.pending.InsertAfter(pj, el)
+	}
+
+	q.notifyListeners()
+}
+
+// notifyListeners wakes up all goroutines waiting for a pending job in a
+// non-blocking way.
+// `q.mu` must be locked when this method is called.
+func (q *fsJobQueue) notifyListeners() {
+	for c := range q.listeners {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
 // hasAllFinishedDependencies returns true if all dependencies of `j`
 // are finished. Otherwise, false is returned. If one of the jobs cannot
 // be read, an error is returned.
@@ -684,11 +902,21 @@ func (q *fsJobQueue) hasAllFinishedDependencies(j *job) (bool, error) {
 // If a suitable job is not found, false is returned.
 // If an error occurs during the search, it's returned.
 func (q *fsJobQueue) dequeueSuitableJob(matches func(*job) bool) (*job, bool, error) {
+	if q.scheduling.FairShare() {
+		return q.dequeueFairShareJob(matches)
+	}
+
+	now := time.Now()
+	quotaRunning := q.runningQuotaJobs()
 	el := q.pending.Front()
 	for el != nil {
-		id := el.Value.(uuid.UUID)
+		pj := el.Value.(pendingJob)
+		if now.Before(pj.notBefore) || q.overQuota(pj, quotaRunning) {
+			el = el.Next()
+			continue
+		}
 
-		j, err := q.readJob(id)
+		j, err := q.readJob(pj.id)
 		if err != nil {
 			return nil, false, err
 		}
@@ -712,13 +940,102 @@ func (q *fsJobQueue) dequeueSuitableJob(matches func(*job) bool) (*job, bool, er
 	return nil, false, nil
 }
 
+// dequeueFairShareJob is like dequeueSuitableJob, but it picks a job from
+// the channel with the lowest load, i.e., the number of running jobs divided
+// by the channel's weight. Ties are broken by the order of the pending list.
+func (q *fsJobQueue) dequeueFairShareJob(matches func(*job) bool) (*job, bool, error) {
+	running := make(map[string]int)
+	for _, r := range q.running {
+		running[r.channel] += 1
+	}
+	quotaRunning := q.runningQuotaJobs()
+	now := time.Now()
+
+	var best *job
+	var bestEl *list.Element
+	var bestLoad float64
+	for el := q.pending.Front(); el != nil; el = el.Next() {
+		pj := el.Value.(pendingJob)
+		load := float64(running[pj.channel]) / q.scheduling.ChannelWeight(pj.channel)
+		if best != nil && load >= bestLoad {
+			continue
+		}
+		if now.Before(pj.notBefore) || q.overQuota(pj, quotaRunning) {
+			continue
+		}
+
+		j, err := q.readJob(pj.id)
+		if err != nil {
+			return nil, false, err
+		}
+		if !matches(j) {
+			continue
+		}
+
+		ready, err := q.hasAllFinishedDependencies(j)
+		if err != nil {
+			return nil, false, err
+		}
+		if ready {
+			best, bestEl, bestLoad = j, el, load
+		}
+	}
+
+	if best == nil {
+		return nil, false, nil
+	}
+	q.pending.Remove(bestEl)
+	return best, true, nil
+}
+
+// nextDelayedJob returns the time until the next delayed pending job becomes
+// ready, or 0 if there are no delayed jobs.
+// `q.mu` must be locked when this method is called.
+func (q *fsJobQueue) nextDelayedJob() time.Duration {
+	var next time.Duration
+	now := time.Now()
+	for el := q.pending.Front(); el != nil; el = el.Next() {
+		notBefore := el.Value.(pendingJob).notBefore
+		if !now.Before(notBefore) {
+			continue
+		}
+		if delay := notBefore.Sub(now); next == 0 || delay < next {
+			next = delay
+		}
+	}
+	return next
+}
+
+// runningQuotaJobs returns the number of running jobs which are subject to
+// quotas per channel.
+// `q.mu` must be locked when this method is called.
+func (q *fsJobQueue) runningQuotaJobs() map[string]int {
+	running := make(map[string]int)
+	for _, r := range q.running {
+		if q.scheduling.Quotas.AppliesTo(r.jobType) {
+			running[r.channel] += 1
+		}
+	}
+	return running
+}
+
+// overQuota returns true if dequeuing `pj` would exceed the quota of its
+// channel. `running` is the result of runningQuotaJobs().
+func (q *fsJobQueue) overQuota(pj pendingJob, running map[string]int) bool {
+	if !q.scheduling.Quotas.AppliesTo(pj.jobType) {
+		return false
+	}
+	limit := q.scheduling.Quotas.MaxRunning(pj.channel)
+	return limit > 0 && running[pj.channel] >= limit
+}
+
 // removePendingJob removes a job with given ID from the list of pending jobs
 //
 // If the job isn't in the list, this is no-op.
 func (q *fsJobQueue) removePendingJob(id uuid.UUID) {
 	el := q.pending.Front()
 	for el != nil {
-		if el.Value.(uuid.UUID) == id {
+		if el.Value.(pendingJob).id == id {
 			q.pending.Remove(el)
 			return
 		}
@@ -768,6 +1085,131 @@ func (q *fsJobQueue) AllRootJobIDs(_ context.Context) ([]uuid.UUID, error) {
 	return jobIDs, nil
 }
 
+func (q *fsJobQueue) CountJobs(_ context.Context, filter jobqueue.JobFilter) (int, int, error) {
+	ids, err := q.db.List()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var pending, running int
+	for _, id := range ids {
+		var j job
+		exists, err := q.db.Read(id, &j)
+		if err != nil {
+			return 0, 0, err
+		}
+		if !exists || j.Channel != filter.Channel || !j.FinishedAt.IsZero() || j.Canceled {
+			continue
+		}
+		if len(filter.JobTypes) > 0 && !slices.Contains(filter.JobTypes, jobqueue.BaseJobType(j.Type)) {
+			continue
+		}
+		if filter.RootOnly && len(j.Dependents) > 0 {
+			continue
+		}
+
+		if j.StartedAt.IsZero() {
+			pending += 1
+		} else {
+			running += 1
+		}
+	}
+
+	return pending, running, nil
+}
+
+func (q *fsJobQueue) ListJobs(_ context.Context, query jobqueue.JobQuery) ([]uuid.UUID, *jobqueue.JobCursor, error) {
+	ids, err := q.db.List()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var jobs []*job
+	for _, id := range ids {
+		var j job
+		exists, err := q.db.Read(id, &j)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists || !jobMatchesQuery(&j, query) {
+			continue
+		}
+		jobs = append(jobs, &j)
+	}
+
+	slices.SortFunc(jobs, func(a, b *job) int {
+		return -compareJobPosition(a.QueuedAt, a.Id, b.QueuedAt, b.Id)
+	})
+
+	var next *jobqueue.JobCursor
+	if query.Limit > 0 && len(jobs) > query.Limit {
+		jobs = jobs[:query.Limit]
+		last := jobs[len(jobs)-1]
+		next = &jobqueue.JobCursor{QueuedAt: last.QueuedAt, ID: last.Id}
+	}
+
+	jobIDs := make([]uuid.UUID, 0, len(jobs))
+	for _, j := range jobs {
+		jobIDs = append(jobIDs, j.Id)
+	}
+	return jobIDs, next, nil
+}
+
+// jobMatchesQuery returns true if the job is selected by `query`
+func jobMatchesQuery(j *job, query jobqueue.JobQuery) bool {
+	if j.Channel != query.Channel {
+		return false
+	}
+	if len(query.JobTypes) > 0 && !slices.Contains(query.JobTypes, jobqueue.BaseJobType(j.Type)) {
+		return false
+	}
+	if query.RootOnly && len(j.Dependents) > 0 {
+		return false
+	}
+	if len(query.States) > 0 && !slices.Contains(query.States, j.record().State()) {
+		return false
+	}
+	if !query.QueuedAfter.IsZero() && !j.QueuedAt.After(query.QueuedAfter) {
+		return false
+	}
+	if !query.QueuedBefore.IsZero() && !j.QueuedAt.Before(query.QueuedBefore) {
+		return false
+	}
+	if query.After != nil && compareJobPosition(j.QueuedAt, j.Id, query.After.QueuedAt, query.After.ID) >= 0 {
+		return false
+	}
+	return true
+}
+
+// compareJobPosition orders jobs by the time they were queued and their id
+func compareJobPosition(aQueued time.Time, aID uuid.UUID, bQueued time.Time, bID uuid.UUID) int {
+	if c := aQueued.Compare(bQueued); c != 0 {
+		return c
+	}
+	return bytes.Compare(aID[:], bID[:])
+}
+
+func (j *job) record() jobqueue.JobRecord {
+	return jobqueue.JobRecord{
+		ID:           j.Id,
+		Token:        j.Token,
+		Type:         j.Type,
+		Channel:      j.Channel,
+		Args:         j.Args,
+		Dependencies: j.Dependencies,
+		Result:       j.Result,
+		Priority:     j.Priority,
+		Retries:      j.Retries,
+		Canceled:     j.Canceled,
+		QueuedAt:     j.QueuedAt,
+		NotBefore:    j.NotBefore,
+		StartedAt:    j.StartedAt,
+		FinishedAt:   j.FinishedAt,
+
+		RequiredLabels: j.RequiredLabels,
+	}
+}
+
 // DeleteJob will delete a job and all of its dependencies
 // If a dependency has multiple depenents it will only delete the parent job from
 // the dependants list and then re-save the job instead of removing it.
@@ -812,3 +1254,116 @@ func (q *fsJobQueue) deleteJob(parent, id uuid.UUID) error {
 
 	return q.db.Delete(id.String())
 }
+
+// ExportJobs implements jobqueue.JobExporter.
+func (q *fsJobQueue) ExportJobs(ctx context.Context, fn func(jobqueue.JobRecord) error) error {
+	// read everything before calling fn, which might use the queue, too
+	records, err := q.exportJobs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		err = fn(r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *fsJobQueue) exportJobs(ctx context.Context) ([]jobqueue.JobRecord, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ids, err := q.db.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing jobs: %v", err)
+	}
+
+	records := make([]jobqueue.JobRecord, 0, len(ids))
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		jobId, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid job '%s' in db: %v", id, err)
+		}
+		j, err := q.readJob(jobId)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, j.record())
+	}
+
+	return records, nil
+}
+
+// ImportJob implements jobqueue.JobImporter.
+func (q *fsJobQueue) ImportJob(_ context.Context, r jobqueue.JobRecord) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var existing job
+	exists, err := q.db.Read(r.ID.String(), &existing)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("job %s already exists", r.ID)
+	}
+
+	j := job{
+		Id:           r.ID,
+		Token:        r.Token,
+		Type:         r.Type,
+		Args:         r.Args,
+		Dependencies: r.Dependencies,
+		Result:       r.Result,
+		Channel:      r.Channel,
+		Priority:     r.Priority,
+		QueuedAt:     r.QueuedAt,
+		NotBefore:    r.NotBefore,
+		StartedAt:    r.StartedAt,
+		FinishedAt:   r.FinishedAt,
+		Retries:      r.Retries,
+		Canceled:     r.Canceled,
+
+		RequiredLabels: r.RequiredLabels,
+	}
+
+	for _, d := range j.Dependencies {
+		var dep job
+		exists, err := q.db.Read(d.String(), &dep)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("dependency %s of job %s: %w", d, j.Id, jobqueue.ErrNotExist)
+		}
+
+		dep.Dependents = append(dep.Dependents, j.Id)
+		err = q.db.Write(d.String(), dep)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = q.db.Write(j.Id.String(), j)
+	if err != nil {
+		return fmt.Errorf("cannot write job: %v", err)
+	}
+
+	if j.Canceled || !j.FinishedAt.IsZero() {
+		return nil
+	}
+	if !j.StartedAt.IsZero() {
+		q.jobIdByToken[j.Token] = j.Id
+		q.heartbeats[j.Token] = time.Now()
+		q.running[j.Id] = runningJob{channel: j.Channel, jobType: j.Type}
+		return nil
+	}
+	return q.maybeEnqueue(&j, true)
+}
@@ -29,6 +29,36 @@ func TestJobQueueInterface(t *testing.T) {
 	})
 }
 
+func TestScheduling(t *testing.T) {
+	jobqueuetest.TestScheduling(t, func(config jobqueue.SchedulingConfig) (jobqueue.JobQueue, func(), error) {
+		dir := t.TempDir()
+		q, err := fsjobqueue.NewWithConfig(dir, fsjobqueue.Config{Scheduling: config})
+		if err != nil {
+			return nil, nil, err
+		}
+		stop := func() {
+		}
+		return q, stop, nil
+	})
+}
+
+func TestInvalidSchedulingConfig(t *testing.T) {
+	q, err := fsjobqueue.NewWithConfig(t.TempDir(), fsjobqueue.Config{
+		Scheduling: jobqueue.SchedulingConfig{Policy: "lottery"},
+	})
+	require.Error(t, err)
+	require.Nil(t, q)
+
+	q, err = fsjobqueue.NewWithConfig(t.TempDir(), fsjobqueue.Config{
+		Scheduling: jobqueue.SchedulingConfig{
+			Policy:         jobqueue.SchedulingFairShare,
+			ChannelWeights: map[string]float64{"org-A": 0},
+		},
+	})
+	require.Error(t, err)
+	require.Nil(t, q)
+}
+
 func TestNonExistant(t *testing.T) {
 	q, err := fsjobqueue.New("/non-existant-directory")
 	require.Error(t, err)
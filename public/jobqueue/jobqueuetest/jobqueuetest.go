@@ -49,6 +49,7 @@ func TestJobQueue(t *testing.T, makeJobQueue MakeJobQueue) {
 	t.Run("errors", wrap(testErrors))
 	t.Run("args", wrap(testArgs))
 	t.Run("cancel", wrap(testCancel))
+	t.Run("cancel-job-tree", wrap(testCancelJobTree))
 	t.Run("dequeue-nil-and-empty-channels", wrap(testDequeueNilAndEmptyChannels))
 	t.Run("requeue", wrap(testRequeue))
 	t.Run("requeue-limit", wrap(testRequeueLimit))
@@ -65,9 +66,71 @@ func TestJobQueue(t *testing.T, makeJobQueue MakeJobQueue) {
 	t.Run("dequeue-any-channel", wrap(testDequeueAnyChannel))
 	t.Run("100-dequeuers", wrap(test100dequeuers))
 	t.Run("workers", wrap(testWorkers))
+	t.Run("worker-labels", wrap(testWorkerLabels))
+	t.Run("worker-draining", wrap(testWorkerDraining))
+	t.Run("requeue-job", wrap(testRequeueJob))
 	t.Run("fail", wrap(testFail))
 	t.Run("all-root-jobs", wrap(testAllRootJobs))
 	t.Run("delete-jobs", wrap(testDeleteJobs))
+	t.Run("priorities", wrap(testPriorities))
+	t.Run("count-jobs", wrap(testCountJobs))
+	t.Run("list-jobs", wrap(testListJobs))
+	t.Run("delayed", wrap(testDelayed))
+	t.Run("export-import", wrap(testExportImport))
+}
+
+// MakeJobQueueWithScheduling creates a job queue with the given scheduling
+// configuration.
+type MakeJobQueueWithScheduling func(config jobqueue.SchedulingConfig) (q jobqueue.JobQueue, stop func(), err error)
+
+// TestScheduling verifies that a queue configured with
+// jobqueue.SchedulingFairShare balances ready jobs across channels, and that
+// channel quotas hold back jobs.
+func TestScheduling(t *testing.T, makeJobQueue MakeJobQueueWithScheduling) {
+	wrap := func(config jobqueue.SchedulingConfig, f func(t *testing.T, q jobqueue.JobQueue)) func(*testing.T) {
+		q, stop, err := makeJobQueue(config)
+		require.NoError(t, err)
+		return func(t *testing.T) {
+			defer stop() // use defer because f() might call testing.T.FailNow()
+			f(t, q)
+		}
+	}
+
+	fairShare := jobqueue.SchedulingConfig{
+		Policy: jobqueue.SchedulingFairShare,
+	}
+	weighted := jobqueue.SchedulingConfig{
+		Policy:         jobqueue.SchedulingFairShare,
+		ChannelWeights: map[string]float64{"org-A": 2},
+	}
+	quotas := jobqueue.QuotaConfig{
+		JobTypes:              []string{"octopus"},
+		DefaultMaxRunningJobs: 1,
+		MaxRunningJobs:        map[string]int{"org-B": 2, "org-C": 0},
+	}
+	fifoQuotas := jobqueue.SchedulingConfig{
+		Quotas: quotas,
+	}
+	fairShareQuotas := jobqueue.SchedulingConfig{
+		Policy: jobqueue.SchedulingFairShare,
+		Quotas: quotas,
+	}
+	backoff := jobqueue.SchedulingConfig{
+		RetryBackoff: jobqueue.RetryBackoff{
+			Initial: 500 * time.Millisecond,
+			Max:     time.Second,
+		},
+	}
+
+	t.Run("round-robin", wrap(fairShare, testFairShareRoundRobin))
+	t.Run("weights", wrap(weighted, testFairShareWeights))
+	t.Run("finished-jobs", wrap(fairShare, testFairShareFinishedJobs))
+	t.Run("priority-within-channel", wrap(fairShare, testFairSharePriority))
+	t.Run("any-channel", wrap(fairShare, testFairShareAnyChannel))
+	t.Run("quotas", wrap(fifoQuotas, testQuotas))
+	t.Run("quotas-fair-share", wrap(fairShareQuotas, testQuotas))
+	t.Run("quotas-release", wrap(fifoQuotas, testQuotasRelease))
+	t.Run("retry-backoff", wrap(backoff, testRetryBackoff))
 }
 
 func pushTestJob(t *testing.T, q jobqueue.JobQueue, jobType string, args interface{}, dependencies []uuid.UUID, channel string) uuid.UUID {
@@ -78,6 +141,14 @@ func pushTestJob(t *testing.T, q jobqueue.JobQueue, jobType string, args interfa
 	return id
 }
 
+func pushTestJobWithPriority(t *testing.T, q jobqueue.JobQueue, jobType string, dependencies []uuid.UUID, channel string, priority int) uuid.UUID {
+	t.Helper()
+	id, err := q.EnqueueWithOptions(jobType, nil, dependencies, channel, jobqueue.EnqueueOptions{Priority: priority})
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	return id
+}
+
 func finishNextTestJob(t *testing.T, q jobqueue.JobQueue, jobType string, result interface{}, deps []uuid.UUID) uuid.UUID {
 	id, tok, d, typ, args, err := q.Dequeue(context.Background(), uuid.Nil, []string{jobType}, []string{""})
 	require.NoError(t, err)
@@ -439,6 +510,45 @@ func testCancel(t *testing.T, q jobqueue.JobQueue) {
 	require.NoError(t, err)
 }
 
+func testCancelJobTree(t *testing.T, q jobqueue.JobQueue) {
+	// finished <- running <- pending <- dependent
+	//             other   <-/
+	finished := pushTestJob(t, q, "octopus", nil, nil, "")
+	require.Equal(t, finished, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+	running := pushTestJob(t, q, "clownfish", nil, []uuid.UUID{finished}, "")
+	require.Equal(t, []uuid.UUID{running}, dequeueTestJobs(t, q, []string{"clownfish"}, []string{""}, 1))
+	other := pushTestJob(t, q, "sailfish", nil, nil, "")
+	pending := pushTestJob(t, q, "kraken", nil, []uuid.UUID{running, other}, "")
+	dependent := pushTestJob(t, q, "kraken", nil, []uuid.UUID{pending}, "")
+	unrelated := pushTestJob(t, q, "octopus", nil, nil, "")
+
+	canceled, err := q.CancelJobTree(pending)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{running, other, pending, dependent}, canceled)
+
+	for _, id := range []uuid.UUID{running, other, pending, dependent} {
+		_, _, _, _, _, _, c, _, _, err := q.JobStatus(id)
+		require.NoError(t, err)
+		require.True(t, c)
+	}
+	_, _, _, _, _, finishedAt, c, _, _, err := q.JobStatus(finished)
+	require.NoError(t, err)
+	require.False(t, c)
+	require.False(t, finishedAt.IsZero())
+
+	// canceled jobs are not dequeued anymore, unrelated ones are
+	requireDequeueTimeout(t, q, []string{"sailfish"}, []string{""})
+	require.Equal(t, unrelated, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+
+	// nothing is left to cancel
+	canceled, err = q.CancelJobTree(finished)
+	require.NoError(t, err)
+	require.Empty(t, canceled)
+
+	_, err = q.CancelJobTree(uuid.New())
+	require.ErrorIs(t, err, jobqueue.ErrNotExist)
+}
+
 func testRequeue(t *testing.T, q jobqueue.JobQueue) {
 	// Requeue a non-existing job
 	_, err := q.RequeueOrFinishJob(uuid.New(), 1, nil)
@@ -877,9 +987,9 @@ func test100dequeuers(t *testing.T, q jobqueue.JobQueue) {
 func testWorkers(t *testing.T, q jobqueue.JobQueue) {
 	one := pushTestJob(t, q, "octopus", nil, nil, "chan")
 
-	w1, err := q.InsertWorker("chan", "x86_64")
+	w1, err := q.InsertWorker("chan", "x86_64", nil)
 	require.NoError(t, err)
-	w2, err := q.InsertWorker("chan", "aarch64")
+	w2, err := q.InsertWorker("chan", "aarch64", nil)
 	require.NoError(t, err)
 
 	workers, err := q.Workers(0)
@@ -897,10 +1007,11 @@ func testWorkers(t *testing.T, q jobqueue.JobQueue) {
 	err = q.DeleteWorker(w1)
 	require.Equal(t, err, jobqueue.ErrActiveJobs)
 
-	err = q.UpdateWorkerStatus(w1)
+	draining, err := q.UpdateWorkerStatus(w1)
 	require.NoError(t, err)
+	require.False(t, draining)
 
-	err = q.UpdateWorkerStatus(uuid.New())
+	_, err = q.UpdateWorkerStatus(uuid.New())
 	require.Equal(t, err, jobqueue.ErrWorkerNotExist)
 
 	requeued, err := q.RequeueOrFinishJob(one, 0, &TestResult{})
@@ -914,6 +1025,143 @@ func testWorkers(t *testing.T, q jobqueue.JobQueue) {
 	require.NoError(t, err)
 }
 
+// Jobs which require labels are only handed to workers which registered all
+// of them
+func testWorkerLabels(t *testing.T, q jobqueue.JobQueue) {
+	plainWorker, err := q.InsertWorker("chan", "x86_64", nil)
+	require.NoError(t, err)
+	kvmWorker, err := q.InsertWorker("chan", "x86_64", []string{"kvm", "large-disk"})
+	require.NoError(t, err)
+
+	workers, err := q.Workers(0)
+	require.NoError(t, err)
+	labels := make(map[uuid.UUID][]string)
+	for _, w := range workers {
+		labels[w.ID] = w.Labels
+	}
+	require.Empty(t, labels[plainWorker])
+	require.ElementsMatch(t, []string{"kvm", "large-disk"}, labels[kvmWorker])
+
+	kvm, err := q.EnqueueWithOptions("octopus", nil, nil, "chan", jobqueue.EnqueueOptions{RequiredLabels: []string{"kvm"}})
+	require.NoError(t, err)
+	gpu, err := q.EnqueueWithOptions("octopus", nil, nil, "chan", jobqueue.EnqueueOptions{RequiredLabels: []string{"kvm", "gpu"}})
+	require.NoError(t, err)
+	plain := pushTestJob(t, q, "octopus", nil, nil, "chan")
+
+	// the plain worker skips the jobs it doesn't have the labels for
+	id, _, _, _, _, err := q.Dequeue(context.Background(), plainWorker, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, plain, id)
+	requireDequeueTimeout(t, q, []string{"octopus"}, []string{"chan"})
+
+	id, _, _, _, _, err = q.Dequeue(context.Background(), kvmWorker, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, kvm, id)
+
+	// no worker has all labels of the last job
+	for _, wID := range []uuid.UUID{plainWorker, kvmWorker} {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		_, _, _, _, _, err = q.Dequeue(ctx, wID, []string{"octopus"}, []string{"chan"})
+		cancel()
+		require.ErrorIs(t, err, jobqueue.ErrDequeueTimeout)
+	}
+
+	require.NoError(t, q.CancelJob(gpu))
+	for _, id := range []uuid.UUID{plain, kvm} {
+		_, err = q.RequeueOrFinishJob(id, 0, &TestResult{})
+		require.NoError(t, err)
+	}
+	require.NoError(t, q.DeleteWorker(plainWorker))
+	require.NoError(t, q.DeleteWorker(kvmWorker))
+}
+
+// Draining workers don't get any new jobs
+func testWorkerDraining(t *testing.T, q jobqueue.JobQueue) {
+	draining, err := q.InsertWorker("chan", "x86_64", nil)
+	require.NoError(t, err)
+	other, err := q.InsertWorker("chan", "x86_64", nil)
+	require.NoError(t, err)
+
+	id := pushTestJob(t, q, "octopus", nil, nil, "chan")
+
+	require.NoError(t, q.SetWorkerDraining(draining))
+	require.Equal(t, jobqueue.ErrWorkerNotExist, q.SetWorkerDraining(uuid.New()))
+
+	isDraining, err := q.UpdateWorkerStatus(draining)
+	require.NoError(t, err)
+	require.True(t, isDraining)
+	isDraining, err = q.UpdateWorkerStatus(other)
+	require.NoError(t, err)
+	require.False(t, isDraining)
+
+	workers, err := q.Workers(0)
+	require.NoError(t, err)
+	for _, w := range workers {
+		require.Equal(t, w.ID == draining, w.Draining)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _, _, _, _, err = q.Dequeue(ctx, draining, []string{"octopus"}, []string{"chan"})
+	require.ErrorIs(t, err, jobqueue.ErrDequeueTimeout)
+
+	dequeued, _, _, _, _, err := q.Dequeue(context.Background(), other, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, id, dequeued)
+
+	_, err = q.RequeueOrFinishJob(id, 0, &TestResult{})
+	require.NoError(t, err)
+	require.NoError(t, q.DeleteWorker(draining))
+	require.NoError(t, q.DeleteWorker(other))
+}
+
+// A job handed back with RequeueJob() is pending again right away and the
+// handoff doesn't count as a retry
+func testRequeueJob(t *testing.T, q jobqueue.JobQueue) {
+	w, err := q.InsertWorker("chan", "x86_64", nil)
+	require.NoError(t, err)
+
+	id := pushTestJob(t, q, "octopus", nil, nil, "chan")
+
+	require.Equal(t, jobqueue.ErrNotRunning, q.RequeueJob(id))
+
+	dequeued, token, _, _, _, err := q.Dequeue(context.Background(), w, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, id, dequeued)
+
+	require.NoError(t, q.RequeueJob(id))
+	require.Equal(t, jobqueue.ErrNotRunning, q.RequeueJob(id))
+
+	// the worker doesn't hold the job anymore
+	_, err = q.IdFromToken(token)
+	require.Equal(t, jobqueue.ErrNotExist, err)
+	require.NoError(t, q.DeleteWorker(w))
+
+	_, _, _, _, started, _, _, _, _, err := q.JobStatus(id)
+	require.NoError(t, err)
+	require.True(t, started.IsZero())
+
+	// one retry is still left
+	dequeued, _, _, _, _, err = q.Dequeue(context.Background(), uuid.Nil, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.Equal(t, id, dequeued)
+	requeued, err := q.RequeueOrFinishJob(id, 1, &TestResult{})
+	require.NoError(t, err)
+	require.True(t, requeued)
+
+	_, _, _, _, _, err = q.Dequeue(context.Background(), uuid.Nil, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	requeued, err = q.RequeueOrFinishJob(id, 1, &TestResult{})
+	require.NoError(t, err)
+	require.False(t, requeued)
+
+	canceled := pushTestJob(t, q, "octopus", nil, nil, "chan")
+	_, _, _, _, _, err = q.Dequeue(context.Background(), uuid.Nil, []string{"octopus"}, []string{"chan"})
+	require.NoError(t, err)
+	require.NoError(t, q.CancelJob(canceled))
+	require.Equal(t, jobqueue.ErrCanceled, q.RequeueJob(canceled))
+}
+
 func testFail(t *testing.T, q jobqueue.JobQueue) {
 	startTime := time.Now()
 
@@ -1100,3 +1348,462 @@ func testDequeueNilAndEmptyChannels(t *testing.T, q jobqueue.JobQueue) {
 		require.Equal(t, jobqueue.ErrDequeueTimeout, err)
 	})
 }
+
+func testPriorities(t *testing.T, q jobqueue.JobQueue) {
+	t.Run("higher priority first, fifo within a priority", func(t *testing.T) {
+		low := pushTestJobWithPriority(t, q, "octopus", nil, "", -10)
+		mid := pushTestJob(t, q, "octopus", nil, nil, "")
+		high1 := pushTestJobWithPriority(t, q, "octopus", nil, "", 10)
+		high2 := pushTestJobWithPriority(t, q, "octopus", nil, "", 10)
+
+		require.Equal(t, high1, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+		require.Equal(t, high2, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+		require.Equal(t, mid, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+		require.Equal(t, low, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+	})
+
+	t.Run("any channel", func(t *testing.T) {
+		low := pushTestJobWithPriority(t, q, "octopus", nil, "org-A", 1)
+		high := pushTestJobWithPriority(t, q, "octopus", nil, "org-B", 5)
+
+		id, _, _, _, _, err := q.DequeueAnyChannel(context.Background(), uuid.Nil, []string{"octopus"})
+		require.NoError(t, err)
+		require.Equal(t, high, id)
+		id, _, _, _, _, err = q.DequeueAnyChannel(context.Background(), uuid.Nil, []string{"octopus"})
+		require.NoError(t, err)
+		require.Equal(t, low, id)
+	})
+
+	t.Run("requeued job keeps its priority", func(t *testing.T) {
+		high := pushTestJobWithPriority(t, q, "octopus", nil, "", 10)
+		low := pushTestJob(t, q, "octopus", nil, nil, "")
+
+		id, _, _, _, _, err := q.Dequeue(context.Background(), uuid.Nil, []string{"octopus"}, []string{""})
+		require.NoError(t, err)
+		require.Equal(t, high, id)
+		requeued, err := q.RequeueOrFinishJob(id, 1, nil)
+		require.NoError(t, err)
+		require.True(t, requeued)
+
+		require.Equal(t, high, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+		require.Equal(t, low, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+	})
+
+	t.Run("dependencies take precedence over priority", func(t *testing.T) {
+		dep := pushTestJob(t, q, "octopus", nil, nil, "")
+		high := pushTestJobWithPriority(t, q, "octopus", []uuid.UUID{dep}, "", 10)
+		low := pushTestJobWithPriority(t, q, "octopus", nil, "", -10)
+
+		require.Equal(t, dep, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+		require.Equal(t, high, finishNextTestJob(t, q, "octopus", TestResult{}, []uuid.UUID{dep}))
+		require.Equal(t, low, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+	})
+}
+
+// dequeueTestJobs dequeues `count` jobs of `jobTypes` from `channels` without
+// finishing them and returns their ids.
+func dequeueTestJobs(t *testing.T, q jobqueue.JobQueue, jobTypes []string, channels []string, count int) []uuid.UUID {
+	t.Helper()
+	var ids []uuid.UUID
+	for range count {
+		id, _, _, _, _, err := q.Dequeue(context.Background(), uuid.Nil, jobTypes, channels)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func testFairShareRoundRobin(t *testing.T, q jobqueue.JobQueue) {
+	a1 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a2 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a3 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	b1 := pushTestJob(t, q, "octopus", nil, nil, "org-B")
+	b2 := pushTestJob(t, q, "octopus", nil, nil, "org-B")
+
+	ids := dequeueTestJobs(t, q, []string{"octopus"}, []string{"org-A", "org-B"}, 5)
+	require.Equal(t, []uuid.UUID{a1, b1, a2, b2, a3}, ids)
+}
+
+func testFairShareWeights(t *testing.T, q jobqueue.JobQueue) {
+	// org-A has a weight of 2
+	a1 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a2 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a3 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a4 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	b1 := pushTestJob(t, q, "octopus", nil, nil, "org-B")
+	b2 := pushTestJob(t, q, "octopus", nil, nil, "org-B")
+
+	ids := dequeueTestJobs(t, q, []string{"octopus"}, []string{"org-A", "org-B"}, 6)
+	require.Equal(t, []uuid.UUID{a1, b1, a2, a3, b2, a4}, ids)
+}
+
+func testFairShareFinishedJobs(t *testing.T, q jobqueue.JobQueue) {
+	a1 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a2 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	b1 := pushTestJob(t, q, "octopus", nil, nil, "org-B")
+
+	ids := dequeueTestJobs(t, q, []string{"octopus"}, []string{"org-A", "org-B"}, 1)
+	require.Equal(t, []uuid.UUID{a1}, ids)
+	requeued, err := q.RequeueOrFinishJob(a1, 0, &TestResult{})
+	require.NoError(t, err)
+	require.False(t, requeued)
+
+	// org-A doesn't have any running jobs anymore
+	ids = dequeueTestJobs(t, q, []string{"octopus"}, []string{"org-A", "org-B"}, 2)
+	require.Equal(t, []uuid.UUID{a2, b1}, ids)
+}
+
+func testFairSharePriority(t *testing.T, q jobqueue.JobQueue) {
+	a1 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a2 := pushTestJobWithPriority(t, q, "octopus", nil, "org-A", 10)
+	b1 := pushTestJob(t, q, "octopus", nil, nil, "org-B")
+
+	// a higher priority doesn't allow org-A to run two jobs while
+	// org-B has none
+	ids := dequeueTestJobs(t, q, []string{"octopus"}, []string{"org-A", "org-B"}, 3)
+	require.Equal(t, []uuid.UUID{a2, b1, a1}, ids)
+}
+
+func testFairShareAnyChannel(t *testing.T, q jobqueue.JobQueue) {
+	a1 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a2 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	b1 := pushTestJob(t, q, "octopus", nil, nil, "org-B")
+
+	var ids []uuid.UUID
+	for range 3 {
+		id, _, _, _, _, err := q.DequeueAnyChannel(context.Background(), uuid.Nil, []string{"octopus"})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	require.Equal(t, []uuid.UUID{a1, b1, a2}, ids)
+}
+
+func testCountJobs(t *testing.T, q jobqueue.JobQueue) {
+	count := func(filter jobqueue.JobFilter) [2]int {
+		pending, running, err := q.CountJobs(context.Background(), filter)
+		require.NoError(t, err)
+		return [2]int{pending, running}
+	}
+
+	one := pushTestJob(t, q, "octopus:one", nil, nil, "org-A")
+	two := pushTestJob(t, q, "clownfish", nil, []uuid.UUID{one}, "org-A")
+	pushTestJob(t, q, "octopus:two", nil, nil, "org-A")
+	pushTestJob(t, q, "octopus", nil, nil, "org-B")
+	canceled := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	require.NoError(t, q.CancelJob(canceled))
+
+	require.Equal(t, [2]int{3, 0}, count(jobqueue.JobFilter{Channel: "org-A"}))
+	require.Equal(t, [2]int{2, 0}, count(jobqueue.JobFilter{Channel: "org-A", JobTypes: []string{"octopus"}}))
+	require.Equal(t, [2]int{2, 0}, count(jobqueue.JobFilter{Channel: "org-A", RootOnly: true}))
+	require.Equal(t, [2]int{1, 0}, count(jobqueue.JobFilter{Channel: "org-B"}))
+	require.Equal(t, [2]int{0, 0}, count(jobqueue.JobFilter{Channel: "org-C"}))
+
+	id, _, _, _, _, err := q.Dequeue(context.Background(), uuid.Nil, []string{"octopus:one"}, []string{"org-A"})
+	require.NoError(t, err)
+	require.Equal(t, one, id)
+	require.Equal(t, [2]int{2, 1}, count(jobqueue.JobFilter{Channel: "org-A"}))
+
+	requeued, err := q.RequeueOrFinishJob(one, 0, &TestResult{})
+	require.NoError(t, err)
+	require.False(t, requeued)
+	require.Equal(t, [2]int{2, 0}, count(jobqueue.JobFilter{Channel: "org-A"}))
+	require.Equal(t, [2]int{1, 0}, count(jobqueue.JobFilter{Channel: "org-A", JobTypes: []string{"clownfish"}}))
+
+	_, _, _, _, err = q.DequeueByID(context.Background(), two, uuid.Nil)
+	require.NoError(t, err)
+	require.Equal(t, [2]int{1, 1}, count(jobqueue.JobFilter{Channel: "org-A"}))
+}
+
+func testListJobs(t *testing.T, q jobqueue.JobQueue) {
+	list := func(query jobqueue.JobQuery) ([]uuid.UUID, *jobqueue.JobCursor) {
+		ids, next, err := q.ListJobs(context.Background(), query)
+		require.NoError(t, err)
+		return ids, next
+	}
+	queuedAt := func(id uuid.UUID) time.Time {
+		_, _, _, queued, _, _, _, _, _, err := q.JobStatus(id)
+		require.NoError(t, err)
+		return queued
+	}
+
+	// space out the jobs, so that they are listed in a predictable order
+	push := func(jobType string, dependencies []uuid.UUID, channel string) uuid.UUID {
+		time.Sleep(2 * time.Millisecond)
+		return pushTestJob(t, q, jobType, nil, dependencies, channel)
+	}
+	one := push("octopus:one", nil, "org-A")
+	two := push("clownfish", []uuid.UUID{one}, "org-A")
+	three := push("octopus:two", nil, "org-A")
+	other := push("octopus", nil, "org-B")
+	canceled := push("octopus", nil, "org-A")
+	require.NoError(t, q.CancelJob(canceled))
+
+	all, next := list(jobqueue.JobQuery{JobFilter: jobqueue.JobFilter{Channel: "org-A"}})
+	require.Equal(t, []uuid.UUID{canceled, three, two, one}, all)
+	require.Nil(t, next)
+
+	ids, _ := list(jobqueue.JobQuery{JobFilter: jobqueue.JobFilter{Channel: "org-B"}})
+	require.Equal(t, []uuid.UUID{other}, ids)
+	ids, _ = list(jobqueue.JobQuery{JobFilter: jobqueue.JobFilter{Channel: "org-C"}})
+	require.Empty(t, ids)
+	ids, _ = list(jobqueue.JobQuery{JobFilter: jobqueue.JobFilter{Channel: "org-A", JobTypes: []string{"octopus"}}})
+	require.Equal(t, []uuid.UUID{canceled, three, one}, ids)
+	ids, _ = list(jobqueue.JobQuery{JobFilter: jobqueue.JobFilter{Channel: "org-A", RootOnly: true}})
+	require.Equal(t, []uuid.UUID{canceled, three, two}, ids)
+
+	// states
+	_, _, _, _, err := q.DequeueByID(context.Background(), one, uuid.Nil)
+	require.NoError(t, err)
+	_, err = q.RequeueOrFinishJob(one, 0, &TestResult{})
+	require.NoError(t, err)
+	_, _, _, _, err = q.DequeueByID(context.Background(), two, uuid.Nil)
+	require.NoError(t, err)
+	byState := func(states ...jobqueue.JobState) []uuid.UUID {
+		ids, _ := list(jobqueue.JobQuery{JobFilter: jobqueue.JobFilter{Channel: "org-A"}, States: states})
+		return ids
+	}
+	require.Equal(t, []uuid.UUID{three}, byState(jobqueue.JobPending))
+	require.Equal(t, []uuid.UUID{two}, byState(jobqueue.JobRunning))
+	require.Equal(t, []uuid.UUID{one}, byState(jobqueue.JobFinished))
+	require.Equal(t, []uuid.UUID{canceled}, byState(jobqueue.JobCanceled))
+	require.Equal(t, []uuid.UUID{three, two}, byState(jobqueue.JobPending, jobqueue.JobRunning))
+
+	// time range
+	ids, _ = list(jobqueue.JobQuery{
+		JobFilter:    jobqueue.JobFilter{Channel: "org-A"},
+		QueuedAfter:  queuedAt(one),
+		QueuedBefore: queuedAt(canceled),
+	})
+	require.Equal(t, []uuid.UUID{three, two}, ids)
+
+	// pages
+	var paged []uuid.UUID
+	query := jobqueue.JobQuery{JobFilter: jobqueue.JobFilter{Channel: "org-A"}, Limit: 3}
+	for {
+		ids, next := list(query)
+		require.LessOrEqual(t, len(ids), 3)
+		paged = append(paged, ids...)
+		if next == nil {
+			break
+		}
+		require.Equal(t, ids[len(ids)-1], next.ID)
+		query.After = next
+	}
+	require.Equal(t, all, paged)
+
+	ids, next = list(jobqueue.JobQuery{JobFilter: jobqueue.JobFilter{Channel: "org-A"}, Limit: 4})
+	require.Equal(t, all, ids)
+	require.Nil(t, next)
+}
+
+// requireDequeueTimeout verifies that no job of `jobTypes` can be dequeued
+// from `channels`.
+func requireDequeueTimeout(t *testing.T, q jobqueue.JobQueue, jobTypes []string, channels []string) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _, _, _, _, err := q.Dequeue(ctx, uuid.Nil, jobTypes, channels)
+	require.ErrorIs(t, err, jobqueue.ErrDequeueTimeout)
+}
+
+// testQuotas expects octopus jobs to be limited to one running job per
+// channel by default, two running jobs in org-B, and no limit in org-C.
+func testQuotas(t *testing.T, q jobqueue.JobQueue) {
+	a1 := pushTestJob(t, q, "octopus:x86_64", nil, nil, "org-A")
+	a2 := pushTestJob(t, q, "octopus:x86_64", nil, nil, "org-A")
+	aOther := pushTestJob(t, q, "clownfish", nil, nil, "org-A")
+	b1 := pushTestJob(t, q, "octopus:x86_64", nil, nil, "org-B")
+	b2 := pushTestJob(t, q, "octopus:x86_64", nil, nil, "org-B")
+	pushTestJob(t, q, "octopus:x86_64", nil, nil, "org-B")
+	c1 := pushTestJob(t, q, "octopus:x86_64", nil, nil, "org-C")
+	c2 := pushTestJob(t, q, "octopus:x86_64", nil, nil, "org-C")
+	c3 := pushTestJob(t, q, "octopus:x86_64", nil, nil, "org-C")
+
+	octopus := []string{"octopus:x86_64"}
+	require.Equal(t, []uuid.UUID{a1}, dequeueTestJobs(t, q, octopus, []string{"org-A"}, 1))
+	requireDequeueTimeout(t, q, octopus, []string{"org-A"})
+
+	// other job types are not limited
+	require.Equal(t, []uuid.UUID{aOther}, dequeueTestJobs(t, q, []string{"clownfish"}, []string{"org-A"}, 1))
+
+	require.Equal(t, []uuid.UUID{b1, b2}, dequeueTestJobs(t, q, octopus, []string{"org-B"}, 2))
+	requireDequeueTimeout(t, q, octopus, []string{"org-B"})
+
+	require.Equal(t, []uuid.UUID{c1, c2, c3}, dequeueTestJobs(t, q, octopus, []string{"org-C"}, 3))
+
+	// finishing a job frees up a slot
+	requeued, err := q.RequeueOrFinishJob(a1, 0, &TestResult{})
+	require.NoError(t, err)
+	require.False(t, requeued)
+	require.Equal(t, []uuid.UUID{a2}, dequeueTestJobs(t, q, octopus, []string{"org-A", "org-B"}, 1))
+}
+
+func testQuotasRelease(t *testing.T, q jobqueue.JobQueue) {
+	octopus := []string{"octopus"}
+	a1 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a2 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	a3 := pushTestJob(t, q, "octopus", nil, nil, "org-A")
+	require.Equal(t, []uuid.UUID{a1}, dequeueTestJobs(t, q, octopus, []string{"org-A"}, 1))
+
+	dequeued := make(chan uuid.UUID, 1)
+	go func() {
+		id, _, _, _, _, err := q.Dequeue(context.Background(), uuid.Nil, octopus, []string{"org-A"})
+		assert.NoError(t, err)
+		dequeued <- id
+	}()
+
+	// a blocked dequeuer is woken up when a running job finishes
+	time.Sleep(100 * time.Millisecond)
+	requeued, err := q.RequeueOrFinishJob(a1, 0, &TestResult{})
+	require.NoError(t, err)
+	require.False(t, requeued)
+	select {
+	case id := <-dequeued:
+		require.Equal(t, a2, id)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "blocked dequeuer was not woken up after finishing a job")
+	}
+
+	go func() {
+		id, _, _, _, _, err := q.Dequeue(context.Background(), uuid.Nil, octopus, []string{"org-A"})
+		assert.NoError(t, err)
+		dequeued <- id
+	}()
+
+	// ... and when a running job is canceled
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, q.CancelJob(a2))
+	select {
+	case id := <-dequeued:
+		require.Equal(t, a3, id)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "blocked dequeuer was not woken up after canceling a job")
+	}
+}
+
+func testDelayed(t *testing.T, q jobqueue.JobQueue) {
+	delay := 500 * time.Millisecond
+	delayed, err := q.EnqueueWithOptions("octopus", nil, nil, "", jobqueue.EnqueueOptions{NotBefore: time.Now().Add(delay)})
+	require.NoError(t, err)
+	ready := pushTestJob(t, q, "octopus", nil, nil, "")
+
+	// the delayed job is skipped, even though it was enqueued first
+	require.Equal(t, ready, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+	requireDequeueTimeout(t, q, []string{"octopus"}, []string{""})
+	_, _, _, _, err = q.DequeueByID(context.Background(), delayed, uuid.Nil)
+	require.ErrorIs(t, err, jobqueue.ErrNotPending)
+
+	// a blocked dequeuer picks it up once the delay has passed
+	start := time.Now()
+	require.Equal(t, delayed, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	// a NotBefore in the past doesn't delay the job
+	past, err := q.EnqueueWithOptions("octopus", nil, nil, "", jobqueue.EnqueueOptions{NotBefore: time.Now().Add(-time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, past, finishNextTestJob(t, q, "octopus", TestResult{}, nil))
+}
+
+// testRetryBackoff expects requeued jobs to be delayed by 500ms after the
+// first retry.
+func testRetryBackoff(t *testing.T, q jobqueue.JobQueue) {
+	octopus := []string{"octopus"}
+	id := pushTestJob(t, q, "octopus", nil, nil, "")
+	require.Equal(t, []uuid.UUID{id}, dequeueTestJobs(t, q, octopus, []string{""}, 1))
+
+	requeued, err := q.RequeueOrFinishJob(id, 3, nil)
+	require.NoError(t, err)
+	require.True(t, requeued)
+	start := time.Now()
+
+	// other jobs are not held back by the requeued one
+	other := pushTestJob(t, q, "octopus", nil, nil, "")
+	require.Equal(t, []uuid.UUID{other}, dequeueTestJobs(t, q, octopus, []string{""}, 1))
+	requireDequeueTimeout(t, q, octopus, []string{""})
+
+	require.Equal(t, []uuid.UUID{id}, dequeueTestJobs(t, q, octopus, []string{""}, 1))
+	require.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+}
+
+func testExportImport(t *testing.T, q jobqueue.JobQueue) {
+	exporter, ok := q.(jobqueue.JobExporter)
+	if !ok {
+		t.Skip("queue doesn't support exporting jobs")
+	}
+	importer, ok := q.(jobqueue.JobImporter)
+	if !ok {
+		t.Skip("queue doesn't support importing jobs")
+	}
+	ctx := context.Background()
+
+	finished := pushTestJob(t, q, "octopus", map[string]int{"arms": 8}, nil, "")
+	require.Equal(t, finished, finishNextTestJob(t, q, "octopus", TestResult{Logs: []byte(`"done"`)}, nil))
+	running := pushTestJob(t, q, "clownfish", nil, []uuid.UUID{finished}, "tenant")
+	require.Equal(t, []uuid.UUID{running}, dequeueTestJobs(t, q, []string{"clownfish"}, []string{"tenant"}, 1))
+	pending := pushTestJob(t, q, "kraken", nil, []uuid.UUID{finished, running}, "")
+
+	records := make(map[uuid.UUID]jobqueue.JobRecord)
+	require.NoError(t, exporter.ExportJobs(ctx, func(r jobqueue.JobRecord) error {
+		records[r.ID] = r
+		return nil
+	}))
+	require.Len(t, records, 3)
+	require.False(t, records[finished].FinishedAt.IsZero())
+	require.JSONEq(t, `{"logs":"done"}`, string(records[finished].Result))
+	require.False(t, records[running].StartedAt.IsZero())
+	require.True(t, records[running].FinishedAt.IsZero())
+	require.NotEqual(t, uuid.Nil, records[running].Token)
+	require.Equal(t, "tenant", records[running].Channel)
+	require.True(t, records[pending].StartedAt.IsZero())
+	require.ElementsMatch(t, []uuid.UUID{finished, running}, records[pending].Dependencies)
+
+	// importing an existing job fails
+	require.Error(t, importer.ImportJob(ctx, records[finished]))
+
+	// import copies of the jobs under new ids
+	copies := map[uuid.UUID]uuid.UUID{
+		finished: uuid.New(),
+		running:  uuid.New(),
+		pending:  uuid.New(),
+	}
+	runningToken := uuid.New()
+	for _, id := range []uuid.UUID{finished, running, pending} {
+		r := records[id]
+		r.ID = copies[id]
+		if id == running {
+			r.Token = runningToken
+		}
+		var deps []uuid.UUID
+		for _, d := range r.Dependencies {
+			deps = append(deps, copies[d])
+		}
+		r.Dependencies = deps
+		require.NoError(t, importer.ImportJob(ctx, r))
+	}
+
+	jobType, _, result, queued, started, ended, canceled, _, _, err := q.JobStatus(copies[finished])
+	require.NoError(t, err)
+	require.Equal(t, "octopus", jobType)
+	require.JSONEq(t, string(records[finished].Result), string(result))
+	require.WithinDuration(t, records[finished].QueuedAt, queued, time.Millisecond)
+	require.WithinDuration(t, records[finished].StartedAt, started, time.Millisecond)
+	require.WithinDuration(t, records[finished].FinishedAt, ended, time.Millisecond)
+	require.False(t, canceled)
+
+	_, args, _, _, err := q.Job(copies[finished])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"arms":8}`, string(args))
+
+	_, _, deps, channel, err := q.Job(copies[pending])
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uuid.UUID{copies[finished], copies[running]}, deps)
+	require.Equal(t, "", channel)
+
+	// the imported running job has a heartbeat and blocks its dependent
+	// until it's finished
+	require.Contains(t, q.Heartbeats(0), runningToken)
+	requireDequeueTimeout(t, q, []string{"kraken"}, []string{""})
+	_, err = q.RequeueOrFinishJob(copies[running], 0, TestResult{})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{copies[pending]}, dequeueTestJobs(t, q, []string{"kraken"}, []string{""}, 1))
+}
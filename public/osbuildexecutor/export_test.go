@@ -1,8 +1,50 @@
 package osbuildexecutor
 
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
+)
+
 var ExtractOutputArchive = extractOutputArchive
-var FetchOutputArchive = fetchOutputArchive
-var HandleBuild = handleBuild
 var ValidateOutputArchive = validateOutputArchive
-var WaitForSI = waitForSI
 var WriteInputArchive = writeInputArchive
+
+func WaitForExecutor(ctx context.Context, host string) bool {
+	return newExecutorClient(host, nil, "").waitForExecutor(ctx)
+}
+
+var ErrExecutorBusy = errExecutorBusy
+
+func HandleBuild(ctx context.Context, inputArchive, host string, logger logrus.FieldLogger, job worker.Job) error {
+	_, err := newExecutorClient(host, nil, "").build(ctx, inputArchive, logger, job, nil)
+	return err
+}
+
+// StartBuild runs the build and returns its path on the executor.
+func StartBuild(ctx context.Context, inputArchive, host string) (string, error) {
+	return newExecutorClient(host, nil, "").build(ctx, inputArchive, logrus.NewEntry(logrus.New()), nil, nil)
+}
+
+func FetchOutputArchive(ctx context.Context, cacheDir, host string) (string, error) {
+	return newExecutorClient(host, nil, "").fetchOutputArchive(ctx, legacyBuildPath, cacheDir)
+}
+
+// AcquireHost takes a free host out of the pool and returns its URL and a
+// function putting it back.
+func (p *RemoteHostPool) AcquireHost(ctx context.Context) (string, func(), error) {
+	c, err := p.acquire(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.host, func() { p.release(c) }, nil
+}
+
+func RemoveBuild(ctx context.Context, host, buildPath string, tlsConfig *tls.Config, token string) error {
+	return newExecutorClient(host, newExecutorTransport(tlsConfig), token).removeBuild(ctx, buildPath)
+}
+
+var QEMUArgs = qemuArgs
@@ -1,6 +1,7 @@
 package osbuildexecutor
 
 import (
+	"context"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -13,6 +14,10 @@ const (
 	MinTimeBetweenUpdates = time.Second * 30
 )
 
+// Executor runs osbuild for a job. Canceling ctx stops the build and makes
+// RunOSBuild return an error wrapping the context's cancellation cause. The
+// timing of the pipelines and stages and the disk usage of the build are
+// recorded in report if it isn't nil.
 type Executor interface {
-	RunOSBuild(manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions) (*osbuild.Result, error)
+	RunOSBuild(ctx context.Context, manifest []byte, logger logrus.FieldLogger, job worker.Job, report *worker.OSBuildReport, opts *osbuild.OSBuildOptions) (*osbuild.Result, error)
 }
@@ -0,0 +1,128 @@
+package osbuildexecutor
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/image-builder/pkg/osbuild"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
+)
+
+// how often the disk usage of the store and output directories is measured
+const diskUsageInterval = time.Second * 10
+
+// recordTiming updates the pipeline and stage timing of the report with a
+// monitor message. osbuild announces every pipeline and stage with a
+// "Starting pipeline"/"Starting module" message, everything which follows
+// until the next one belongs to it.
+func recordTiming(report *worker.OSBuildReport, st *osbuild.Status) {
+	if report == nil || st.Timestamp.IsZero() || st.Pipeline == "" {
+		return
+	}
+
+	n := len(report.Pipelines)
+	if n == 0 || report.Pipelines[n-1].Name != st.Pipeline || strings.HasPrefix(st.Message, "Starting pipeline") {
+		report.Pipelines = append(report.Pipelines, worker.PipelineTiming{
+			Name:    st.Pipeline,
+			Started: st.Timestamp,
+		})
+		n++
+	}
+	pipeline := &report.Pipelines[n-1]
+	pipeline.Finished = st.Timestamp
+
+	var stageName string
+	if st.Progress != nil && st.Progress.SubProgress != nil {
+		stageName = strings.TrimPrefix(st.Progress.SubProgress.Message, "Stage ")
+	}
+	if stageName == "" {
+		return
+	}
+	m := len(pipeline.Stages)
+	if m == 0 || pipeline.Stages[m-1].Name != stageName || strings.HasPrefix(st.Message, "Starting module") {
+		pipeline.Stages = append(pipeline.Stages, worker.StageTiming{
+			Name:    stageName,
+			Started: st.Timestamp,
+		})
+		m++
+	}
+	stage := &pipeline.Stages[m-1]
+	stage.Finished = st.Timestamp
+	// the result of the stage comes with the time osbuild measured
+	if st.Duration > 0 {
+		stage.Started = stage.Finished.Add(-st.Duration)
+	}
+}
+
+// DiskUsage returns the disk space used by the files under root in bytes.
+// Files which disappear while walking the tree are skipped, osbuild keeps
+// changing the store while it's being measured.
+func DiskUsage(root string) int64 {
+	var size int64
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			// allocated blocks, images are often sparse
+			size += stat.Blocks * 512
+		} else {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// watchDiskUsage measures the store and output directories until the
+// returned function is called and records their peak size in the report.
+func watchDiskUsage(report *worker.OSBuildReport, storeDir, outputDir string) (stop func()) {
+	if report == nil {
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	measure := func() {
+		if storeDir != "" {
+			report.PeakStoreSize = max(report.PeakStoreSize, DiskUsage(storeDir))
+		}
+		if outputDir != "" {
+			report.PeakOutputSize = max(report.PeakOutputSize, DiskUsage(outputDir))
+		}
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(diskUsageInterval)
+		defer ticker.Stop()
+		for {
+			measure()
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+		// the output is complete only now
+		measure()
+		logrus.Debugf("Peak disk usage: store %d bytes, output %d bytes", report.PeakStoreSize, report.PeakOutputSize)
+	}
+}
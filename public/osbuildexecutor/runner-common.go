@@ -10,7 +10,7 @@ import (
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 )
 
-func handleProgress(osbuildStatus *osbuild.StatusScanner, logger logrus.FieldLogger, job worker.Job) error {
+func handleProgress(osbuildStatus *osbuild.StatusScanner, logger logrus.FieldLogger, job worker.Job, report *worker.OSBuildReport) error {
 	if osbuildStatus == nil {
 		return fmt.Errorf("status scanner is required to handle osbuild progress")
 	}
@@ -24,6 +24,7 @@ func handleProgress(osbuildStatus *osbuild.StatusScanner, logger logrus.FieldLog
 		if st == nil {
 			break
 		}
+		recordTiming(report, st)
 
 		progress := logrus.Fields{}
 		if st.Progress != nil {
@@ -28,19 +28,19 @@ func makeMockEntry() (*logrus.Entry, *test.Hook) {
 	return logger.WithFields(nil), hook
 }
 
-func TestWaitForSI(t *testing.T) {
+func TestWaitForExecutor(t *testing.T) {
 	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*1)
 	defer cancel()
-	require.False(t, osbuildexecutor.WaitForSI(ctx, server.URL))
+	require.False(t, osbuildexecutor.WaitForExecutor(ctx, server.URL))
 
 	server.Start()
 	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second*1)
 	defer cancel2()
-	require.True(t, osbuildexecutor.WaitForSI(ctx2, server.URL))
+	require.True(t, osbuildexecutor.WaitForExecutor(ctx2, server.URL))
 }
 
 func TestWriteInputArchive(t *testing.T) {
@@ -114,6 +114,10 @@ func (j *testJob) UploadArtifact(name string, readSeeker io.ReadSeeker) error {
 	return nil
 }
 
+func (j *testJob) Requeue() error {
+	return nil
+}
+
 func TestHandleBuild(t *testing.T) {
 	buildServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		input, err := io.ReadAll(r.Body)
@@ -133,7 +137,7 @@ func TestHandleBuild(t *testing.T) {
 
 	entry, hook := makeMockEntry()
 	job := testJob{}
-	err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, entry, &job)
+	err := osbuildexecutor.HandleBuild(context.Background(), inputArchive, buildServer.URL, entry, &job)
 	require.NoError(t, err)
 	require.Len(t, hook.Entries, 3)
 	require.Equal(t, "OSBuild status: starting pipeline", hook.Entries[0].Message)
@@ -186,7 +190,7 @@ func TestHandleBuildTraceDebug(t *testing.T) {
 	require.NoError(t, os.WriteFile(inputArchive, []byte("test"), 0600))
 
 	entry, hook := makeMockEntry()
-	err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, entry, nil)
+	err := osbuildexecutor.HandleBuild(context.Background(), inputArchive, buildServer.URL, entry, nil)
 	require.NoError(t, err)
 	require.Len(t, hook.Entries, 2)
 	require.Equal(t, "OSBuild status: starting pipeline", hook.Entries[0].Message)
@@ -216,7 +220,7 @@ func TestHandleBuildNoJSON(t *testing.T) {
 	require.NoError(t, os.WriteFile(inputArchive, []byte("test"), 0600))
 
 	entry, _ := makeMockEntry()
-	err := osbuildexecutor.HandleBuild(inputArchive, buildServer.URL, entry, nil)
+	err := osbuildexecutor.HandleBuild(context.Background(), inputArchive, buildServer.URL, entry, nil)
 	require.ErrorContains(t, err, `error parsing osbuild status, please report a bug: cannot scan line "bad non-json text": invalid character 'b' looking for beginning of value`)
 }
 
@@ -250,7 +254,7 @@ func TestHandleOutputArchive(t *testing.T) {
 	}))
 
 	outputDir := t.TempDir()
-	archive, err := osbuildexecutor.FetchOutputArchive(outputDir, resultServer.URL)
+	archive, err := osbuildexecutor.FetchOutputArchive(context.Background(), outputDir, resultServer.URL)
 	require.NoError(t, err)
 
 	extractDir := filepath.Join(outputDir, "extracted")
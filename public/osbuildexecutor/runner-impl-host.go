@@ -2,10 +2,13 @@ package osbuildexecutor
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -13,9 +16,12 @@ import (
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 )
 
+// How long osbuild gets to clean up after SIGTERM before it's killed.
+const CancelGracePeriod = time.Second * 30
+
 type hostExecutor struct{}
 
-func (he *hostExecutor) RunOSBuild(manifest []byte, logger logrus.FieldLogger, job worker.Job, opts *osbuild.OSBuildOptions) (*osbuild.Result, error) {
+func (he *hostExecutor) RunOSBuild(ctx context.Context, manifest []byte, logger logrus.FieldLogger, job worker.Job, report *worker.OSBuildReport, opts *osbuild.OSBuildOptions) (*osbuild.Result, error) {
 	// MonitorFile needs an *os.File
 	rPipe, wPipe, err := os.Pipe()
 	if err != nil {
@@ -31,17 +37,40 @@ func (he *hostExecutor) RunOSBuild(manifest []byte, logger logrus.FieldLogger, j
 	opts.Stdout = &stdoutBuffer
 
 	cmd := osbuild.NewOSBuildCmd(manifest, opts)
+	// Run osbuild in its own process group so that it can be stopped
+	// together with all the stages it spawned.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	osbuildStatus := osbuild.NewStatusScanner(rPipe)
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("error starting osbuild: %v", err)
 	}
 	wPipe.Close()
+	// A cached store is shared by all the build slots, its size doesn't
+	// tell anything about this build.
+	storeDir := opts.StoreDir
+	if opts.CacheMaxSize > 0 {
+		storeDir = ""
+	}
+	stopWatchingDiskUsage := watchDiskUsage(report, storeDir, opts.OutputDir)
+	defer stopWatchingDiskUsage()
 
-	if err := handleProgress(osbuildStatus, logger, job); err != nil {
+	done := make(chan struct{})
+	defer close(done)
+	go StopOnCancel(ctx, done, cmd.Process.Pid, logger)
+
+	if err := handleProgress(osbuildStatus, logger, job, report); err != nil {
+		if ctx.Err() != nil {
+			_ = cmd.Wait()
+			return nil, fmt.Errorf("osbuild was stopped: %w", context.Cause(ctx))
+		}
 		return nil, fmt.Errorf("unable to construct osbuild result: %w", err)
 	}
 
-	if err := cmd.Wait(); err != nil {
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("osbuild was stopped: %w", context.Cause(ctx))
+	}
+	if err != nil {
 		// ignore ExitError if output can be decoded correctly (only if running with --json)
 		if _, isExitError := err.(*exec.ExitError); !isExitError || !opts.JSONOutput {
 			return nil, fmt.Errorf("osbuild failed: %w, %s", err, stdoutBuffer.String())
@@ -66,6 +95,37 @@ func (he *hostExecutor) RunOSBuild(manifest []byte, logger logrus.FieldLogger, j
 	return &result, nil
 }
 
+// StopOnCancel terminates the osbuild process group once ctx is canceled. The
+// processes get SIGTERM first and SIGKILL if they are still around after
+// CancelGracePeriod. Returns when done is closed.
+func StopOnCancel(ctx context.Context, done <-chan struct{}, pid int, logger logrus.FieldLogger) {
+	select {
+	case <-ctx.Done():
+	case <-done:
+		return
+	}
+	select {
+	case <-done:
+		// osbuild finished on its own
+		return
+	default:
+	}
+
+	logger.Infof("Stopping osbuild (pid %d): %v", pid, context.Cause(ctx))
+	if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil && err != syscall.ESRCH {
+		logger.Warnf("Unable to send SIGTERM to osbuild: %v", err)
+	}
+
+	select {
+	case <-time.After(CancelGracePeriod):
+		logger.Warnf("osbuild (pid %d) did not stop in %v, killing it", pid, CancelGracePeriod)
+		if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+			logger.Warnf("Unable to send SIGKILL to osbuild: %v", err)
+		}
+	case <-done:
+	}
+}
+
 func NewHostExecutor() Executor {
 	return &hostExecutor{}
 }
@@ -1,11 +1,14 @@
 package osbuildexecutor_test
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +17,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/osbuild"
 
 	"github.com/ondrejbudai/osbuild-composer-public/public/osbuildexecutor"
+	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 )
 
 func TestHostRunOSBuild(t *testing.T) {
@@ -131,7 +135,7 @@ exit 2
 			require.NoError(t, err)
 
 			hostExe := osbuildexecutor.NewHostExecutor()
-			result, err := hostExe.RunOSBuild(nil, logger, nil, &osbuild.OSBuildOptions{
+			result, err := hostExe.RunOSBuild(context.Background(), nil, logger, nil, nil, &osbuild.OSBuildOptions{
 				JSONOutput: tt.json,
 			})
 			if tt.error != "" {
@@ -144,3 +148,110 @@ exit 2
 		})
 	}
 }
+
+func TestHostRunOSBuildCanceled(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("PATH", tmpDir)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	//nolint:gosec
+	err := os.WriteFile(filepath.Join(tmpDir, "osbuild"), []byte(`#!/bin/sh
+exec /bin/sleep 60
+`), 0700)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	hostExe := osbuildexecutor.NewHostExecutor()
+	result, err := hostExe.RunOSBuild(ctx, nil, logger, nil, nil, &osbuild.OSBuildOptions{
+		JSONOutput: true,
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, result)
+	assert.Less(t, time.Since(start), 30*time.Second)
+}
+
+func TestHostRunOSBuildReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("PATH", tmpDir)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	monitor := func(msg, pipeline, stage string, timestamp float64, extra string) string {
+		return fmt.Sprintf(`>&3 echo '{"message": "%s", "context": {"origin": "osbuild.monitor", "id": "%s-%s", "pipeline": {"name": "%s", "id": "%s", "stage": {"name": "%s", "id": "%s"}}}, "progress": {"name": "pipelines", "total": 2, "done": 0, "progress": {"name": "stages", "total": 2, "done": 0}}, "timestamp": %f%s}'`,
+			msg, pipeline, stage, pipeline, pipeline, stage, stage, timestamp, extra)
+	}
+	finished := func(stage string, duration float64) string {
+		return fmt.Sprintf(`, "result": {"name": "%s", "id": "%s", "success": true}, "duration": %f`, stage, stage, duration)
+	}
+	script := []string{
+		"#!/bin/sh",
+		monitor("Starting pipeline build", "build", "", 100, ""),
+		monitor("Starting module org.osbuild.rpm", "build", "org.osbuild.rpm", 101, ""),
+		monitor("Finished module org.osbuild.rpm", "build", "org.osbuild.rpm", 110, finished("org.osbuild.rpm", 8)),
+		monitor("Starting module org.osbuild.selinux", "build", "org.osbuild.selinux", 111, ""),
+		monitor("Finished module org.osbuild.selinux", "build", "org.osbuild.selinux", 113, finished("org.osbuild.selinux", 2)),
+		monitor("Starting pipeline image", "image", "", 114, ""),
+		monitor("Starting module org.osbuild.copy", "image", "org.osbuild.copy", 115, ""),
+		monitor("Finished module org.osbuild.copy", "image", "org.osbuild.copy", 120, finished("org.osbuild.copy", 5)),
+		`echo '{"success": true}'`,
+	}
+	//nolint:gosec
+	err := os.WriteFile(filepath.Join(tmpDir, "osbuild"), []byte(strings.Join(script, "\n")+"\n"), 0700)
+	require.NoError(t, err)
+
+	storeDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(storeDir, "source"), make([]byte, 8192), 0600))
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "disk.img"), make([]byte, 4096), 0600))
+
+	var report worker.OSBuildReport
+	hostExe := osbuildexecutor.NewHostExecutor()
+	result, err := hostExe.RunOSBuild(context.Background(), nil, logger, nil, &report, &osbuild.OSBuildOptions{
+		StoreDir:   storeDir,
+		OutputDir:  outputDir,
+		JSONOutput: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+
+	at := func(seconds int64) time.Time {
+		return time.Unix(seconds, 0)
+	}
+	assert.Equal(t, []worker.PipelineTiming{
+		{
+			Name:     "build",
+			Started:  at(100),
+			Finished: at(113),
+			Stages: []worker.StageTiming{
+				{Name: "org.osbuild.rpm", Started: at(102), Finished: at(110)},
+				{Name: "org.osbuild.selinux", Started: at(111), Finished: at(113)},
+			},
+		},
+		{
+			Name:     "image",
+			Started:  at(114),
+			Finished: at(120),
+			Stages: []worker.StageTiming{
+				{Name: "org.osbuild.copy", Started: at(115), Finished: at(120)},
+			},
+		},
+	}, report.Pipelines)
+	assert.GreaterOrEqual(t, report.PeakStoreSize, int64(8192))
+	assert.GreaterOrEqual(t, report.PeakOutputSize, int64(4096))
+
+	// the store cache is shared with other builds, it isn't measured
+	report = worker.OSBuildReport{}
+	_, err = hostExe.RunOSBuild(context.Background(), nil, logger, nil, &report, &osbuild.OSBuildOptions{
+		StoreDir:     storeDir,
+		OutputDir:    outputDir,
+		CacheMaxSize: 20 << 30,
+		JSONOutput:   true,
+	})
+	require.NoError(t, err)
+	assert.Zero(t, report.PeakStoreSize)
+	assert.GreaterOrEqual(t, report.PeakOutputSize, int64(4096))
+}
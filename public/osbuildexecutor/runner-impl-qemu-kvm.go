@@ -0,0 +1,265 @@
+package osbuildexecutor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/image-builder/pkg/arch"
+	"github.com/osbuild/image-builder/pkg/osbuild"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
+)
+
+const (
+	// port osbuild-worker-executor listens on inside of the guest
+	qemuExecutorPort = 8001
+
+	qemuDefaultMemory = 4096
+	qemuDefaultCPUs   = 2
+
+	// the last bytes of the guest console which are logged when the
+	// executor doesn't come online
+	qemuConsoleTail = 4096
+)
+
+// QEMUOptions configure the throwaway guest of the qemu.kvm executor.
+type QEMUOptions struct {
+	// qcow2 image the guest boots, it needs to start osbuild-worker-executor
+	// listening on port 8001. The image itself is never modified, each
+	// build gets its own overlay on top of it.
+	Image string
+	// memory of the guest in MiB, defaults to 4096
+	Memory int
+	// defaults to 2
+	CPUs int
+	// defaults to qemu-system-<host architecture>
+	Binary string
+	// UEFI firmware of the virt machine of aarch64, arm and riscv64, which
+	// can't boot a disk image without one. Defaults to the one of edk2.
+	Firmware string
+	// Credentials presented to the executor in the guest, https is used if
+	// TLSConfig is set. The guest is reached on 127.0.0.1.
+	TLSConfig *tls.Config
+	Token     string
+}
+
+// UEFI firmware images of the edk2 packages, padded to the size of the
+// pflash of the virt machine
+var qemuDefaultFirmware = map[arch.Arch]string{
+	arch.ARCH_AARCH64: "/usr/share/edk2/aarch64/QEMU_EFI-pflash.raw",
+	arch.ARCH_ARM:     "/usr/share/edk2/arm/QEMU_EFI-pflash.raw",
+	arch.ARCH_RISCV64: "/usr/share/edk2/riscv/RISCV_VIRT_CODE.fd",
+}
+
+type qemuKVMExecutor struct {
+	options QEMUOptions
+	tmpDir  string
+}
+
+func kvmAvailable() bool {
+	f, err := os.OpenFile("/dev/kvm", os.O_RDWR, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+func qemuBinary() string {
+	switch arch.Current() {
+	case arch.ARCH_PPC64LE:
+		return "qemu-system-ppc64"
+	default:
+		return "qemu-system-" + arch.Current().String()
+	}
+}
+
+// Returns the arguments for booting a guest of architecture a from the
+// overlay and forwarding hostPort on localhost to the executor in the guest.
+// The guest uses KVM when it's available and falls back to TCG otherwise.
+func qemuArgs(options QEMUOptions, a arch.Arch, overlay, console string, hostPort int, kvm bool) []string {
+	memory := options.Memory
+	if memory == 0 {
+		memory = qemuDefaultMemory
+	}
+	cpus := options.CPUs
+	if cpus == 0 {
+		cpus = qemuDefaultCPUs
+	}
+
+	accel, cpu := "tcg", "max"
+	if kvm {
+		accel, cpu = "kvm", "host"
+	}
+
+	// qemu doesn't have a default machine on all architectures and the
+	// default one of x86_64 is the old i440fx
+	machine, nic := "q35", "virtio-net-pci"
+	firmware := ""
+	switch a {
+	case arch.ARCH_AARCH64, arch.ARCH_ARM, arch.ARCH_RISCV64:
+		machine = "virt"
+		firmware = options.Firmware
+		if firmware == "" {
+			firmware = qemuDefaultFirmware[a]
+		}
+	case arch.ARCH_PPC64LE:
+		machine = "pseries"
+	case arch.ARCH_S390X:
+		// s390x has no PCI by default
+		machine, nic = "s390-ccw-virtio", "virtio-net-ccw"
+	}
+
+	args := []string{
+		"-machine", machine,
+		"-accel", accel,
+		"-cpu", cpu,
+		"-m", strconv.Itoa(memory),
+		"-smp", strconv.Itoa(cpus),
+	}
+	if firmware != "" {
+		args = append(args, "-drive", fmt.Sprintf("file=%s,if=pflash,format=raw,unit=0,readonly=on", firmware))
+	}
+	return append(args,
+		"-drive", fmt.Sprintf("file=%s,if=virtio,format=qcow2", overlay),
+		"-nic", fmt.Sprintf("user,model=%s,hostfwd=tcp:127.0.0.1:%d-:%d", nic, hostPort, qemuExecutorPort),
+		"-display", "none",
+		"-serial", "file:"+console,
+	)
+}
+
+// This is racy, but the guest is the only one supposed to use the port and
+// qemu fails to start if somebody takes it in the meantime.
+func freeLocalPort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+func logConsoleTail(console string) {
+	data, err := os.ReadFile(console)
+	if err != nil {
+		logrus.Errorf("Unable to read guest console: %v", err)
+		return
+	}
+	if len(data) > qemuConsoleTail {
+		data = data[len(data)-qemuConsoleTail:]
+	}
+	logrus.WithField("guest_console", string(data)).Errorf("Guest didn't start the executor, end of its console output:\n%s", data)
+}
+
+func (qe *qemuKVMExecutor) RunOSBuild(ctx context.Context, manifest []byte, logger logrus.FieldLogger, job worker.Job, report *worker.OSBuildReport, opts *osbuild.OSBuildOptions) (*osbuild.Result, error) {
+	prepSrcRes, err := prepareSources(ctx, manifest, logger, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to prepare sources: %w", err)
+	}
+	if !prepSrcRes.Success {
+		return prepSrcRes, nil
+	}
+
+	image, err := filepath.Abs(qe.options.Image)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid guest image %s: %w", qe.options.Image, err)
+	}
+	overlay := filepath.Join(qe.tmpDir, "guest.qcow2")
+	/* #nosec G204 */
+	cmd := exec.Command("qemu-img", "create", "-f", "qcow2", "-F", "qcow2", "-b", image, overlay)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("Unable to create guest overlay: %w, %s", err, output)
+	}
+
+	port, err := freeLocalPort()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to find a free port for the guest: %w", err)
+	}
+
+	kvm := kvmAvailable()
+	if !kvm {
+		logger.Warn("KVM isn't available, falling back to TCG, the build is going to be slow")
+	}
+
+	binary := qe.options.Binary
+	if binary == "" {
+		binary = qemuBinary()
+	}
+	console := filepath.Join(qe.tmpDir, "console.log")
+	/* #nosec G204 */
+	guest := exec.Command(binary, qemuArgs(qe.options, arch.Current(), overlay, console, port, kvm)...)
+	guest.Stdout = os.Stderr
+	guest.Stderr = os.Stderr
+	if err := guest.Start(); err != nil {
+		return nil, fmt.Errorf("Unable to start guest: %w", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = guest.Wait()
+		close(exited)
+	}()
+	// The guest is thrown away after the build, there is nothing to shut
+	// down cleanly. Its overlay is removed together with tmpDir.
+	defer func() {
+		select {
+		case <-exited:
+			return
+		default:
+		}
+		if err := guest.Process.Kill(); err != nil {
+			logrus.Errorf("Error stopping guest: %v", err)
+		}
+		<-exited
+	}()
+
+	scheme := "http"
+	if qe.options.TLSConfig != nil {
+		scheme = "https"
+	}
+	executor := newExecutorClient(fmt.Sprintf("%s://127.0.0.1:%d", scheme, port), newExecutorTransport(qe.options.TLSConfig), qe.options.Token)
+
+	// TCG guests can take a long time to boot.
+	waitCtx, cancel := context.WithTimeout(ctx, time.Minute*10)
+	defer cancel()
+	go func() {
+		// stop waiting if qemu dies, e.g. because of an invalid image
+		select {
+		case <-exited:
+			cancel()
+		case <-waitCtx.Done():
+		}
+	}()
+	if !executor.waitForExecutor(waitCtx) {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("build was stopped: %w", context.Cause(ctx))
+		}
+		logConsoleTail(console)
+		select {
+		case <-exited:
+			return nil, fmt.Errorf("Guest stopped before the executor came online: %s", guest.ProcessState)
+		default:
+			return nil, fmt.Errorf("Timeout waiting for executor to come online")
+		}
+	}
+
+	return runRemoteBuild(ctx, executor, qe.tmpDir, manifest, logger, job, report, opts)
+}
+
+// NewQEMUKVMExecutor returns an executor which runs each build in a new QEMU
+// guest booted from options.Image. tmpDir holds the guest's disk overlay and
+// the archives sent to and received from the guest.
+func NewQEMUKVMExecutor(options QEMUOptions, tmpDir string) Executor {
+	return &qemuKVMExecutor{
+		options: options,
+		tmpDir:  tmpDir,
+	}
+}
@@ -0,0 +1,136 @@
+package osbuildexecutor_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osbuild/image-builder/pkg/arch"
+	"github.com/osbuild/image-builder/pkg/osbuild"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/osbuildexecutor"
+)
+
+func TestQEMUArgs(t *testing.T) {
+	args := osbuildexecutor.QEMUArgs(osbuildexecutor.QEMUOptions{}, arch.ARCH_X86_64, "/tmp/guest.qcow2", "/tmp/console.log", 40123, true)
+	require.Equal(t, []string{
+		"-machine", "q35",
+		"-accel", "kvm",
+		"-cpu", "host",
+		"-m", "4096",
+		"-smp", "2",
+		"-drive", "file=/tmp/guest.qcow2,if=virtio,format=qcow2",
+		"-nic", "user,model=virtio-net-pci,hostfwd=tcp:127.0.0.1:40123-:8001",
+		"-display", "none",
+		"-serial", "file:/tmp/console.log",
+	}, args)
+}
+
+func TestQEMUArgsTCG(t *testing.T) {
+	args := osbuildexecutor.QEMUArgs(osbuildexecutor.QEMUOptions{
+		Memory: 8192,
+		CPUs:   4,
+	}, arch.ARCH_X86_64, "/tmp/guest.qcow2", "/tmp/console.log", 40123, false)
+	require.Equal(t, []string{
+		"-machine", "q35",
+		"-accel", "tcg",
+		"-cpu", "max",
+		"-m", "8192",
+		"-smp", "4",
+		"-drive", "file=/tmp/guest.qcow2,if=virtio,format=qcow2",
+		"-nic", "user,model=virtio-net-pci,hostfwd=tcp:127.0.0.1:40123-:8001",
+		"-display", "none",
+		"-serial", "file:/tmp/console.log",
+	}, args)
+}
+
+func TestQEMUArgsMachine(t *testing.T) {
+	tests := []struct {
+		arch     arch.Arch
+		machine  string
+		nic      string
+		firmware string
+	}{
+		{arch.ARCH_X86_64, "q35", "virtio-net-pci", ""},
+		{arch.ARCH_AARCH64, "virt", "virtio-net-pci", "/usr/share/edk2/aarch64/QEMU_EFI-pflash.raw"},
+		{arch.ARCH_ARM, "virt", "virtio-net-pci", "/usr/share/edk2/arm/QEMU_EFI-pflash.raw"},
+		{arch.ARCH_RISCV64, "virt", "virtio-net-pci", "/usr/share/edk2/riscv/RISCV_VIRT_CODE.fd"},
+		{arch.ARCH_PPC64LE, "pseries", "virtio-net-pci", ""},
+		{arch.ARCH_S390X, "s390-ccw-virtio", "virtio-net-ccw", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arch.String(), func(t *testing.T) {
+			args := osbuildexecutor.QEMUArgs(osbuildexecutor.QEMUOptions{}, tt.arch, "/tmp/guest.qcow2", "/tmp/console.log", 40123, true)
+			require.Equal(t, []string{"-machine", tt.machine}, args[:2])
+			require.Contains(t, args, "user,model="+tt.nic+",hostfwd=tcp:127.0.0.1:40123-:8001")
+			pflash := "file=" + tt.firmware + ",if=pflash,format=raw,unit=0,readonly=on"
+			if tt.firmware == "" {
+				for _, arg := range args {
+					require.NotContains(t, arg, "if=pflash")
+				}
+			} else {
+				require.Contains(t, args, pflash)
+			}
+		})
+	}
+}
+
+func TestQEMUArgsFirmware(t *testing.T) {
+	args := osbuildexecutor.QEMUArgs(osbuildexecutor.QEMUOptions{
+		Firmware: "/usr/share/AAVMF/AAVMF_CODE.fd",
+	}, arch.ARCH_AARCH64, "/tmp/guest.qcow2", "/tmp/console.log", 40123, true)
+	require.Equal(t, []string{
+		"-machine", "virt",
+		"-accel", "kvm",
+		"-cpu", "host",
+		"-m", "4096",
+		"-smp", "2",
+		"-drive", "file=/usr/share/AAVMF/AAVMF_CODE.fd,if=pflash,format=raw,unit=0,readonly=on",
+		"-drive", "file=/tmp/guest.qcow2,if=virtio,format=qcow2",
+		"-nic", "user,model=virtio-net-pci,hostfwd=tcp:127.0.0.1:40123-:8001",
+		"-display", "none",
+		"-serial", "file:/tmp/console.log",
+	}, args)
+}
+
+func TestQEMUKVMGuestStopped(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("PATH", tmpDir)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	//nolint:gosec
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "osbuild"), []byte(`#!/bin/sh
+echo '{"success": true}'
+`), 0700))
+	//nolint:gosec
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qemu-img"), []byte(`#!/bin/sh
+exit 0
+`), 0700))
+	// the guest writes to its console and dies before the executor is up
+	//nolint:gosec
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "qemu"), []byte(`#!/bin/sh
+while [ $# -gt 0 ]; do
+	if [ "$1" = "-serial" ]; then
+		echo "Kernel panic" > "${2#file:}"
+	fi
+	shift
+done
+exit 1
+`), 0700))
+
+	executor := osbuildexecutor.NewQEMUKVMExecutor(osbuildexecutor.QEMUOptions{
+		Image:  filepath.Join(tmpDir, "guest.qcow2"),
+		Binary: filepath.Join(tmpDir, "qemu"),
+	}, t.TempDir())
+	result, err := executor.RunOSBuild(context.Background(), nil, logger, nil, nil, &osbuild.OSBuildOptions{
+		JSONOutput: true,
+	})
+	require.EqualError(t, err, "Guest stopped before the executor came online: exit status 1")
+	require.Nil(t, result)
+}
@@ -0,0 +1,115 @@
+package osbuildexecutor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/osbuild/image-builder/pkg/osbuild"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
+)
+
+// RemoteHostPool is a set of pre-provisioned hosts running
+// osbuild-worker-executor. Each entry runs one build at a time, builds wait
+// until one of them is free. A host can be listed several times to run that
+// many builds on it concurrently, as far as its -max-builds allows. The pool
+// is meant to be shared by all job slots of a worker.
+type RemoteHostPool struct {
+	free chan *executorClient
+}
+
+// NewRemoteHostPool returns a pool of the executors listening on the given
+// URLs. The TLS config is used for https URLs and the token, if set, is sent
+// to the executors as a bearer token.
+func NewRemoteHostPool(hosts []string, tlsConfig *tls.Config, token string) (*RemoteHostPool, error) {
+	if len(hosts) == 0 {
+		return nil, errors.New("at least one executor host is required")
+	}
+
+	transport := newExecutorTransport(tlsConfig)
+	pool := &RemoteHostPool{
+		free: make(chan *executorClient, len(hosts)),
+	}
+	for _, host := range hosts {
+		u, err := url.Parse(host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid executor host %q: %w", host, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid executor host %q: expected an http or https URL", host)
+		}
+		pool.free <- newExecutorClient(host, transport, token)
+	}
+	return pool, nil
+}
+
+func (p *RemoteHostPool) acquire(ctx context.Context) (*executorClient, error) {
+	select {
+	case c := <-p.free:
+		return c, nil
+	default:
+	}
+
+	logrus.Info("All executor hosts are busy, waiting for one of them")
+	select {
+	case c := <-p.free:
+		return c, nil
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+}
+
+func (p *RemoteHostPool) release(c *executorClient) {
+	p.free <- c
+}
+
+type remoteExecutor struct {
+	pool   *RemoteHostPool
+	tmpDir string
+}
+
+func (re *remoteExecutor) RunOSBuild(ctx context.Context, manifest []byte, logger logrus.FieldLogger, job worker.Job, report *worker.OSBuildReport, opts *osbuild.OSBuildOptions) (*osbuild.Result, error) {
+	prepSrcRes, err := prepareSources(ctx, manifest, logger, opts)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to prepare sources: %w", err)
+	}
+	if !prepSrcRes.Success {
+		return prepSrcRes, nil
+	}
+
+	executor, err := re.pool.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("build was stopped: %w", err)
+	}
+	defer re.pool.release(executor)
+	logger.Infof("Running the build on executor %s", executor.host)
+
+	// The hosts are already provisioned, give them a moment in case one of
+	// them was just restarted.
+	waitCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	if !executor.waitForExecutor(waitCtx) {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("build was stopped: %w", context.Cause(ctx))
+		}
+		return nil, fmt.Errorf("Timeout waiting for executor %s to come online", executor.host)
+	}
+
+	return runRemoteBuild(ctx, executor, re.tmpDir, manifest, logger, job, report, opts)
+}
+
+// NewRemoteExecutor returns an executor which runs the build on one of the
+// hosts of the pool, tmpDir is used for the archives sent to and received
+// from the host.
+func NewRemoteExecutor(pool *RemoteHostPool, tmpDir string) Executor {
+	return &remoteExecutor{
+		pool:   pool,
+		tmpDir: tmpDir,
+	}
+}
@@ -0,0 +1,140 @@
+package osbuildexecutor_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/osbuildexecutor"
+)
+
+func TestRemoteHostPoolInvalidHosts(t *testing.T) {
+	for _, hosts := range [][]string{
+		nil,
+		{"10.0.0.1:8001"},
+		{"ftp://10.0.0.1"},
+		{"http://10.0.0.1:8001", "http://"},
+	} {
+		_, err := osbuildexecutor.NewRemoteHostPool(hosts, nil, "")
+		require.Error(t, err, hosts)
+	}
+}
+
+func TestRemoteHostPool(t *testing.T) {
+	pool, err := osbuildexecutor.NewRemoteHostPool([]string{"http://10.0.0.1:8001/", "https://10.0.0.2:8001"}, nil, "")
+	require.NoError(t, err)
+
+	host1, release1, err := pool.AcquireHost(context.Background())
+	require.NoError(t, err)
+	host2, release2, err := pool.AcquireHost(context.Background())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"http://10.0.0.1:8001", "https://10.0.0.2:8001"}, []string{host1, host2})
+
+	// all hosts are busy
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _, err = pool.AcquireHost(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release2()
+	host, release, err := pool.AcquireHost(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, host2, host)
+	release()
+	release1()
+}
+
+func TestStartBuild(t *testing.T) {
+	inputArchive := filepath.Join(t.TempDir(), "input.tar")
+	require.NoError(t, os.WriteFile(inputArchive, []byte("test"), 0600))
+
+	for _, tc := range []struct {
+		status   int
+		location string
+
+		expectedPath string
+		expectedErr  error
+	}{
+		{http.StatusCreated, "/api/v1/build/some-id", "/api/v1/build/some-id", nil},
+		// executors which don't know about build IDs
+		{http.StatusCreated, "", "/api/v1", nil},
+		{http.StatusServiceUnavailable, "", "", osbuildexecutor.ErrExecutorBusy},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/v1/build", r.URL.Path)
+			if tc.location != "" {
+				w.Header().Set("Location", tc.location)
+			}
+			w.WriteHeader(tc.status)
+		}))
+		defer server.Close()
+
+		path, err := osbuildexecutor.StartBuild(context.Background(), inputArchive, server.URL)
+		if tc.expectedErr != nil {
+			require.ErrorIs(t, err, tc.expectedErr)
+		} else {
+			require.NoError(t, err)
+		}
+		require.Equal(t, tc.expectedPath, path)
+	}
+}
+
+func TestRemoveBuild(t *testing.T) {
+	var removed atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/build/some-id", r.URL.Path)
+		require.Equal(t, http.MethodDelete, r.Method)
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		if removed.Swap(true) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	require.NoError(t, osbuildexecutor.RemoveBuild(context.Background(), server.URL, "/api/v1/build/some-id", nil, "secret"))
+	// already gone
+	require.NoError(t, osbuildexecutor.RemoveBuild(context.Background(), server.URL, "/api/v1/build/some-id", nil, "secret"))
+}
+
+func TestExecutorClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequestClientCert,
+		MinVersion: tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	// the test server's certificate doubles as the client certificate
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	tlsConfig := &tls.Config{
+		RootCAs:      roots,
+		Certificates: server.TLS.Certificates,
+		MinVersion:   tls.VersionTLS12,
+	}
+	require.NoError(t, osbuildexecutor.RemoveBuild(context.Background(), server.URL, "/api/v1/build/some-id", tlsConfig, ""))
+
+	// without the client certificate
+	require.Error(t, osbuildexecutor.RemoveBuild(context.Background(), server.URL, "/api/v1/build/some-id", &tls.Config{
+		RootCAs:    roots,
+		MinVersion: tls.VersionTLS12,
+	}, ""))
+}
@@ -0,0 +1,407 @@
+package osbuildexecutor
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/exp/slices"
+
+	"github.com/osbuild/image-builder/pkg/osbuild"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
+)
+
+const OSBuildResultFilename = "osbuild-result.json"
+
+// the executor is running as many builds as it can
+var errExecutorBusy = errors.New("executor is busy")
+
+// Executors which don't address builds by ID serve the log and the result of
+// their only build from here.
+const legacyBuildPath = "/api/v1"
+
+// Downloads the sources of the manifest into the local store, so that they
+// can be sent to the remote executor along with the manifest.
+func prepareSources(ctx context.Context, manifest []byte, logger logrus.FieldLogger, opts *osbuild.OSBuildOptions) (*osbuild.Result, error) {
+	hostExecutor := NewHostExecutor()
+	return hostExecutor.RunOSBuild(ctx, manifest, logger, nil, nil, &osbuild.OSBuildOptions{
+		StoreDir:   opts.StoreDir,
+		ExtraEnv:   opts.ExtraEnv,
+		Stderr:     opts.Stderr,
+		JSONOutput: true,
+	})
+}
+
+// executorClient talks to an osbuild-worker-executor instance over HTTP. It
+// is shared by all executors which run osbuild on another machine.
+type executorClient struct {
+	// e.g. http://10.0.0.1:8001
+	host      string
+	transport http.RoundTripper
+	// sent as a bearer token with every request if set
+	token string
+}
+
+// Returns the transport used to talk to executors, tlsConfig can hold the
+// executor's CA and a client certificate.
+func newExecutorTransport(tlsConfig *tls.Config) http.RoundTripper {
+	if tlsConfig == nil {
+		return nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+func newExecutorClient(host string, transport http.RoundTripper, token string) *executorClient {
+	return &executorClient{
+		host:      strings.TrimSuffix(host, "/"),
+		transport: transport,
+		token:     token,
+	}
+}
+
+func (c *executorClient) do(ctx context.Context, timeout time.Duration, method, path string, body io.Reader) (*http.Response, error) {
+	client := http.Client{
+		Transport: c.transport,
+		Timeout:   timeout,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.host+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-tar")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return client.Do(req)
+}
+
+func (c *executorClient) waitForExecutor(ctx context.Context) bool {
+	for {
+		resp, err := c.do(ctx, time.Second*1, http.MethodGet, "/api/v1/", nil)
+		if err != nil {
+			logrus.Debugf("Waiting for executor continues: %v", err)
+		}
+		if resp != nil {
+			defer resp.Body.Close()
+			if resp.StatusCode == 200 {
+				return true
+			}
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				logrus.Warningf("Unable to read body waiting for executor: %v", err)
+			}
+			logrus.Debugf("Waiting for executor continues: %s", body)
+		}
+		select {
+		case <-ctx.Done():
+			logrus.Error("Timeout waiting for executor to come online")
+			return false
+		default:
+			time.Sleep(time.Second)
+			continue
+		}
+	}
+}
+
+// Stops the build if it's still running and removes it from the executor.
+// A build which is already gone is not an error.
+func (c *executorClient) removeBuild(ctx context.Context, buildPath string) error {
+	resp, err := c.do(ctx, time.Minute, http.MethodDelete, buildPath, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("cannot remove build: %w, http status: %d", err, resp.StatusCode)
+		}
+		return fmt.Errorf("cannot remove build: http status: %d, body: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// Runs the build and returns its path on the executor, the log and the
+// result of the build are served below it. The path is empty if the executor
+// didn't start the build, errExecutorBusy is returned if it's running too
+// many builds already.
+func (c *executorClient) build(ctx context.Context, inputArchive string, logger logrus.FieldLogger, job worker.Job, report *worker.OSBuildReport) (string, error) {
+	inputFile, err := os.Open(inputArchive)
+	if err != nil {
+		return "", fmt.Errorf("unable to open inputArchive (%s): %w", inputArchive, err)
+	}
+	defer inputFile.Close()
+
+	resp, err := c.do(ctx, time.Minute*60, http.MethodPost, "/api/v1/build", inputFile)
+	if err != nil {
+		return "", fmt.Errorf("unable to request build from executor instance: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		return "", errExecutorBusy
+	}
+	if resp.StatusCode != 201 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("unable to read body waiting for build to run: %w,  http status: %d", err, resp.StatusCode)
+		}
+		return "", fmt.Errorf("something went wrong during executor build: http status: %v, %d, %s", err, resp.StatusCode, body)
+	}
+
+	buildPath := resp.Header.Get("Location")
+	if buildPath == "" {
+		buildPath = legacyBuildPath
+	}
+	osbuildStatus := osbuild.NewStatusScanner(resp.Body)
+	return buildPath, handleProgress(osbuildStatus, logger, job, report)
+}
+
+func (c *executorClient) fetchLog(ctx context.Context, buildPath string) (string, error) {
+	resp, err := c.do(ctx, time.Minute, http.MethodGet, buildPath+"/log", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("cannot fetch output archive: %w, http status: %d", err, resp.StatusCode)
+		}
+		return "", fmt.Errorf("cannot fetch output archive: %w, http status: %d, body: %s", err, resp.StatusCode, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("cannot read log response: %w, http status: %d", err, resp.StatusCode)
+	}
+	return string(body), nil
+}
+
+func (c *executorClient) fetchOutputArchive(ctx context.Context, buildPath, cacheDir string) (string, error) {
+	resp, err := c.do(ctx, time.Minute*30, http.MethodGet, buildPath+"/result/output.tar", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("cannot fetch output archive: %w, http status: %d", err, resp.StatusCode)
+		}
+		return "", fmt.Errorf("cannot fetch output archive: %w, http status: %d, body: %s", err, resp.StatusCode, body)
+	}
+	file, err := os.Create(filepath.Join(cacheDir, "output.tar"))
+	if err != nil {
+		return "", fmt.Errorf("Unable to write executor result tarball: %w", err)
+	}
+	defer file.Close()
+	_, err = io.Copy(file, resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("Unable to write executor result tarball: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// Runs the build on an executor which is already online and returns the
+// osbuild result it produced. The output is extracted into opts.OutputDir,
+// tmpDir holds the archives sent to and received from the executor.
+func runRemoteBuild(ctx context.Context, c *executorClient, tmpDir string, manifest []byte, logger logrus.FieldLogger, job worker.Job, report *worker.OSBuildReport, opts *osbuild.OSBuildOptions) (*osbuild.Result, error) {
+	inputArchive, err := writeInputArchive(tmpDir, opts.StoreDir, opts.Exports, manifest)
+	if err != nil {
+		logrus.Errorf("Unable to write input archive: %v", err)
+		return nil, err
+	}
+
+	var buildPath string
+	for {
+		buildPath, err = c.build(ctx, inputArchive, logger, job, report)
+		if !errors.Is(err, errExecutorBusy) {
+			break
+		}
+		logrus.Infof("Executor %s is running too many builds, waiting for one of them to finish", c.host)
+		select {
+		case <-time.After(time.Second * 10):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("build was stopped: %w", context.Cause(ctx))
+		}
+	}
+	if buildPath != "" && buildPath != legacyBuildPath {
+		defer func() {
+			// Stops the build if the job was canceled and doesn't leave
+			// the output lying around on the executor.
+			if err := c.removeBuild(context.Background(), buildPath); err != nil {
+				logrus.Warnf("Unable to remove build %s from executor %s: %v", buildPath, c.host, err)
+			}
+		}()
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("build was stopped: %w", context.Cause(ctx))
+		}
+		if buildPath == "" {
+			logrus.Errorf("Executor didn't start the build: %v", err)
+			return nil, err
+		}
+		log, logErr := c.fetchLog(ctx, buildPath)
+		if logErr != nil {
+			logrus.Errorf("something went wrong during the executor's build: %v, unable to fetch log: %v", err, logErr)
+			return nil, fmt.Errorf("something went wrong during the executor's build: %w, unable to fetch log: %w", err, logErr)
+		}
+		logrus.WithField("osbuild_output", string(log)).Errorf("something went wrong handling the executor's build: %v\nosbuild log: %v", err, log)
+		return nil, fmt.Errorf("osbuild failed: %s", log)
+	}
+
+	outputArchive, err := c.fetchOutputArchive(ctx, buildPath, tmpDir)
+	if err != nil {
+		logrus.Errorf("Unable to fetch executor output: %v", err)
+		return nil, err
+	}
+
+	err = extractOutputArchive(opts.OutputDir, outputArchive)
+	if err != nil {
+		logrus.Errorf("Unable to extract executor output: %v", err)
+		return nil, err
+	}
+	if report != nil {
+		// the store lives on the executor, only the output can be measured
+		report.PeakOutputSize = DiskUsage(opts.OutputDir)
+	}
+
+	resultData, err := os.ReadFile(filepath.Join(opts.OutputDir, OSBuildResultFilename))
+	if err != nil {
+		logrus.Errorf("Unable to find and read osbuild result: %v", err)
+		return nil, err
+	}
+	var result osbuild.Result
+	if err := json.Unmarshal(resultData, &result); err != nil {
+		logrus.Errorf("Unable to unmarshal json result: %v\nraw output:\n%s", err, resultData)
+		return nil, fmt.Errorf("error decoding osbuild output: %w\nraw output:\n%s", err, resultData)
+	}
+	return &result, nil
+}
+
+func writeInputArchive(cacheDir, store string, exports []string, manifestData []byte) (string, error) {
+	archive := filepath.Join(cacheDir, "input.tar")
+	control := filepath.Join(cacheDir, "control.json")
+	manifest := filepath.Join(cacheDir, "manifest.json")
+
+	controlData := struct {
+		Exports []string `json:"exports"`
+	}{
+		Exports: exports,
+	}
+	controlDataBytes, err := json.Marshal(controlData)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(control, controlDataBytes, 0600)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(manifest, manifestData, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("tar",
+		"-C",
+		cacheDir,
+		"-cf",
+		archive,
+		filepath.Base(control),
+		filepath.Base(manifest),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("Unable to create input tar: %w, %s", err, output)
+	}
+	// Separate tar call, as we need to switch to the store directory.
+	/* #nosec G204 */
+	cmd = exec.Command("tar",
+		"-C",
+		filepath.Dir(store),
+		"-rf",
+		archive,
+		filepath.Base(store),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("Unable to create input tar: %w, %s", err, output)
+	}
+
+	return archive, nil
+}
+
+func validateOutputArchive(outputTarPath string) error {
+	f, err := os.Open(outputTarPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// check for directory traversal attacks
+		if filepath.Clean(hdr.Name) != strings.TrimSuffix(hdr.Name, "/") {
+			return fmt.Errorf("name %q not clean, got %q after cleaning", hdr.Name, filepath.Clean(hdr.Name))
+		}
+		if strings.HasPrefix(filepath.Clean(hdr.Name), "/") {
+			return fmt.Errorf("name %q must not start with an absolute path", hdr.Name)
+		}
+		// protect against someone smuggling in eg. device files
+		// XXX: should we support symlinks here?
+		if !slices.Contains([]byte{tar.TypeReg, tar.TypeDir, tar.TypeGNUSparse}, hdr.Typeflag) {
+			return fmt.Errorf("name %q must be a file/dir, is header type %q", hdr.Name, hdr.Typeflag)
+		}
+		// protect against executables, this implicitly protects
+		// against suid/sgid (XXX: or should we also check that?)
+		if hdr.Typeflag == tar.TypeReg && hdr.Mode&0111 != 0 {
+			return fmt.Errorf("name %q must not be executable (is mode 0%o)", hdr.Name, hdr.Mode)
+		}
+	}
+
+	return nil
+}
+
+func extractOutputArchive(outputDirectory, outputTar string) error {
+	// validate against directory traversal attacks
+	if err := validateOutputArchive(outputTar); err != nil {
+		return fmt.Errorf("unable to validate output tar: %w", err)
+	}
+
+	cmd := exec.Command("tar",
+		"--strip-components=1",
+		"-C",
+		outputDirectory,
+		"-Sxf",
+		outputTar,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("Unable to create input tar: %w, %s", err, output)
+	}
+	return nil
+
+}
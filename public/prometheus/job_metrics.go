@@ -55,6 +55,15 @@ var (
 	}, []string{"type", "tenant", "arch"})
 )
 
+var (
+	StoreCacheSources = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "store_cache_sources_total",
+		Namespace: Namespace,
+		Subsystem: WorkerSubsystem,
+		Help:      "Sources of osbuild jobs found in (hit) or missing from (miss) the store cache of the worker.",
+	}, []string{"result", "arch"})
+)
+
 func EnqueueJobMetrics(jobType, tenant string) {
 	PendingJobs.WithLabelValues(jobType, tenant).Inc()
 }
@@ -81,6 +90,11 @@ func CancelJobMetrics(started time.Time, jobType, tenant string) {
 	}
 }
 
+func StoreCacheMetrics(hits, misses int, arch string) {
+	StoreCacheSources.WithLabelValues("hit", arch).Add(float64(hits))
+	StoreCacheSources.WithLabelValues("miss", arch).Add(float64(misses))
+}
+
 func FinishJobMetrics(started time.Time, finished time.Time, canceled bool, jobType, tenant, arch string, status clienterrors.StatusCode) {
 	if !finished.IsZero() && !canceled {
 		diff := finished.Sub(started).Seconds()
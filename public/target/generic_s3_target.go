@@ -0,0 +1,35 @@
+package target
+
+const TargetNameGenericS3 TargetName = "org.osbuild.generic.s3"
+
+// GenericS3TargetOptions upload the image to a bucket of an S3-compatible
+// object store, e.g. Ceph or MinIO.
+type GenericS3TargetOptions struct {
+	Endpoint string `json:"endpoint"`
+	Region   string `json:"region,omitempty"`
+	Bucket   string `json:"bucket"`
+	Key      string `json:"key"`
+
+	// CredentialsRef is the name of credentials in the worker's
+	// configuration, the credentials themselves never leave the worker
+	CredentialsRef string `json:"credentials_ref"`
+}
+
+func (GenericS3TargetOptions) isTargetOptions() {}
+
+func NewGenericS3Target(options *GenericS3TargetOptions) *Target {
+	return newTarget(TargetNameGenericS3, options)
+}
+
+type GenericS3TargetResultOptions struct {
+	// presigned URL of the uploaded object
+	URL    string `json:"url"`
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+}
+
+func (GenericS3TargetResultOptions) isTargetResultOptions() {}
+
+func NewGenericS3TargetResult(options *GenericS3TargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameGenericS3, options, artifact)
+}
@@ -0,0 +1,35 @@
+package target
+
+const TargetNamePulpOSTree TargetName = "org.osbuild.pulp.ostree"
+
+type PulpOSTreeTargetOptions struct {
+	// ServerAddress of the Pulp instance, the worker's configuration is
+	// used when it's empty. The worker's credentials are only sent to the
+	// server of its configuration.
+	ServerAddress string `json:"server_address,omitempty"`
+
+	// Repository to import the ostree commit to, created if it doesn't exist
+	Repository string `json:"repository,omitempty"`
+
+	// BasePath for distributing the repository if it's created
+	BasePath string `json:"basepath,omitempty"`
+
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+func (PulpOSTreeTargetOptions) isTargetOptions() {}
+
+func NewPulpOSTreeTarget(options *PulpOSTreeTargetOptions) *Target {
+	return newTarget(TargetNamePulpOSTree, options)
+}
+
+type PulpOSTreeTargetResultOptions struct {
+	RepoURL string `json:"repository_url"`
+}
+
+func (PulpOSTreeTargetResultOptions) isTargetResultOptions() {}
+
+func NewPulpOSTreeTargetResult(options *PulpOSTreeTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNamePulpOSTree, options, artifact)
+}
@@ -0,0 +1,42 @@
+package target
+
+const TargetNameSFTP TargetName = "org.osbuild.sftp"
+
+// SFTPTargetOptions upload the image to a file server over SFTP. The image is
+// stored in the directory `Path` under the name of the target's image, or the
+// name of the exported file if the target has no image name.
+type SFTPTargetOptions struct {
+	Host string `json:"host"`
+	// Port defaults to 22
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Path     string `json:"path"`
+
+	// KnownHosts pins the host keys of the server, in the format of an
+	// OpenSSH known_hosts file. The known hosts from the worker's
+	// configuration are used too.
+	KnownHosts string `json:"known_hosts,omitempty"`
+
+	// KeyRef is the name of a private key in the worker's configuration,
+	// the default key of the worker is used if it's empty
+	KeyRef string `json:"key_ref,omitempty"`
+}
+
+func (SFTPTargetOptions) isTargetOptions() {}
+
+func NewSFTPTarget(options *SFTPTargetOptions) *Target {
+	return newTarget(TargetNameSFTP, options)
+}
+
+type SFTPTargetResultOptions struct {
+	// path of the uploaded image on the server
+	Path string `json:"path"`
+	// "sha256:" followed by the hex digest of the image
+	Checksum string `json:"checksum"`
+}
+
+func (SFTPTargetResultOptions) isTargetResultOptions() {}
+
+func NewSFTPTargetResult(options *SFTPTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameSFTP, options, artifact)
+}
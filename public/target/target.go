@@ -93,6 +93,12 @@ func (target *Target) UnmarshalJSON(data []byte) error {
 		options = new(ContainerTargetOptions)
 	case TargetNameWorkerServer:
 		options = new(WorkerServerTargetOptions)
+	case TargetNamePulpOSTree:
+		options = new(PulpOSTreeTargetOptions)
+	case TargetNameGenericS3:
+		options = new(GenericS3TargetOptions)
+	case TargetNameSFTP:
+		options = new(SFTPTargetOptions)
 	default:
 		return fmt.Errorf("unexpected target name: %s", rawTarget.Name)
 	}
@@ -278,6 +284,11 @@ func (target Target) MarshalJSON() ([]byte, error) {
 			// the incompatible change.
 			rawOptions, err = json.Marshal(target.Options)
 
+		case *PulpOSTreeTargetOptions, *GenericS3TargetOptions, *SFTPTargetOptions:
+			// Same as the WorkerServer target, these targets were added
+			// after the incompatible change.
+			rawOptions, err = json.Marshal(target.Options)
+
 		default:
 			return nil, fmt.Errorf("unexpected target options type: %t", t)
 		}
@@ -78,6 +78,14 @@ func UnmarshalTargetResultOptions(trName TargetName, rawOptions json.RawMessage)
 		options = new(ContainerTargetResultOptions)
 	case TargetNameWorkerServer:
 		options = new(WorkerServerTargetResultOptions)
+	case TargetNamePulpOSTree:
+		options = new(PulpOSTreeTargetResultOptions)
+	case TargetNameVMWare:
+		options = new(VMWareTargetResultOptions)
+	case TargetNameGenericS3:
+		options = new(GenericS3TargetResultOptions)
+	case TargetNameSFTP:
+		options = new(SFTPTargetResultOptions)
 	default:
 		return nil, fmt.Errorf("unexpected target result name: %s", trName)
 	}
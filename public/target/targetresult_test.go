@@ -87,6 +87,48 @@ func TestTargetResultUnmarshal(t *testing.T) {
 				Name: TargetNameVMWare,
 			},
 		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.vmware","options":{"image_name":"image","host":"vcenter.example.com","datacenter":"dc","datastore":"ds"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameVMWare,
+				Options: &VMWareTargetResultOptions{
+					ImageName:  "image",
+					Host:       "vcenter.example.com",
+					Datacenter: "dc",
+					Datastore:  "ds",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.pulp.ostree","options":{"repository_url":"https://pulp.example.com/pulp/content/edge/"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNamePulpOSTree,
+				Options: &PulpOSTreeTargetResultOptions{
+					RepoURL: "https://pulp.example.com/pulp/content/edge/",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.generic.s3","options":{"url":"https://s3.example.com/images/image.raw?X-Amz-Signature=sig","bucket":"images","key":"image.raw"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameGenericS3,
+				Options: &GenericS3TargetResultOptions{
+					URL:    "https://s3.example.com/images/image.raw?X-Amz-Signature=sig",
+					Bucket: "images",
+					Key:    "image.raw",
+				},
+			},
+		},
+		{
+			resultJSON: []byte(`{"name":"org.osbuild.sftp","options":{"path":"/srv/images/disk.qcow2","checksum":"sha256:abc"}}`),
+			expectedResult: &TargetResult{
+				Name: TargetNameSFTP,
+				Options: &SFTPTargetResultOptions{
+					Path:     "/srv/images/disk.qcow2",
+					Checksum: "sha256:abc",
+				},
+			},
+		},
 		// target results with error without options
 		{
 			resultJSON: []byte(`{"name":"org.osbuild.aws","target_error":{"id":11,"reason":"failed to uplad image","details":"detail"}}`),
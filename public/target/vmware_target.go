@@ -18,6 +18,17 @@ func NewVMWareTarget(options *VMWareTargetOptions) *Target {
 	return newTarget(TargetNameVMWare, options)
 }
 
-func NewVMWareTargetResult(artifact *OsbuildArtifact) *TargetResult {
-	return newTargetResult(TargetNameVMWare, nil, artifact)
+type VMWareTargetResultOptions struct {
+	ImageName  string `json:"image_name"`
+	Host       string `json:"host"`
+	Datacenter string `json:"datacenter"`
+	Datastore  string `json:"datastore"`
+	Folder     string `json:"folder,omitempty"`
+}
+
+func (VMWareTargetResultOptions) isTargetResultOptions() {}
+
+// Results of older workers don't have any options.
+func NewVMWareTargetResult(options *VMWareTargetResultOptions, artifact *OsbuildArtifact) *TargetResult {
+	return newTargetResult(TargetNameVMWare, options, artifact)
 }
@@ -0,0 +1,317 @@
+package pulp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const apiRoot = "/pulp/api/v3/"
+
+// TaskPollInterval is the time between two checks of the state of a Pulp
+// task.
+var TaskPollInterval = 5 * time.Second
+
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ReadCredentials reads the credentials from a JSON file with a username
+// and a password.
+func ReadCredentials(path string) (*Credentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the pulp credentials: %v", err)
+	}
+	var creds Credentials
+	err = json.Unmarshal(data, &creds)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the pulp credentials: %v", err)
+	}
+	return &creds, nil
+}
+
+// Client talks to the REST API of a Pulp instance with the OSTree plugin.
+type Client struct {
+	server *url.URL
+	creds  *Credentials
+	client *http.Client
+}
+
+func NewClient(serverAddress string, creds *Credentials) (*Client, error) {
+	if serverAddress == "" {
+		return nil, errors.New("no pulp server address set")
+	}
+	server, err := url.Parse(serverAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pulp server address %q: %v", serverAddress, err)
+	}
+	return &Client{
+		server: server,
+		creds:  creds,
+		client: &http.Client{},
+	}, nil
+}
+
+type taskResponse struct {
+	Task string `json:"task"`
+}
+
+type hrefResponse struct {
+	PulpHref string `json:"pulp_href"`
+}
+
+type task struct {
+	State string          `json:"state"`
+	Error json.RawMessage `json:"error"`
+}
+
+type listResponse[T any] struct {
+	Count   int `json:"count"`
+	Results []T `json:"results"`
+}
+
+type distribution struct {
+	BaseURL string `json:"base_url"`
+}
+
+// url resolves an href of the API, which is an absolute path on the server,
+// or a path relative to the root of the API.
+func (cl *Client) url(href string, query url.Values) string {
+	if !strings.HasPrefix(href, "/") {
+		href = apiRoot + href
+	}
+	u := cl.server.ResolveReference(&url.URL{Path: href})
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// do sends a request and decodes the JSON response into `v` if the response
+// has the status `expected`
+func (cl *Client) do(ctx context.Context, method, href string, query url.Values, contentType string, body io.Reader, expected int, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, cl.url(href, query), body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if cl.creds != nil {
+		req.SetBasicAuth(cl.creds.Username, cl.creds.Password)
+	}
+
+	resp, err := cl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s %s returned %s: %s", method, href, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (cl *Client) doJSON(ctx context.Context, method, href string, body interface{}, expected int, v interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return cl.do(ctx, method, href, nil, "application/json", bytes.NewReader(data), expected, v)
+}
+
+// UploadFile uploads the file as a Pulp artifact and returns its href. The
+// file is streamed, it doesn't have to fit in memory.
+func (cl *Client) UploadFile(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		part, err := mw.CreateFormFile("file", filepath.Base(path))
+		if err == nil {
+			_, err = io.Copy(part, f)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	var artifact hrefResponse
+	err = cl.do(ctx, http.MethodPost, "artifacts/", nil, mw.FormDataContentType(), pr, http.StatusCreated, &artifact)
+	// unblock the writer if the request failed before reading the body
+	pr.Close()
+	if err != nil {
+		return "", fmt.Errorf("cannot upload %s: %v", path, err)
+	}
+	return artifact.PulpHref, nil
+}
+
+// GetOSTreeRepositoryByName returns the href of the OSTree repository or an
+// empty string if it doesn't exist.
+func (cl *Client) GetOSTreeRepositoryByName(ctx context.Context, name string) (string, error) {
+	var repos listResponse[hrefResponse]
+	err := cl.do(ctx, http.MethodGet, "repositories/ostree/ostree/", url.Values{"name": {name}}, "", nil, http.StatusOK, &repos)
+	if err != nil {
+		return "", fmt.Errorf("cannot look up repository %s: %v", name, err)
+	}
+	switch len(repos.Results) {
+	case 0:
+		return "", nil
+	case 1:
+		return repos.Results[0].PulpHref, nil
+	default:
+		return "", fmt.Errorf("more than one repository named %s", name)
+	}
+}
+
+// CreateOSTreeRepository creates an OSTree repository and returns its href.
+func (cl *Client) CreateOSTreeRepository(ctx context.Context, name string) (string, error) {
+	var repo hrefResponse
+	err := cl.doJSON(ctx, http.MethodPost, "repositories/ostree/ostree/", map[string]string{
+		"name": name,
+	}, http.StatusCreated, &repo)
+	if err != nil {
+		return "", fmt.Errorf("cannot create repository %s: %v", name, err)
+	}
+	return repo.PulpHref, nil
+}
+
+// DistributeOSTreeRepo creates a distribution of the repository under
+// `basePath` and returns the href of the task creating it.
+func (cl *Client) DistributeOSTreeRepo(ctx context.Context, basePath, name, repoHref string) (string, error) {
+	var resp taskResponse
+	err := cl.doJSON(ctx, http.MethodPost, "distributions/ostree/ostree/", map[string]string{
+		"name":       name,
+		"base_path":  basePath,
+		"repository": repoHref,
+	}, http.StatusAccepted, &resp)
+	if err != nil {
+		return "", fmt.Errorf("cannot distribute repository %s: %v", name, err)
+	}
+	return resp.Task, nil
+}
+
+// ImportCommit imports the tarball of an ostree repository uploaded as the
+// artifact `artifactHref` into the OSTree repository and returns the href of
+// the task importing it.
+func (cl *Client) ImportCommit(ctx context.Context, artifactHref, repoHref string) (string, error) {
+	var resp taskResponse
+	err := cl.doJSON(ctx, http.MethodPost, repoHref+"import_all/", map[string]string{
+		"artifact": artifactHref,
+		// name of the repository directory in the tarball
+		"repository_name": "repo",
+	}, http.StatusAccepted, &resp)
+	if err != nil {
+		return "", fmt.Errorf("cannot import the commit: %v", err)
+	}
+	return resp.Task, nil
+}
+
+// WaitForTask polls the task until it finishes and fails if the task didn't
+// complete successfully.
+func (cl *Client) WaitForTask(ctx context.Context, taskHref string) error {
+	for {
+		var t task
+		err := cl.do(ctx, http.MethodGet, taskHref, nil, "", nil, http.StatusOK, &t)
+		if err != nil {
+			return fmt.Errorf("cannot get the state of task %s: %v", taskHref, err)
+		}
+
+		switch t.State {
+		case "completed":
+			return nil
+		case "failed", "canceled", "skipped":
+			return fmt.Errorf("task %s %s: %s", taskHref, t.State, string(t.Error))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(TaskPollInterval):
+		}
+	}
+}
+
+// GetDistributionURLForOSTreeRepo returns the URL the repository is
+// distributed at.
+func (cl *Client) GetDistributionURLForOSTreeRepo(ctx context.Context, repoHref string) (string, error) {
+	var dists listResponse[distribution]
+	err := cl.do(ctx, http.MethodGet, "distributions/ostree/ostree/", url.Values{"repository": {repoHref}}, "", nil, http.StatusOK, &dists)
+	if err != nil {
+		return "", fmt.Errorf("cannot look up the distribution of %s: %v", repoHref, err)
+	}
+	if len(dists.Results) == 0 {
+		return "", fmt.Errorf("repository %s is not distributed", repoHref)
+	}
+	return dists.Results[0].BaseURL, nil
+}
+
+// UploadAndDistributeCommit uploads the tarball of an ostree commit and
+// imports it into the repository `repoName`. The repository is created and
+// distributed under `basePath` if it doesn't exist. Either of the two
+// defaults to the other one. Returns the URL of the distributed repository.
+func (cl *Client) UploadAndDistributeCommit(ctx context.Context, archivePath, repoName, basePath string) (string, error) {
+	if repoName == "" {
+		repoName = basePath
+	}
+	if basePath == "" {
+		basePath = repoName
+	}
+	if repoName == "" {
+		return "", errors.New("either the repository name or the basepath must be set")
+	}
+
+	artifactHref, err := cl.UploadFile(ctx, archivePath)
+	if err != nil {
+		return "", err
+	}
+
+	repoHref, err := cl.GetOSTreeRepositoryByName(ctx, repoName)
+	if err != nil {
+		return "", err
+	}
+	if repoHref == "" {
+		repoHref, err = cl.CreateOSTreeRepository(ctx, repoName)
+		if err != nil {
+			return "", err
+		}
+		taskHref, err := cl.DistributeOSTreeRepo(ctx, basePath, repoName, repoHref)
+		if err != nil {
+			return "", err
+		}
+		if err := cl.WaitForTask(ctx, taskHref); err != nil {
+			return "", fmt.Errorf("cannot distribute repository %s: %v", repoName, err)
+		}
+	}
+
+	taskHref, err := cl.ImportCommit(ctx, artifactHref, repoHref)
+	if err != nil {
+		return "", err
+	}
+	if err := cl.WaitForTask(ctx, taskHref); err != nil {
+		return "", fmt.Errorf("cannot import the commit into %s: %v", repoName, err)
+	}
+
+	return cl.GetDistributionURLForOSTreeRepo(ctx, repoHref)
+}
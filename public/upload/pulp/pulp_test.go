@@ -0,0 +1,234 @@
+package pulp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ondrejbudai/osbuild-composer-public/public/upload/pulp"
+)
+
+// fakePulp implements the parts of the Pulp API with the OSTree plugin
+// which are used to upload and distribute a commit. Tasks complete on the
+// second poll.
+type fakePulp struct {
+	t   *testing.T
+	url string
+
+	mu            sync.Mutex
+	artifacts     map[string]string
+	repos         map[string]string // name -> href
+	distributions map[string]map[string]string
+	imports       map[string][]string // repo href -> artifact hrefs
+	tasks         map[string]int
+	failImport    bool
+}
+
+func newFakePulp(t *testing.T) *fakePulp {
+	p := &fakePulp{
+		t:             t,
+		artifacts:     map[string]string{},
+		repos:         map[string]string{},
+		distributions: map[string]map[string]string{},
+		imports:       map[string][]string{},
+		tasks:         map[string]int{},
+	}
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	p.url = srv.URL
+	return p
+}
+
+func (p *fakePulp) newTask() string {
+	href := fmt.Sprintf("/pulp/api/v3/tasks/%d/", len(p.tasks))
+	p.tasks[href] = 0
+	return href
+}
+
+func (p *fakePulp) reply(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(p.t, json.NewEncoder(w).Encode(v))
+}
+
+func (p *fakePulp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	if !ok || username != "admin" || password != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/pulp/api/v3/artifacts/":
+		f, _, err := r.FormFile("file")
+		require.NoError(p.t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(p.t, err)
+		href := fmt.Sprintf("/pulp/api/v3/artifacts/%d/", len(p.artifacts))
+		p.artifacts[href] = string(content)
+		p.reply(w, http.StatusCreated, map[string]string{"pulp_href": href})
+
+	case r.Method == http.MethodGet && r.URL.Path == "/pulp/api/v3/repositories/ostree/ostree/":
+		results := []map[string]string{}
+		if href, ok := p.repos[r.URL.Query().Get("name")]; ok {
+			results = append(results, map[string]string{"pulp_href": href})
+		}
+		p.reply(w, http.StatusOK, map[string]interface{}{"count": len(results), "results": results})
+
+	case r.Method == http.MethodPost && r.URL.Path == "/pulp/api/v3/repositories/ostree/ostree/":
+		var body map[string]string
+		require.NoError(p.t, json.NewDecoder(r.Body).Decode(&body))
+		href := fmt.Sprintf("/pulp/api/v3/repositories/ostree/ostree/%d/", len(p.repos))
+		p.repos[body["name"]] = href
+		p.reply(w, http.StatusCreated, map[string]string{"pulp_href": href})
+
+	case r.Method == http.MethodPost && r.URL.Path == "/pulp/api/v3/distributions/ostree/ostree/":
+		var body map[string]string
+		require.NoError(p.t, json.NewDecoder(r.Body).Decode(&body))
+		p.distributions[body["repository"]] = body
+		p.reply(w, http.StatusAccepted, map[string]string{"task": p.newTask()})
+
+	case r.Method == http.MethodGet && r.URL.Path == "/pulp/api/v3/distributions/ostree/ostree/":
+		results := []map[string]string{}
+		if dist, ok := p.distributions[r.URL.Query().Get("repository")]; ok {
+			results = append(results, map[string]string{
+				"base_url": p.url + "/pulp/content/" + dist["base_path"] + "/",
+			})
+		}
+		p.reply(w, http.StatusOK, map[string]interface{}{"count": len(results), "results": results})
+
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/import_all/"):
+		var body map[string]string
+		require.NoError(p.t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(p.t, "repo", body["repository_name"])
+		repoHref := strings.TrimSuffix(r.URL.Path, "import_all/")
+		p.imports[repoHref] = append(p.imports[repoHref], body["artifact"])
+		task := p.newTask()
+		if p.failImport {
+			p.tasks[task] = -1
+		}
+		p.reply(w, http.StatusAccepted, map[string]string{"task": task})
+
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/pulp/api/v3/tasks/"):
+		polls, ok := p.tasks[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		state := "running"
+		switch {
+		case polls < 0:
+			state = "failed"
+		case polls > 0:
+			state = "completed"
+		}
+		if polls >= 0 {
+			p.tasks[r.URL.Path] = polls + 1
+		}
+		p.reply(w, http.StatusOK, map[string]interface{}{
+			"state": state,
+			"error": map[string]string{"description": "import failed"},
+		})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func writeCommit(t *testing.T) string {
+	archive := filepath.Join(t.TempDir(), "commit.tar")
+	require.NoError(t, os.WriteFile(archive, []byte("ostree commit"), 0600))
+	return archive
+}
+
+func newTestClient(t *testing.T, p *fakePulp) *pulp.Client {
+	pulp.TaskPollInterval = time.Millisecond
+	client, err := pulp.NewClient(p.url, &pulp.Credentials{Username: "admin", Password: "secret"})
+	require.NoError(t, err)
+	return client
+}
+
+func TestUploadAndDistributeCommit(t *testing.T) {
+	p := newFakePulp(t)
+	client := newTestClient(t, p)
+	archive := writeCommit(t)
+	ctx := context.Background()
+
+	// the repository is created and distributed on the first upload
+	repoURL, err := client.UploadAndDistributeCommit(ctx, archive, "edge", "edge/fedora")
+	require.NoError(t, err)
+	require.Equal(t, p.url+"/pulp/content/edge/fedora/", repoURL)
+	require.Len(t, p.repos, 1)
+	repoHref := p.repos["edge"]
+	require.Equal(t, "edge", p.distributions[repoHref]["name"])
+	require.Len(t, p.imports[repoHref], 1)
+	require.Equal(t, "ostree commit", p.artifacts[p.imports[repoHref][0]])
+
+	// and reused on the following ones
+	repoURL, err = client.UploadAndDistributeCommit(ctx, archive, "edge", "")
+	require.NoError(t, err)
+	require.Equal(t, p.url+"/pulp/content/edge/fedora/", repoURL)
+	require.Len(t, p.repos, 1)
+	require.Len(t, p.distributions, 1)
+	require.Len(t, p.imports[repoHref], 2)
+}
+
+func TestUploadAndDistributeCommitBasePathOnly(t *testing.T) {
+	p := newFakePulp(t)
+	client := newTestClient(t, p)
+
+	repoURL, err := client.UploadAndDistributeCommit(context.Background(), writeCommit(t), "", "iot")
+	require.NoError(t, err)
+	require.Equal(t, p.url+"/pulp/content/iot/", repoURL)
+	require.Contains(t, p.repos, "iot")
+}
+
+func TestUploadAndDistributeCommitErrors(t *testing.T) {
+	p := newFakePulp(t)
+	client := newTestClient(t, p)
+	archive := writeCommit(t)
+	ctx := context.Background()
+
+	_, err := client.UploadAndDistributeCommit(ctx, archive, "", "")
+	require.Error(t, err)
+
+	_, err = client.UploadAndDistributeCommit(ctx, filepath.Join(t.TempDir(), "missing.tar"), "edge", "")
+	require.Error(t, err)
+
+	p.failImport = true
+	_, err = client.UploadAndDistributeCommit(ctx, archive, "edge", "")
+	require.ErrorContains(t, err, "import failed")
+
+	unauthorized, err := pulp.NewClient(p.url, nil)
+	require.NoError(t, err)
+	_, err = unauthorized.UploadAndDistributeCommit(ctx, archive, "edge", "")
+	require.ErrorContains(t, err, "401")
+
+	_, err = pulp.NewClient("", nil)
+	require.Error(t, err)
+}
+
+func TestReadCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pulp-creds")
+	require.NoError(t, os.WriteFile(path, []byte(`{"username": "admin", "password": "secret"}`), 0600))
+	creds, err := pulp.ReadCredentials(path)
+	require.NoError(t, err)
+	require.Equal(t, &pulp.Credentials{Username: "admin", Password: "secret"}, creds)
+
+	_, err = pulp.ReadCredentials(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
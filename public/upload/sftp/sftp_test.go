@@ -0,0 +1,394 @@
+package sftp
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// packetSize is the size of the writes of the client
+const packetSize = 32 * 1024
+
+// fakeServer is an SSH server with an sftp subsystem serving files from a
+// directory.
+type fakeServer struct {
+	t       *testing.T
+	root    string
+	addr    string
+	hostKey ssh.Signer
+
+	mu sync.Mutex
+	// the connection is dropped after this many writes if not 0
+	dropAfterWrites int
+	writeOffsets    []int64
+}
+
+func newFakeServer(t *testing.T, clientKey ssh.PublicKey) *fakeServer {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostKey, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+
+	s := &fakeServer{
+		t:       t,
+		root:    t.TempDir(),
+		hostKey: hostKey,
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "osbuild" && bytes.Equal(key.Marshal(), clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	s.addr = l.Addr().String()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serveConn(conn, config)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) knownHosts() string {
+	return knownhosts.Line([]string{s.addr}, s.hostKey.PublicKey())
+}
+
+func (s *fakeServer) serveConn(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				_ = req.Reply(ok, nil)
+				if ok {
+					h := &fakeHandlers{s: s, conn: conn}
+					server := sftp.NewRequestServer(channel, sftp.Handlers{
+						FileGet:  h,
+						FilePut:  h,
+						FileCmd:  h,
+						FileList: h,
+					})
+					go func() {
+						_ = server.Serve()
+						server.Close()
+						conn.Close()
+					}()
+				}
+			}
+		}()
+	}
+}
+
+// fakeHandlers implement the requests of the client on the files of the
+// server, the connection is dropped after the configured number of writes
+type fakeHandlers struct {
+	s    *fakeServer
+	conn net.Conn
+}
+
+func (h *fakeHandlers) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	return os.Open(h.s.path(r.Filepath))
+}
+
+func (h *fakeHandlers) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	flags := os.O_WRONLY | os.O_CREATE
+	if r.Pflags().Trunc {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(h.s.path(r.Filepath), flags, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingWriter{File: f, h: h}, nil
+}
+
+func (h *fakeHandlers) Filecmd(r *sftp.Request) error {
+	switch r.Method {
+	case "Rename":
+		// plain SFTP renames don't replace the target
+		if _, err := os.Stat(h.s.path(r.Target)); err == nil {
+			return fs.ErrExist
+		}
+		return os.Rename(h.s.path(r.Filepath), h.s.path(r.Target))
+	case "Remove":
+		return os.Remove(h.s.path(r.Filepath))
+	case "Rmdir":
+		// the client tries this when removing a file fails
+		return syscall.Rmdir(h.s.path(r.Filepath))
+	}
+	h.s.t.Errorf("unexpected request %s", r.Method)
+	return sftp.ErrSSHFxOpUnsupported
+}
+
+func (h *fakeHandlers) PosixRename(r *sftp.Request) error {
+	return os.Rename(h.s.path(r.Filepath), h.s.path(r.Target))
+}
+
+func (h *fakeHandlers) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	if r.Method != "Stat" {
+		h.s.t.Errorf("unexpected request %s", r.Method)
+		return nil, sftp.ErrSSHFxOpUnsupported
+	}
+	info, err := os.Stat(h.s.path(r.Filepath))
+	if err != nil {
+		return nil, err
+	}
+	return listerAt{info}, nil
+}
+
+type listerAt []fs.FileInfo
+
+func (l listerAt) ListAt(ls []fs.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+	n := copy(ls, l[offset:])
+	if n < len(ls) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+type recordingWriter struct {
+	*os.File
+	h *fakeHandlers
+}
+
+func (w *recordingWriter) WriteAt(p []byte, offset int64) (int, error) {
+	n, err := w.File.WriteAt(p, offset)
+
+	s := w.h.s
+	s.mu.Lock()
+	s.writeOffsets = append(s.writeOffsets, offset)
+	drop := s.dropAfterWrites > 0 && len(s.writeOffsets) >= s.dropAfterWrites
+	if drop {
+		s.dropAfterWrites = 0
+	}
+	s.mu.Unlock()
+	if drop {
+		w.h.conn.Close()
+	}
+	return n, err
+}
+
+// dropAfter makes the server drop the connection after `writes` writes and
+// resets the offsets of the writes
+func (s *fakeServer) dropAfter(writes int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropAfterWrites = writes
+	s.writeOffsets = nil
+}
+
+func (s *fakeServer) offsets() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writeOffsets
+}
+
+func (s *fakeServer) path(p string) string {
+	return filepath.Join(s.root, p)
+}
+
+func newClientKey(t *testing.T) ssh.Signer {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(priv)
+	require.NoError(t, err)
+	return signer
+}
+
+func newTestUploader(t *testing.T, s *fakeServer, key ssh.Signer) *Uploader {
+	callback, err := HostKeyCallback(s.knownHosts())
+	require.NoError(t, err)
+	RetryInterval = time.Millisecond
+	return &Uploader{
+		Address: s.addr,
+		Config: &ssh.ClientConfig{
+			User:            "osbuild",
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(key)},
+			HostKeyCallback: callback,
+		},
+	}
+}
+
+// writeImage writes a file spanning several writes
+func writeImage(t *testing.T) (string, []byte) {
+	content := make([]byte, 5*packetSize+123)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "disk.qcow2")
+	require.NoError(t, os.WriteFile(path, content, 0600))
+	return path, content
+}
+
+func TestUpload(t *testing.T) {
+	for _, posixRename := range []bool{true, false} {
+		t.Run(fmt.Sprintf("posix-rename=%v", posixRename), func(t *testing.T) {
+			key := newClientKey(t)
+			s := newFakeServer(t, key.PublicKey())
+			if !posixRename {
+				require.NoError(t, sftp.SetSFTPExtensions("statvfs@openssh.com"))
+				t.Cleanup(func() {
+					require.NoError(t, sftp.SetSFTPExtensions("hardlink@openssh.com", "posix-rename@openssh.com", "statvfs@openssh.com"))
+				})
+			}
+			u := newTestUploader(t, s, key)
+			image, content := writeImage(t)
+
+			checksum, err := u.Upload(context.Background(), image, "disk.qcow2")
+			require.NoError(t, err)
+			require.Equal(t, fmt.Sprintf("%x", sha256.Sum256(content)), checksum)
+			uploaded, err := os.ReadFile(s.path("disk.qcow2"))
+			require.NoError(t, err)
+			require.Equal(t, content, uploaded)
+			require.NoFileExists(t, s.path("disk.qcow2.part"))
+
+			// uploading again replaces the file
+			_, err = u.Upload(context.Background(), image, "disk.qcow2")
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUploadResume(t *testing.T) {
+	key := newClientKey(t)
+	s := newFakeServer(t, key.PublicKey())
+	u := newTestUploader(t, s, key)
+	image, content := writeImage(t)
+
+	// the connection breaks after the second write
+	s.dropAfter(2)
+	_, err := u.Upload(context.Background(), image, "disk.qcow2")
+	require.Error(t, err)
+	part, err := os.ReadFile(s.path("disk.qcow2.part"))
+	require.NoError(t, err)
+	require.Equal(t, content[:2*packetSize], part)
+
+	// the next upload continues where the last one ended
+	s.dropAfter(0)
+	_, err = u.Upload(context.Background(), image, "disk.qcow2")
+	require.NoError(t, err)
+	require.Equal(t, int64(2*packetSize), s.offsets()[0])
+	uploaded, err := os.ReadFile(s.path("disk.qcow2"))
+	require.NoError(t, err)
+	require.Equal(t, content, uploaded)
+
+	// and so does an upload which is retried
+	s.dropAfter(3)
+	require.NoError(t, os.Remove(s.path("disk.qcow2")))
+	u.Retries = 1
+	_, err = u.Upload(context.Background(), image, "disk.qcow2")
+	require.NoError(t, err)
+	require.Equal(t, []int64{0, packetSize, 2 * packetSize, 3 * packetSize, 4 * packetSize, 5 * packetSize}, s.offsets())
+	uploaded, err = os.ReadFile(s.path("disk.qcow2"))
+	require.NoError(t, err)
+	require.Equal(t, content, uploaded)
+}
+
+func TestUploadMismatchingPart(t *testing.T) {
+	key := newClientKey(t)
+	s := newFakeServer(t, key.PublicKey())
+	u := newTestUploader(t, s, key)
+	image, content := writeImage(t)
+
+	require.NoError(t, os.WriteFile(s.path("disk.qcow2.part"), []byte("something else"), 0600))
+	_, err := u.Upload(context.Background(), image, "disk.qcow2")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), s.offsets()[0])
+	uploaded, err := os.ReadFile(s.path("disk.qcow2"))
+	require.NoError(t, err)
+	require.Equal(t, content, uploaded)
+}
+
+func TestUploadErrors(t *testing.T) {
+	key := newClientKey(t)
+	s := newFakeServer(t, key.PublicKey())
+	image, _ := writeImage(t)
+
+	// unknown host key
+	u := newTestUploader(t, s, key)
+	u.Retries = 3
+	other := newFakeServer(t, key.PublicKey())
+	u.Config.HostKeyCallback, _ = HostKeyCallback(other.knownHosts())
+	_, err := u.Upload(context.Background(), image, "disk.qcow2")
+	var keyErr *knownhosts.KeyError
+	require.ErrorAs(t, err, &keyErr)
+
+	// unknown client key
+	u = newTestUploader(t, s, newClientKey(t))
+	_, err = u.Upload(context.Background(), image, "disk.qcow2")
+	require.ErrorContains(t, err, "unable to authenticate")
+
+	// missing remote directory
+	u = newTestUploader(t, s, key)
+	u.Retries = 3
+	_, err = u.Upload(context.Background(), image, "missing/disk.qcow2")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = HostKeyCallback("")
+	require.Error(t, err)
+}
+
+func TestReadPrivateKey(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0600))
+
+	signer, err := ReadPrivateKey(path)
+	require.NoError(t, err)
+	require.Equal(t, ssh.KeyAlgoED25519, signer.PublicKey().Type())
+
+	_, err = ReadPrivateKey(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
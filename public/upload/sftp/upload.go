@@ -0,0 +1,247 @@
+// Package sftp uploads files to SFTP servers, resuming interrupted uploads.
+package sftp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// RetryInterval is the time between two attempts to upload a file.
+var RetryInterval = 10 * time.Second
+
+// Uploader uploads files to a server over SFTP.
+type Uploader struct {
+	// Address of the server, host:port
+	Address string
+	Config  *ssh.ClientConfig
+
+	// Retries is the number of times an interrupted upload is resumed
+	Retries int
+}
+
+// ReadPrivateKey reads an unencrypted private key in any of the formats
+// OpenSSH supports.
+func ReadPrivateKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the private key: %v", err)
+	}
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the private key %s: %v", path, err)
+	}
+	return signer, nil
+}
+
+// HostKeyCallback returns a callback which only accepts the host keys listed
+// in `knownHosts`, in the format of an OpenSSH known_hosts file, and in the
+// known_hosts files `files`. Unknown hosts are always rejected.
+func HostKeyCallback(knownHosts string, files ...string) (ssh.HostKeyCallback, error) {
+	if knownHosts != "" {
+		// knownhosts only reads files, the file isn't needed after
+		// the callback is created
+		f, err := os.CreateTemp("", "known_hosts")
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(f.Name())
+		_, err = f.WriteString(knownHosts + "\n")
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f.Name())
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no known host keys to verify the server with")
+	}
+
+	callback, err := knownhosts.New(files...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the known host keys: %v", err)
+	}
+	return callback, nil
+}
+
+// Upload uploads the file to `remotePath` and returns its SHA256 digest. The
+// file is uploaded to `remotePath` with the suffix ".part" first, and renamed
+// when it's complete. An upload which was interrupted, in this call or by an
+// earlier one, resumes where it ended. The uploaded part is read back and
+// compared to the file first, a part which doesn't match is uploaded again.
+func (u *Uploader) Upload(ctx context.Context, localPath, remotePath string) (string, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s: %v", localPath, err)
+	}
+	checksum := fmt.Sprintf("%x", h.Sum(nil))
+
+	for attempt := 0; ; attempt++ {
+		err = u.upload(ctx, f, size, remotePath)
+		if err == nil {
+			return checksum, nil
+		}
+
+		if attempt >= u.Retries || permanent(err) || ctx.Err() != nil {
+			return "", fmt.Errorf("cannot upload %s to %s:%s: %w", localPath, u.Address, remotePath, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(RetryInterval):
+		}
+	}
+}
+
+// permanent returns true for errors which won't go away by trying again,
+// errors reported by the server and unknown host keys
+func permanent(err error) bool {
+	var statusErr *sftp.StatusError
+	var keyErr *knownhosts.KeyError
+	return errors.As(err, &statusErr) || errors.As(err, &keyErr) ||
+		errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission)
+}
+
+func (u *Uploader) dial(ctx context.Context) (*ssh.Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", u.Address)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, u.Address, u.Config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(c, chans, reqs), nil
+}
+
+func (u *Uploader) upload(ctx context.Context, f *os.File, size int64, remotePath string) error {
+	conn, err := u.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// closing the connection aborts any request in flight
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	partPath := remotePath + ".part"
+	offset, err := uploadedPart(client, partPath, f, size)
+	if err != nil {
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	part, err := client.OpenFile(partPath, flags)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+	if _, err := part.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	// the writes are sequential, an interrupted upload leaves a part
+	// without holes behind
+	if _, err := part.ReadFrom(io.NewSectionReader(f, offset, size-offset)); err != nil {
+		return err
+	}
+	if err := part.Close(); err != nil {
+		return err
+	}
+
+	info, err := client.Stat(partPath)
+	if err != nil {
+		return err
+	}
+	if info.Size() != size {
+		return fmt.Errorf("uploaded %d bytes of %d", info.Size(), size)
+	}
+	return rename(client, partPath, remotePath)
+}
+
+// rename moves `oldPath` to `newPath`, replacing it if it exists. Plain SFTP
+// renames fail if the target exists, the OpenSSH extension replaces it
+// atomically.
+func rename(client *sftp.Client, oldPath, newPath string) error {
+	if _, ok := client.HasExtension("posix-rename@openssh.com"); ok {
+		return client.PosixRename(oldPath, newPath)
+	}
+	err := client.Remove(newPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return client.Rename(oldPath, newPath)
+}
+
+// uploadedPart returns the number of bytes of the file which were uploaded to
+// `partPath` already, 0 if the part doesn't exist or doesn't match the file
+func uploadedPart(client *sftp.Client, partPath string, f *os.File, size int64) (int64, error) {
+	info, err := client.Stat(partPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	partSize := info.Size()
+	if partSize > size {
+		return 0, nil
+	}
+
+	part, err := client.Open(partPath)
+	if err != nil {
+		return 0, err
+	}
+	defer part.Close()
+
+	remote := make([]byte, 32*1024)
+	local := make([]byte, len(remote))
+	var offset int64
+	for offset < partSize {
+		n, err := io.ReadFull(part, remote)
+		if err == io.EOF {
+			break
+		} else if err != nil && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+		if _, err := f.ReadAt(local[:n], offset); err != nil && err != io.EOF {
+			return 0, err
+		}
+		if !bytes.Equal(local[:n], remote[:n]) {
+			return 0, nil
+		}
+		offset += int64(n)
+	}
+	return offset, nil
+}
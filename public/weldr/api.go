@@ -2311,7 +2311,7 @@ func (api *API) resolveContainers(sourceSpecs map[string][]container.SourceSpec,
 		}
 	}
 
-	jobId, err := api.workers.EnqueueContainerResolveJob(&job, nil, "")
+	jobId, err := api.workers.EnqueueContainerResolveJob(&job, nil, "", jobqueue.EnqueueOptions{Priority: worker.JobPriorityInteractive})
 	if err != nil {
 		return specs, err
 	}
@@ -2637,7 +2637,7 @@ func (api *API) composeHandler(writer http.ResponseWriter, request *http.Request
 				Payload: manifest.PayloadPipelines(),
 			},
 			ImageBootMode: imageType.BootMode().String(),
-		}, "")
+		}, "", jobqueue.EnqueueOptions{})
 		if err == nil {
 			err = api.store.PushCompose(jobID, mf, imageType, bp, size, targets, weldrPackages)
 		}
@@ -1128,6 +1128,53 @@ func TestCompose(t *testing.T) {
 		Packages: weldrtypes.RPMMDPackageListToDepsolvedPackageInfoList(depsolvednf_mock.BaseDepsolveResult(testRepoID)),
 	}
 
+	expectedComposeLocalAndSFTP := &weldrtypes.Compose{
+		Blueprint: &blueprint.Blueprint{
+			Name:           "test",
+			Version:        "0.0.0",
+			Packages:       []blueprint.Package{},
+			Modules:        []blueprint.Package{},
+			EnabledModules: []blueprint.EnabledModule{},
+			Groups:         []blueprint.Group{},
+			Customizations: nil,
+		},
+		ImageBuild: weldrtypes.ImageBuild{
+			QueueStatus: common.IBWaiting,
+			ImageType:   imgType,
+			Size:        imgType.Size(0),
+			Manifest:    mf,
+			Targets: []*target.Target{
+				{
+					ImageName: imgType.Filename(),
+					OsbuildArtifact: target.OsbuildArtifact{
+						ExportFilename: imgType.Filename(),
+						ExportName:     imgType.Exports()[0],
+					},
+					Name:    target.TargetNameWorkerServer,
+					Options: &target.WorkerServerTargetOptions{},
+				},
+				{
+					Name:      target.TargetNameSFTP,
+					Status:    common.IBWaiting,
+					ImageName: "test_upload",
+					OsbuildArtifact: target.OsbuildArtifact{
+						ExportFilename: imgType.Filename(),
+						ExportName:     imgType.Exports()[0],
+					},
+					Options: &target.SFTPTargetOptions{
+						Host:       "files.example.com",
+						Port:       2222,
+						Username:   "images",
+						Path:       "/srv/images",
+						KnownHosts: "files.example.com ssh-ed25519 AAAA",
+						KeyRef:     "fileserver",
+					},
+				},
+			},
+		},
+		Packages: weldrtypes.RPMMDPackageListToDepsolvedPackageInfoList(depsolvednf_mock.BaseDepsolveResult(testRepoID)),
+	}
+
 	expectedComposeOSTree := &weldrtypes.Compose{
 		Blueprint: &blueprint.Blueprint{
 			Name:           "test",
@@ -1287,6 +1334,17 @@ func TestCompose(t *testing.T) {
 			[]string{"build_id", "warnings"},
 			getSolverFn,
 		},
+		"sftp": {
+			false,
+			"POST",
+			"/api/v1/compose",
+			fmt.Sprintf(`{"blueprint_name": "test","compose_type":"%s","branch":"master","upload":{"image_name":"test_upload","provider":"sftp","settings":{"host":"files.example.com","port":2222,"username":"images","path":"/srv/images","known_hosts":"files.example.com ssh-ed25519 AAAA","key_ref":"fileserver"}}}`, test_distro.TestImageTypeName),
+			http.StatusOK,
+			`{"status": true}`,
+			expectedComposeLocalAndSFTP,
+			[]string{"build_id", "warnings"},
+			getSolverFn,
+		},
 		"good-distro": {
 			false,
 			"POST",
@@ -1451,7 +1509,7 @@ func TestCompose(t *testing.T) {
 			api, sf := createTestWeldrAPI(t.TempDir(), test_distro.TestDistro1Name, test_distro.TestArchName, c.GetSolverFn, rpmmd_mock.NoComposesFixture, nil)
 			t.Cleanup(sf.Cleanup)
 
-			_, err = api.workers.RegisterWorker("", arch.Name())
+			_, err = api.workers.RegisterWorker("", arch.Name(), nil)
 			require.NoError(t, err)
 			test.TestRoute(t, api, c.External, c.Method, c.Path, c.Body, c.ExpectedStatus, c.ExpectedJSON, c.IgnoreFields...)
 
@@ -2706,7 +2764,7 @@ func TestComposePOST_ImageTypeDenylist(t *testing.T) {
 		t.Run(fmt.Sprintf("case %d", idx), func(t *testing.T) {
 			api, sf := createTestWeldrAPI(t.TempDir(), distro2.Name(), arch.Name(), c.GetSolverFn, rpmmd_mock.NoComposesFixture, c.imageTypeDenylist)
 			t.Cleanup(sf.Cleanup)
-			_, err = api.workers.RegisterWorker("", arch.Name())
+			_, err = api.workers.RegisterWorker("", arch.Name(), nil)
 			require.NoError(t, err)
 			test.TestRoute(t, api, true, "POST", c.Path, c.Body, c.ExpectedStatus, c.ExpectedJSON, c.IgnoreFields...)
 
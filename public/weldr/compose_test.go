@@ -12,6 +12,7 @@ import (
 	"github.com/osbuild/image-builder/pkg/distro"
 	"github.com/osbuild/image-builder/pkg/distro/test_distro"
 	rpmmd_mock "github.com/ondrejbudai/osbuild-composer-public/public/mocks/rpmmd"
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker/clienterrors"
 )
@@ -43,9 +44,9 @@ func TestComposeStatusFromLegacyError(t *testing.T) {
 		t.Fatalf("error serializing osbuild manifest: %v", err)
 	}
 
-	_, err = api.workers.RegisterWorker("", arch.Name())
+	_, err = api.workers.RegisterWorker("", arch.Name(), nil)
 	require.NoError(t, err)
-	jobId, err := api.workers.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := api.workers.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	j, token, _, _, _, err := api.workers.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -99,9 +100,9 @@ func TestComposeStatusFromJobError(t *testing.T) {
 		t.Fatalf("error serializing osbuild manifest: %v", err)
 	}
 
-	_, err = api.workers.RegisterWorker("", arch.Name())
+	_, err = api.workers.RegisterWorker("", arch.Name(), nil)
 	require.NoError(t, err)
-	jobId, err := api.workers.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := api.workers.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	j, token, _, _, _, err := api.workers.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -110,6 +110,20 @@ type containerUploadSettings struct {
 
 func (containerUploadSettings) isUploadSettings() {}
 
+type sftpUploadSettings struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Path     string `json:"path"`
+
+	// host keys of the server in the known_hosts format
+	KnownHosts string `json:"known_hosts,omitempty"`
+	// name of a private key in the worker's configuration
+	KeyRef string `json:"key_ref,omitempty"`
+}
+
+func (sftpUploadSettings) isUploadSettings() {}
+
 type uploadRequest struct {
 	Provider  string         `json:"provider"`
 	ImageName string         `json:"image_name"`
@@ -149,6 +163,8 @@ func (u *uploadRequest) UnmarshalJSON(data []byte) error {
 		settings = new(awsS3UploadSettings)
 	case "container":
 		settings = new(containerUploadSettings)
+	case "sftp":
+		settings = new(sftpUploadSettings)
 	default:
 		return errors.New("unexpected provider name")
 	}
@@ -237,6 +253,17 @@ func targetsToUploadResponses(targets []*target.Target, state ComposeState) []up
 				// AccessKeyID and SecretAccessKey are intentionally not included.
 			}
 			uploads = append(uploads, upload)
+		case *target.SFTPTargetOptions:
+			upload.ProviderName = "sftp"
+			upload.Settings = &sftpUploadSettings{
+				Host:       options.Host,
+				Port:       options.Port,
+				Username:   options.Username,
+				Path:       options.Path,
+				KnownHosts: options.KnownHosts,
+				KeyRef:     options.KeyRef,
+			}
+			uploads = append(uploads, upload)
 		}
 	}
 
@@ -368,6 +395,16 @@ func uploadRequestToTarget(u uploadRequest, imageType distro.ImageType) *target.
 
 			TlsVerify: options.TlsVerify,
 		}
+	case *sftpUploadSettings:
+		t.Name = target.TargetNameSFTP
+		t.Options = &target.SFTPTargetOptions{
+			Host:       options.Host,
+			Port:       options.Port,
+			Username:   options.Username,
+			Path:       options.Path,
+			KnownHosts: options.KnownHosts,
+			KeyRef:     options.KeyRef,
+		}
 	}
 
 	return &t
@@ -24,6 +24,20 @@ const (
 	BearerScopes = "Bearer.Scopes"
 )
 
+// ArtifactUploadStatus defines model for ArtifactUploadStatus.
+type ArtifactUploadStatus struct {
+	Href   string `json:"href"`
+	Id     string `json:"id"`
+	Kind   string `json:"kind"`
+	Offset int64  `json:"offset"`
+}
+
+// CompleteArtifactUploadRequest defines model for CompleteArtifactUploadRequest.
+type CompleteArtifactUploadRequest struct {
+	Sha256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
 // Error defines model for Error.
 type Error struct {
 	Code string `json:"code"`
@@ -52,9 +66,20 @@ type ObjectReference struct {
 	Kind string `json:"kind"`
 }
 
+// PostWorkerStatusResponse defines model for PostWorkerStatusResponse.
+type PostWorkerStatusResponse struct {
+	// Draining The worker was asked to drain, it should stop requesting jobs,
+	// finish or requeue the jobs it runs, and deregister.
+	Draining bool `json:"draining"`
+}
+
 // PostWorkersRequest defines model for PostWorkersRequest.
 type PostWorkersRequest struct {
 	Arch string `json:"arch"`
+
+	// Labels Capability labels of the worker, e.g. "nested-virt". Jobs which
+	// require labels are only handed to workers which have all of them.
+	Labels *[]string `json:"labels,omitempty"`
 }
 
 // PostWorkersResponse defines model for PostWorkersResponse.
@@ -74,16 +99,24 @@ type RequestJobRequest struct {
 
 // RequestJobResponse defines model for RequestJobResponse.
 type RequestJobResponse struct {
-	Args             *json.RawMessage   `json:"args,omitempty"`
-	ArtifactLocation string             `json:"artifact_location"`
-	DynamicArgs      *[]json.RawMessage `json:"dynamic_args,omitempty"`
-	Href             string             `json:"href"`
-	Id               string             `json:"id"`
-	Kind             string             `json:"kind"`
-	Location         string             `json:"location"`
-	Type             string             `json:"type"`
+	Args             *json.RawMessage `json:"args,omitempty"`
+	ArtifactLocation string           `json:"artifact_location"`
+
+	// ArtifactUploadLocation Location of the chunked, resumable artifact uploads. Servers
+	// which don't support them leave it out, workers fall back to
+	// uploading to artifact_location in a single request then.
+	ArtifactUploadLocation *string            `json:"artifact_upload_location,omitempty"`
+	DynamicArgs            *[]json.RawMessage `json:"dynamic_args,omitempty"`
+	Href                   string             `json:"href"`
+	Id                     string             `json:"id"`
+	Kind                   string             `json:"kind"`
+	Location               string             `json:"location"`
+	Type                   string             `json:"type"`
 }
 
+// RequeueJobResponse defines model for RequeueJobResponse.
+type RequeueJobResponse = ObjectReference
+
 // StatusResponse defines model for StatusResponse.
 type StatusResponse struct {
 	Href   string `json:"href"`
@@ -110,12 +143,21 @@ type UpdateJobResult struct {
 	Result json.RawMessage `json:"result"`
 }
 
+// UploadArtifactChunkParams defines parameters for UploadArtifactChunk.
+type UploadArtifactChunkParams struct {
+	Offset int64  `form:"offset" json:"offset"`
+	Sha256 string `form:"sha256" json:"sha256"`
+}
+
 // RequestJobJSONRequestBody defines body for RequestJob for application/json ContentType.
 type RequestJobJSONRequestBody = RequestJobRequest
 
 // UpdateJobJSONRequestBody defines body for UpdateJob for application/json ContentType.
 type UpdateJobJSONRequestBody = UpdateJobRequest
 
+// CompleteArtifactUploadJSONRequestBody defines body for CompleteArtifactUpload for application/json ContentType.
+type CompleteArtifactUploadJSONRequestBody = CompleteArtifactUploadRequest
+
 // PostWorkersJSONRequestBody defines body for PostWorkers for application/json ContentType.
 type PostWorkersJSONRequestBody = PostWorkersRequest
 
@@ -198,6 +240,18 @@ type ServerInterface interface {
 	// Upload an artifact
 	// (PUT /jobs/{token}/artifacts/{name})
 	UploadJobArtifact(ctx echo.Context, token string, name string) error
+	// Hand a running job back
+	// (POST /jobs/{token}/requeue)
+	RequeueJob(ctx echo.Context, token string) error
+	// Get the state of a chunked artifact upload
+	// (GET /jobs/{token}/uploads/{name})
+	GetArtifactUpload(ctx echo.Context, token string, name string) error
+	// Upload a chunk of an artifact
+	// (PATCH /jobs/{token}/uploads/{name})
+	UploadArtifactChunk(ctx echo.Context, token string, name string, params UploadArtifactChunkParams) error
+	// Complete a chunked artifact upload
+	// (POST /jobs/{token}/uploads/{name})
+	CompleteArtifactUpload(ctx echo.Context, token string, name string) error
 	// Get the openapi spec in json format
 	// (GET /openapi)
 	GetOpenapi(ctx echo.Context) error
@@ -207,6 +261,12 @@ type ServerInterface interface {
 	// Create a new worker
 	// (POST /workers)
 	PostWorkers(ctx echo.Context) error
+	// Deregister a worker
+	// (DELETE /workers/{worker_id})
+	DeleteWorker(ctx echo.Context, workerId openapi_types.UUID) error
+	// Drain a worker
+	// (POST /workers/{worker_id}/drain)
+	PostWorkerDrain(ctx echo.Context, workerId openapi_types.UUID) error
 	// Refresh worker status
 	// (POST /workers/{worker_id}/status)
 	PostWorkerStatus(ctx echo.Context, workerId openapi_types.UUID) error
@@ -300,6 +360,110 @@ func (w *ServerInterfaceWrapper) UploadJobArtifact(ctx echo.Context) error {
 	return err
 }
 
+// RequeueJob converts echo context to params.
+func (w *ServerInterfaceWrapper) RequeueJob(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", ctx.Param("token"), &token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RequeueJob(ctx, token)
+	return err
+}
+
+// GetArtifactUpload converts echo context to params.
+func (w *ServerInterfaceWrapper) GetArtifactUpload(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", ctx.Param("token"), &token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetArtifactUpload(ctx, token, name)
+	return err
+}
+
+// UploadArtifactChunk converts echo context to params.
+func (w *ServerInterfaceWrapper) UploadArtifactChunk(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", ctx.Param("token"), &token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UploadArtifactChunkParams
+	// ------------- Required query parameter "offset" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, true, "offset", ctx.QueryParams(), &params.Offset, runtime.BindQueryParameterOptions{Type: "integer", Format: "int64"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter offset: %s", err))
+	}
+
+	// ------------- Required query parameter "sha256" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, true, "sha256", ctx.QueryParams(), &params.Sha256, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sha256: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UploadArtifactChunk(ctx, token, name, params)
+	return err
+}
+
+// CompleteArtifactUpload converts echo context to params.
+func (w *ServerInterfaceWrapper) CompleteArtifactUpload(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "token" -------------
+	var token string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "token", ctx.Param("token"), &token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter token: %s", err))
+	}
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", ctx.Param("name"), &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: ""})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter name: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CompleteArtifactUpload(ctx, token, name)
+	return err
+}
+
 // GetOpenapi converts echo context to params.
 func (w *ServerInterfaceWrapper) GetOpenapi(ctx echo.Context) error {
 	var err error
@@ -327,6 +491,38 @@ func (w *ServerInterfaceWrapper) PostWorkers(ctx echo.Context) error {
 	return err
 }
 
+// DeleteWorker converts echo context to params.
+func (w *ServerInterfaceWrapper) DeleteWorker(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker_id" -------------
+	var workerId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "worker_id", ctx.Param("worker_id"), &workerId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.DeleteWorker(ctx, workerId)
+	return err
+}
+
+// PostWorkerDrain converts echo context to params.
+func (w *ServerInterfaceWrapper) PostWorkerDrain(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "worker_id" -------------
+	var workerId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "worker_id", ctx.Param("worker_id"), &workerId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "uuid"})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter worker_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.PostWorkerDrain(ctx, workerId)
+	return err
+}
+
 // PostWorkerStatus converts echo context to params.
 func (w *ServerInterfaceWrapper) PostWorkerStatus(ctx echo.Context) error {
 	var err error
@@ -376,9 +572,15 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.GET(baseURL+"/jobs/:token", wrapper.GetJob)
 	router.PATCH(baseURL+"/jobs/:token", wrapper.UpdateJob)
 	router.PUT(baseURL+"/jobs/:token/artifacts/:name", wrapper.UploadJobArtifact)
+	router.POST(baseURL+"/jobs/:token/requeue", wrapper.RequeueJob)
+	router.GET(baseURL+"/jobs/:token/uploads/:name", wrapper.GetArtifactUpload)
+	router.PATCH(baseURL+"/jobs/:token/uploads/:name", wrapper.UploadArtifactChunk)
+	router.POST(baseURL+"/jobs/:token/uploads/:name", wrapper.CompleteArtifactUpload)
 	router.GET(baseURL+"/openapi", wrapper.GetOpenapi)
 	router.GET(baseURL+"/status", wrapper.GetStatus)
 	router.POST(baseURL+"/workers", wrapper.PostWorkers)
+	router.DELETE(baseURL+"/workers/:worker_id", wrapper.DeleteWorker)
+	router.POST(baseURL+"/workers/:worker_id/drain", wrapper.PostWorkerDrain)
 	router.POST(baseURL+"/workers/:worker_id/status", wrapper.PostWorkerStatus)
 
 }
@@ -386,32 +588,49 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/9xYX2/bOBL/KgTvgLsDFNtpei8G9qHpLop00U2RbLEFmqAYSWOLCUWq5DCuYfi7L0hK",
-	"si3JTgLEwLZPsqXh/PnNj8MZrnimy0orVGT5dMVtVmAJ4edvxmjjf4CUlzM+/bLi/zY441P+r/Fm0bhe",
-	"Mb5M7zCjK5yhQZUhXycrXhldoSGBQWGmc/RPWlbIp9ySEWrO1wkv0VqYh2852syIioRWfMrPIbtfgMmZ",
-	"twckUiEFLdlCUMEW2tyjsezGTSZn2S/s4ewsYfjNgbTMIFiteNI35f0Br/2ryAd9qZf2P4Vv35wwmPPp",
-	"lxhMK95RvAnptvVBB3z4+nad8HdI73V6hbbSyuKLYgwqQ4nbsaVaSwTVj6ARHfaxa2vaNVUERwcg3IPs",
-	"vVD547gG9IJoEi30vUv4R23pr5j/K/zm0FLfPTBZ8bi5IPWoiZfPU2RvTcKZNiUQn3LnQviHXd4sHU5c",
-	"jUgg2POwieqChCAs7V4RPuVgDCz9/51IDnsetSf7Qd92/eUxBzMPz+8nc31S276zWo2uYPGh3q9r7x2J",
-	"GWT0VeoMYiEagCFfKihF9rVR2gL2iPYufAeNxBdPgZVvaRoKYZgq1wTkjsJvGzQ/7nstN+zepyoHwvc6",
-	"/egDAtkncrX5cBD3jtVm2RAHW6Nbu0crfAIqWyutkxRQeZJ8E95u0Ntp+cdU3m6MPd9M+/45CalX9S16",
-	"QaFmut8b/FkIy4RloNibjxdspk3bEpBmJiaPgcpZASqXyO50ake+ugqS3sTl9bkTMmdvfWYsGnbCYsHn",
-	"CX9AY6OZ07prUFAJPuVno8lowhNeARUh3jH6NsmOVyJf+/9zpL6v79B7woSy5A9dpmeMCmRhKbMVZmIm",
-	"MGfpkoUktL3ERR4Xx1bMWzVQIqGxgYy7Ri5+3dHLPXB8GjzlCVdQ+qCD/g3yZBwmddPn3cbvUFYBndOz",
-	"gYPo1q+NrAzBv5pMYmOnCFWIG6pKilhzxnd1I7VRf2g3xBjXIeOvP38+it7/H0XvOuEWM2cELUNazhEM",
-	"Gj79cusBs64swSxrFsSUbyfOLx97boa9pO0AfepKZBl4Eo9YoH5LEpZKnd1b5hQJGUXCvngAISGVOOox",
-	"anPM1mRAS+c6X74YNv0WJMLUIc/pUQzWVTMY3MXxrUEgzP2OfjV5/WLGe6dh3/IfOqRlAVt5SRiZJYM5",
-	"CMV/NM534wss3jD9qqm+PuoNw8cr0veotutkr9Q1pDxSlelMXgOhXP7Of8gKtFNmjFNKqHmEv3duDJwL",
-	"ITEHj4aBs6ACinPEbhbbHuFI1aXXoQ0Wl8kx7P3EtIlRMtjlTnfrjpvRwo5XnjphL1eOhlggNeTvdfqm",
-	"XsGfwsPweA4Nk5ej89O4qjNCOrFkEMpd0Lsq95HypyOOT7TvbxtuRNq0TfP+Yn9ZizwFp1pdaJeZUMz7",
-	"zuqbEx/oMVrR7ib/pPB7hRlhXjdyOsuc8fzql2DfiB/02WO0GZMH54Zr4btxFqXqOcawRSGyghkkZ5Rl",
-	"Fs2DyBqhoenhuvlytArZuUf4GctjDW/IWj1n7m/YY6Pp+3WFi3osDaNokzRgzom8zqQttJM5S5E5i7nn",
-	"CUjJrEutL0iKWAZS2tGN6iV365LySGftwE3rkVv5oYvX/b38DsSdXRhF+hJN/sar9u5yvbUTHz+kNlee",
-	"h06Yx251fQO1Z9ybGbQF2lBDCgRDKQI10320ngQ+eQU2bH0vDJ4qqiUSaZb7OEqhkOkHNCDljarZWCBI",
-	"Kkb1lUe9OtdMaWIxtzlbCCnDixTZPVbEyEB27/2AGXlGMxIlakcjdjG7UbnRVYV5ew2zQINtNxFGgYSR",
-	"H13DKOR1p+htERjC/DC/D9awXfisyzK0MyflkrnQ1DQu/ceyrW28Pa4EwJuN2sqEwd48DF+3fACh2H8r",
-	"o3OX+Vf/Y1GWJ9wZyae8IKrsdDyGSoz8QWALMaNRpkv/ZixKmONJ6oTM0ZxEy+OH03Bp2DkECOYewQPq",
-	"LcEcn2kkanmO2NaH2/XfAQAA///1vRKCORwAAA==",
+	"H4sIAAAAAAAC/+xab2/bONL/KgM+D7B3gGJn2919EeBepNu73e7dXovkilugDgpaGlmsJVIlR3F9gb/7",
+	"YUhKli3ZSRYxri32VRyRHM6f3wxnhrwTqalqo1GTExd3wqUFVtL/vLSkcpnS27o0MrsmSY3/LsvydS4u",
+	"3t2J/7eYiwvxf9MtjWkkMH09/4ApXWGOFnWKYpPcidqaGi0p9HRMnjsk/pUbW0kSF0Jp+uE7kQha1xj+",
+	"xQVasdkkwuLHRlnMxMW7duVNN9H4zcTmZpOIH01Vl0i4y/4VfmzQ+d12uXCFfPb9D/wr0nJklV6ITSKc",
+	"+g/+Hvb8uqSlPOQyEX+11tin1GVqMhyVoULn5MKPZehSq2pSRosL8UKmy5W0GfB+ktRclYrWsFJUwMrY",
+	"JVoHs+b8/Hn6F7h9/jwB/NjI0oFF6YwWyXAr5kcy9fcqG+UlLh0O7enPC9NN3yO8FWnc/D8h/WLmV+hq",
+	"ox0+qY6lTrHEvmxzY0qUeihBO3Wcx/29BqAsPKMjKjyg2aXS2f169drzU5Owwxg03xhH//b2Dx7f1+Qu",
+	"k5mVSvM+A2z9q8CIIVhJB9ItMQMy4FckoAhcYZoyA0emBhtcU+kFfDBzl8x0rrRyBRgbxhoEKtAP8lrb",
+	"aJeA1BlkaHGhHKGdzHqQPGSVjuHjcruDsULatBjVfynnWLqhIn6UtYyOFaaAyYE67SSAk8UEZkKjI8zO",
+	"bpWlmZjALyzpqlBpMdNRgHa9tAhGl2sopM6CVltv9QugkLcIsizjTlXQjCKs3Cjr8YO0Vq4HGvMC36ut",
+	"p3e1IFKMI134bRqP4OMg3y4d971oXB8jHmtm/uBnPFSdya4kxzkP1JPDSu+z/vQ6l3bh/346W5izuPcH",
+	"Z/TkSq5+jSF3w9yFY/V9aVIZYD6ihm5W40/fncm7LvKPONJ6Rlo0eolZAhZdU8l5idASg0DMTeAa7S1a",
+	"N9MB85nR3xC4pq6NJY96KJH9QBGYhpLORXJ2jLlMl0BmpgM5jjtkYCAXKA0SnNKLEtsYxbT1TqzZipyt",
+	"taxU+r7VY4eRexS6j5ijeg0fHoIk0aM0ZrUj3tHgHsQ+m8NpeCQ9Ffpdl94eZzPOG9fe2zqTxMp7w/qW",
+	"5VB39XbgKCz2dm2Xjemk27QXz4zGB2ilt9I1JXmtPGh+K96u0J8lYvZlHPBmu++PMUhcNdyRJyqdm7Gk",
+	"SDlQDqSGyzevIDe2C0tkugDDaQ0f7WXIdyYiEaSo5C1eX79oVJkBFzjGoYUzCEewSARHw7DNtzEV17JW",
+	"4kI8n5xPzkUiakmFl3eK1hrrpncq2/D/C6Qhrz8hcwJKO+JMto3Nfim4GlOVK8xgvgZvhC5Bf5WFxaG+",
+	"4V2trJDQOg/G3U1evdyhK1hx4sJzKhKhZeVrrEz0NU+2wSQWqcw2fpJc7bHcz0dSgxteG1DphX92fi58",
+	"taQJtZdb1nWpQkicfojVyZb8MW8IMm68xb/77beT0P3+JHQ3iXCYNlbR2pvlBUqLVly8u2GFuaaqpF1H",
+	"FAST9w3Hy6eMTe9Lxo3AJ0YiB5JBPAEP/Q4kMC9NunTQaFJlmOL94laqks/7yQBR28QnggEdvTDZ+sl0",
+	"M0wKg5r2wPPtSTYMW4TQsVc+WJSEGXv0s/PvnmzzwWk43PmfxptlJXt2SYDsGuRCKi2+NMzvy+dRvEX6",
+	"VRt9Weotwqd3ZJao+3FyEOpaUJ4oyuy1M0ZEef138UVGoJ0wYxutYwNAbAbnxsi54A1z9GgYOQtqSWkx",
+	"tGKXI5wougwytNHgcn6K/b5i2AQpQe5iZ991p23l46Z3DB3vy3VDYyjgavAXM28bx+IhOPR/HgPD5Ong",
+	"/DCsmpSQzhxZlNWu0vdJHgLlVwccNjTnty02RmATG4+xZjtJMBpNnd405HYxHfoVSpPxubJnC6xaFARy",
+	"JdcJOB6QnLAbKtDOdGy9plJDrXgtd05iFpYZdNwsSU2jCaTfC/lcj6k452szHVt70DhuvSruLaLm8bVv",
+	"QLqiId+vzcxKwxxzYzGMptzW7Rq4XfqH4FXEaZ7St7JUGcic0PIFhAv9lJGMr8ETH64j3Y6vMVL+zEXl",
+	"EFMjqI8Ntl6oHC0Pr5Aaq50HjG6qOVqGz3xN2DW4u54d/+N8uw4spqhuMQNnZjqXNvGD4TIv0MJPFBqA",
+	"UEhfFjuSlkDSBF4Ro+fc182Rzbb9t0KLvgHIkzGDNRKXphHO3zhIG2tR+ywj8YD3Zs94tO0COlDkOq4d",
+	"5NZUgXcmOgbRn5B2rxhPidTRu9ivNRmMaiffd5BtR3i/DXyqPDF5mnO+n27u2uiyrlFnrpUMYmBvxQvx",
+	"ctQLeN5M9xymc6zaWHK+Z446dLQLvjJyrTtJ8vg2Vi2UlmVHxGJdyhQZ/f19lQOLXCRiFkI/L77++fLZ",
+	"9z9AWmC6dE010+1ZUrGcYy4S0Npi90cmfSCp+tigXW+1Hbg7qu8HXMqP047X8p9prvVHtPg9qVyErcl3",
+	"s7pDSdalc1jNSwwnWAgnmAUibptqtYSSkKzALVqVr9m7FLmZ5mcevl+75xhjnjD+KOVE9ebxFzB/YO9p",
+	"sNdq+dgJxQlW14s/3EN6Hac8pPyK5HwXnq8oWRaIwZAFP0WHe992bzV+qsPpEPrDJvUpVibGD/OjPLOO",
+	"trdvo/nmtWJVQ5gVr0dsfO9gYy7KR6FK20lj2dp1O3Iy7O9dT36NqHetQydiGq+vDt8DhP41ZzoaV+2L",
+	"II6YrdEk8JuOaMn4JmiOXHZm/vq9LME1c8eRSxOksixHq8Xea5QThdSR10EnviEYe2Fz+IpgR8V7Xhim",
+	"DGe09pvedY9UNsGGHNbGqr7K3MYzM6yYwN+kKh2o/qMmTh4dqbLsF5zO13rrma4aR2zi0CHArPfIK4Nc",
+	"WTdaa730LHW3nfdHSdekKbq8Kcs1WM931mPxC/fBl92zN5A9ie4vhjo7PyyzPvDe6uYAdqb+fd3DOmZP",
+	"xMmhBO9XaZd9oHKjq339N4HL7nc73FYyCyTvJf7qfaZDz4EMv1Q0YEIZ5JsU8ShqfA+6zQ9DGFPUgtuB",
+	"seEBHzbRb/pvGPeeMN4T2V565T4W+5W0S8wOaOJLdwMWo+cBh2C5TS7+57i8wtyiRwZbpEBpaY6S9l+F",
+	"MjCYgPM4c6G9y+3c9mwkAxnLUSmNYG7RyrLs+r4FypKKCbRNXL86M6ANQUBPBiuOz/xhjrDEmoCsTEMB",
+	"lYe4QqpC03DjLZ/pzJq65l5A+9QULXbh3Xd5k9An9pfGTHuOvFdoyB0H9unTsoNPmkfO077zBO9upf7G",
+	"QS/56d8de5u2/uV6FU9oz4y9ffmVofun2pqsSfnTn2MrRySisaW4EAVR7S6mU1mrialRu0LlNElNxV+m",
+	"qpILPJvzcyC0Z2Hn6e23/gXXXupMcsFGOkLekVzgIzcJVB4zrTdws/nvAJ/6Unl2MgAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
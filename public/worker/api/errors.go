@@ -26,6 +26,11 @@ const (
 	ErrorMalformedWorkerId    ServiceErrorCode = 17
 	ErrorWorkerIdNotFound     ServiceErrorCode = 18
 	ErrorInvalidContent       ServiceErrorCode = 19
+	ErrorWorkerHasActiveJobs  ServiceErrorCode = 20
+	ErrorArtifactsNotEnabled  ServiceErrorCode = 21
+	ErrorUploadOffsetMismatch ServiceErrorCode = 22
+	ErrorChecksumMismatch     ServiceErrorCode = 23
+	ErrorMalformedChecksum    ServiceErrorCode = 24
 
 	// internal errors
 	ErrorDiscardingArtifact       ServiceErrorCode = 1000
@@ -39,6 +44,10 @@ const (
 	ErrorInsertingWorker          ServiceErrorCode = 1008
 	ErrorUpdatingWorkerStatus     ServiceErrorCode = 1009
 	ErrorUpdatingJob              ServiceErrorCode = 1010
+	ErrorRequeueingJob            ServiceErrorCode = 1011
+	ErrorDeletingWorker           ServiceErrorCode = 1012
+	ErrorDrainingWorker           ServiceErrorCode = 1013
+	ErrorReadingArtifactUpload    ServiceErrorCode = 1014
 
 	// Errors contained within this file
 	ErrorUnspecified          ServiceErrorCode = 10000
@@ -75,6 +84,11 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorMalformedWorkerId, http.StatusBadRequest, "Given worker id is not a uuidv4"},
 		serviceError{ErrorWorkerIdNotFound, http.StatusBadRequest, "Given worker id doesn't exist"},
 		serviceError{ErrorInvalidContent, http.StatusBadRequest, "Content of body is not valid"},
+		serviceError{ErrorWorkerHasActiveJobs, http.StatusBadRequest, "Worker still has active jobs"},
+		serviceError{ErrorArtifactsNotEnabled, http.StatusBadRequest, "Server does not accept artifacts"},
+		serviceError{ErrorUploadOffsetMismatch, http.StatusConflict, "Chunk does not start at the offset of the upload"},
+		serviceError{ErrorChecksumMismatch, http.StatusBadRequest, "Checksum of the uploaded data does not match"},
+		serviceError{ErrorMalformedChecksum, http.StatusBadRequest, "Given checksum is not a hex encoded sha256 digest"},
 
 		serviceError{ErrorDiscardingArtifact, http.StatusInternalServerError, "Error discarding artifact"},
 		serviceError{ErrorCreatingArtifact, http.StatusInternalServerError, "Error creating artifact"},
@@ -87,6 +101,10 @@ func getServiceErrors() serviceErrors {
 		serviceError{ErrorInsertingWorker, http.StatusInternalServerError, "Unable to register the worker"},
 		serviceError{ErrorUpdatingWorkerStatus, http.StatusInternalServerError, "Unable update worker status"},
 		serviceError{ErrorUpdatingJob, http.StatusInternalServerError, "Error updating job"},
+		serviceError{ErrorRequeueingJob, http.StatusInternalServerError, "Error requeueing job"},
+		serviceError{ErrorDeletingWorker, http.StatusInternalServerError, "Unable to remove the worker"},
+		serviceError{ErrorDrainingWorker, http.StatusInternalServerError, "Unable to drain the worker"},
+		serviceError{ErrorReadingArtifactUpload, http.StatusInternalServerError, "Error reading the state of the artifact upload"},
 
 		serviceError{ErrorUnspecified, http.StatusInternalServerError, "Unspecified internal error "},
 		serviceError{ErrorNotHTTPError, http.StatusInternalServerError, "Error is not an instance of HTTPError"},
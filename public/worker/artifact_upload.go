@@ -0,0 +1,312 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
+	"github.com/ondrejbudai/osbuild-composer-public/public/artifacts"
+)
+
+var ErrArtifactsNotEnabled = errors.New("artifacts are not enabled")
+var ErrUploadOffsetMismatch = errors.New("chunk does not start at the offset of the upload")
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Chunked artifact uploads keep their chunks in the artifact store until
+// they are assembled, so that an upload can be resumed on any composer
+// replica sharing the store. The chunks and the index of the upload live
+// next to the job's artifacts, which removes leftovers of unfinished uploads
+// together with them.
+//
+// Uploads belong to a run of the job, they are keyed by its token. A job which
+// is requeued runs with a new token and uploads its artifacts from the start,
+// the chunks of the previous run are from a different build.
+//
+// The index is written after the chunk, an upload which was interrupted in
+// between resumes at the offset of the chunk, which is then replaced. A chunk
+// replacing the last one is removed from the index before it's overwritten,
+// the index never lists a chunk whose content wasn't verified.
+type artifactUpload struct {
+	Chunks []artifactChunk `json:"chunks"`
+}
+
+type artifactChunk struct {
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// size is the number of bytes uploaded so far, which is the offset of the
+// next chunk
+func (u *artifactUpload) size() int64 {
+	if len(u.Chunks) == 0 {
+		return 0
+	}
+	last := u.Chunks[len(u.Chunks)-1]
+	return last.Offset + last.Size
+}
+
+func artifactUploadPrefix(jobId, token uuid.UUID, name string) string {
+	return path.Join(jobId.String(), fmt.Sprintf(".upload-%s-%s", token, name))
+}
+
+func artifactUploadIndexKey(jobId, token uuid.UUID, name string) string {
+	return path.Join(artifactUploadPrefix(jobId, token, name), "index.json")
+}
+
+func artifactChunkKey(jobId, token uuid.UUID, name string, offset int64) string {
+	return path.Join(artifactUploadPrefix(jobId, token, name), fmt.Sprintf("%020d", offset))
+}
+
+// artifactUploadJob resolves the token of a running job for uploading
+// artifacts
+func (s *Server) artifactUploadJob(token uuid.UUID) (uuid.UUID, error) {
+	if s.config.Artifacts == nil {
+		return uuid.Nil, ErrArtifactsNotEnabled
+	}
+
+	jobId, err := s.jobs.IdFromToken(token)
+	if err != nil {
+		switch err {
+		case jobqueue.ErrNotExist:
+			return uuid.Nil, ErrInvalidToken
+		default:
+			return uuid.Nil, err
+		}
+	}
+	return jobId, nil
+}
+
+func (s *Server) readArtifactUpload(ctx context.Context, jobId, token uuid.UUID, name string) (*artifactUpload, error) {
+	r, _, err := s.config.Artifacts.Get(ctx, artifactUploadIndexKey(jobId, token, name))
+	if errors.Is(err, artifacts.ErrNotFound) {
+		return &artifactUpload{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var upload artifactUpload
+	err = json.NewDecoder(r).Decode(&upload)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode the index of the upload of %s: %v", name, err)
+	}
+	return &upload, nil
+}
+
+func (s *Server) writeArtifactUpload(ctx context.Context, jobId, token uuid.UUID, name string, upload *artifactUpload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+	return s.config.Artifacts.Put(ctx, artifactUploadIndexKey(jobId, token, name), bytes.NewReader(data))
+}
+
+// ArtifactUploadOffset returns the number of bytes of the artifact `name` of
+// the job with `token` which were uploaded in chunks so far.
+func (s *Server) ArtifactUploadOffset(ctx context.Context, token uuid.UUID, name string) (int64, error) {
+	jobId, err := s.artifactUploadJob(token)
+	if err != nil {
+		return 0, err
+	}
+
+	upload, err := s.readArtifactUpload(ctx, jobId, token, name)
+	if err != nil {
+		return 0, err
+	}
+	return upload.size(), nil
+}
+
+// UploadArtifactChunk stores the chunk read from `r` at `offset` of the
+// artifact `name` and returns the offset of the next chunk. The chunk has to
+// start where the upload ended so far, or replace the last chunk. A chunk at
+// offset 0 restarts the upload. The chunk is discarded if its SHA256 digest
+// doesn't match `checksum`.
+func (s *Server) UploadArtifactChunk(ctx context.Context, token uuid.UUID, name string, offset int64, checksum []byte, r io.Reader) (int64, error) {
+	jobId, err := s.artifactUploadJob(token)
+	if err != nil {
+		return 0, err
+	}
+
+	upload, err := s.readArtifactUpload(ctx, jobId, token, name)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case offset == 0:
+		if len(upload.Chunks) > 0 {
+			err = s.config.Artifacts.Delete(ctx, artifactUploadPrefix(jobId, token, name))
+			if err != nil {
+				return 0, err
+			}
+		}
+		upload.Chunks = nil
+	case offset == upload.size():
+		// append
+	case len(upload.Chunks) > 0 && offset == upload.Chunks[len(upload.Chunks)-1].Offset:
+		upload.Chunks = upload.Chunks[:len(upload.Chunks)-1]
+		err = s.writeArtifactUpload(ctx, jobId, token, name, upload)
+		if err != nil {
+			return 0, err
+		}
+	default:
+		return 0, fmt.Errorf("%w: chunk at %d, upload at %d", ErrUploadOffsetMismatch, offset, upload.size())
+	}
+
+	key := artifactChunkKey(jobId, token, name, offset)
+	h := sha256.New()
+	cr := &countingReader{r: io.TeeReader(r, h)}
+	err = s.config.Artifacts.Put(ctx, key, cr)
+	if err != nil {
+		return 0, err
+	}
+
+	if !bytes.Equal(h.Sum(nil), checksum) {
+		if err := s.config.Artifacts.Delete(ctx, key); err != nil {
+			logrus.Errorf("Error deleting chunk %s of artifact %s of job %s: %v", key, name, jobId, err)
+		}
+		return 0, fmt.Errorf("%w: chunk at %d of %s", ErrChecksumMismatch, offset, name)
+	}
+
+	upload.Chunks = append(upload.Chunks, artifactChunk{
+		Offset: offset,
+		Size:   cr.n,
+		SHA256: fmt.Sprintf("%x", checksum),
+	})
+	err = s.writeArtifactUpload(ctx, jobId, token, name, upload)
+	if err != nil {
+		return 0, err
+	}
+
+	return upload.size(), nil
+}
+
+// CompleteArtifactUpload assembles the chunks of the artifact `name` into
+// the artifact, which has to be `size` bytes large and match the SHA256
+// digest `checksum`.
+func (s *Server) CompleteArtifactUpload(ctx context.Context, token uuid.UUID, name string, size int64, checksum []byte) error {
+	jobId, err := s.artifactUploadJob(token)
+	if err != nil {
+		return err
+	}
+
+	upload, err := s.readArtifactUpload(ctx, jobId, token, name)
+	if err != nil {
+		return err
+	}
+
+	if upload.size() != size {
+		return fmt.Errorf("%w: artifact has %d bytes, upload has %d", ErrUploadOffsetMismatch, size, upload.size())
+	}
+
+	key := path.Join(jobId.String(), name)
+	h := sha256.New()
+	cr := &chunksReader{
+		ctx:    ctx,
+		store:  s.config.Artifacts,
+		chunks: upload.Chunks,
+		jobId:  jobId,
+		token:  token,
+		name:   name,
+		h:      h,
+	}
+	err = s.config.Artifacts.Put(ctx, key, cr)
+	cr.Close()
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(h.Sum(nil), checksum) {
+		if err := s.config.Artifacts.Delete(ctx, key); err != nil {
+			logrus.Errorf("Error deleting artifact %s of job %s: %v", name, jobId, err)
+		}
+		return fmt.Errorf("%w: artifact %s", ErrChecksumMismatch, name)
+	}
+
+	// The artifact is complete, failing to remove the chunks only leaves
+	// them around until the job's artifacts are deleted.
+	if err := s.config.Artifacts.Delete(ctx, artifactUploadPrefix(jobId, token, name)); err != nil {
+		logrus.Errorf("Error deleting the chunks of artifact %s of job %s: %v", name, jobId, err)
+	}
+	return nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// chunksReader reads the chunks of an upload one after the other, opening
+// each only when the previous one is exhausted. It verifies the checksum of
+// every chunk and feeds the content into `h`.
+type chunksReader struct {
+	ctx    context.Context
+	store  artifacts.Store
+	chunks []artifactChunk
+	jobId  uuid.UUID
+	token  uuid.UUID
+	name   string
+	h      hash.Hash
+
+	cur      io.ReadCloser
+	curHash  hash.Hash
+	curChunk artifactChunk
+}
+
+func (c *chunksReader) Read(p []byte) (int, error) {
+	for {
+		if c.cur == nil {
+			if len(c.chunks) == 0 {
+				return 0, io.EOF
+			}
+			c.curChunk = c.chunks[0]
+			c.chunks = c.chunks[1:]
+			r, _, err := c.store.Get(c.ctx, artifactChunkKey(c.jobId, c.token, c.name, c.curChunk.Offset))
+			if err != nil {
+				return 0, err
+			}
+			c.cur = r
+			c.curHash = sha256.New()
+		}
+
+		n, err := c.cur.Read(p)
+		c.h.Write(p[:n])
+		c.curHash.Write(p[:n])
+		if err == io.EOF {
+			c.cur.Close()
+			c.cur = nil
+			if fmt.Sprintf("%x", c.curHash.Sum(nil)) != c.curChunk.SHA256 {
+				return n, fmt.Errorf("%w: stored chunk at %d of %s", ErrChecksumMismatch, c.curChunk.Offset, c.name)
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (c *chunksReader) Close() {
+	if c.cur != nil {
+		c.cur.Close()
+		c.cur = nil
+	}
+}
@@ -3,7 +3,9 @@ package worker
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +13,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -30,7 +33,13 @@ type Client struct {
 	accessToken  string
 	clientId     string
 	clientSecret string
+	labels       []string
 	workerID     uuid.UUID
+	deregistered bool
+
+	// closed when osbuild-composer asks the worker to drain
+	draining     chan struct{}
+	drainingOnce sync.Once
 
 	tokenMu    sync.RWMutex
 	workerIDMu sync.RWMutex
@@ -45,6 +54,8 @@ type ClientConfig struct {
 	ClientSecret string
 	BasePath     string
 	ProxyURL     string
+	// Capability labels the worker registers with, see api.PostWorkersRequest
+	Labels []string
 }
 
 type Job interface {
@@ -57,6 +68,9 @@ type Job interface {
 	Finish(result interface{}) error
 	Canceled() (bool, error)
 	UploadArtifact(name string, readSeeker io.ReadSeeker) error
+	// Hands the job back to osbuild-composer, which requeues it right
+	// away. The job must not be updated or finished afterwards.
+	Requeue() error
 }
 
 var ErrClientRequestJobTimeout = errors.New("Dequeue timed out, retry")
@@ -66,11 +80,21 @@ type job struct {
 	id               uuid.UUID
 	location         string
 	artifactLocation string
-	jobType          string
-	args             json.RawMessage
-	dynamicArgs      []json.RawMessage
+	// empty if the server doesn't support chunked uploads
+	artifactUploadLocation string
+	jobType                string
+	args                   json.RawMessage
+	dynamicArgs            []json.RawMessage
 }
 
+// Size of the chunks of artifact uploads and how often an upload is resumed
+// after a chunk failed to upload.
+var (
+	artifactChunkSize    int64 = 64 * 1024 * 1024
+	artifactChunkRetries       = 5
+	artifactChunkBackoff       = 5 * time.Second
+)
+
 type tokenResponse struct {
 	AccessToken string `json:"access_token"`
 }
@@ -121,6 +145,8 @@ func NewClient(conf ClientConfig) (*Client, error) {
 		oAuthURL:     conf.OAuthURL,
 		clientId:     conf.ClientId,
 		clientSecret: conf.ClientSecret,
+		labels:       conf.Labels,
+		draining:     make(chan struct{}),
 	}
 	err = client.registerWorker()
 	if err != nil {
@@ -153,6 +179,8 @@ func NewClientUnix(conf ClientConfig) *Client {
 	client := &Client{
 		serverURL: serverURL,
 		requester: requester,
+		labels:    conf.Labels,
+		draining:  make(chan struct{}),
 	}
 	err = client.registerWorker()
 	if err != nil {
@@ -172,9 +200,13 @@ func (c *Client) registerWorker() error {
 	}
 
 	var buf bytes.Buffer
-	err = json.NewEncoder(&buf).Encode(api.PostWorkersRequest{
+	request := api.PostWorkersRequest{
 		Arch: arch.Current().String(),
-	})
+	}
+	if len(c.labels) > 0 {
+		request.Labels = &c.labels
+	}
+	err = json.NewEncoder(&buf).Encode(request)
 	if err != nil {
 		logrus.Errorf("Unable create worker request: %v", err)
 		return err
@@ -210,7 +242,10 @@ func (c *Client) getWorkerID() uuid.UUID {
 func (c *Client) workerHeartbeat() {
 	//nolint:staticcheck // avoid SA1015, this is an endless function
 	for range time.Tick(time.Minute * 1) {
-		workerID := c.getWorkerID()
+		workerID, deregistered := c.getWorkerState()
+		if deregistered {
+			return
+		}
 
 		if workerID == uuid.Nil {
 			err := c.registerWorker()
@@ -243,9 +278,90 @@ func (c *Client) workerHeartbeat() {
 			logrus.Errorf("Error updating worker status: %d", resp.StatusCode)
 			continue
 		}
+
+		var status api.PostWorkerStatusResponse
+		err = json.NewDecoder(resp.Body).Decode(&status)
+		resp.Body.Close()
+		// older versions of osbuild-composer don't send a body
+		if err != nil && err != io.EOF {
+			logrus.Errorf("Error parsing worker status: %v", err)
+			continue
+		}
+		if status.Draining {
+			c.drainingOnce.Do(func() {
+				logrus.Info("osbuild-composer asked the worker to drain")
+				close(c.draining)
+			})
+		}
 	}
 }
 
+func (c *Client) getWorkerState() (uuid.UUID, bool) {
+	c.workerIDMu.RLock()
+	defer c.workerIDMu.RUnlock()
+	return c.workerID, c.deregistered
+}
+
+// Returns a channel which is closed when osbuild-composer asks the worker to
+// drain, i.e., to stop requesting jobs, finish or requeue the ones it runs,
+// and deregister.
+func (c *Client) Draining() <-chan struct{} {
+	return c.draining
+}
+
+// Tells osbuild-composer that the worker is draining, so that it doesn't get
+// any new jobs.
+func (c *Client) Drain() error {
+	workerID := c.getWorkerID()
+	if workerID == uuid.Nil {
+		return nil
+	}
+
+	url, err := c.serverURL.Parse(fmt.Sprintf("workers/%s/drain", workerID))
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.NewRequest("POST", url.String(), map[string]string{"Content-Type": "application/json"}, nil)
+	if err != nil {
+		return fmt.Errorf("error draining worker: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errorFromResponse(resp, "error draining worker")
+	}
+	return nil
+}
+
+// Removes the worker from osbuild-composer and stops sending heartbeats. All
+// jobs of the worker must have been finished or requeued.
+func (c *Client) Deregister() error {
+	c.workerIDMu.Lock()
+	defer c.workerIDMu.Unlock()
+
+	if c.workerID != uuid.Nil {
+		url, err := c.serverURL.Parse(fmt.Sprintf("workers/%s", c.workerID))
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.NewRequest(http.MethodDelete, url.String(), map[string]string{}, nil)
+		if err != nil {
+			return fmt.Errorf("error deregistering worker: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return errorFromResponse(resp, "error deregistering worker")
+		}
+	}
+
+	c.workerID = uuid.Nil
+	c.deregistered = true
+	return nil
+}
+
 func (c *Client) refreshAccessToken() error {
 	c.tokenMu.Lock()
 	defer c.tokenMu.Unlock()
@@ -278,6 +394,10 @@ func (c *Client) refreshAccessToken() error {
 }
 
 func (c *Client) NewRequest(method, url string, headers map[string]string, body io.ReadSeeker) (*http.Response, error) {
+	return c.newRequest(context.Background(), method, url, headers, body)
+}
+
+func (c *Client) newRequest(ctx context.Context, method, url string, headers map[string]string, body io.ReadSeeker) (*http.Response, error) {
 	token := func() string {
 		c.tokenMu.RLock()
 		defer c.tokenMu.RUnlock()
@@ -292,7 +412,7 @@ func (c *Client) NewRequest(method, url string, headers map[string]string, body
 			}
 		}
 
-		req, err := http.NewRequest(method, url, body)
+		req, err := http.NewRequestWithContext(ctx, method, url, body)
 		if err != nil {
 			return nil, err
 		}
@@ -332,7 +452,9 @@ func (c *Client) NewRequest(method, url string, headers map[string]string, body
 	return resp, err
 }
 
-func (c *Client) RequestJob(types []string, arch string) (Job, error) {
+// Requests a job of one of `types`, blocking until osbuild-composer hands one
+// out or `ctx` is canceled.
+func (c *Client) RequestJob(ctx context.Context, types []string, arch string) (Job, error) {
 	url, err := c.serverURL.Parse("jobs")
 	if err != nil {
 		// This only happens when "jobs" cannot be parsed.
@@ -355,7 +477,7 @@ func (c *Client) RequestJob(types []string, arch string) (Job, error) {
 		panic(err)
 	}
 
-	response, err := c.NewRequest("POST", url.String(), map[string]string{"Content-Type": "application/json"}, bytes.NewReader(buf.Bytes()))
+	response, err := c.newRequest(ctx, "POST", url.String(), map[string]string{"Content-Type": "application/json"}, bytes.NewReader(buf.Bytes()))
 	if err != nil {
 		return nil, err
 	}
@@ -384,6 +506,15 @@ func (c *Client) RequestJob(types []string, arch string) (Job, error) {
 		return nil, fmt.Errorf("error parsing artifact location url in response: %v", err)
 	}
 
+	var artifactUploadLocation string
+	if jr.ArtifactUploadLocation != nil {
+		loc, err := c.serverURL.Parse(*jr.ArtifactUploadLocation)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing artifact upload location url in response: %v", err)
+		}
+		artifactUploadLocation = loc.String()
+	}
+
 	jobId, err := uuid.Parse(jr.Id)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing job id in response: %v", err)
@@ -399,13 +530,14 @@ func (c *Client) RequestJob(types []string, arch string) (Job, error) {
 	}
 
 	return &job{
-		client:           c,
-		id:               jobId,
-		jobType:          jr.Type,
-		args:             args,
-		dynamicArgs:      dynamicArgs,
-		location:         location.String(),
-		artifactLocation: artifactLocation.String(),
+		client:                 c,
+		id:                     jobId,
+		jobType:                jr.Type,
+		args:                   args,
+		dynamicArgs:            dynamicArgs,
+		location:               location.String(),
+		artifactLocation:       artifactLocation.String(),
+		artifactUploadLocation: artifactUploadLocation,
 	}, nil
 }
 
@@ -509,7 +641,28 @@ func (j *job) Canceled() (bool, error) {
 	return jr.Canceled, nil
 }
 
+func (j *job) Requeue() error {
+	response, err := j.client.NewRequest("POST", j.location+"/requeue", map[string]string{"Content-Type": "application/json"}, nil)
+	if err != nil {
+		return fmt.Errorf("error requeueing job: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return errorFromResponse(response, "error requeueing job")
+	}
+
+	return nil
+}
+
+// UploadArtifact uploads the artifact in chunks when the server supports it,
+// so that an upload which fails midway resumes where it stopped. Older
+// servers get the whole artifact in a single request.
 func (j *job) UploadArtifact(name string, readSeeker io.ReadSeeker) error {
+	if j.artifactUploadLocation != "" {
+		return j.uploadArtifactChunked(name, readSeeker)
+	}
+
 	if j.artifactLocation == "" {
 		return fmt.Errorf("server does not accept artifacts for this job")
 	}
@@ -536,6 +689,149 @@ func (j *job) UploadArtifact(name string, readSeeker io.ReadSeeker) error {
 	return nil
 }
 
+func (j *job) uploadArtifactChunked(name string, readSeeker io.ReadSeeker) error {
+	loc, err := url.Parse(j.artifactUploadLocation)
+	if err != nil {
+		return fmt.Errorf("error parsing artifact upload location: %v", err)
+	}
+	loc, err = loc.Parse(url.PathEscape(name))
+	if err != nil {
+		panic(err)
+	}
+
+	// The checksum of the whole artifact is verified after the server
+	// assembled the chunks, it has to cover the part uploaded before a
+	// resume as well.
+	if _, err := readSeeker.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error reading artifact: %v", err)
+	}
+	h := sha256.New()
+	size, err := io.Copy(h, readSeeker)
+	if err != nil {
+		return fmt.Errorf("error reading artifact: %v", err)
+	}
+	checksum := hex.EncodeToString(h.Sum(nil))
+
+	offset, err := j.artifactUploadOffset(loc.String())
+	if err != nil {
+		return err
+	}
+	if offset > size {
+		// the artifact changed since the upload started
+		offset = 0
+	}
+	if offset > 0 {
+		logrus.Infof("Resuming upload of artifact %s at %d of %d bytes", name, offset, size)
+	}
+
+	buf := make([]byte, artifactChunkSize)
+	retries := 0
+	for offset < size {
+		chunk := buf[:min(artifactChunkSize, size-offset)]
+		_, err := readSeeker.Seek(offset, io.SeekStart)
+		if err == nil {
+			_, err = io.ReadFull(readSeeker, chunk)
+		}
+		if err != nil {
+			return fmt.Errorf("error reading artifact: %v", err)
+		}
+
+		next, err := j.uploadArtifactChunk(loc.String(), offset, chunk)
+		if err == nil {
+			offset = next
+			retries = 0
+			continue
+		}
+
+		if retries >= artifactChunkRetries {
+			return err
+		}
+		retries++
+		logrus.Warnf("Uploading chunk of artifact %s at %d failed, resuming (%d/%d): %v", name, offset, retries, artifactChunkRetries, err)
+		time.Sleep(artifactChunkBackoff)
+
+		// The chunk might have been stored even though the request
+		// failed, the server knows where to continue.
+		resumeAt, err := j.artifactUploadOffset(loc.String())
+		if err != nil {
+			continue
+		}
+		if resumeAt <= size {
+			offset = resumeAt
+		}
+	}
+
+	var body bytes.Buffer
+	err = json.NewEncoder(&body).Encode(api.CompleteArtifactUploadJSONRequestBody{
+		Size:   size,
+		Sha256: checksum,
+	})
+	if err != nil {
+		panic(err)
+	}
+	response, err := j.client.NewRequest("POST", loc.String(), map[string]string{"Content-Type": "application/json"}, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return fmt.Errorf("error completing artifact upload: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return errorFromResponse(response, "error completing artifact upload")
+	}
+
+	return nil
+}
+
+// artifactUploadOffset asks the server how much of the artifact it has
+// received so far
+func (j *job) artifactUploadOffset(location string) (int64, error) {
+	response, err := j.client.NewRequest("GET", location, map[string]string{}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching artifact upload status: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, errorFromResponse(response, "error fetching artifact upload status")
+	}
+
+	var status api.ArtifactUploadStatus
+	err = json.NewDecoder(response.Body).Decode(&status)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing artifact upload status: %v", err)
+	}
+	return status.Offset, nil
+}
+
+func (j *job) uploadArtifactChunk(location string, offset int64, chunk []byte) (int64, error) {
+	loc, err := url.Parse(location)
+	if err != nil {
+		return 0, err
+	}
+	digest := sha256.Sum256(chunk)
+	loc.RawQuery = url.Values{
+		"offset": {strconv.FormatInt(offset, 10)},
+		"sha256": {hex.EncodeToString(digest[:])},
+	}.Encode()
+
+	response, err := j.client.NewRequest("PATCH", loc.String(), map[string]string{"Content-Type": "application/octet-stream"}, bytes.NewReader(chunk))
+	if err != nil {
+		return 0, fmt.Errorf("error uploading artifact chunk: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return 0, errorFromResponse(response, "error uploading artifact chunk")
+	}
+
+	var status api.ArtifactUploadStatus
+	err = json.NewDecoder(response.Body).Decode(&status)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing artifact upload status: %v", err)
+	}
+	return status.Offset, nil
+}
+
 // Parses an api.Error from a response and returns it as a golang error. Other
 // errors, such failing to parse the response, are returned as golang error as
 // well. If client code expects an error, it gets one.
@@ -2,15 +2,19 @@ package worker_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"path"
 	"strings"
 	"testing"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
+	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/jobqueue/fsjobqueue"
 	"github.com/ondrejbudai/osbuild-composer-public/public/worker"
 )
@@ -31,7 +35,7 @@ func newTestWorkerServer(t *testing.T) (string, string, string) {
 		BasePath:     "/api/image-builder-worker/v1",
 	}
 	workerServer := worker.NewServer(nil, q, config)
-	_, err = workerServer.EnqueueOSBuild("arch", &worker.OSBuildJob{}, "")
+	_, err = workerServer.EnqueueOSBuild("arch", &worker.OSBuildJob{}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	handler := workerServer.Handler()
@@ -94,7 +98,7 @@ func TestOAuth(t *testing.T) {
 		BasePath:     "/api/image-builder-worker/v1",
 	})
 	require.NoError(t, err)
-	job, err := client.RequestJob([]string{worker.JobTypeOSBuild}, "arch")
+	job, err := client.RequestJob(context.Background(), []string{worker.JobTypeOSBuild}, "arch")
 	require.NoError(t, err)
 	r := strings.NewReader("artifact contents")
 	require.NoError(t, job.UploadArtifact("some-artifact", r))
@@ -103,6 +107,36 @@ func TestOAuth(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDrainAndRequeue(t *testing.T) {
+	workerURL, oauthURL, offlineToken := newTestWorkerServer(t)
+
+	client, err := worker.NewClient(worker.ClientConfig{
+		BaseURL:      workerURL,
+		TlsConfig:    nil,
+		ClientId:     "rhsm-api",
+		OfflineToken: offlineToken,
+		OAuthURL:     oauthURL,
+		BasePath:     "/api/image-builder-worker/v1",
+	})
+	require.NoError(t, err)
+	job, err := client.RequestJob(context.Background(), []string{worker.JobTypeOSBuild}, "arch")
+	require.NoError(t, err)
+
+	require.NoError(t, client.Drain())
+
+	// the worker can't be removed while it runs a job
+	require.Error(t, client.Deregister())
+
+	require.NoError(t, job.Requeue())
+	require.Error(t, job.Requeue())
+	require.NoError(t, client.Deregister())
+
+	// the job is available again right away
+	again, err := client.RequestJob(context.Background(), []string{worker.JobTypeOSBuild}, "arch")
+	require.NoError(t, err)
+	require.Equal(t, job.Id(), again.Id())
+}
+
 func TestProxy(t *testing.T) {
 	workerURL, oauthURL, offlineToken := newTestWorkerServer(t)
 
@@ -122,7 +156,7 @@ func TestProxy(t *testing.T) {
 	})
 
 	require.NoError(t, err)
-	job, err := client.RequestJob([]string{worker.JobTypeOSBuild}, "arch")
+	job, err := client.RequestJob(context.Background(), []string{worker.JobTypeOSBuild}, "arch")
 	require.NoError(t, err)
 	r := strings.NewReader("artifact contents")
 	require.NoError(t, job.UploadArtifact("some-artifact", r))
@@ -130,14 +164,16 @@ func TestProxy(t *testing.T) {
 	require.False(t, c)
 	require.NoError(t, err)
 
-	// we expect 6 calls to go through the proxy:
+	// we expect 8 calls to go through the proxy:
 	// - register worker
 	// - request job (fails, no oauth token)
 	// - oauth call
 	// - request job (succeeds)
-	// - upload artifact
+	// - get artifact upload status
+	// - upload artifact chunk
+	// - complete artifact upload
 	// - cancel
-	require.Equal(t, 6, proxy.calls)
+	require.Equal(t, 8, proxy.calls)
 }
 
 func TestNewClientWorkerNoErrorOnRegisterWorkerFailure(t *testing.T) {
@@ -164,3 +200,98 @@ func TestNewClientWorkerNoErrorOnRegisterWorkerFailure(t *testing.T) {
 	require.Equal(t, 1, apiCalls)
 	require.Contains(t, logrusOutput.String(), `Error registering worker on startup, error registering worker: 400`)
 }
+
+// newTestUploadServer returns a worker server with one pending osbuild job of
+// arch "arch" and the URL it is served at through `middleware`
+func newTestUploadServer(t *testing.T, middleware func(http.Handler) http.Handler) (*worker.Server, string) {
+	tempdir := t.TempDir()
+
+	q, err := fsjobqueue.New(tempdir)
+	require.NoError(t, err)
+	workerServer := worker.NewServer(nil, q, worker.Config{
+		ArtifactsDir: path.Join(tempdir, "artifacts"),
+		BasePath:     "/api/image-builder-worker/v1",
+	})
+	_, err = workerServer.EnqueueOSBuild("arch", &worker.OSBuildJob{}, "", jobqueue.EnqueueOptions{})
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(middleware(workerServer.Handler()))
+	t.Cleanup(srv.Close)
+	return workerServer, srv.URL
+}
+
+// isUploadChunk returns true for chunks of artifact uploads, finishing a job
+// is a PATCH too
+func isUploadChunk(r *http.Request) bool {
+	return r.Method == http.MethodPatch && strings.Contains(r.URL.Path, "/uploads/")
+}
+
+func uploadTestArtifact(t *testing.T, workerServer *worker.Server, url, content string) {
+	client, err := worker.NewClient(worker.ClientConfig{
+		BaseURL:  url,
+		BasePath: "/api/image-builder-worker/v1",
+	})
+	require.NoError(t, err)
+	job, err := client.RequestJob(context.Background(), []string{worker.JobTypeOSBuild}, "arch")
+	require.NoError(t, err)
+	require.NoError(t, job.UploadArtifact("disk.img", strings.NewReader(content)))
+	require.NoError(t, job.Finish(&worker.OSBuildJobResult{Success: true}))
+
+	r, size, err := workerServer.JobArtifact(job.Id(), "disk.img")
+	require.NoError(t, err)
+	defer r.(io.Closer).Close()
+	uploaded, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, content, string(uploaded))
+	require.Equal(t, int64(len(content)), size)
+}
+
+func TestUploadArtifactChunked(t *testing.T) {
+	defer worker.MockArtifactUploads(4)()
+
+	var chunks []string
+	workerServer, url := newTestUploadServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isUploadChunk(r) {
+				chunks = append(chunks, r.URL.Query().Get("offset"))
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	uploadTestArtifact(t, workerServer, url, "artifact contents")
+	require.Equal(t, []string{"0", "4", "8", "12", "16"}, chunks)
+}
+
+func TestUploadArtifactChunkedResume(t *testing.T) {
+	defer worker.MockArtifactUploads(4)()
+
+	var chunks []string
+	patches := 0
+	workerServer, url := newTestUploadServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isUploadChunk(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			patches++
+			chunks = append(chunks, r.URL.Query().Get("offset"))
+			switch patches {
+			case 2:
+				// the connection breaks before the chunk arrives
+				w.WriteHeader(http.StatusBadGateway)
+				_, _ = w.Write([]byte(`{"reason":"bad gateway", "code":"-1"}`))
+			case 4:
+				// the chunk is stored, but the response is lost
+				next.ServeHTTP(httptest.NewRecorder(), r)
+				w.WriteHeader(http.StatusBadGateway)
+				_, _ = w.Write([]byte(`{"reason":"bad gateway", "code":"-1"}`))
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	})
+
+	uploadTestArtifact(t, workerServer, url, "artifact contents")
+	require.Equal(t, []string{"0", "4", "4", "8", "12", "16"}, chunks)
+}
@@ -19,3 +19,13 @@ type Repository = repository
 var (
 	RPMMDRepoConfigsToDiskArchMap = rpmmdRepoConfigsToDiskArchMap
 )
+
+// MockArtifactUploads makes artifact uploads use chunks of `size` bytes and
+// resume without waiting. It returns a function restoring the defaults.
+func MockArtifactUploads(size int64) (restore func()) {
+	originalSize, originalBackoff := artifactChunkSize, artifactChunkBackoff
+	artifactChunkSize, artifactChunkBackoff = size, 0
+	return func() {
+		artifactChunkSize, artifactChunkBackoff = originalSize, originalBackoff
+	}
+}
@@ -100,9 +100,46 @@ type OSBuildJobResult struct {
 	ImageBootMode string `json:"image_boot_mode,omitempty"`
 	// Version of the osbuild binary used by the worker to build the image
 	OSBuildVersion string `json:"osbuild_version,omitempty"`
+	// Timing and resource usage of the osbuild run
+	OSBuildReport *OSBuildReport `json:"osbuild_report,omitempty"`
 	JobResult
 }
 
+// OSBuildReport records how long the pipelines and stages of an osbuild run
+// took and how much disk space the run needed. It is assembled from the
+// osbuild monitor output.
+type OSBuildReport struct {
+	Pipelines []PipelineTiming `json:"pipelines"`
+	// Peak size of the osbuild store and the output directory in bytes,
+	// zero if it couldn't be measured, e.g. because osbuild ran on another
+	// machine.
+	PeakStoreSize  int64 `json:"peak_store_size,omitempty"`
+	PeakOutputSize int64 `json:"peak_output_size,omitempty"`
+	// Set if the worker keeps a store cache
+	StoreCache *StoreCacheStats `json:"store_cache,omitempty"`
+}
+
+// StoreCacheStats counts the sources of the manifest which were already in
+// the worker's store cache and the ones which had to be downloaded.
+type StoreCacheStats struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+type PipelineTiming struct {
+	Name     string        `json:"name"`
+	Started  time.Time     `json:"started"`
+	Finished time.Time     `json:"finished"`
+	Stages   []StageTiming `json:"stages,omitempty"`
+}
+
+type StageTiming struct {
+	// Type of the stage, e.g. org.osbuild.rpm
+	Name     string    `json:"name"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
 // TargetErrors returns a slice of *clienterrors.Error gathered
 // from the job result's target results. If there were no target errors
 // then the returned slice will be empty.
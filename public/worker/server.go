@@ -1,14 +1,17 @@
 package worker
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"os"
+	"net/url"
 	"path"
 	"strconv"
 	"strings"
@@ -21,6 +24,7 @@ import (
 
 	"github.com/ondrejbudai/osbuild-composer-public/pkg/jobqueue"
 
+	"github.com/ondrejbudai/osbuild-composer-public/public/artifacts"
 	"github.com/ondrejbudai/osbuild-composer-public/public/auth"
 	"github.com/ondrejbudai/osbuild-composer-public/public/common"
 	"github.com/ondrejbudai/osbuild-composer-public/public/prometheus"
@@ -48,6 +52,11 @@ const (
 	JobTypeBootcPreManifest string = "bootc-pre-manifest"
 )
 
+// JobPriorityInteractive is the priority of jobs a client is synchronously
+// waiting for, such as depsolving or searching packages through the API. It
+// is higher than any priority a client can request for a compose.
+const JobPriorityInteractive int = 1000
+
 type Server struct {
 	jobs   jobqueue.JobQueue
 	logger *log.Logger
@@ -74,6 +83,10 @@ var ErrJobNotRunning = errors.New("job isn't running")
 var ErrInvalidJobType = errors.New("job has invalid type")
 
 type Config struct {
+	// Artifacts stores the artifacts workers upload. When it's nil and
+	// ArtifactsDir is set, the artifacts are stored in that directory.
+	// Artifacts are disabled when neither is set.
+	Artifacts            artifacts.Store
 	ArtifactsDir         string
 	RequestJobTimeout    time.Duration
 	BasePath             string
@@ -105,6 +118,10 @@ func NewServer(logger *log.Logger, jobs jobqueue.JobQueue, config Config) *Serve
 		s.config.WorkerWatchFreq = time.Second * 300
 	}
 
+	if s.config.Artifacts == nil && s.config.ArtifactsDir != "" {
+		s.config.Artifacts = artifacts.NewLocal(s.config.ArtifactsDir)
+	}
+
 	api.BasePath = config.BasePath
 
 	go s.WatchHeartbeats()
@@ -191,72 +208,96 @@ func (s *Server) WatchWorkers() {
 	}
 }
 
-func (s *Server) EnqueueOSBuild(arch string, job *OSBuildJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeOSBuild+":"+arch, job, nil, channel)
+func (s *Server) EnqueueOSBuild(arch string, job *OSBuildJob, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeOSBuild+":"+arch, job, nil, channel, opts)
 }
 
-func (s *Server) EnqueueOSBuildAsDependency(arch string, job *OSBuildJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeOSBuild+":"+arch, job, dependencies, channel)
+func (s *Server) EnqueueOSBuildAsDependency(arch string, job *OSBuildJob, dependencies []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeOSBuild+":"+arch, job, dependencies, channel, opts)
 }
 
-func (s *Server) EnqueueKojiInit(job *KojiInitJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeKojiInit, job, nil, channel)
+func (s *Server) EnqueueKojiInit(job *KojiInitJob, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeKojiInit, job, nil, channel, opts)
 }
 
-func (s *Server) EnqueueKojiFinalize(job *KojiFinalizeJob, initID uuid.UUID, buildIDs []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeKojiFinalize, job, append([]uuid.UUID{initID}, buildIDs...), channel)
+func (s *Server) EnqueueKojiFinalize(job *KojiFinalizeJob, initID uuid.UUID, buildIDs []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeKojiFinalize, job, append([]uuid.UUID{initID}, buildIDs...), channel, opts)
 }
 
-func (s *Server) EnqueueDepsolve(job *DepsolveJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeDepsolve, job, nil, channel)
+func (s *Server) EnqueueDepsolve(job *DepsolveJob, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeDepsolve, job, nil, channel, opts)
 }
 
-func (s *Server) EnqueueSearchPackages(job *SearchPackagesJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeSearchPackages, job, nil, channel)
+func (s *Server) EnqueueSearchPackages(job *SearchPackagesJob, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeSearchPackages, job, nil, channel, opts)
 }
 
-func (s *Server) EnqueueManifestJobByID(job *ManifestJobByID, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+func (s *Server) EnqueueManifestJobByID(job *ManifestJobByID, dependencies []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
 	if len(dependencies) == 0 {
 		panic("EnqueueManifestJobByID has no dependencies, expected at least one dependency")
 	}
-	return s.enqueue(JobTypeManifestIDOnly, job, dependencies, channel)
+	return s.enqueue(JobTypeManifestIDOnly, job, dependencies, channel, opts)
+}
+
+func (s *Server) EnqueueContainerResolveJob(job *ContainerResolveJob, dependencies []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeContainerResolve, job, dependencies, channel, opts)
+}
+
+func (s *Server) EnqueueFileResolveJob(job *FileResolveJob, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeFileResolve, job, nil, channel, opts)
 }
 
-func (s *Server) EnqueueContainerResolveJob(job *ContainerResolveJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeContainerResolve, job, dependencies, channel)
+func (s *Server) EnqueueOSTreeResolveJob(job *OSTreeResolveJob, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeOSTreeResolve, job, nil, channel, opts)
 }
 
-func (s *Server) EnqueueFileResolveJob(job *FileResolveJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeFileResolve, job, nil, channel)
+func (s *Server) EnqueueAWSEC2CopyJob(job *AWSEC2CopyJob, parent uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeAWSEC2Copy, job, []uuid.UUID{parent}, channel, opts)
 }
 
-func (s *Server) EnqueueOSTreeResolveJob(job *OSTreeResolveJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeOSTreeResolve, job, nil, channel)
+func (s *Server) EnqueueAWSEC2ShareJob(job *AWSEC2ShareJob, parent uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeAWSEC2Share, job, []uuid.UUID{parent}, channel, opts)
 }
 
-func (s *Server) EnqueueAWSEC2CopyJob(job *AWSEC2CopyJob, parent uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeAWSEC2Copy, job, []uuid.UUID{parent}, channel)
+func (s *Server) EnqueueImageBuilderManifestJob(job *ImageBuilderManifestJob, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeImageBuilderManifest, job, nil, channel, opts)
 }
 
-func (s *Server) EnqueueAWSEC2ShareJob(job *AWSEC2ShareJob, parent uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeAWSEC2Share, job, []uuid.UUID{parent}, channel)
+func (s *Server) EnqueueBootcInfoResolveJob(arch string, job *BootcInfoResolveJob, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeBootcInfoResolve+":"+arch, job, nil, channel, opts)
 }
 
-func (s *Server) EnqueueImageBuilderManifestJob(job *ImageBuilderManifestJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeImageBuilderManifest, job, nil, channel)
+func (s *Server) EnqueueBootcPreManifestJob(job *BootcPreManifestJob, dependencies []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
+	return s.enqueue(JobTypeBootcPreManifest, job, dependencies, channel, opts)
 }
 
-func (s *Server) EnqueueBootcInfoResolveJob(arch string, job *BootcInfoResolveJob, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeBootcInfoResolve+":"+arch, job, nil, channel)
+// CountUnfinishedComposes returns the number of composes in `channel` which
+// haven't finished yet. Composes are counted by their root jobs, which are
+// osbuild jobs, or koji-finalize jobs for Koji composes.
+func (s *Server) CountUnfinishedComposes(ctx context.Context, channel string) (int, error) {
+	pending, running, err := s.jobs.CountJobs(ctx, jobqueue.JobFilter{
+		Channel:  channel,
+		JobTypes: []string{JobTypeOSBuild, JobTypeKojiFinalize},
+		RootOnly: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pending + running, nil
 }
 
-func (s *Server) EnqueueBootcPreManifestJob(job *BootcPreManifestJob, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
-	return s.enqueue(JobTypeBootcPreManifest, job, dependencies, channel)
+// ListComposes returns the root jobs of the composes matching `query`, most
+// recently queued first. See CountUnfinishedComposes for which jobs are
+// root jobs of composes.
+func (s *Server) ListComposes(ctx context.Context, query jobqueue.JobQuery) ([]uuid.UUID, *jobqueue.JobCursor, error) {
+	query.JobTypes = []string{JobTypeOSBuild, JobTypeKojiFinalize}
+	query.RootOnly = true
+	return s.jobs.ListJobs(ctx, query)
 }
 
-func (s *Server) enqueue(jobType string, job interface{}, dependencies []uuid.UUID, channel string) (uuid.UUID, error) {
+func (s *Server) enqueue(jobType string, job interface{}, dependencies []uuid.UUID, channel string, opts jobqueue.EnqueueOptions) (uuid.UUID, error) {
 	prometheus.EnqueueJobMetrics(strings.Split(jobType, ":")[0], channel)
-	return s.jobs.Enqueue(jobType, job, dependencies, channel)
+	return s.jobs.EnqueueWithOptions(jobType, job, dependencies, channel, opts)
 }
 
 // DependencyChainErrors recursively gathers all errors from job's dependencies,
@@ -393,29 +434,31 @@ func (s *Server) AllRootJobIDs(ctx context.Context) ([]uuid.UUID, error) {
 	return s.jobs.AllRootJobIDs(ctx)
 }
 
-// CleanupArtifacts removes worker artifact directories that do not have matching jobs
-// The UUID used for the artifact directory is the same as for the job that created it
+// CleanupArtifacts removes worker artifacts that do not have matching jobs
+// The UUID used for the artifact prefix is the same as for the job that created it
 func (s *Server) CleanupArtifacts() error {
-	artifacts, err := os.ReadDir(s.config.ArtifactsDir)
+	if s.config.Artifacts == nil {
+		return errors.New("Artifacts not enabled")
+	}
+
+	ctx := context.Background()
+	names, err := s.config.Artifacts.List(ctx)
 	if err != nil {
 		return err
 	}
 
-	for _, d := range artifacts {
-		if !d.IsDir() {
-			continue
-		}
-		id, err := uuid.Parse(d.Name())
+	for _, name := range names {
+		id, err := uuid.Parse(name)
 		if err != nil {
 			continue
 		}
 
 		// Is there a job with this UUID?
 		if _, _, _, _, err := s.jobs.Job(id); err != nil {
-			// No associated job, it is safe to remove the unused artifact directory
-			// and everything under it, and the ComposeRequest (if it exists)
-			_ = os.Remove(path.Join(s.config.ArtifactsDir, "ComposeRequest", id.String()+".json"))
-			err = os.RemoveAll(path.Join(s.config.ArtifactsDir, id.String()))
+			// No associated job, it is safe to remove the unused artifacts
+			// and the ComposeRequest (if it exists)
+			_ = s.config.Artifacts.Delete(ctx, composeRequestKey(id))
+			err = s.config.Artifacts.Delete(ctx, id.String())
 			if err != nil {
 				return err
 			}
@@ -730,6 +773,25 @@ func (s *Server) Cancel(id uuid.UUID) error {
 	return s.jobs.CancelJob(id)
 }
 
+// CancelJobTree cancels the job with `id` and all unfinished jobs connected
+// to it by dependencies, e.g., all jobs of a compose. It returns the ids of
+// the canceled jobs.
+func (s *Server) CancelJobTree(id uuid.UUID) ([]uuid.UUID, error) {
+	canceled, err := s.jobs.CancelJobTree(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range canceled {
+		jobInfo, err := s.jobInfo(c, nil)
+		if err != nil {
+			logrus.Errorf("error getting job status: %v", err)
+			continue
+		}
+		prometheus.CancelJobMetrics(jobInfo.JobStatus.Started, jobInfo.JobType, jobInfo.Channel)
+	}
+	return canceled, nil
+}
+
 // SetFailed sets the given job id to "failed" with the given error
 func (s *Server) SetFailed(id uuid.UUID, error *clienterrors.Error) error {
 	FailedJobErrorResult := JobResult{
@@ -744,15 +806,55 @@ func (s *Server) SetFailed(id uuid.UUID, error *clienterrors.Error) error {
 	return s.jobs.FailJob(id, res)
 }
 
-// Return the ArtifactsDir path
-func (s *Server) ArtifactsDir() string {
-	return s.config.ArtifactsDir
+// Artifacts returns the store of the uploaded artifacts, nil if artifacts
+// are not enabled
+func (s *Server) Artifacts() artifacts.Store {
+	return s.config.Artifacts
+}
+
+// composeRequestKey is the artifact key of the original compose request of
+// the compose `id`. It is stored next to the artifacts, so that it's
+// removed together with them.
+func composeRequestKey(id uuid.UUID) string {
+	return path.Join("ComposeRequest", id.String()+".json")
+}
+
+// SaveComposeRequest stores the json of the compose request of `id` with the
+// artifacts. If artifacts are not enabled it saves nothing and silently
+// returns.
+func (s *Server) SaveComposeRequest(id uuid.UUID, request interface{}) error {
+	if s.config.Artifacts == nil {
+		return nil
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	return s.config.Artifacts.Put(context.Background(), composeRequestKey(id), bytes.NewReader(data))
+}
+
+// ReadComposeRequest reads the json of the compose request of `id` into
+// `request`. It returns false if there is no such request or artifacts are
+// not enabled.
+func (s *Server) ReadComposeRequest(id uuid.UUID, request interface{}) (bool, error) {
+	if s.config.Artifacts == nil {
+		return false, nil
+	}
+	r, _, err := s.config.Artifacts.Get(context.Background(), composeRequestKey(id))
+	if errors.Is(err, artifacts.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	defer r.Close()
+	return true, json.NewDecoder(r).Decode(request)
 }
 
 // Provides access to artifacts of a job. Returns an io.Reader for the artifact
-// and the artifact's size.
+// and the artifact's size. The reader is an io.ReadSeekCloser callers should
+// close.
 func (s *Server) JobArtifact(id uuid.UUID, name string) (io.Reader, int64, error) {
-	if s.config.ArtifactsDir == "" {
+	if s.config.Artifacts == nil {
 		return nil, 0, errors.New("Artifacts not enabled")
 	}
 
@@ -765,22 +867,18 @@ func (s *Server) JobArtifact(id uuid.UUID, name string) (io.Reader, int64, error
 		return nil, 0, fmt.Errorf("Cannot access artifacts before job is finished: %s", id)
 	}
 
-	p := path.Join(s.config.ArtifactsDir, id.String(), name)
-	f, err := os.Open(p)
+	r, size, err := s.config.Artifacts.Get(context.Background(), path.Join(id.String(), name))
 	if err != nil {
 		return nil, 0, fmt.Errorf("Error accessing artifact %s for job %s: %v", name, id, err)
 	}
 
-	info, err := f.Stat()
-	if err != nil {
-		return nil, 0, fmt.Errorf("Error getting size of artifact %s for job %s: %v", name, id, err)
-	}
-
-	return f, info.Size(), nil
+	return r, size, nil
 }
 
+// JobArtifactLocation returns where the artifact `name` of job `id` is
+// stored, a path for the local store and a URL otherwise.
 func (s *Server) JobArtifactLocation(id uuid.UUID, name string) (string, error) {
-	if s.config.ArtifactsDir == "" {
+	if s.config.Artifacts == nil {
 		return "", errors.New("Artifacts not enabled")
 	}
 
@@ -793,8 +891,9 @@ func (s *Server) JobArtifactLocation(id uuid.UUID, name string) (string, error)
 		return "", fmt.Errorf("Cannot access artifacts before job is finished: %s", id)
 	}
 
-	p := path.Join(s.config.ArtifactsDir, id.String(), name)
-	if _, err := os.Stat(p); errors.Is(err, os.ErrNotExist) {
+	key := path.Join(id.String(), name)
+	p := s.config.Artifacts.Location(key)
+	if _, err := s.config.Artifacts.Stat(context.Background(), key); err != nil {
 		return p, fmt.Errorf("Artifact not found: %s", p)
 	}
 	return p, nil
@@ -802,7 +901,7 @@ func (s *Server) JobArtifactLocation(id uuid.UUID, name string) (string, error)
 
 // Deletes all artifacts for job `id`.
 func (s *Server) DeleteArtifacts(id uuid.UUID) error {
-	if s.config.ArtifactsDir == "" {
+	if s.config.Artifacts == nil {
 		return errors.New("Artifacts not enabled")
 	}
 
@@ -815,10 +914,12 @@ func (s *Server) DeleteArtifacts(id uuid.UUID) error {
 		return fmt.Errorf("Cannot delete artifacts before job is finished: %s", id)
 	}
 
+	ctx := context.Background()
+
 	// Remove the ComposeRequest but ignore any errors
-	_ = os.Remove(path.Join(s.config.ArtifactsDir, "ComposeRequest", id.String()+".json"))
+	_ = s.config.Artifacts.Delete(ctx, composeRequestKey(id))
 
-	return os.RemoveAll(path.Join(s.config.ArtifactsDir, id.String()))
+	return s.config.Artifacts.Delete(ctx, id.String())
 }
 
 func (s *Server) RequestJob(ctx context.Context, arch string, jobTypes, channels []string, workerID uuid.UUID) (uuid.UUID, uuid.UUID, string, json.RawMessage, []json.RawMessage, error) {
@@ -910,13 +1011,6 @@ func (s *Server) requestJob(ctx context.Context, arch string, jobTypes []string,
 		dynamicArgs = append(dynamicArgs, result)
 	}
 
-	if s.config.ArtifactsDir != "" {
-		err = os.MkdirAll(path.Join(s.config.ArtifactsDir, "tmp", token.String()), 0700)
-		if err != nil {
-			return
-		}
-	}
-
 	prometheus.DequeueJobMetrics(pending, jobInfo.JobStatus.Started, jobInfo.JobType, jobInfo.Channel, archPromLabel)
 
 	return
@@ -999,6 +1093,10 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 			return err
 		}
 		jobResult = &osbuildJR.JobResult
+		if osbuildJR.OSBuildReport != nil && osbuildJR.OSBuildReport.StoreCache != nil {
+			cache := osbuildJR.OSBuildReport.StoreCache
+			prometheus.StoreCacheMetrics(cache.Hits, cache.Misses, jobArch)
+		}
 
 	case JobTypeDepsolve:
 		var depsolveJR DepsolveJobResult
@@ -1102,25 +1200,59 @@ func (s *Server) RequeueOrFinishJob(token uuid.UUID, maxRetries uint64, result j
 	statusCode := clienterrors.GetStatusCode(jobResult.JobError)
 	prometheus.FinishJobMetrics(jobInfo.JobStatus.Started, jobInfo.JobStatus.Finished, jobInfo.JobStatus.Canceled, jobType, jobInfo.Channel, jobArch, statusCode)
 
-	// Move artifacts from the temporary location to the final job
-	// location. Log any errors, but do not treat them as fatal. The job is
-	// already finished.
-	if s.config.ArtifactsDir != "" {
-		err := os.Rename(path.Join(s.config.ArtifactsDir, "tmp", token.String()), path.Join(s.config.ArtifactsDir, jobId.String()))
-		if err != nil {
-			logrus.Errorf("Error moving artifacts for job %s: %v", jobId, err)
+	return nil
+}
+
+// Puts the job with `token` back into the queue without counting it as a
+// retry, so that another worker picks it up right away.
+func (s *Server) RequeueJob(token uuid.UUID) error {
+	jobId, err := s.jobs.IdFromToken(token)
+	if err != nil {
+		switch err {
+		case jobqueue.ErrNotExist:
+			return ErrInvalidToken
+		default:
+			return err
+		}
+	}
+
+	jobInfo, err := s.jobInfo(jobId, nil)
+	if err != nil {
+		return fmt.Errorf("error fetching job info: %v", err)
+	}
+
+	err = s.jobs.RequeueJob(jobId)
+	if err != nil {
+		switch err {
+		case jobqueue.ErrNotRunning:
+			return ErrJobNotRunning
+		default:
+			return fmt.Errorf("error requeueing job: %v", err)
 		}
 	}
 
+	prometheus.RequeueJobMetrics(jobInfo.JobType, jobInfo.Channel)
+	logrus.Infof("Job %s was handed back by its worker", jobId)
+	return nil
+}
+
+// Marks a worker as draining. It doesn't get new jobs anymore and learns
+// about it on its next status update.
+func (s *Server) DrainWorker(workerID uuid.UUID) error {
+	err := s.jobs.SetWorkerDraining(workerID)
+	if err != nil {
+		return err
+	}
+	logrus.Infof("Worker %s is draining", workerID)
 	return nil
 }
 
-func (s *Server) RegisterWorker(c, a string) (uuid.UUID, error) {
-	workerID, err := s.jobs.InsertWorker(c, a)
+func (s *Server) RegisterWorker(c, a string, labels []string) (uuid.UUID, error) {
+	workerID, err := s.jobs.InsertWorker(c, a, labels)
 	if err != nil {
 		return uuid.Nil, err
 	}
-	logrus.Infof("Worker (%v) registered", a)
+	logrus.Infof("Worker (%v) registered with labels %v", a, labels)
 	return workerID, nil
 }
 
@@ -1236,6 +1368,9 @@ func (h *apiHandlers) RequestJob(ctx echo.Context) error {
 		Args:             respArgs,
 		DynamicArgs:      respDynArgs,
 	}
+	if h.server.config.Artifacts != nil {
+		response.ArtifactUploadLocation = common.ToPtr(fmt.Sprintf("%s/jobs/%v/uploads/", api.BasePath, jobToken))
+	}
 	return ctx.JSON(http.StatusCreated, response)
 }
 
@@ -1328,6 +1463,31 @@ func (h *apiHandlers) UpdateJob(ctx echo.Context, idstr string) error {
 	})
 }
 
+func (h *apiHandlers) RequeueJob(ctx echo.Context, tokenstr string) error {
+	token, err := uuid.Parse(tokenstr)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorMalformedJobToken, err)
+	}
+
+	err = h.server.RequeueJob(token)
+	if err != nil {
+		switch err {
+		case ErrInvalidToken:
+			return api.HTTPError(api.ErrorJobNotFound)
+		case ErrJobNotRunning:
+			return api.HTTPError(api.ErrorJobNotRunning)
+		default:
+			return api.HTTPErrorWithInternal(api.ErrorRequeueingJob, err)
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, api.RequeueJobResponse{
+		Href: fmt.Sprintf("%s/jobs/%v/requeue", api.BasePath, token),
+		Id:   token.String(),
+		Kind: "RequeueJobResponse",
+	})
+}
+
 func (h *apiHandlers) UploadJobArtifact(ctx echo.Context, tokenstr string, name string) error {
 	token, err := uuid.Parse(tokenstr)
 	if err != nil {
@@ -1336,17 +1496,25 @@ func (h *apiHandlers) UploadJobArtifact(ctx echo.Context, tokenstr string, name
 
 	request := ctx.Request()
 
-	if h.server.config.ArtifactsDir == "" {
+	if h.server.config.Artifacts == nil {
 		// indicate to the worker that the server is not accepting any artifacts
 		return ctx.NoContent(http.StatusBadRequest)
 	}
 
-	f, err := os.Create(path.Join(h.server.config.ArtifactsDir, "tmp", token.String(), name))
+	// Artifacts are stored under the job's ID right away. The token of a
+	// job which was requeued doesn't resolve anymore, so that a stale
+	// worker can't overwrite the artifacts of the job's new run.
+	jobId, err := h.server.jobs.IdFromToken(token)
 	if err != nil {
-		return api.HTTPErrorWithInternal(api.ErrorDiscardingArtifact, err)
+		switch err {
+		case jobqueue.ErrNotExist:
+			return api.HTTPError(api.ErrorJobNotFound)
+		default:
+			return api.HTTPErrorWithInternal(api.ErrorResolvingJobId, err)
+		}
 	}
 
-	_, err = io.Copy(f, request.Body)
+	err = h.server.config.Artifacts.Put(request.Context(), path.Join(jobId.String(), name), request.Body)
 	if err != nil {
 		return api.HTTPErrorWithInternal(api.ErrorWritingArtifact, err)
 	}
@@ -1354,6 +1522,101 @@ func (h *apiHandlers) UploadJobArtifact(ctx echo.Context, tokenstr string, name
 	return ctx.NoContent(http.StatusOK)
 }
 
+// artifactUploadError maps the errors of chunked artifact uploads to API errors
+func artifactUploadError(err error, code api.ServiceErrorCode) error {
+	switch {
+	case errors.Is(err, ErrArtifactsNotEnabled):
+		return api.HTTPError(api.ErrorArtifactsNotEnabled)
+	case errors.Is(err, ErrInvalidToken):
+		return api.HTTPError(api.ErrorJobNotFound)
+	case errors.Is(err, ErrUploadOffsetMismatch):
+		return api.HTTPErrorWithInternal(api.ErrorUploadOffsetMismatch, err)
+	case errors.Is(err, ErrChecksumMismatch):
+		return api.HTTPErrorWithInternal(api.ErrorChecksumMismatch, err)
+	default:
+		return api.HTTPErrorWithInternal(code, err)
+	}
+}
+
+func parseSHA256(checksum string) ([]byte, error) {
+	digest, err := hex.DecodeString(checksum)
+	if err != nil {
+		return nil, err
+	}
+	if len(digest) != sha256.Size {
+		return nil, fmt.Errorf("digest has %d bytes", len(digest))
+	}
+	return digest, nil
+}
+
+func artifactUploadStatus(token uuid.UUID, name string, offset int64) api.ArtifactUploadStatus {
+	return api.ArtifactUploadStatus{
+		Href:   fmt.Sprintf("%s/jobs/%v/uploads/%s", api.BasePath, token, url.PathEscape(name)),
+		Id:     name,
+		Kind:   "ArtifactUploadStatus",
+		Offset: offset,
+	}
+}
+
+func (h *apiHandlers) GetArtifactUpload(ctx echo.Context, tokenstr string, name string) error {
+	token, err := uuid.Parse(tokenstr)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorMalformedJobToken, err)
+	}
+
+	offset, err := h.server.ArtifactUploadOffset(ctx.Request().Context(), token, name)
+	if err != nil {
+		return artifactUploadError(err, api.ErrorReadingArtifactUpload)
+	}
+
+	return ctx.JSON(http.StatusOK, artifactUploadStatus(token, name, offset))
+}
+
+func (h *apiHandlers) UploadArtifactChunk(ctx echo.Context, tokenstr string, name string, params api.UploadArtifactChunkParams) error {
+	token, err := uuid.Parse(tokenstr)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorMalformedJobToken, err)
+	}
+
+	checksum, err := parseSHA256(params.Sha256)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorMalformedChecksum, err)
+	}
+
+	request := ctx.Request()
+	offset, err := h.server.UploadArtifactChunk(request.Context(), token, name, params.Offset, checksum, request.Body)
+	if err != nil {
+		return artifactUploadError(err, api.ErrorWritingArtifact)
+	}
+
+	return ctx.JSON(http.StatusOK, artifactUploadStatus(token, name, offset))
+}
+
+func (h *apiHandlers) CompleteArtifactUpload(ctx echo.Context, tokenstr string, name string) error {
+	token, err := uuid.Parse(tokenstr)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorMalformedJobToken, err)
+	}
+
+	var body api.CompleteArtifactUploadJSONRequestBody
+	err = ctx.Bind(&body)
+	if err != nil {
+		return err
+	}
+
+	checksum, err := parseSHA256(body.Sha256)
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorMalformedChecksum, err)
+	}
+
+	err = h.server.CompleteArtifactUpload(ctx.Request().Context(), token, name, body.Size, checksum)
+	if err != nil {
+		return artifactUploadError(err, api.ErrorWritingArtifact)
+	}
+
+	return ctx.JSON(http.StatusOK, artifactUploadStatus(token, name, body.Size))
+}
+
 func (h *apiHandlers) PostWorkers(ctx echo.Context) error {
 	var body api.PostWorkersRequest
 	err := ctx.Bind(&body)
@@ -1372,7 +1635,12 @@ func (h *apiHandlers) PostWorkers(ctx echo.Context) error {
 		channel = "org-" + tenant
 	}
 
-	workerID, err := h.server.RegisterWorker(channel, body.Arch)
+	var labels []string
+	if body.Labels != nil {
+		labels = *body.Labels
+	}
+
+	workerID, err := h.server.RegisterWorker(channel, body.Arch, labels)
 	if err != nil {
 		return api.HTTPErrorWithInternal(api.ErrorInsertingWorker, err)
 	}
@@ -1385,8 +1653,38 @@ func (h *apiHandlers) PostWorkers(ctx echo.Context) error {
 	})
 }
 
+func (h *apiHandlers) DeleteWorker(ctx echo.Context, workerID uuid.UUID) error {
+	err := h.server.jobs.DeleteWorker(workerID)
+	switch err {
+	case nil:
+	case jobqueue.ErrWorkerNotExist:
+		return api.HTTPErrorWithInternal(api.ErrorWorkerIdNotFound, err)
+	case jobqueue.ErrActiveJobs:
+		return api.HTTPErrorWithInternal(api.ErrorWorkerHasActiveJobs, err)
+	default:
+		return api.HTTPErrorWithInternal(api.ErrorDeletingWorker, err)
+	}
+
+	logrus.Infof("Worker %s deregistered", workerID)
+	return ctx.NoContent(http.StatusOK)
+}
+
+func (h *apiHandlers) PostWorkerDrain(ctx echo.Context, workerID uuid.UUID) error {
+	err := h.server.DrainWorker(workerID)
+
+	if err == jobqueue.ErrWorkerNotExist {
+		return api.HTTPErrorWithInternal(api.ErrorWorkerIdNotFound, err)
+	}
+
+	if err != nil {
+		return api.HTTPErrorWithInternal(api.ErrorDrainingWorker, err)
+	}
+
+	return ctx.NoContent(http.StatusOK)
+}
+
 func (h *apiHandlers) PostWorkerStatus(ctx echo.Context, workerID uuid.UUID) error {
-	err := h.server.jobs.UpdateWorkerStatus(workerID)
+	draining, err := h.server.jobs.UpdateWorkerStatus(workerID)
 
 	if err == jobqueue.ErrWorkerNotExist {
 		return api.HTTPErrorWithInternal(api.ErrorWorkerIdNotFound, err)
@@ -1396,7 +1694,9 @@ func (h *apiHandlers) PostWorkerStatus(ctx echo.Context, workerID uuid.UUID) err
 		return api.HTTPErrorWithInternal(api.ErrorUpdatingWorkerStatus, err)
 	}
 
-	return ctx.NoContent(http.StatusOK)
+	return ctx.JSON(http.StatusOK, api.PostWorkerStatusResponse{
+		Draining: draining,
+	})
 }
 
 // A simple echo.Binder(), which only accepts application/json, but is more
@@ -2,12 +2,16 @@ package worker_test
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -166,7 +170,7 @@ func TestCreate(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 	handler := server.Handler()
 
-	_, err = server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	_, err = server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 	require.Equal(t, float64(1), promtest.ToFloat64(prometheus.PendingJobs))
 
@@ -197,7 +201,7 @@ func TestCancel(t *testing.T) {
 	}
 	handler := server.Handler()
 
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 	require.Equal(t, float64(1), promtest.ToFloat64(prometheus.PendingJobs))
 
@@ -241,7 +245,7 @@ func TestUpdate(t *testing.T) {
 	}
 	handler := server.Handler()
 
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 	require.Equal(t, float64(1), promtest.ToFloat64(prometheus.PendingJobs))
 
@@ -308,7 +312,7 @@ func TestArgs(t *testing.T) {
 	}
 
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &job, "")
+	jobId, err := server.EnqueueOSBuild(arch.Name(), &job, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	_, _, _, args, _, err := server.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -343,7 +347,7 @@ func TestUpload(t *testing.T) {
 	}
 	handler := server.Handler()
 
-	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	j, token, typ, args, dynamicArgs, err := server.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -386,7 +390,7 @@ func TestUploadNotAcceptingArtifacts(t *testing.T) {
 		t.Fatalf("error creating osbuild manifest: %v", err)
 	}
 
-	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	j, token, typ, args, dynamicArgs, err := server.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -426,7 +430,7 @@ func TestUploadAlteredBasePath(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), config, true)
 	handler := server.Handler()
 
-	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	j, token, typ, args, dynamicArgs, err := server.RequestJob(context.Background(), arch.Name(), []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
@@ -439,6 +443,159 @@ func TestUploadAlteredBasePath(t *testing.T) {
 	test.TestRoute(t, handler, false, "PUT", fmt.Sprintf("/api/image-builder-worker/v1/jobs/%s/artifacts/foobar", token), `this is my artifact`, http.StatusOK, `?`)
 }
 
+func TestChunkedArtifactUpload(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), defaultConfig, true)
+	handler := server.Handler()
+	ctx := context.Background()
+
+	jobID, err := server.EnqueueOSBuild("x86_64", &worker.OSBuildJob{}, "", jobqueue.EnqueueOptions{})
+	require.NoError(t, err)
+	_, token, _, _, _, err := server.RequestJob(ctx, "x86_64", []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+
+	digest := func(s string) []byte {
+		d := sha256.Sum256([]byte(s))
+		return d[:]
+	}
+
+	offset, err := server.ArtifactUploadOffset(ctx, token, "disk.img")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), offset)
+
+	offset, err = server.UploadArtifactChunk(ctx, token, "disk.img", 0, digest("this "), strings.NewReader("this "))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), offset)
+
+	// chunks with a wrong checksum are discarded
+	_, err = server.UploadArtifactChunk(ctx, token, "disk.img", 5, digest("is my"), strings.NewReader("is no"))
+	require.ErrorIs(t, err, worker.ErrChecksumMismatch)
+	offset, err = server.ArtifactUploadOffset(ctx, token, "disk.img")
+	require.NoError(t, err)
+	require.Equal(t, int64(5), offset)
+
+	// chunks have to continue the upload
+	_, err = server.UploadArtifactChunk(ctx, token, "disk.img", 7, digest("is my"), strings.NewReader("is my"))
+	require.ErrorIs(t, err, worker.ErrUploadOffsetMismatch)
+
+	_, err = server.UploadArtifactChunk(ctx, token, "disk.img", 5, digest("is my"), strings.NewReader("is my"))
+	require.NoError(t, err)
+
+	// a replacement of the last chunk with a wrong checksum drops it
+	_, err = server.UploadArtifactChunk(ctx, token, "disk.img", 5, digest("is my "), strings.NewReader("is no "))
+	require.ErrorIs(t, err, worker.ErrChecksumMismatch)
+	offset, err = server.ArtifactUploadOffset(ctx, token, "disk.img")
+	require.NoError(t, err)
+	require.Equal(t, int64(5), offset)
+
+	_, err = server.UploadArtifactChunk(ctx, token, "disk.img", 5, digest("is my"), strings.NewReader("is my"))
+	require.NoError(t, err)
+
+	// resending the last chunk replaces it
+	offset, err = server.UploadArtifactChunk(ctx, token, "disk.img", 5, digest("is my "), strings.NewReader("is my "))
+	require.NoError(t, err)
+	require.Equal(t, int64(11), offset)
+
+	offset, err = server.UploadArtifactChunk(ctx, token, "disk.img", 11, digest("artifact"), strings.NewReader("artifact"))
+	require.NoError(t, err)
+	require.Equal(t, int64(19), offset)
+
+	err = server.CompleteArtifactUpload(ctx, token, "disk.img", 18, digest("this is my artifact"))
+	require.ErrorIs(t, err, worker.ErrUploadOffsetMismatch)
+	err = server.CompleteArtifactUpload(ctx, token, "disk.img", 19, digest("this is not my artifact"))
+	require.ErrorIs(t, err, worker.ErrChecksumMismatch)
+	err = server.CompleteArtifactUpload(ctx, token, "disk.img", 19, digest("this is my artifact"))
+	require.NoError(t, err)
+
+	require.NoError(t, server.FinishJob(token, []byte("{}")))
+	r, size, err := server.JobArtifact(jobID, "disk.img")
+	require.NoError(t, err)
+	defer r.(io.Closer).Close()
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "this is my artifact", string(content))
+	require.Equal(t, int64(19), size)
+
+	// the chunks are gone, only the artifact is left
+	names, err := os.ReadDir(server.Artifacts().Location(jobID.String()))
+	require.NoError(t, err)
+	require.Len(t, names, 1)
+
+	// the token is invalid after the job finished
+	test.TestRoute(t, handler, false, "GET", fmt.Sprintf("/api/worker/v1/jobs/%s/uploads/disk.img", token), ``, http.StatusNotFound, `?`)
+}
+
+// A requeued job builds its artifacts again, its new run must not continue
+// the upload of the previous one.
+func TestChunkedArtifactUploadRequeued(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), defaultConfig, true)
+	ctx := context.Background()
+
+	jobID, err := server.EnqueueOSBuild("x86_64", &worker.OSBuildJob{}, "", jobqueue.EnqueueOptions{})
+	require.NoError(t, err)
+	_, token, _, _, _, err := server.RequestJob(ctx, "x86_64", []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+
+	digest := func(s string) []byte {
+		d := sha256.Sum256([]byte(s))
+		return d[:]
+	}
+
+	offset, err := server.UploadArtifactChunk(ctx, token, "disk.img", 0, digest("this "), strings.NewReader("this "))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), offset)
+
+	require.NoError(t, server.RequeueJob(token))
+	requeuedID, requeuedToken, _, _, _, err := server.RequestJob(ctx, "x86_64", []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+	require.Equal(t, jobID, requeuedID)
+	require.NotEqual(t, token, requeuedToken)
+
+	offset, err = server.ArtifactUploadOffset(ctx, requeuedToken, "disk.img")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), offset)
+
+	offset, err = server.UploadArtifactChunk(ctx, requeuedToken, "disk.img", 0, digest("that "), strings.NewReader("that "))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), offset)
+	offset, err = server.UploadArtifactChunk(ctx, requeuedToken, "disk.img", 5, digest("is my artifact"), strings.NewReader("is my artifact"))
+	require.NoError(t, err)
+	require.Equal(t, int64(19), offset)
+	require.NoError(t, server.CompleteArtifactUpload(ctx, requeuedToken, "disk.img", 19, digest("that is my artifact")))
+
+	require.NoError(t, server.FinishJob(requeuedToken, []byte("{}")))
+	r, _, err := server.JobArtifact(jobID, "disk.img")
+	require.NoError(t, err)
+	defer r.(io.Closer).Close()
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "that is my artifact", string(content))
+}
+
+func TestChunkedArtifactUploadRoutes(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), defaultConfig, true)
+	handler := server.Handler()
+
+	_, err := server.EnqueueOSBuild("x86_64", &worker.OSBuildJob{}, "", jobqueue.EnqueueOptions{})
+	require.NoError(t, err)
+	_, token, _, _, _, err := server.RequestJob(context.Background(), "x86_64", []string{worker.JobTypeOSBuild}, []string{""}, uuid.Nil)
+	require.NoError(t, err)
+
+	location := fmt.Sprintf("/api/worker/v1/jobs/%s/uploads/disk.img", token)
+	digest := sha256.Sum256([]byte("chunk"))
+	checksum := hex.EncodeToString(digest[:])
+
+	test.TestRoute(t, handler, false, "GET", location, ``, http.StatusOK,
+		fmt.Sprintf(`{"href":"%s","id":"disk.img","kind":"ArtifactUploadStatus","offset":0}`, location))
+	test.TestRoute(t, handler, false, "PATCH", location+"?offset=0&sha256=nothex", `chunk`, http.StatusBadRequest,
+		`{"kind":"Error","code":"IMAGE-BUILDER-WORKER-24"}`, "message", "href", "operation_id", "reason", "id", "details")
+	test.TestRoute(t, handler, false, "PATCH", location+"?offset=0&sha256="+checksum, `chunk`, http.StatusOK,
+		fmt.Sprintf(`{"href":"%s","id":"disk.img","kind":"ArtifactUploadStatus","offset":5}`, location))
+	test.TestRoute(t, handler, false, "PATCH", location+"?offset=3&sha256="+checksum, `chunk`, http.StatusConflict,
+		`{"kind":"Error","code":"IMAGE-BUILDER-WORKER-22"}`, "message", "href", "operation_id", "reason", "id", "details")
+	test.TestRoute(t, handler, false, "POST", location, fmt.Sprintf(`{"size":5,"sha256":"%s"}`, checksum), http.StatusOK,
+		fmt.Sprintf(`{"href":"%s","id":"disk.img","kind":"ArtifactUploadStatus","offset":5}`, location))
+}
+
 func TestTimeout(t *testing.T) {
 	distroStruct := newTestDistro(t)
 	arch, err := distroStruct.GetArch(test_distro.TestArchName)
@@ -467,10 +624,10 @@ func TestRequestJobById(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 	handler := server.Handler()
 
-	depsolveJobId, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "")
+	depsolveJobId, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
-	jobId, err := server.EnqueueManifestJobByID(&worker.ManifestJobByID{}, []uuid.UUID{depsolveJobId}, "")
+	jobId, err := server.EnqueueManifestJobByID(&worker.ManifestJobByID{}, []uuid.UUID{depsolveJobId}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	test.TestRoute(t, server.Handler(), false, "POST", "/api/worker/v1/jobs", fmt.Sprintf(`{"arch":"arch","types":["%s"]}`, worker.JobTypeManifestIDOnly), http.StatusBadRequest,
@@ -521,7 +678,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 		switch dep.main.(type) {
 		case *worker.OSBuildJob:
 			job := dep.main.(*worker.OSBuildJob)
-			id, err = s.EnqueueOSBuildAsDependency(arch.ARCH_X86_64.String(), job, depUUIDs, "")
+			id, err = s.EnqueueOSBuildAsDependency(arch.ARCH_X86_64.String(), job, depUUIDs, "", jobqueue.EnqueueOptions{})
 			if err != nil {
 				return nil, err
 			}
@@ -531,7 +688,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) < 1 {
 				return nil, fmt.Errorf("at least one dependency is expected for ManifestJobByID, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueManifestJobByID(job, depUUIDs, "")
+			id, err = s.EnqueueManifestJobByID(job, depUUIDs, "", jobqueue.EnqueueOptions{})
 			if err != nil {
 				return nil, err
 			}
@@ -541,7 +698,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) != 0 {
 				return nil, fmt.Errorf("dependencies are not supported for DepsolveJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueDepsolve(job, "")
+			id, err = s.EnqueueDepsolve(job, "", jobqueue.EnqueueOptions{})
 			if err != nil {
 				return nil, err
 			}
@@ -551,7 +708,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) != 0 {
 				return nil, fmt.Errorf("dependencies are not supported for KojiInitJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueKojiInit(job, "")
+			id, err = s.EnqueueKojiInit(job, "", jobqueue.EnqueueOptions{})
 			if err != nil {
 				return nil, err
 			}
@@ -561,14 +718,14 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) < 2 {
 				return nil, fmt.Errorf("at least two dependencies are expected for KojiFinalizeJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueKojiFinalize(job, depUUIDs[0], depUUIDs[1:], "")
+			id, err = s.EnqueueKojiFinalize(job, depUUIDs[0], depUUIDs[1:], "", jobqueue.EnqueueOptions{})
 			if err != nil {
 				return nil, err
 			}
 
 		case *worker.ContainerResolveJob:
 			job := dep.main.(*worker.ContainerResolveJob)
-			id, err = s.EnqueueContainerResolveJob(job, depUUIDs, "")
+			id, err = s.EnqueueContainerResolveJob(job, depUUIDs, "", jobqueue.EnqueueOptions{})
 			if err != nil {
 				return nil, err
 			}
@@ -578,7 +735,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) != 0 {
 				return nil, fmt.Errorf("dependencies are not supported for OSTreeResolveJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueOSTreeResolveJob(job, "")
+			id, err = s.EnqueueOSTreeResolveJob(job, "", jobqueue.EnqueueOptions{})
 			if err != nil {
 				return nil, err
 			}
@@ -588,7 +745,7 @@ func enqueueAndFinishTestJobDependencies(s *worker.Server, deps []testJob) ([]uu
 			if len(depUUIDs) != 0 {
 				return nil, fmt.Errorf("dependencies are not supported for BootcInfoResolveJob, got: %d", len(depUUIDs))
 			}
-			id, err = s.EnqueueBootcInfoResolveJob(arch.ARCH_X86_64.String(), job, "")
+			id, err = s.EnqueueBootcInfoResolveJob(arch.ARCH_X86_64.String(), job, "", jobqueue.EnqueueOptions{})
 			if err != nil {
 				return nil, err
 			}
@@ -1365,7 +1522,7 @@ func TestWorkerWatch(t *testing.T) {
 	var resp api.PostWorkersResponse
 	require.NoError(t, json.Unmarshal(reply, &resp))
 
-	test.TestRoute(t, server.Handler(), false, "POST", fmt.Sprintf("/api/worker/v1/workers/%s/status", resp.WorkerId), "{}", 200, "")
+	test.TestRoute(t, server.Handler(), false, "POST", fmt.Sprintf("/api/worker/v1/workers/%s/status", resp.WorkerId), "{}", 200, `{"draining":false}`)
 	time.Sleep(time.Millisecond * 400)
 	test.TestRoute(t, server.Handler(), false, "POST", fmt.Sprintf("/api/worker/v1/workers/%s/status", resp.WorkerId), "", 400,
 		`{"href":"/api/worker/v1/errors/18","code":"IMAGE-BUILDER-WORKER-18","id":"18","kind":"Error","message":"Given worker id doesn't exist","reason":"Given worker id doesn't exist"}`,
@@ -1377,7 +1534,7 @@ func TestRequestJobForWorker(t *testing.T) {
 	reply := test.TestRouteWithReply(t, server.Handler(), false, "POST", "/api/worker/v1/workers", fmt.Sprintf(`{"arch":"%s"}`, arch.Current().String()), 201, `{"href":"/api/worker/v1/workers","kind":"WorkerID","id": "15"}`, "id", "worker_id")
 	var resp api.PostWorkersResponse
 	require.NoError(t, json.Unmarshal(reply, &resp))
-	test.TestRoute(t, server.Handler(), false, "POST", fmt.Sprintf("/api/worker/v1/workers/%s/status", resp.WorkerId), "{}", 200, "")
+	test.TestRoute(t, server.Handler(), false, "POST", fmt.Sprintf("/api/worker/v1/workers/%s/status", resp.WorkerId), "{}", 200, `{"draining":false}`)
 
 	distroStruct := newTestDistro(t)
 	arch, err := distroStruct.GetArch(test_distro.TestArchName)
@@ -1396,7 +1553,7 @@ func TestRequestJobForWorker(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error creating osbuild manifest: %v", err)
 	}
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	// Can request a job with worker ID
@@ -1408,6 +1565,55 @@ func TestRequestJobForWorker(t *testing.T) {
 	require.Nil(t, dynamicArgs)
 }
 
+func TestWorkerDrain(t *testing.T) {
+	server := newTestServer(t, t.TempDir(), defaultConfig, false)
+	handler := server.Handler()
+
+	reply := test.TestRouteWithReply(t, handler, false, "POST", "/api/worker/v1/workers", fmt.Sprintf(`{"arch":"%s"}`, arch.Current().String()), 201, `{"href":"/api/worker/v1/workers","kind":"WorkerID","id": "15"}`, "id", "worker_id")
+	var resp api.PostWorkersResponse
+	require.NoError(t, json.Unmarshal(reply, &resp))
+	test.TestRoute(t, handler, false, "POST", fmt.Sprintf("/api/worker/v1/workers/%s/status", resp.WorkerId), "{}", 200, `{"draining":false}`)
+
+	jobId, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "", jobqueue.EnqueueOptions{})
+	require.NoError(t, err)
+	j, token, _, _, _, err := server.RequestJob(context.Background(), arch.Current().String(), []string{worker.JobTypeDepsolve}, []string{""}, resp.WorkerId)
+	require.NoError(t, err)
+	require.Equal(t, jobId, j)
+
+	test.TestRoute(t, handler, false, "POST", fmt.Sprintf("/api/worker/v1/workers/%s/drain", resp.WorkerId), "{}", 200, "")
+	test.TestRoute(t, handler, false, "POST", fmt.Sprintf("/api/worker/v1/workers/%s/status", resp.WorkerId), "{}", 200, `{"draining":true}`)
+
+	// the worker still runs its job
+	test.TestRoute(t, handler, false, "DELETE", fmt.Sprintf("/api/worker/v1/workers/%s", resp.WorkerId), ``, 400,
+		`{"href":"/api/worker/v1/errors/20","code":"IMAGE-BUILDER-WORKER-20","id":"20","kind":"Error","message":"Worker still has active jobs","reason":"Worker still has active jobs"}`,
+		"operation_id")
+
+	// a draining worker doesn't get new jobs
+	otherJobId, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "", jobqueue.EnqueueOptions{})
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+	_, _, _, _, _, err = server.RequestJob(ctx, arch.Current().String(), []string{worker.JobTypeDepsolve}, []string{""}, resp.WorkerId)
+	require.Equal(t, jobqueue.ErrDequeueTimeout, err)
+
+	test.TestRoute(t, handler, false, "POST", fmt.Sprintf("/api/worker/v1/jobs/%s/requeue", token), `{}`, 200,
+		fmt.Sprintf(`{"href":"/api/worker/v1/jobs/%s/requeue","id":"%s","kind":"RequeueJobResponse"}`, token, token))
+	test.TestRoute(t, handler, false, "POST", fmt.Sprintf("/api/worker/v1/jobs/%s/requeue", token), `{}`, 404,
+		`{"href":"/api/worker/v1/errors/5","code":"IMAGE-BUILDER-WORKER-5","id":"5","kind":"Error","message":"Token not found","reason":"Token not found"}`,
+		"operation_id")
+
+	test.TestRoute(t, handler, false, "DELETE", fmt.Sprintf("/api/worker/v1/workers/%s", resp.WorkerId), ``, 200, "")
+
+	// other workers pick up the job that was handed back
+	var dequeued []uuid.UUID
+	for i := 0; i < 2; i++ {
+		j, _, _, _, _, err = server.RequestJob(context.Background(), arch.Current().String(), []string{worker.JobTypeDepsolve}, []string{""}, uuid.Nil)
+		require.NoError(t, err)
+		dequeued = append(dequeued, j)
+	}
+	require.ElementsMatch(t, []uuid.UUID{jobId, otherJobId}, dequeued)
+}
+
 func TestJobHeartbeats(t *testing.T) {
 	config := defaultConfig
 	config.JobTimeout = time.Millisecond * 1
@@ -1431,7 +1637,7 @@ func TestJobHeartbeats(t *testing.T) {
 	if err != nil {
 		t.Fatalf("error creating osbuild manifest: %v", err)
 	}
-	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobId, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 	require.Eventually(t, func() bool {
 		return promtest.ToFloat64(prometheus.PendingJobs) == 1
@@ -1546,7 +1752,7 @@ func TestCleanupArtifacts(t *testing.T) {
 		t.Fatalf("error creating osbuild manifest: %v", err)
 	}
 
-	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "")
+	jobID, err := server.EnqueueOSBuild(arch.Name(), &worker.OSBuildJob{Manifest: mf}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	// Make a fake artifact for the existing jobid
@@ -1587,7 +1793,7 @@ func TestBootcInfoResolveJob(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 	job := testBootcInfoResolveSampleJob()
 
-	jobID, err := server.EnqueueBootcInfoResolveJob("x86_64", job, "")
+	jobID, err := server.EnqueueBootcInfoResolveJob("x86_64", job, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 	require.NotEqual(t, uuid.Nil, jobID)
 
@@ -1638,7 +1844,7 @@ func TestBootcInfoResolveJobArchRouting(t *testing.T) {
 		BasePath:          "/api/worker/v1",
 	}, false)
 
-	_, err := server.EnqueueBootcInfoResolveJob("x86_64", testBootcInfoResolveSampleJob(), "")
+	_, err := server.EnqueueBootcInfoResolveJob("x86_64", testBootcInfoResolveSampleJob(), "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	_, _, _, _, _, err = server.RequestJob(
@@ -1651,7 +1857,7 @@ func TestBootcInfoResolveJobArchRouting(t *testing.T) {
 func TestBootcInfoResolveJobInfoWrongType(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 
-	depsolveJobID, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "")
+	depsolveJobID, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	_, token, _, _, _, err := server.RequestJob(
@@ -1679,11 +1885,12 @@ func testBootcPreManifestSampleJob() *worker.BootcPreManifestJob {
 func TestEnqueueBootcPreManifestJob(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 
-	infoResolveJobID, err := server.EnqueueBootcInfoResolveJob("x86_64", testBootcInfoResolveSampleJob(), "")
+	infoResolveJobID, err := server.EnqueueBootcInfoResolveJob("x86_64", testBootcInfoResolveSampleJob(), "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	preManifestJobID, err := server.EnqueueBootcPreManifestJob(
 		testBootcPreManifestSampleJob(), []uuid.UUID{infoResolveJobID}, "",
+		jobqueue.EnqueueOptions{},
 	)
 	require.NoError(t, err)
 	require.NotEqual(t, uuid.Nil, preManifestJobID)
@@ -1697,7 +1904,7 @@ func TestBootcPreManifestJobInfo(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 	job := testBootcPreManifestSampleJob()
 
-	preManifestJobID, err := server.EnqueueBootcPreManifestJob(job, nil, "")
+	preManifestJobID, err := server.EnqueueBootcPreManifestJob(job, nil, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	dequeuedID, token, jobType, args, dynamicArgs, err := server.RequestJob(
@@ -1747,7 +1954,7 @@ func TestBootcPreManifestJobInfoWrongType(t *testing.T) {
 	server := newTestServer(t, t.TempDir(), defaultConfig, false)
 
 	// Enqueue a depsolve job, then try to read it as a bootc-pre-manifest job
-	depsolveJobID, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "")
+	depsolveJobID, err := server.EnqueueDepsolve(&worker.DepsolveJob{}, "", jobqueue.EnqueueOptions{})
 	require.NoError(t, err)
 
 	_, token, _, _, _, err := server.RequestJob(